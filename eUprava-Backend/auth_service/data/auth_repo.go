@@ -89,6 +89,11 @@ func (rr *AuthRepo) DobaviKorisnike(ctx context.Context) (Korisnici, error) {
 	return rr.filter(ctx, filter)
 }
 
+func (rr *AuthRepo) DobaviKorisnikePoRoli(ctx context.Context, rola Rola) (Korisnici, error) {
+	filter := bson.D{{Key: "rola", Value: rola}}
+	return rr.filter(ctx, filter)
+}
+
 func (rr *AuthRepo) DodajKorisnika(ctx context.Context, koisnik *Korisnik) error {
 
 	koisnik.ID = primitive.NewObjectID()
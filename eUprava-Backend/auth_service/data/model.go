@@ -79,6 +79,8 @@ const (
 	Tuzioc            = "Tuzioc"
 	Istrazitelj       = "Istrazitelj"
 	Sudija            = "Sudija"
+	PredsednikSuda    = "PredsednikSuda"
+	GlavniTuzilac     = "GlavniTuzilac"
 )
 
 type Pol string
@@ -91,6 +93,7 @@ const (
 type Claims struct {
 	ID            primitive.ObjectID `bson:"_id" json:"id"`
 	KorisnickoIme string             `json:"korisnickoIme"`
+	ImeIPrezime   string             `json:"imeIPrezime"`
 	Rola          Rola               `json:"rola"`
 	ExpiresAt     time.Time          `json:"expires_at"`
 }
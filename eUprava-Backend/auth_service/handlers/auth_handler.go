@@ -13,6 +13,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -51,6 +52,39 @@ func (h *AuthHandler) DobaviKorisnike(rw http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// DobaviKorisnikePoRoli vraca korisnike sa zadatom rolom, samo sa podacima potrebnim drugim servisima
+func (h *AuthHandler) DobaviKorisnikePoRoli(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AuthHandler.DobaviKorisnikePoRoli")
+	defer span.End()
+
+	rola := data.Rola(mux.Vars(r)["rola"])
+	korisnici, err := h.authRepo.DobaviKorisnikePoRoli(ctx, rola)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja korisnika")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja korisnika"))
+		return
+	}
+
+	rezultat := data.Korisnici{}
+	for _, korisnik := range korisnici {
+		rezultat = append(rezultat, &data.Korisnik{
+			ID:            korisnik.ID,
+			Ime:           korisnik.Ime,
+			Prezime:       korisnik.Prezime,
+			KorisnickoIme: korisnik.KorisnickoIme,
+			Rola:          korisnik.Rola,
+		})
+	}
+
+	err = rezultat.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
 func (h *AuthHandler) DobaviKorisnikaPoId(rw http.ResponseWriter, r *http.Request) {
 	ctx, span := h.tracer.Start(r.Context(), "AuthHandler.DobaviKorisnikaPoId")
 	defer span.End()
@@ -69,12 +103,15 @@ func (h *AuthHandler) DobaviKorisnikaPoId(rw http.ResponseWriter, r *http.Reques
 		rw.WriteHeader(http.StatusBadRequest)
 		rw.Write([]byte("Greska dobavljanja korisnika po ID"))
 		span.SetStatus(codes.Error, "Greska dobavljanja korisnika po ID")
+		return
 	}
-	korisnik.Lozinka = ""
-
 	if korisnik == nil {
+		rw.WriteHeader(http.StatusNotFound)
+		rw.Write([]byte("Korisnik sa prosledjenim id ne postoji"))
+		span.SetStatus(codes.Error, "Korisnik sa prosledjenim id ne postoji")
 		return
 	}
+	korisnik.Lozinka = ""
 
 	err = korisnik.ToJSON(rw)
 	if err != nil {
@@ -193,6 +230,7 @@ func GenerateJWT(user *data.Korisnik) (string, error) {
 	claims := &data.Claims{
 		ID:            user.ID,
 		KorisnickoIme: user.KorisnickoIme,
+		ImeIPrezime:   strings.TrimSpace(user.Ime + " " + user.Prezime),
 		Rola:          user.Rola,
 		ExpiresAt:     time.Now().Add(time.Minute * 60),
 	}
@@ -77,6 +77,9 @@ func main() {
 	dobaviKorisnikaPoId := router.Methods(http.MethodGet).Subrouter()
 	dobaviKorisnikaPoId.HandleFunc("/korisnik/{id}", authHandler.DobaviKorisnikaPoId)
 
+	dobaviKorisnikePoRoli := router.Methods(http.MethodGet).Subrouter()
+	dobaviKorisnikePoRoli.HandleFunc("/korisnici/rola/{rola}", authHandler.DobaviKorisnikePoRoli)
+
 	login := router.Methods(http.MethodPost).Subrouter()
 	login.HandleFunc("/login", authHandler.Login)
 
@@ -0,0 +1,82 @@
+package drzavljanstvo
+
+import (
+	"strings"
+)
+
+// Srbija je sifra domaceg drzavljanstva
+const Srbija = "SRB"
+
+// Drzavljanstvo se cuva kao troslovna ISO 3166-1 sifra drzave. Pored sifre prepoznaju se dvoslovna sifra i
+// uobicajeni nazivi drzave i drzavljanstva, bez obzira na velika slova i dijakriticke znake.
+var sifre = map[string][]string{
+	Srbija: {"RS", "Srbija", "Republika Srbija", "Republike Srbije", "Serbia", "Republic of Serbia", "Serbian",
+		"Srpsko", "Srpsko drzavljanstvo", "Drzavljanin Srbije", "Drzavljanka Srbije", "Srbin", "Srpkinja"},
+	"BIH": {"BA", "Bosna i Hercegovina", "BiH", "Bosnia and Herzegovina", "Bosansko"},
+	"HRV": {"HR", "Hrvatska", "Republika Hrvatska", "Croatia", "Hrvatsko"},
+	"MNE": {"ME", "Crna Gora", "Montenegro", "Crnogorsko"},
+	"MKD": {"MK", "Severna Makedonija", "Makedonija", "North Macedonia", "Macedonia", "Makedonsko"},
+	"SVN": {"SI", "Slovenija", "Slovenia", "Slovenacko"},
+	"ALB": {"AL", "Albanija", "Albania", "Albansko"},
+	"HUN": {"HU", "Madjarska", "Hungary", "Madjarsko"},
+	"ROU": {"RO", "Rumunija", "Romania", "Rumunsko"},
+	"BGR": {"BG", "Bugarska", "Bulgaria", "Bugarsko"},
+	"GRC": {"GR", "Grcka", "Greece", "Grcko"},
+	"TUR": {"TR", "Turska", "Turkey", "Turkiye", "Tursko"},
+	"AUT": {"AT", "Austrija", "Austria", "Austrijsko"},
+	"DEU": {"DE", "Nemacka", "Germany", "Nemacko"},
+	"CHE": {"CH", "Svajcarska", "Switzerland", "Svajcarsko"},
+	"ITA": {"IT", "Italija", "Italy", "Italijansko"},
+	"FRA": {"FR", "Francuska", "France", "Francusko"},
+	"GBR": {"GB", "UK", "Velika Britanija", "Ujedinjeno Kraljevstvo", "United Kingdom", "Britansko"},
+	"USA": {"US", "SAD", "Sjedinjene Americke Drzave", "United States", "Americko"},
+	"RUS": {"RU", "Rusija", "Russia", "Rusko"},
+	"UKR": {"UA", "Ukrajina", "Ukraine", "Ukrajinsko"},
+	"CHN": {"CN", "Kina", "China", "Kinesko"},
+}
+
+var poNazivu = func() map[string]string {
+	nazivi := map[string]string{}
+	for sifra, varijante := range sifre {
+		nazivi[kljuc(sifra)] = sifra
+		for _, varijanta := range varijante {
+			nazivi[kljuc(varijanta)] = sifra
+		}
+	}
+	return nazivi
+}()
+
+var latinica = strings.NewReplacer("š", "s", "đ", "dj", "č", "c", "ć", "c", "ž", "z")
+
+func kljuc(tekst string) string {
+	return latinica.Replace(strings.ToLower(strings.Join(strings.Fields(tekst), " ")))
+}
+
+// Normalizuj vraca sifru drzavljanstva iz unetog teksta. Drzava koja nije prepoznata zadrzava uneti naziv velikim
+// slovima, a prazan tekst ostaje prazan.
+func Normalizuj(tekst string) string {
+	if strings.TrimSpace(tekst) == "" {
+		return ""
+	}
+	if sifra, ok := poNazivu[kljuc(tekst)]; ok {
+		return sifra
+	}
+	return strings.ToUpper(strings.Join(strings.Fields(tekst), " "))
+}
+
+// Poznato vraca da li je drzavljanstvo prepoznato kao drzava iz tabele sifara. Neprepoznat ili prazan tekst je
+// nepoznato drzavljanstvo, koje nije ni domace ni strano.
+func Poznato(drzavljanstvo string) bool {
+	_, ok := poNazivu[kljuc(drzavljanstvo)]
+	return ok
+}
+
+// JeDomace vraca da li je drzavljanstvo domace
+func JeDomace(drzavljanstvo string) bool {
+	return Normalizuj(drzavljanstvo) == Srbija
+}
+
+// JeStrano vraca da li je drzavljanstvo prepoznato i nije domace
+func JeStrano(drzavljanstvo string) bool {
+	return Poznato(drzavljanstvo) && !JeDomace(drzavljanstvo)
+}
@@ -0,0 +1,66 @@
+package drzavljanstvo
+
+import "testing"
+
+func TestNormalizuj(t *testing.T) {
+	tests := []struct {
+		name  string
+		tekst string
+		want  string
+	}{
+		{"prazan tekst", "", ""},
+		{"samo razmaci", "   ", ""},
+		{"troslovna sifra", "SRB", Srbija},
+		{"sifra malim slovima", "srb", Srbija},
+		{"dvoslovna sifra", "RS", Srbija},
+		{"naziv drzave", "Srbija", Srbija},
+		{"naziv sa visestrukim razmacima", "  Republika   Srbija ", Srbija},
+		{"naziv na engleskom", "Republic of Serbia", Srbija},
+		{"drzavljanstvo sa dijakritikom", "Državljanin Srbije", Srbija},
+		{"drzavljanstvo bez dijakritike", "drzavljanka srbije", Srbija},
+		{"pridev", "Srpsko", Srbija},
+		{"strana drzava", "Hrvatska", "HRV"},
+		{"strana drzava dvoslovnom sifrom", "de", "DEU"},
+		{"skraceni naziv", "BiH", "BIH"},
+		{"neprepoznata drzava ostaje velikim slovima", " atlantida  nova ", "ATLANTIDA NOVA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalizuj(tt.tekst); got != tt.want {
+				t.Errorf("Normalizuj(%q) = %q, ocekivano %q", tt.tekst, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDomaceStranoPoznato(t *testing.T) {
+	tests := []struct {
+		name    string
+		tekst   string
+		poznato bool
+		domace  bool
+		strano  bool
+	}{
+		{"prazno je nepoznato", "", false, false, false},
+		{"neprepoznato nije strano", "Atlantida", false, false, false},
+		{"domace sifrom", "SRB", true, true, false},
+		{"domace nazivom", "Srpkinja", true, true, false},
+		{"strano", "Nemacka", true, false, true},
+		{"strano sifrom", "HRV", true, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Poznato(tt.tekst); got != tt.poznato {
+				t.Errorf("Poznato(%q) = %v, ocekivano %v", tt.tekst, got, tt.poznato)
+			}
+			if got := JeDomace(tt.tekst); got != tt.domace {
+				t.Errorf("JeDomace(%q) = %v, ocekivano %v", tt.tekst, got, tt.domace)
+			}
+			if got := JeStrano(tt.tekst); got != tt.strano {
+				t.Errorf("JeStrano(%q) = %v, ocekivano %v", tt.tekst, got, tt.strano)
+			}
+		})
+	}
+}
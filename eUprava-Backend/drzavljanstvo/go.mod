@@ -0,0 +1,3 @@
+module drzavljanstvo
+
+go 1.20
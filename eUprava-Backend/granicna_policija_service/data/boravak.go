@@ -0,0 +1,151 @@
+package data
+
+import (
+	"drzavljanstvo"
+	"math"
+	"time"
+	_ "time/tzdata"
+)
+
+// Dani i sati boravka i statistike racunaju se po lokalnom vremenu u Srbiji
+const NazivLokalneZone = "Europe/Belgrade"
+
+var LokalnaZona = func() *time.Location {
+	zona, err := time.LoadLocation(NazivLokalneZone)
+	if err != nil {
+		panic(err)
+	}
+	return zona
+}()
+
+const (
+	DomaceDrzavljanstvo = drzavljanstvo.Srbija
+	MaxDanaBoravka      = 90
+	PeriodBoravkaDana   = 180
+	PragUpozorenjaDana  = 10
+)
+
+type Boravak struct {
+	Ulaz     *Prelaz `json:"ulaz,omitempty"`
+	Izlaz    *Prelaz `json:"izlaz,omitempty"`
+	BrojDana int     `json:"brojDana"`
+	UToku    bool    `json:"uToku"`
+}
+
+type IstorijaPutnika struct {
+	Prelazi           []Prelaz  `json:"prelazi"`
+	Boravci           []Boravak `json:"boravci"`
+	DanaUPeriodu      int       `json:"danaUPeriodu"`
+	PreostaloDana     int       `json:"preostaloDana"`
+	StraniDrzavljanin bool      `json:"straniDrzavljanin"`
+	// Ni na jednom prelazu drzavljanstvo putnika nije evidentirano niti prepoznato
+	DrzavljanstvoNepoznato bool `json:"drzavljanstvoNepoznato"`
+}
+
+// NapraviIstorijuPutnika uparuje hronoloski sortirane prelaze u boravke (ULAZ -> IZLAZ)
+// i racuna broj dana boravka u poslednjih PeriodBoravkaDana dana
+func NapraviIstorijuPutnika(prelazi []Prelaz, sada time.Time) IstorijaPutnika {
+	istorija := IstorijaPutnika{
+		Prelazi: prelazi,
+		Boravci: UpariPrelaze(prelazi, sada),
+	}
+	if istorija.Prelazi == nil {
+		istorija.Prelazi = []Prelaz{}
+	}
+
+	istorija.DrzavljanstvoNepoznato = true
+	for _, prelaz := range prelazi {
+		if drzavljanstvo.Poznato(prelaz.DrzavljanstvoPutnika) {
+			istorija.DrzavljanstvoNepoznato = false
+		}
+		if drzavljanstvo.JeStrano(prelaz.DrzavljanstvoPutnika) {
+			istorija.StraniDrzavljanin = true
+		}
+	}
+
+	istorija.DanaUPeriodu = DaniBoravkaUPeriodu(istorija.Boravci, sada.AddDate(0, 0, -PeriodBoravkaDana+1), sada)
+	istorija.PreostaloDana = MaxDanaBoravka - istorija.DanaUPeriodu
+	if istorija.PreostaloDana < 0 {
+		istorija.PreostaloDana = 0
+	}
+
+	return istorija
+}
+
+func UpariPrelaze(prelazi []Prelaz, sada time.Time) []Boravak {
+	boravci := []Boravak{}
+	var otvoren *Boravak
+
+	for i := range prelazi {
+		prelaz := &prelazi[i]
+		// Odbijeni prelazi ne menjaju boravak putnika
+		if prelaz.Odbijanje != nil {
+			continue
+		}
+		switch prelaz.Smer {
+		case ULAZ:
+			// Ulaz bez evidentiranog izlaza ostaje neuparen
+			if otvoren != nil {
+				boravci = append(boravci, *otvoren)
+			}
+			otvoren = &Boravak{Ulaz: prelaz}
+		case IZLAZ:
+			if otvoren == nil {
+				boravci = append(boravci, Boravak{Izlaz: prelaz})
+				continue
+			}
+			otvoren.Izlaz = prelaz
+			otvoren.BrojDana = brojKalendarskihDana(otvoren.Ulaz.Datum.Time(), prelaz.Datum.Time())
+			boravci = append(boravci, *otvoren)
+			otvoren = nil
+		}
+	}
+
+	if otvoren != nil {
+		otvoren.UToku = true
+		otvoren.BrojDana = brojKalendarskihDana(otvoren.Ulaz.Datum.Time(), sada)
+		boravci = append(boravci, *otvoren)
+	}
+
+	return boravci
+}
+
+// DaniBoravkaUPeriodu broji kalendarske dane (ukljucujuci dan ulaska i izlaska) provedene u zemlji izmedju od i do
+func DaniBoravkaUPeriodu(boravci []Boravak, od time.Time, do time.Time) int {
+	od = PocetakDana(od)
+	do = PocetakDana(do)
+	dani := make(map[time.Time]bool)
+
+	for _, boravak := range boravci {
+		if boravak.Ulaz == nil {
+			continue
+		}
+		pocetak := PocetakDana(boravak.Ulaz.Datum.Time())
+		kraj := do
+		if boravak.Izlaz != nil {
+			kraj = PocetakDana(boravak.Izlaz.Datum.Time())
+		}
+		if pocetak.Before(od) {
+			pocetak = od
+		}
+		if kraj.After(do) {
+			kraj = do
+		}
+		for dan := pocetak; !dan.After(kraj); dan = dan.AddDate(0, 0, 1) {
+			dani[dan] = true
+		}
+	}
+
+	return len(dani)
+}
+
+func brojKalendarskihDana(od time.Time, do time.Time) int {
+	// dan promene na letnje ili zimsko vreme traje 23 ili 25 sati
+	return int(math.Round(PocetakDana(do).Sub(PocetakDana(od)).Hours()/24)) + 1
+}
+
+// PocetakDana vraca ponoc lokalnog dana kome pripada zadato vreme
+func PocetakDana(t time.Time) time.Time {
+	t = t.In(LokalnaZona)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, LokalnaZona)
+}
@@ -0,0 +1,303 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func lokalno(godina int, mesec time.Month, dan, sat, minut int) time.Time {
+	return time.Date(godina, mesec, dan, sat, minut, 0, 0, LokalnaZona)
+}
+
+func prelazU(smer Smer, vreme time.Time, drzavljanstvo string) Prelaz {
+	return Prelaz{Smer: smer, Datum: primitive.NewDateTimeFromTime(vreme), DrzavljanstvoPutnika: drzavljanstvo}
+}
+
+func odbijenPrelazU(smer Smer, vreme time.Time) Prelaz {
+	prelaz := prelazU(smer, vreme, "")
+	prelaz.Odbijanje = &Odbijanje{}
+	return prelaz
+}
+
+func TestUpariPrelaze(t *testing.T) {
+	sada := lokalno(2025, time.June, 10, 12, 0)
+
+	tests := []struct {
+		name  string
+		ulaz  []Prelaz
+		dani  []int
+		uToku []bool
+	}{
+		{
+			name: "ulaz i izlaz istog dana",
+			ulaz: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.June, 1, 8, 0), ""),
+				prelazU(IZLAZ, lokalno(2025, time.June, 1, 20, 0), ""),
+			},
+			dani:  []int{1},
+			uToku: []bool{false},
+		},
+		{
+			name: "boravak bez izlaza traje do danas",
+			ulaz: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.June, 1, 8, 0), ""),
+			},
+			dani:  []int{10},
+			uToku: []bool{true},
+		},
+		{
+			name: "izlaz bez ulaza je poseban boravak",
+			ulaz: []Prelaz{
+				prelazU(IZLAZ, lokalno(2025, time.June, 1, 8, 0), ""),
+			},
+			dani:  []int{0},
+			uToku: []bool{false},
+		},
+		{
+			name: "dva uzastopna ulaza ostavljaju prvi neuparen",
+			ulaz: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.May, 1, 8, 0), ""),
+				prelazU(ULAZ, lokalno(2025, time.June, 1, 8, 0), ""),
+				prelazU(IZLAZ, lokalno(2025, time.June, 3, 8, 0), ""),
+			},
+			dani:  []int{0, 3},
+			uToku: []bool{false, false},
+		},
+		{
+			name: "odbijeni prelazi se preskacu",
+			ulaz: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.June, 1, 8, 0), ""),
+				odbijenPrelazU(IZLAZ, lokalno(2025, time.June, 2, 8, 0)),
+				prelazU(IZLAZ, lokalno(2025, time.June, 4, 8, 0), ""),
+			},
+			dani:  []int{4},
+			uToku: []bool{false},
+		},
+		{
+			name: "prelazak na letnje vreme ne skracuje boravak",
+			ulaz: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.March, 29, 23, 30), ""),
+				prelazU(IZLAZ, lokalno(2025, time.March, 31, 0, 30), ""),
+			},
+			dani:  []int{3},
+			uToku: []bool{false},
+		},
+		{
+			name: "ponoc po lokalnom vremenu a ne po UTC",
+			ulaz: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.June, 1, 0, 30), ""),
+				prelazU(IZLAZ, lokalno(2025, time.June, 1, 23, 30), ""),
+			},
+			dani:  []int{1},
+			uToku: []bool{false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boravci := UpariPrelaze(tt.ulaz, sada)
+			if len(boravci) != len(tt.dani) {
+				t.Fatalf("broj boravaka = %d, ocekivano %d", len(boravci), len(tt.dani))
+			}
+			for i, boravak := range boravci {
+				if boravak.BrojDana != tt.dani[i] {
+					t.Errorf("boravak %d: BrojDana = %d, ocekivano %d", i, boravak.BrojDana, tt.dani[i])
+				}
+				if boravak.UToku != tt.uToku[i] {
+					t.Errorf("boravak %d: UToku = %v, ocekivano %v", i, boravak.UToku, tt.uToku[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDaniBoravkaUPeriodu(t *testing.T) {
+	od := lokalno(2025, time.June, 1, 0, 0)
+	do := lokalno(2025, time.June, 30, 23, 0)
+
+	tests := []struct {
+		name    string
+		prelazi []Prelaz
+		want    int
+	}{
+		{
+			name: "boravak unutar perioda",
+			prelazi: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.June, 5, 10, 0), ""),
+				prelazU(IZLAZ, lokalno(2025, time.June, 9, 10, 0), ""),
+			},
+			want: 5,
+		},
+		{
+			name: "boravak pre perioda se odseca",
+			prelazi: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.May, 20, 10, 0), ""),
+				prelazU(IZLAZ, lokalno(2025, time.June, 2, 10, 0), ""),
+			},
+			want: 2,
+		},
+		{
+			name: "boravak u toku se racuna do kraja perioda",
+			prelazi: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.June, 21, 10, 0), ""),
+			},
+			want: 10,
+		},
+		{
+			name: "dan izlaska i ponovnog ulaska se broji jednom",
+			prelazi: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.June, 1, 10, 0), ""),
+				prelazU(IZLAZ, lokalno(2025, time.June, 3, 8, 0), ""),
+				prelazU(ULAZ, lokalno(2025, time.June, 3, 20, 0), ""),
+				prelazU(IZLAZ, lokalno(2025, time.June, 4, 10, 0), ""),
+			},
+			want: 4,
+		},
+		{
+			name: "izlaz bez ulaza se ne broji",
+			prelazi: []Prelaz{
+				prelazU(IZLAZ, lokalno(2025, time.June, 3, 8, 0), ""),
+			},
+			want: 0,
+		},
+		{
+			name: "boravak posle perioda se ne broji",
+			prelazi: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.July, 2, 10, 0), ""),
+				prelazU(IZLAZ, lokalno(2025, time.July, 5, 10, 0), ""),
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boravci := UpariPrelaze(tt.prelazi, do)
+			if got := DaniBoravkaUPeriodu(boravci, od, do); got != tt.want {
+				t.Errorf("DaniBoravkaUPeriodu() = %d, ocekivano %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNapraviIstorijuPutnika(t *testing.T) {
+	sada := lokalno(2025, time.June, 30, 12, 0)
+
+	tests := []struct {
+		name          string
+		prelazi       []Prelaz
+		danaUPeriodu  int
+		preostaloDana int
+		strani        bool
+		nepoznato     bool
+	}{
+		{
+			name:          "bez prelaza",
+			prelazi:       nil,
+			danaUPeriodu:  0,
+			preostaloDana: MaxDanaBoravka,
+			nepoznato:     true,
+		},
+		{
+			name: "stranac sa 85 dana u periodu",
+			prelazi: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.April, 1, 10, 0), "Hrvatska"),
+				prelazU(IZLAZ, lokalno(2025, time.June, 24, 10, 0), "HRV"),
+			},
+			danaUPeriodu:  85,
+			preostaloDana: 5,
+			strani:        true,
+		},
+		{
+			name: "prekoracen boravak ne daje negativan ostatak",
+			prelazi: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.March, 1, 10, 0), "DEU"),
+			},
+			danaUPeriodu:  122,
+			preostaloDana: 0,
+			strani:        true,
+		},
+		{
+			name: "boravak stariji od 180 dana se ne broji",
+			prelazi: []Prelaz{
+				prelazU(ULAZ, lokalno(2024, time.October, 1, 10, 0), "DEU"),
+				prelazU(IZLAZ, lokalno(2024, time.December, 1, 10, 0), "DEU"),
+			},
+			danaUPeriodu:  0,
+			preostaloDana: MaxDanaBoravka,
+			strani:        true,
+		},
+		{
+			name: "domaci drzavljanin nije stranac",
+			prelazi: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.June, 1, 10, 0), "Srbija"),
+			},
+			danaUPeriodu:  30,
+			preostaloDana: 60,
+		},
+		{
+			name: "neprepoznato drzavljanstvo nije strano",
+			prelazi: []Prelaz{
+				prelazU(ULAZ, lokalno(2025, time.June, 1, 10, 0), "Atlantida"),
+			},
+			danaUPeriodu:  30,
+			preostaloDana: 60,
+			nepoznato:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			istorija := NapraviIstorijuPutnika(tt.prelazi, sada)
+			if istorija.Prelazi == nil {
+				t.Error("Prelazi = nil, ocekivan prazan niz")
+			}
+			if istorija.DanaUPeriodu != tt.danaUPeriodu {
+				t.Errorf("DanaUPeriodu = %d, ocekivano %d", istorija.DanaUPeriodu, tt.danaUPeriodu)
+			}
+			if istorija.PreostaloDana != tt.preostaloDana {
+				t.Errorf("PreostaloDana = %d, ocekivano %d", istorija.PreostaloDana, tt.preostaloDana)
+			}
+			if istorija.StraniDrzavljanin != tt.strani {
+				t.Errorf("StraniDrzavljanin = %v, ocekivano %v", istorija.StraniDrzavljanin, tt.strani)
+			}
+			if istorija.DrzavljanstvoNepoznato != tt.nepoznato {
+				t.Errorf("DrzavljanstvoNepoznato = %v, ocekivano %v", istorija.DrzavljanstvoNepoznato, tt.nepoznato)
+			}
+		})
+	}
+}
+
+func TestPocetakDana(t *testing.T) {
+	tests := []struct {
+		name  string
+		vreme time.Time
+		want  time.Time
+	}{
+		{
+			name:  "kasno uvece po UTC je sledeci lokalni dan",
+			vreme: time.Date(2025, time.June, 1, 22, 30, 0, 0, time.UTC),
+			want:  lokalno(2025, time.June, 2, 0, 0),
+		},
+		{
+			name:  "zimsko vreme",
+			vreme: time.Date(2025, time.January, 15, 23, 30, 0, 0, time.UTC),
+			want:  lokalno(2025, time.January, 16, 0, 0),
+		},
+		{
+			name:  "popodne ostaje isti dan",
+			vreme: lokalno(2025, time.June, 1, 15, 0),
+			want:  lokalno(2025, time.June, 1, 0, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PocetakDana(tt.vreme); !got.Equal(tt.want) {
+				t.Errorf("PocetakDana() = %v, ocekivano %v", got, tt.want)
+			}
+		})
+	}
+}
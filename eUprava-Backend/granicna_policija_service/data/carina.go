@@ -0,0 +1,82 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+)
+
+type KategorijaRobe string
+
+const (
+	GOTOVINA = "GOTOVINA"
+	CIGARETE = "CIGARETE"
+	ALKOHOL  = "ALKOHOL"
+	ROBA     = "ROBA"
+)
+
+const (
+	LimitGotovineEUR = 10000.0
+	LimitRobeEUR     = 300.0
+)
+
+// Dozvoljene kolicine po putniku bez prijave, u jedinicama mere kategorije
+var limitiKolicine = map[KategorijaRobe]float64{
+	CIGARETE: 200,
+	ALKOHOL:  1,
+}
+
+// Okvirni kursevi za preracunavanje prijavljenih vrednosti u evre
+var kursEUR = map[string]float64{
+	"EUR": 1,
+	"RSD": 1 / 117.0,
+	"USD": 0.92,
+	"CHF": 1.04,
+	"GBP": 1.17,
+	"BAM": 0.51,
+	"HUF": 1 / 395.0,
+}
+
+// VrednostUEUR preracunava iznos u evre, drugi rezultat je false ako valuta nije podrzana
+func VrednostUEUR(iznos float64, valuta string) (float64, bool) {
+	kurs, ok := kursEUR[strings.ToUpper(valuta)]
+	if !ok {
+		return 0, false
+	}
+	return iznos * kurs, true
+}
+
+// ProveriDeklaraciju primenjuje pravila o limitima gotovine, robe i kolicina
+// i vraca razloge zbog kojih prelaz treba uputiti na pregled
+func ProveriDeklaraciju(deklaracija *CarinskaDeklaracija) []string {
+	razlozi := []string{}
+	var gotovina, roba float64
+	kolicine := make(map[KategorijaRobe]float64)
+
+	for _, stavka := range deklaracija.Stavke {
+		vrednost, ok := VrednostUEUR(stavka.Vrednost, stavka.Valuta)
+		if !ok {
+			razlozi = append(razlozi, fmt.Sprintf("Nepodrzana valuta %q za stavku %q", stavka.Valuta, stavka.Naziv))
+			continue
+		}
+		if stavka.Kategorija == GOTOVINA {
+			gotovina += vrednost
+			continue
+		}
+		roba += vrednost
+		kolicine[stavka.Kategorija] += stavka.Kolicina
+	}
+
+	if gotovina > LimitGotovineEUR {
+		razlozi = append(razlozi, fmt.Sprintf("Gotovina od %.2f EUR prelazi limit od %.2f EUR", gotovina, LimitGotovineEUR))
+	}
+	if roba > LimitRobeEUR {
+		razlozi = append(razlozi, fmt.Sprintf("Vrednost robe od %.2f EUR prelazi limit od %.2f EUR", roba, LimitRobeEUR))
+	}
+	for kategorija, limit := range limitiKolicine {
+		if kolicine[kategorija] > limit {
+			razlozi = append(razlozi, fmt.Sprintf("Kolicina za kategoriju %s (%.2f) prelazi dozvoljenih %.2f", kategorija, kolicine[kategorija], limit))
+		}
+	}
+
+	return razlozi
+}
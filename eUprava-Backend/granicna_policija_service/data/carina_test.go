@@ -0,0 +1,118 @@
+package data
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVrednostUEUR(t *testing.T) {
+	tests := []struct {
+		name   string
+		iznos  float64
+		valuta string
+		want   float64
+		ok     bool
+	}{
+		{"evri", 100, "EUR", 100, true},
+		{"valuta malim slovima", 117, "rsd", 1, true},
+		{"nepodrzana valuta", 100, "JPY", 0, false},
+		{"prazna valuta", 100, "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := VrednostUEUR(tt.iznos, tt.valuta)
+			if ok != tt.ok {
+				t.Fatalf("VrednostUEUR(%v, %q) ok = %v, ocekivano %v", tt.iznos, tt.valuta, ok, tt.ok)
+			}
+			if diff := got - tt.want; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("VrednostUEUR(%v, %q) = %v, ocekivano %v", tt.iznos, tt.valuta, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProveriDeklaraciju(t *testing.T) {
+	tests := []struct {
+		name   string
+		stavke []StavkaDeklaracije
+		// delovi teksta koje redom moraju da sadrze razlozi pregleda
+		razlozi []string
+	}{
+		{
+			name:    "prazna deklaracija",
+			stavke:  nil,
+			razlozi: nil,
+		},
+		{
+			name: "gotovina tacno na limitu",
+			stavke: []StavkaDeklaracije{
+				{Naziv: "Gotovina", Kategorija: GOTOVINA, Vrednost: LimitGotovineEUR, Valuta: "EUR"},
+			},
+			razlozi: nil,
+		},
+		{
+			name: "gotovina iznad limita zbirno u vise valuta",
+			stavke: []StavkaDeklaracije{
+				{Naziv: "Evri", Kategorija: GOTOVINA, Vrednost: 9000, Valuta: "EUR"},
+				{Naziv: "Dinari", Kategorija: GOTOVINA, Vrednost: 234000, Valuta: "RSD"},
+			},
+			razlozi: []string{"Gotovina"},
+		},
+		{
+			name: "gotovina se ne racuna u robu",
+			stavke: []StavkaDeklaracije{
+				{Naziv: "Gotovina", Kategorija: GOTOVINA, Vrednost: 5000, Valuta: "EUR"},
+				{Naziv: "Parfem", Kategorija: ROBA, Kolicina: 1, Vrednost: 100, Valuta: "EUR"},
+			},
+			razlozi: nil,
+		},
+		{
+			name: "roba iznad limita",
+			stavke: []StavkaDeklaracije{
+				{Naziv: "Telefon", Kategorija: ROBA, Kolicina: 1, Vrednost: 250, Valuta: "EUR"},
+				{Naziv: "Cigarete", Kategorija: CIGARETE, Kolicina: 200, Vrednost: 60, Valuta: "EUR"},
+			},
+			razlozi: []string{"Vrednost robe"},
+		},
+		{
+			name: "kolicina cigareta iznad dozvoljene",
+			stavke: []StavkaDeklaracije{
+				{Naziv: "Cigarete", Kategorija: CIGARETE, Kolicina: 400, Vrednost: 100, Valuta: "EUR"},
+			},
+			razlozi: []string{"CIGARETE"},
+		},
+		{
+			name: "kolicine se sabiraju po kategoriji",
+			stavke: []StavkaDeklaracije{
+				{Naziv: "Vino", Kategorija: ALKOHOL, Kolicina: 0.75, Vrednost: 20, Valuta: "EUR"},
+				{Naziv: "Rakija", Kategorija: ALKOHOL, Kolicina: 0.5, Vrednost: 15, Valuta: "EUR"},
+			},
+			razlozi: []string{"ALKOHOL"},
+		},
+		{
+			name: "nepodrzana valuta se prijavljuje i ne sabira",
+			stavke: []StavkaDeklaracije{
+				{Naziv: "Jeni", Kategorija: GOTOVINA, Vrednost: 5000000, Valuta: "JPY"},
+			},
+			razlozi: []string{"Nepodrzana valuta"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			razlozi := ProveriDeklaraciju(&CarinskaDeklaracija{Stavke: tt.stavke})
+			if razlozi == nil {
+				t.Fatal("ProveriDeklaraciju() = nil, ocekivan prazan niz")
+			}
+			if len(razlozi) != len(tt.razlozi) {
+				t.Fatalf("ProveriDeklaraciju() = %q, ocekivano %d razloga", razlozi, len(tt.razlozi))
+			}
+			for i, deo := range tt.razlozi {
+				if !strings.Contains(razlozi[i], deo) {
+					t.Errorf("razlog %d = %q, ocekivano da sadrzi %q", i, razlozi[i], deo)
+				}
+			}
+		})
+	}
+}
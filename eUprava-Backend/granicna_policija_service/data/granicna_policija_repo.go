@@ -2,6 +2,7 @@ package data
 
 import (
 	"context"
+	"drzavljanstvo"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -11,6 +12,8 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -82,15 +85,270 @@ func (rr *GranicnaPolicijaRepo) Ping() {
 	fmt.Println(databases)
 }
 
-func (pr *GranicnaPolicijaRepo) CreateSumnjivoLice(ctx context.Context, sumnjivoLice *SumnjivoLice) error {
+// zameniSumnjivoLice upisuje ceo zapis o osobi i koristi se samo u migracijama pre pokretanja servera,
+// izmene u toku rada su atomicne kako istovremene kontrole ne bi prepisale jedna drugu
+func (pr *GranicnaPolicijaRepo) zameniSumnjivoLice(ctx context.Context, sumnjivoLice *SumnjivoLice) error {
 	collection := pr.cli.Database("granicna_policija_db").Collection("sumnjiva_lica")
-	_, err := collection.InsertOne(ctx, sumnjivoLice)
+	sumnjivoLice.Azurirano = primitive.NewDateTimeFromTime(time.Now())
+	_, err := collection.ReplaceOne(ctx, bson.M{"_id": sumnjivoLice.ID}, sumnjivoLice, options.Replace().SetUpsert(true))
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// KreirajIndekseSumnjivihLica spaja duple zapise istog JMBG-a i kreira jedinstven indeks po JMBG-u,
+// kao i indekse po kojima se traze osobe pri kontroli prelaza
+func (pr *GranicnaPolicijaRepo) KreirajIndekseSumnjivihLica(ctx context.Context) error {
+	if err := pr.dopuniKljuceveImena(ctx); err != nil {
+		return err
+	}
+	if err := pr.spojiDupleSumnjivaLica(ctx); err != nil {
+		return err
+	}
+
+	collection := pr.cli.Database("granicna_policija_db").Collection("sumnjiva_lica")
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "jmbg", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.D{{Key: "jmbg", Value: bson.D{{Key: "$type", Value: "string"}}}}),
+		},
+		{Keys: bson.D{{Key: "brojeviPasosa", Value: 1}}},
+		{Keys: bson.D{{Key: "oznaceno", Value: 1}, {Key: "kljuceviImena", Value: 1}}},
+	})
+	return err
+}
+
+// dopuniKljuceveImena racuna kljuceve imena za zapise nastale pre nego sto su se kljucevi cuvali
+func (pr *GranicnaPolicijaRepo) dopuniKljuceveImena(ctx context.Context) error {
+	collection := pr.cli.Database("granicna_policija_db").Collection("sumnjiva_lica")
+
+	cursor, err := collection.Find(ctx, bson.D{{Key: "kljuceviImena", Value: bson.D{{Key: "$exists", Value: false}}}})
+	if err != nil {
+		return err
+	}
+	var zapisi []SumnjivoLice
+	if err := cursor.All(ctx, &zapisi); err != nil {
+		return err
+	}
+	for i := range zapisi {
+		zapisi[i].dodajKljuceveImena()
+		if len(zapisi[i].KljuceviImena) == 0 {
+			continue
+		}
+		_, err := collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: zapisi[i].ID}},
+			bson.D{{Key: "$set", Value: bson.D{{Key: "kljuceviImena", Value: zapisi[i].KljuceviImena}}}})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spojiDupleSumnjivaLica spaja zapise sa istim JMBG-om u najstariji zapis i preusmerava alarme na njega
+func (pr *GranicnaPolicijaRepo) spojiDupleSumnjivaLica(ctx context.Context) error {
+	collection := pr.cli.Database("granicna_policija_db").Collection("sumnjiva_lica")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "jmbg", Value: bson.D{{Key: "$type", Value: "string"}}}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$jmbg"},
+			{Key: "ukupno", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "ukupno", Value: bson.D{{Key: "$gt", Value: 1}}}}}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	var dupli []struct {
+		JMBG string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &dupli); err != nil {
+		return err
+	}
+
+	sada := time.Now()
+	for _, d := range dupli {
+		cursor, err := collection.Find(ctx, bson.D{{Key: "jmbg", Value: d.JMBG}}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+		if err != nil {
+			return err
+		}
+		var zapisi []SumnjivoLice
+		if err := cursor.All(ctx, &zapisi); err != nil {
+			return err
+		}
+		if len(zapisi) < 2 {
+			continue
+		}
+
+		zadrzan := &zapisi[0]
+		var uklonjeni []primitive.ObjectID
+		for i := 1; i < len(zapisi); i++ {
+			zadrzan.Spoji(&zapisi[i], sada)
+			uklonjeni = append(uklonjeni, zapisi[i].ID)
+		}
+		if err := pr.zameniSumnjivoLice(ctx, zadrzan); err != nil {
+			return err
+		}
+		_, err = pr.cli.Database("granicna_policija_db").Collection("alarmi").UpdateMany(ctx,
+			bson.D{{Key: "pogodak.referencaId", Value: bson.D{{Key: "$in", Value: uklonjeni}}}},
+			bson.D{{Key: "$set", Value: bson.D{{Key: "pogodak.referencaId", Value: zadrzan.ID}}}})
+		if err != nil {
+			return err
+		}
+		if _, err := collection.DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: uklonjeni}}}}); err != nil {
+			return err
+		}
+		pr.logger.Printf("Spojeno %d duplih zapisa sumnjivog lica %s", len(uklonjeni), zadrzan.ID.Hex())
+	}
+	return nil
+}
+
+// izmenaPovezivanja dodaje osobi prelaz, broj pasosa i varijantu imena putnika bez citanja celog zapisa,
+// uz postavljanje prosledjenih polja
+func izmenaPovezivanja(prelaz *Prelaz, postavi bson.D) bson.D {
+	dodaj := bson.D{}
+	if prelaz.BrojPasosaPutnika != "" {
+		dodaj = append(dodaj, bson.E{Key: "brojeviPasosa", Value: prelaz.BrojPasosaPutnika})
+	}
+	if varijanta := NormalizujIme(prelaz.ImePutnika, prelaz.PrezimePutnika); varijanta != "" {
+		dodaj = append(dodaj,
+			bson.E{Key: "varijanteImena", Value: varijanta},
+			bson.E{Key: "kljuceviImena", Value: bson.D{{Key: "$each", Value: KljuceviImena(varijanta)}}})
+	}
+	if !prelaz.ID.IsZero() {
+		dodaj = append(dodaj, bson.E{Key: "prelaziIds", Value: prelaz.ID})
+	}
+
+	izmena := bson.D{{Key: "$set", Value: postavi}}
+	if len(dodaj) > 0 {
+		izmena = append(izmena, bson.E{Key: "$addToSet", Value: dodaj})
+	}
+	return izmena
+}
+
+func (pr *GranicnaPolicijaRepo) izmeniSumnjivoLice(ctx context.Context, id primitive.ObjectID, izmena bson.D) (*SumnjivoLice, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("sumnjiva_lica")
+
+	var sumnjivoLice SumnjivoLice
+	err := collection.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: id}}, izmena,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&sumnjivoLice)
+	if err != nil {
+		return nil, err
+	}
+	return &sumnjivoLice, nil
+}
+
+// osveziRizik ponovo racuna rizik iz zapisa procitanog posle izmene
+func (pr *GranicnaPolicijaRepo) osveziRizik(ctx context.Context, sumnjivoLice *SumnjivoLice, sada time.Time) (*SumnjivoLice, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("sumnjiva_lica")
+
+	sumnjivoLice.IzracunajRizik(sada)
+	_, err := collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: sumnjivoLice.ID}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "rizik", Value: sumnjivoLice.Rizik}}}})
+	if err != nil {
+		return nil, err
+	}
+	return sumnjivoLice, nil
+}
+
+// PovezPrelazSaSumnjivimLicem pripisuje prelaz osobi i vraca azuriran zapis
+func (pr *GranicnaPolicijaRepo) PovezPrelazSaSumnjivimLicem(ctx context.Context, id primitive.ObjectID, prelaz *Prelaz) (*SumnjivoLice, error) {
+	sada := time.Now()
+	izmena := izmenaPovezivanja(prelaz, bson.D{{Key: "azurirano", Value: primitive.NewDateTimeFromTime(sada)}})
+	sumnjivoLice, err := pr.izmeniSumnjivoLice(ctx, id, izmena)
+	if err != nil {
+		return nil, err
+	}
+	return pr.osveziRizik(ctx, sumnjivoLice, sada)
+}
+
+// EvidentirajIncidentSumnjivogLica dodaje incident osobi sa prelaza, a ako osoba nije evidentirana kreira novi zapis.
+// Kada dva zahteva istovremeno kreiraju zapis za isti JMBG, jedinstven indeks odbija drugi upis koji se zatim
+// dodaje zapisu koji je prvi kreiran.
+func (pr *GranicnaPolicijaRepo) EvidentirajIncidentSumnjivogLica(ctx context.Context, prelaz *Prelaz, incident Incident) (*SumnjivoLice, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("sumnjiva_lica")
+	sada := time.Now()
+
+	for pokusaj := 0; pokusaj < 2; pokusaj++ {
+		postojece, err := pr.PronadjiSumnjivoLice(ctx, prelaz)
+		if err != nil {
+			return nil, err
+		}
+
+		if postojece == nil {
+			sumnjivoLice := NovoSumnjivoLice(prelaz)
+			sumnjivoLice.PovezPrelaz(prelaz)
+			sumnjivoLice.DodajIncident(incident, sada)
+			sumnjivoLice.Azurirano = primitive.NewDateTimeFromTime(sada)
+			_, err := collection.InsertOne(ctx, sumnjivoLice)
+			if mongo.IsDuplicateKeyError(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			return sumnjivoLice, nil
+		}
+
+		izmena := izmenaPovezivanja(prelaz, bson.D{
+			{Key: "opis", Value: incident.Opis},
+			{Key: "azurirano", Value: primitive.NewDateTimeFromTime(sada)},
+		})
+		izmena = append(izmena, bson.E{Key: "$push", Value: bson.D{{Key: "incidenti", Value: incident}}})
+		sumnjivoLice, err := pr.izmeniSumnjivoLice(ctx, postojece.ID, izmena)
+		if err != nil {
+			return nil, err
+		}
+
+		// osoba kojoj je oznaka skinuta ponovo se oznacava, osim ako ju je u medjuvremenu oznacio neko drugi
+		if !sumnjivoLice.Oznaceno {
+			oznaceno, _, err := pr.PromeniOznakuSumnjivogLica(ctx, sumnjivoLice.ID, PromenaOznake{
+				Oznaceno:     true,
+				Razlog:       incident.Opis,
+				IdSluzbenika: incident.IdSluzbenika,
+			})
+			if err != nil {
+				return nil, err
+			}
+			sumnjivoLice = oznaceno
+		}
+		return pr.osveziRizik(ctx, sumnjivoLice, sada)
+	}
+	return nil, fmt.Errorf("zapis o osobi nije kreiran ni pronadjen")
+}
+
+// PromeniOznakuSumnjivogLica menja oznaku samo ako osoba vec nije u trazenom stanju i vraca false ako jeste
+func (pr *GranicnaPolicijaRepo) PromeniOznakuSumnjivogLica(ctx context.Context, id primitive.ObjectID, promena PromenaOznake) (*SumnjivoLice, bool, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("sumnjiva_lica")
+	sada := time.Now()
+	promena.Datum = primitive.NewDateTimeFromTime(sada)
+
+	filter := bson.D{{Key: "_id", Value: id}, {Key: "oznaceno", Value: bson.D{{Key: "$ne", Value: promena.Oznaceno}}}}
+	izmena := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "oznaceno", Value: promena.Oznaceno},
+			{Key: "azurirano", Value: promena.Datum},
+		}},
+		{Key: "$push", Value: bson.D{{Key: "istorijaOznaka", Value: promena}}},
+	}
+	var sumnjivoLice SumnjivoLice
+	err := collection.FindOneAndUpdate(ctx, filter, izmena, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&sumnjivoLice)
+	if err == mongo.ErrNoDocuments {
+		postojece, err := pr.GetSumnjivoLiceByID(ctx, id)
+		if err != nil {
+			return nil, false, err
+		}
+		return postojece, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return &sumnjivoLice, true, nil
+}
+
 func (pr *GranicnaPolicijaRepo) CreatePrelaz(ctx context.Context, prelaz *Prelaz) error {
 	collection := pr.cli.Database("granicna_policija_db").Collection("prelazi")
 	_, err := collection.InsertOne(ctx, prelaz)
@@ -109,10 +367,134 @@ func (pr *GranicnaPolicijaRepo) CreateKrivicnaPrijava(ctx context.Context, krivi
 	return nil
 }
 
+func (pr *GranicnaPolicijaRepo) GetKrivicnaPrijavaByID(ctx context.Context, id primitive.ObjectID) (*KrivicnaPrijava, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("krivicne_prijave")
+	var krivicnaPrijava KrivicnaPrijava
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&krivicnaPrijava)
+	if err != nil {
+		return nil, err
+	}
+
+	return &krivicnaPrijava, nil
+}
+
+type FilterKrivicnihPrijava struct {
+	Status           StatusPrijave
+	Od               time.Time
+	Do               time.Time
+	JMBG             string
+	GranicniPrelazId primitive.ObjectID
+	Stranica         int64
+	Velicina         int64
+}
+
+// PretraziKrivicnePrijave vraca jednu stranicu prijava koje odgovaraju filteru i ukupan broj takvih prijava
+func (pr *GranicnaPolicijaRepo) PretraziKrivicnePrijave(ctx context.Context, f FilterKrivicnihPrijava) ([]KrivicnaPrijava, int64, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("krivicne_prijave")
+
+	filter := bson.M{}
+	if f.Status != "" {
+		if f.Status == PODNETA {
+			filter["status"] = bson.M{"$in": bson.A{PODNETA, nil}}
+		} else {
+			filter["status"] = f.Status
+		}
+	}
+	datum := bson.M{}
+	if !f.Od.IsZero() {
+		datum["$gte"] = primitive.NewDateTimeFromTime(f.Od)
+	}
+	if !f.Do.IsZero() {
+		datum["$lt"] = primitive.NewDateTimeFromTime(f.Do)
+	}
+	if len(datum) > 0 {
+		filter["datum"] = datum
+	}
+	if f.JMBG != "" {
+		filter["$or"] = bson.A{
+			bson.M{"osumnjiceni.jmbg": f.JMBG},
+			bson.M{"prelaz.JMBGPutnika": f.JMBG},
+		}
+	}
+	if !f.GranicniPrelazId.IsZero() {
+		filter["prelaz.granicniPrelazId"] = f.GranicniPrelazId
+	}
+
+	ukupno, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "datum", Value: -1}}).
+		SetSkip((f.Stranica - 1) * f.Velicina).
+		SetLimit(f.Velicina)
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	krivicnePrijave := []KrivicnaPrijava{}
+	if err := cursor.All(ctx, &krivicnePrijave); err != nil {
+		return nil, 0, err
+	}
+
+	return krivicnePrijave, ukupno, nil
+}
+
+// UpdateSadrzajKrivicnePrijave menja sadrzaj prijave samo dok je prijava u statusu PODNETA
+func (pr *GranicnaPolicijaRepo) UpdateSadrzajKrivicnePrijave(ctx context.Context, krivicnaPrijava *KrivicnaPrijava) error {
+	collection := pr.cli.Database("granicna_policija_db").Collection("krivicne_prijave")
+
+	filter := bson.M{
+		"_id":    krivicnaPrijava.ID,
+		"status": bson.M{"$in": bson.A{PODNETA, nil}},
+	}
+	update := bson.M{"$set": bson.M{
+		"opis":                krivicnaPrijava.Opis,
+		"pravnaKvalifikacija": krivicnaPrijava.PravnaKvalifikacija,
+		"osumnjiceni":         krivicnaPrijava.Osumnjiceni,
+		"svedoci":             krivicnaPrijava.Svedoci,
+	}}
+	rezultat, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// PromeniStatusKrivicnePrijave menja status samo ako je prijava i dalje u statusu iz kog se prelazi
+func (pr *GranicnaPolicijaRepo) PromeniStatusKrivicnePrijave(ctx context.Context, id primitive.ObjectID, iz StatusPrijave, promena PromenaStatusa) error {
+	collection := pr.cli.Database("granicna_policija_db").Collection("krivicne_prijave")
+
+	trenutni := bson.A{iz}
+	if iz == PODNETA {
+		trenutni = append(trenutni, nil)
+	}
+	filter := bson.M{"_id": id, "status": bson.M{"$in": trenutni}}
+	update := bson.M{
+		"$set":  bson.M{"status": promena.Status},
+		"$push": bson.M{"istorijaStatusa": promena},
+	}
+	rezultat, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
 func (pr *GranicnaPolicijaRepo) GetSumnjivaLica(ctx context.Context) ([]SumnjivoLice, error) {
 	collection := pr.cli.Database("granicna_policija_db").Collection("sumnjiva_lica")
 
-	cursor, err := collection.Find(ctx, bson.M{})
+	opcije := options.Find().SetSort(bson.D{{Key: "rizik", Value: -1}, {Key: "azurirano", Value: -1}})
+	cursor, err := collection.Find(ctx, bson.M{}, opcije)
 	if err != nil {
 		return nil, err
 	}
@@ -126,6 +508,17 @@ func (pr *GranicnaPolicijaRepo) GetSumnjivaLica(ctx context.Context) ([]Sumnjivo
 	return sumnjivaLica, nil
 }
 
+func (pr *GranicnaPolicijaRepo) GetSumnjivoLiceByID(ctx context.Context, id primitive.ObjectID) (*SumnjivoLice, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("sumnjiva_lica")
+
+	var sumnjivoLice SumnjivoLice
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&sumnjivoLice)
+	if err != nil {
+		return nil, err
+	}
+	return &sumnjivoLice, nil
+}
+
 func (pr *GranicnaPolicijaRepo) GetPrelazi(ctx context.Context) ([]Prelaz, error) {
 	collection := pr.cli.Database("granicna_policija_db").Collection("prelazi")
 
@@ -170,3 +563,860 @@ func (pr *GranicnaPolicijaRepo) GetPrelazByID(ctx context.Context, id primitive.
 
 	return &prelaz, nil
 }
+
+// UpdateCarinskaDeklaracija menja samo stavke, datum i razloge pregleda deklaracije. Zaplene se ne prepisuju,
+// a prelaz ostaje upucen na pregled dok ima zaplena, i kada su zaplene dodate posle citanja prelaza.
+func (pr *GranicnaPolicijaRepo) UpdateCarinskaDeklaracija(ctx context.Context, prelazId primitive.ObjectID, deklaracija *CarinskaDeklaracija) (*Prelaz, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("prelazi")
+
+	imaZaplena := bson.D{{Key: "$gt", Value: bson.A{
+		bson.D{{Key: "$size", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$carinskaDeklaracija.zaplene", bson.A{}}}}}},
+		0,
+	}}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.D{
+		{Key: "carinskaDeklaracija.datum", Value: deklaracija.Datum},
+		{Key: "carinskaDeklaracija.stavke", Value: bson.D{{Key: "$literal", Value: deklaracija.Stavke}}},
+		{Key: "carinskaDeklaracija.razloziPregleda", Value: bson.D{{Key: "$literal", Value: deklaracija.RazloziPregleda}}},
+		{Key: "zaPregled", Value: bson.D{{Key: "$or", Value: bson.A{len(deklaracija.RazloziPregleda) > 0, imaZaplena}}}},
+	}}}}
+
+	var prelaz Prelaz
+	err := collection.FindOneAndUpdate(ctx, bson.M{"_id": prelazId}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&prelaz)
+	if err != nil {
+		return nil, err
+	}
+	return &prelaz, nil
+}
+
+func (pr *GranicnaPolicijaRepo) DodajZaplenu(ctx context.Context, prelazId primitive.ObjectID, zaplena *Zaplena) error {
+	collection := pr.cli.Database("granicna_policija_db").Collection("prelazi")
+
+	update := bson.M{
+		"$push": bson.M{"carinskaDeklaracija.zaplene": zaplena},
+		"$set":  bson.M{"zaPregled": true},
+	}
+	rezultat, err := collection.UpdateOne(ctx, bson.M{"_id": prelazId}, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UkloniZaplenu ponistava zaplenu cija krivicna prijava nije mogla da se podnese
+func (pr *GranicnaPolicijaRepo) UkloniZaplenu(ctx context.Context, prelazId primitive.ObjectID, zaplenaId primitive.ObjectID) error {
+	collection := pr.cli.Database("granicna_policija_db").Collection("prelazi")
+
+	update := bson.M{"$pull": bson.M{"carinskaDeklaracija.zaplene": bson.M{"_id": zaplenaId}}}
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": prelazId}, update)
+	return err
+}
+
+func (pr *GranicnaPolicijaRepo) CreateGranicniPrelaz(ctx context.Context, granicniPrelaz *GranicniPrelaz) error {
+	collection := pr.cli.Database("granicna_policija_db").Collection("granicni_prelazi")
+	_, err := collection.InsertOne(ctx, granicniPrelaz)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *GranicnaPolicijaRepo) GetGranicniPrelazi(ctx context.Context) ([]GranicniPrelaz, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("granicni_prelazi")
+
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var granicniPrelazi []GranicniPrelaz
+	if err := cursor.All(ctx, &granicniPrelazi); err != nil {
+		return nil, err
+	}
+
+	return granicniPrelazi, nil
+}
+
+func (pr *GranicnaPolicijaRepo) GetGranicniPrelazByID(ctx context.Context, id primitive.ObjectID) (*GranicniPrelaz, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("granicni_prelazi")
+	var granicniPrelaz GranicniPrelaz
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&granicniPrelaz)
+	if err != nil {
+		return nil, err
+	}
+
+	return &granicniPrelaz, nil
+}
+
+func (pr *GranicnaPolicijaRepo) GetGranicniPrelazBySifra(ctx context.Context, sifra string) (*GranicniPrelaz, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("granicni_prelazi")
+	var granicniPrelaz GranicniPrelaz
+	err := collection.FindOne(ctx, bson.M{"sifra": sifra}).Decode(&granicniPrelaz)
+	if err != nil {
+		return nil, err
+	}
+
+	return &granicniPrelaz, nil
+}
+
+func (pr *GranicnaPolicijaRepo) CreateGrupniPrelaz(ctx context.Context, grupniPrelaz *GrupniPrelaz) error {
+	collection := pr.cli.Database("granicna_policija_db").Collection("grupni_prelazi")
+	_, err := collection.InsertOne(ctx, grupniPrelaz)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *GranicnaPolicijaRepo) UpdateGrupniPrelaz(ctx context.Context, grupniPrelaz *GrupniPrelaz) error {
+	collection := pr.cli.Database("granicna_policija_db").Collection("grupni_prelazi")
+	_, err := collection.ReplaceOne(ctx, bson.M{"_id": grupniPrelaz.ID}, grupniPrelaz)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *GranicnaPolicijaRepo) GetGrupniPrelazByID(ctx context.Context, id primitive.ObjectID) (*GrupniPrelaz, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("grupni_prelazi")
+
+	var grupniPrelaz GrupniPrelaz
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&grupniPrelaz)
+	if err != nil {
+		return nil, err
+	}
+	return &grupniPrelaz, nil
+}
+
+func (pr *GranicnaPolicijaRepo) GetPrelaziByGrupniPrelaz(ctx context.Context, grupniPrelazId primitive.ObjectID) ([]Prelaz, error) {
+	return pr.getPrelaziHronoloski(ctx, bson.M{"grupniPrelazId": grupniPrelazId})
+}
+
+func (pr *GranicnaPolicijaRepo) GetPrelaziByJMBG(ctx context.Context, jmbg string) ([]Prelaz, error) {
+	return pr.getPrelaziHronoloski(ctx, bson.M{"JMBGPutnika": jmbg})
+}
+
+func (pr *GranicnaPolicijaRepo) GetPrelaziByBrojPasosa(ctx context.Context, brojPasosa string) ([]Prelaz, error) {
+	return pr.getPrelaziHronoloski(ctx, bson.M{"brojPasosaPutnika": brojPasosa})
+}
+
+func (pr *GranicnaPolicijaRepo) getPrelaziHronoloski(ctx context.Context, filter interface{}) ([]Prelaz, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("prelazi")
+
+	opts := options.Find().SetSort(bson.D{{Key: "datum", Value: 1}})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var prelazi []Prelaz
+	if err := cursor.All(ctx, &prelazi); err != nil {
+		return nil, err
+	}
+
+	return prelazi, nil
+}
+
+// PronadjiSumnjivoLice trazi osobu po JMBG-u i broju pasosa putnika, nil znaci da osoba nije evidentirana
+func (pr *GranicnaPolicijaRepo) PronadjiSumnjivoLice(ctx context.Context, prelaz *Prelaz) (*SumnjivoLice, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("sumnjiva_lica")
+
+	uslovi := []bson.M{}
+	if prelaz.JMBGPutnika != "" {
+		uslovi = append(uslovi, bson.M{"jmbg": prelaz.JMBGPutnika})
+	}
+	if prelaz.BrojPasosaPutnika != "" {
+		uslovi = append(uslovi, bson.M{"brojeviPasosa": prelaz.BrojPasosaPutnika})
+	}
+	if len(uslovi) == 0 {
+		return nil, nil
+	}
+
+	var sumnjivoLice SumnjivoLice
+	err := collection.FindOne(ctx, bson.M{"$or": uslovi}).Decode(&sumnjivoLice)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sumnjivoLice, nil
+}
+
+// PronadjiMogucaSumnjivaLica poredi ime putnika bez JMBG-a sa poznatim varijantama imena oznacenih osoba
+// istog drzavljanstva. Pronadjene osobe su samo moguca poklapanja koja sluzbenik potvrdjuje.
+// Kandidati se biraju po indeksu, pa poredjenje obuhvata osobe koje sa putnikom dele bar jedan deo imena.
+func (pr *GranicnaPolicijaRepo) PronadjiMogucaSumnjivaLica(ctx context.Context, prelaz *Prelaz) ([]SumnjivoLice, error) {
+	kljucevi := KljuceviImena(NormalizujIme(prelaz.ImePutnika, prelaz.PrezimePutnika))
+	if prelaz.JMBGPutnika != "" || len(kljucevi) == 0 {
+		return nil, nil
+	}
+	collection := pr.cli.Database("granicna_policija_db").Collection("sumnjiva_lica")
+
+	filter := bson.M{"oznaceno": true, "kljuceviImena": bson.M{"$in": kljucevi}}
+	if prelaz.DrzavljanstvoPutnika != "" {
+		filter["drzavljanstvo"] = bson.M{"$in": bson.A{prelaz.DrzavljanstvoPutnika, nil}}
+	}
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var kandidati []SumnjivoLice
+	for cursor.Next(ctx) {
+		var kandidat SumnjivoLice
+		if err := cursor.Decode(&kandidat); err != nil {
+			return nil, err
+		}
+		if kandidat.MoguceOdgovaraPutniku(prelaz) {
+			kandidati = append(kandidati, kandidat)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return kandidati, nil
+}
+
+func (pr *GranicnaPolicijaRepo) GetPrelaziByIDs(ctx context.Context, ids []primitive.ObjectID) ([]Prelaz, error) {
+	if len(ids) == 0 {
+		return []Prelaz{}, nil
+	}
+	return pr.getPrelaziHronoloski(ctx, bson.M{"_id": bson.M{"$in": ids}})
+}
+
+// MigrirajSumnjivaLica prevodi stare zapise, u kojima je svako sumnjivo lice imalo kopiju jednog prelaza,
+// u zapise o osobama. Stari zapisi iste osobe se spajaju, a zadrzava se id prvog kako bi reference iz alarma ostale vazece.
+func (pr *GranicnaPolicijaRepo) MigrirajSumnjivaLica(ctx context.Context) error {
+	collection := pr.cli.Database("granicna_policija_db").Collection("sumnjiva_lica")
+
+	cursor, err := collection.Find(ctx, bson.M{"prelaz": bson.M{"$exists": true}})
+	if err != nil {
+		return err
+	}
+	var stari []struct {
+		ID     primitive.ObjectID `bson:"_id"`
+		Prelaz Prelaz             `bson:"prelaz"`
+		Opis   string             `bson:"opis"`
+	}
+	if err := cursor.All(ctx, &stari); err != nil {
+		return err
+	}
+
+	sada := time.Now()
+	for _, zapis := range stari {
+		prelaz := zapis.Prelaz
+		sumnjivoLice, err := pr.PronadjiSumnjivoLice(ctx, &prelaz)
+		if err != nil {
+			return err
+		}
+		spojen := sumnjivoLice != nil
+		if !spojen {
+			sumnjivoLice = NovoSumnjivoLice(&prelaz)
+			sumnjivoLice.ID = zapis.ID
+		}
+
+		tip := TipIncidenta(RUCNA_PRIJAVA)
+		if strings.HasPrefix(zapis.Opis, "Automatski kreirano") {
+			tip = PONOVLJENA_ODBIJANJA
+		}
+		sumnjivoLice.PovezPrelaz(&prelaz)
+		sumnjivoLice.DodajIncident(Incident{
+			Tip:      tip,
+			Datum:    prelaz.Datum,
+			Opis:     zapis.Opis,
+			PrelazId: prelaz.ID,
+		}, sada)
+
+		if err := pr.zameniSumnjivoLice(ctx, sumnjivoLice); err != nil {
+			return err
+		}
+		if spojen {
+			if _, err := collection.DeleteOne(ctx, bson.M{"_id": zapis.ID}); err != nil {
+				return err
+			}
+		}
+	}
+	if len(stari) > 0 {
+		pr.logger.Printf("Migrirano %d sumnjivih lica", len(stari))
+	}
+
+	return nil
+}
+
+// poljaDrzavljanstva su polja u kojima se cuva drzavljanstvo, po kolekcijama
+var poljaDrzavljanstva = map[string][]string{
+	"prelazi":          {"drzavljanstvoPutnika"},
+	"sumnjiva_lica":    {"drzavljanstvo"},
+	"krivicne_prijave": {"prelaz.drzavljanstvoPutnika", "osumnjiceni.drzavljanstvo"},
+}
+
+// MigrirajDrzavljanstva zamenjuje drzavljanstva uneta kao slobodan tekst sifrom drzave. Dnevna statistika je
+// grupisana po drzavljanstvu, pa se posle izmene prelaza brise i racuna iznova.
+func (pr *GranicnaPolicijaRepo) MigrirajDrzavljanstva(ctx context.Context) error {
+	db := pr.cli.Database("granicna_policija_db")
+	izmenjeniPrelazi := false
+	for kolekcija, polja := range poljaDrzavljanstva {
+		for _, polje := range polja {
+			vrednosti, err := db.Collection(kolekcija).Distinct(ctx, polje, bson.M{polje: bson.M{"$type": "string"}})
+			if err != nil {
+				return err
+			}
+			for _, vrednost := range vrednosti {
+				staro, _ := vrednost.(string)
+				novo := drzavljanstvo.Normalizuj(staro)
+				if novo == staro {
+					continue
+				}
+				izmena := bson.M{"$set": bson.M{polje: novo}}
+				if novo == "" {
+					izmena = bson.M{"$unset": bson.M{polje: ""}}
+				}
+				rezultat, err := db.Collection(kolekcija).UpdateMany(ctx, bson.M{polje: staro}, izmena)
+				if err != nil {
+					return err
+				}
+				if rezultat.ModifiedCount > 0 {
+					pr.logger.Printf("Drzavljanstvo \"%s\" zamenjeno sa \"%s\" u %d zapisa kolekcije %s", staro, novo, rezultat.ModifiedCount, kolekcija)
+					izmenjeniPrelazi = izmenjeniPrelazi || kolekcija == "prelazi"
+				}
+			}
+		}
+	}
+
+	if !izmenjeniPrelazi {
+		return nil
+	}
+	pr.logger.Println("Drzavljanstva prelaza su izmenjena, dnevna statistika se racuna iznova")
+	if err := db.Collection("dnevna_statistika").Drop(ctx); err != nil {
+		return err
+	}
+	return db.Collection("statistika_dani").Drop(ctx)
+}
+
+func (pr *GranicnaPolicijaRepo) CreateAlarm(ctx context.Context, alarm *Alarm) error {
+	collection := pr.cli.Database("granicna_policija_db").Collection("alarmi")
+	_, err := collection.InsertOne(ctx, alarm)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *GranicnaPolicijaRepo) GetAlarmiBySluzbenik(ctx context.Context, sluzbenikId primitive.ObjectID) ([]Alarm, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("alarmi")
+
+	opts := options.Find().SetSort(bson.D{{Key: "datum", Value: -1}})
+	cursor, err := collection.Find(ctx, bson.M{"idSluzbenika": sluzbenikId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var alarmi []Alarm
+	if err := cursor.All(ctx, &alarmi); err != nil {
+		return nil, err
+	}
+
+	return alarmi, nil
+}
+
+func (pr *GranicnaPolicijaRepo) OznaciAlarmProcitan(ctx context.Context, id primitive.ObjectID, sluzbenikId primitive.ObjectID) error {
+	collection := pr.cli.Database("granicna_policija_db").Collection("alarmi")
+
+	filter := bson.M{"_id": id, "idSluzbenika": sluzbenikId}
+	rezultat, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"procitan": true}})
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// KreirajIndekseEvidencijeOdbijanja kreira indekse po kojima se pretrazuje evidencija odbijanja i u evidenciju
+// upisuje odbijene prelaze koji u njoj nedostaju, odbijene pre uvodjenja evidencije ili kada upis nije uspeo
+func (pr *GranicnaPolicijaRepo) KreirajIndekseEvidencijeOdbijanja(ctx context.Context) error {
+	collection := pr.cli.Database("granicna_policija_db").Collection("evidencija_odbijanja")
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "datum", Value: -1}}},
+		{Keys: bson.D{{Key: "idSluzbenika", Value: 1}, {Key: "datum", Value: -1}}},
+		{Keys: bson.D{{Key: "granicniPrelazId", Value: 1}, {Key: "datum", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	odbijeni, err := pr.GetOdbijeniPrelazi(ctx, FilterOdbijenihPrelaza{})
+	if err != nil {
+		return err
+	}
+	dopunjeno := 0
+	for i := range odbijeni {
+		upisan, err := pr.upisiOdbijanje(ctx, NoviZapisOdbijanja(&odbijeni[i]))
+		if err != nil {
+			return err
+		}
+		if upisan {
+			dopunjeno++
+		}
+	}
+	if dopunjeno > 0 {
+		pr.logger.Printf("U evidenciju odbijanja upisano %d ranije odbijenih prelaza", dopunjeno)
+	}
+	return nil
+}
+
+// EvidentirajOdbijanje upisuje odbijanje u evidenciju, postojeci zapis za isti prelaz se ne menja
+func (pr *GranicnaPolicijaRepo) EvidentirajOdbijanje(ctx context.Context, zapis *ZapisOdbijanja) error {
+	_, err := pr.upisiOdbijanje(ctx, zapis)
+	return err
+}
+
+func (pr *GranicnaPolicijaRepo) upisiOdbijanje(ctx context.Context, zapis *ZapisOdbijanja) (bool, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("evidencija_odbijanja")
+	_, err := collection.InsertOne(ctx, zapis)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type FilterEvidencijeOdbijanja struct {
+	IdSluzbenika     primitive.ObjectID
+	GranicniPrelazId primitive.ObjectID
+	Razlog           RazlogOdbijanja
+	Od               time.Time
+	Do               time.Time
+}
+
+func (pr *GranicnaPolicijaRepo) GetEvidencijaOdbijanja(ctx context.Context, f FilterEvidencijeOdbijanja) ([]ZapisOdbijanja, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("evidencija_odbijanja")
+
+	filter := bson.M{}
+	if !f.IdSluzbenika.IsZero() {
+		filter["idSluzbenika"] = f.IdSluzbenika
+	}
+	if !f.GranicniPrelazId.IsZero() {
+		filter["granicniPrelazId"] = f.GranicniPrelazId
+	}
+	if f.Razlog != "" {
+		filter["razlog"] = f.Razlog
+	}
+	datum := bson.M{}
+	if !f.Od.IsZero() {
+		datum["$gte"] = primitive.NewDateTimeFromTime(f.Od)
+	}
+	if !f.Do.IsZero() {
+		datum["$lt"] = primitive.NewDateTimeFromTime(f.Do)
+	}
+	if len(datum) > 0 {
+		filter["datum"] = datum
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "datum", Value: -1}})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	zapisi := []ZapisOdbijanja{}
+	if err := cursor.All(ctx, &zapisi); err != nil {
+		return nil, err
+	}
+
+	return zapisi, nil
+}
+
+type FilterOdbijenihPrelaza struct {
+	JMBG       string
+	BrojPasosa string
+	Razlog     RazlogOdbijanja
+	Od         time.Time
+	Do         time.Time
+}
+
+func (pr *GranicnaPolicijaRepo) GetOdbijeniPrelazi(ctx context.Context, f FilterOdbijenihPrelaza) ([]Prelaz, error) {
+	collection := pr.cli.Database("granicna_policija_db").Collection("prelazi")
+
+	filter := bson.M{"odobren": false, "odbijanje": bson.M{"$exists": true}}
+	if f.JMBG != "" {
+		filter["JMBGPutnika"] = f.JMBG
+	}
+	if f.BrojPasosa != "" {
+		filter["brojPasosaPutnika"] = f.BrojPasosa
+	}
+	if f.Razlog != "" {
+		filter["odbijanje.razlog"] = f.Razlog
+	}
+	datum := bson.M{}
+	if !f.Od.IsZero() {
+		datum["$gte"] = primitive.NewDateTimeFromTime(f.Od)
+	}
+	if !f.Do.IsZero() {
+		datum["$lt"] = primitive.NewDateTimeFromTime(f.Do)
+	}
+	if len(datum) > 0 {
+		filter["datum"] = datum
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "datum", Value: -1}})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var prelazi []Prelaz
+	if err := cursor.All(ctx, &prelazi); err != nil {
+		return nil, err
+	}
+
+	return prelazi, nil
+}
+
+const formatDana = "2006-01-02"
+
+var kljuceviGrupisanja = map[GrupisanjeStatistike]interface{}{
+	PO_DANU:              "$dan",
+	PO_SATU:              "$sat",
+	PO_DRZAVLJANSTVU:     "$drzavljanstvoPutnika",
+	PO_SVRSI_PUTOVANJA:   "$svrhaPutovanja",
+	PO_GRANICNOM_PRELAZU: bson.M{"$toString": "$granicniPrelazId"},
+}
+
+func PodrzanoGrupisanje(grupisanje GrupisanjeStatistike) bool {
+	_, ok := kljuceviGrupisanja[grupisanje]
+	return ok
+}
+
+// pripremiPrelazeZaStatistiku svodi prelaze iz zadatog perioda na dimenzije dnevne statistike
+func pripremiPrelazeZaStatistiku(od time.Time, do time.Time) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"datum": bson.M{
+			"$gte": primitive.NewDateTimeFromTime(od),
+			"$lt":  primitive.NewDateTimeFromTime(do),
+		}}}},
+		{{Key: "$project", Value: bson.M{
+			"dan":                  bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$datum", "timezone": NazivLokalneZone}},
+			"sat":                  bson.M{"$dateToString": bson.M{"format": "%H", "date": "$datum", "timezone": NazivLokalneZone}},
+			"granicniPrelazId":     1,
+			"drzavljanstvoPutnika": bson.M{"$ifNull": bson.A{"$drzavljanstvoPutnika", ""}},
+			"svrhaPutovanja":       bson.M{"$ifNull": bson.A{"$svrhaPutovanja", ""}},
+			"smer":                 bson.M{"$ifNull": bson.A{"$smer", ""}},
+			"odbijen":              bson.M{"$ne": bson.A{bson.M{"$type": "$odbijanje"}, "missing"}},
+			"broj":                 bson.M{"$literal": 1},
+		}}},
+	}
+}
+
+func grupisiStatistiku(grupisanje GrupisanjeStatistike) bson.D {
+	return bson.D{{Key: "$group", Value: bson.M{
+		"_id":      kljuceviGrupisanja[grupisanje],
+		"ukupno":   bson.M{"$sum": "$broj"},
+		"ulazi":    bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$smer", ULAZ}}, "$broj", 0}}},
+		"izlazi":   bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$smer", IZLAZ}}, "$broj", 0}}},
+		"odbijeni": bson.M{"$sum": bson.M{"$cond": bson.A{"$odbijen", "$broj", 0}}},
+	}}}
+}
+
+// dimenzijeStatistike su polja koja zajedno odredjuju jednu stavku dnevne statistike, po njima postoji jedinstven indeks
+var dimenzijeStatistike = []string{"dan", "sat", "granicniPrelazId", "drzavljanstvoPutnika", "svrhaPutovanja", "smer", "odbijen"}
+
+// KreirajIndekseStatistike kreira jedinstven indeks po dimenzijama dnevne statistike. Statistika izracunata pre
+// indeksa moze imati duple stavke, a statistika izracunata pre prelaska na lokalno vreme pomerene dane. Kako se
+// uvek moze ponovo izracunati iz prelaza, tada se brise i racuna iznova.
+func (pr *GranicnaPolicijaRepo) KreirajIndekseStatistike(ctx context.Context) error {
+	db := pr.cli.Database("granicna_policija_db")
+	kljucevi := bson.D{}
+	for _, dimenzija := range dimenzijeStatistike {
+		kljucevi = append(kljucevi, bson.E{Key: dimenzija, Value: 1})
+	}
+	indeks := mongo.IndexModel{Keys: kljucevi, Options: options.Index().SetUnique(true)}
+
+	// dani izracunati po drugoj vremenskoj zoni imaju pomerene dane i sate, pa se statistika racuna iznova
+	uDrugojZoni, err := db.Collection("statistika_dani").CountDocuments(ctx, bson.M{"zona": bson.M{"$ne": NazivLokalneZone}})
+	if err != nil {
+		return err
+	}
+	if uDrugojZoni == 0 {
+		_, err = db.Collection("dnevna_statistika").Indexes().CreateOne(ctx, indeks)
+		if err == nil || !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+		pr.logger.Println("Dnevna statistika ima duple stavke, racuna se iznova")
+	} else {
+		pr.logger.Println("Dnevna statistika je racunata po drugoj vremenskoj zoni, racuna se iznova")
+	}
+	if err := db.Collection("dnevna_statistika").Drop(ctx); err != nil {
+		return err
+	}
+	if err := db.Collection("statistika_dani").Drop(ctx); err != nil {
+		return err
+	}
+	_, err = db.Collection("dnevna_statistika").Indexes().CreateOne(ctx, indeks)
+	return err
+}
+
+// IzracunajDnevnuStatistiku ponovo racuna zbirne podatke za dan kome pripada zadato vreme. Stavke se upisuju po
+// dimenzijama, pa istovremeno racunanje istog dana na vise instanci ne duplira brojeve, a stavke kojih vise
+// nema brise samo racunanje koje je poslednje pocelo.
+func (pr *GranicnaPolicijaRepo) IzracunajDnevnuStatistiku(ctx context.Context, dan time.Time) error {
+	db := pr.cli.Database("granicna_policija_db")
+	od := PocetakDana(dan)
+	oznakaDana := od.Format(formatDana)
+	izracunato := primitive.NewDateTimeFromTime(time.Now())
+
+	kljucGrupe := bson.D{}
+	for _, dimenzija := range dimenzijeStatistike {
+		kljucGrupe = append(kljucGrupe, bson.E{Key: dimenzija, Value: "$" + dimenzija})
+	}
+	pipeline := append(pripremiPrelazeZaStatistiku(od, od.AddDate(0, 0, 1)),
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: kljucGrupe},
+			{Key: "broj", Value: bson.M{"$sum": "$broj"}},
+		}}},
+	)
+	cursor, err := db.Collection("prelazi").Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var grupe []struct {
+		Kljuc DnevnaStatistika `bson:"_id"`
+		Broj  int              `bson:"broj"`
+	}
+	if err := cursor.All(ctx, &grupe); err != nil {
+		return err
+	}
+
+	statistika := db.Collection("dnevna_statistika")
+	if len(grupe) > 0 {
+		upisi := make([]mongo.WriteModel, 0, len(grupe))
+		for _, grupa := range grupe {
+			kljuc := grupa.Kljuc
+			var prelazId interface{}
+			if !kljuc.GranicniPrelazId.IsZero() {
+				prelazId = kljuc.GranicniPrelazId
+			}
+			filter := bson.D{
+				{Key: "dan", Value: kljuc.Dan},
+				{Key: "sat", Value: kljuc.Sat},
+				{Key: "granicniPrelazId", Value: prelazId},
+				{Key: "drzavljanstvoPutnika", Value: kljuc.DrzavljanstvoPutnika},
+				{Key: "svrhaPutovanja", Value: kljuc.SvrhaPutovanja},
+				{Key: "smer", Value: kljuc.Smer},
+				{Key: "odbijen", Value: kljuc.Odbijen},
+			}
+			update := bson.D{
+				{Key: "$set", Value: bson.D{{Key: "broj", Value: grupa.Broj}}},
+				{Key: "$max", Value: bson.D{{Key: "izracunato", Value: izracunato}}},
+			}
+			upisi = append(upisi, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+		}
+		opts := options.BulkWrite().SetOrdered(false)
+		_, err := statistika.BulkWrite(ctx, upisi, opts)
+		if mongo.IsDuplicateKeyError(err) {
+			// druga instanca je istovremeno upisala istu novu stavku, ponovljen upis je menja
+			_, err = statistika.BulkWrite(ctx, upisi, opts)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = statistika.DeleteMany(ctx, bson.D{
+		{Key: "dan", Value: oznakaDana},
+		{Key: "izracunato", Value: bson.D{{Key: "$lt", Value: izracunato}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("statistika_dani").UpdateOne(ctx,
+		bson.M{"_id": oznakaDana},
+		bson.M{"$set": bson.M{"izracunato": primitive.NewDateTimeFromTime(time.Now()), "zona": NazivLokalneZone}},
+		options.Update().SetUpsert(true))
+	return err
+}
+
+// izracunatiDani vraca dane iz perioda [od, do) za koje postoji dnevna statistika
+func (pr *GranicnaPolicijaRepo) izracunatiDani(ctx context.Context, od time.Time, do time.Time) (map[string]bool, error) {
+	cursor, err := pr.cli.Database("granicna_policija_db").Collection("statistika_dani").Find(ctx, bson.M{"_id": bson.M{
+		"$gte": od.Format(formatDana),
+		"$lt":  do.Format(formatDana),
+	}})
+	if err != nil {
+		return nil, err
+	}
+	var dani []struct {
+		Dan string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &dani); err != nil {
+		return nil, err
+	}
+	izracunati := make(map[string]bool, len(dani))
+	for _, dan := range dani {
+		izracunati[dan.Dan] = true
+	}
+	return izracunati, nil
+}
+
+// DopuniDnevnuStatistiku racuna zbirne podatke za zavrsene dane u periodu koji jos nisu obradjeni
+func (pr *GranicnaPolicijaRepo) DopuniDnevnuStatistiku(ctx context.Context, od time.Time, do time.Time) error {
+	danas := PocetakDana(time.Now())
+	if do.After(danas) {
+		do = danas
+	}
+	od = PocetakDana(od)
+	if !od.Before(do) {
+		return nil
+	}
+
+	izracunati, err := pr.izracunatiDani(ctx, od, do)
+	if err != nil {
+		return err
+	}
+	for dan := od; dan.Before(do); dan = dan.AddDate(0, 0, 1) {
+		if izracunati[dan.Format(formatDana)] {
+			continue
+		}
+		if err := pr.IzracunajDnevnuStatistiku(ctx, dan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetStatistikaPrelaza vraca broj prelaza u periodu [od, do) grupisan po zadatoj dimenziji.
+// Izracunati zavrseni dani se citaju iz dnevne statistike, a tekuci dan i dani koje pozadinsko racunanje
+// jos nije obradilo direktno iz prelaza, kako zahtev ne bi cekao racunanje statistike.
+func (pr *GranicnaPolicijaRepo) GetStatistikaPrelaza(ctx context.Context, od time.Time, do time.Time, grupisanje GrupisanjeStatistike) ([]StavkaStatistike, error) {
+	db := pr.cli.Database("granicna_policija_db")
+	danas := PocetakDana(time.Now())
+	ukupno := make(map[string]*StavkaStatistike)
+
+	saberi := func(cursor *mongo.Cursor) error {
+		var stavke []StavkaStatistike
+		if err := cursor.All(ctx, &stavke); err != nil {
+			return err
+		}
+		for _, stavka := range stavke {
+			postojeca, ok := ukupno[stavka.Kljuc]
+			if !ok {
+				stavka := stavka
+				ukupno[stavka.Kljuc] = &stavka
+				continue
+			}
+			postojeca.Ukupno += stavka.Ukupno
+			postojeca.Ulazi += stavka.Ulazi
+			postojeca.Izlazi += stavka.Izlazi
+			postojeca.Odbijeni += stavka.Odbijeni
+		}
+		return nil
+	}
+
+	if od.Before(danas) {
+		krajZbirnih := do
+		if krajZbirnih.After(danas) {
+			krajZbirnih = danas
+		}
+		izracunati, err := pr.izracunatiDani(ctx, PocetakDana(od), krajZbirnih)
+		if err != nil {
+			return nil, err
+		}
+		var sracunati, neobradjeni bson.A
+		for dan := PocetakDana(od); dan.Before(krajZbirnih); dan = dan.AddDate(0, 0, 1) {
+			if izracunati[dan.Format(formatDana)] {
+				sracunati = append(sracunati, dan.Format(formatDana))
+			} else {
+				neobradjeni = append(neobradjeni, dan.Format(formatDana))
+			}
+		}
+
+		if len(sracunati) > 0 {
+			pipeline := mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"dan": bson.M{"$in": sracunati}}}},
+				grupisiStatistiku(grupisanje),
+			}
+			cursor, err := db.Collection("dnevna_statistika").Aggregate(ctx, pipeline)
+			if err != nil {
+				return nil, err
+			}
+			if err := saberi(cursor); err != nil {
+				return nil, err
+			}
+		}
+		if len(neobradjeni) > 0 {
+			pipeline := append(pripremiPrelazeZaStatistiku(PocetakDana(od), krajZbirnih),
+				bson.D{{Key: "$match", Value: bson.M{"dan": bson.M{"$in": neobradjeni}}}},
+				grupisiStatistiku(grupisanje),
+			)
+			cursor, err := db.Collection("prelazi").Aggregate(ctx, pipeline)
+			if err != nil {
+				return nil, err
+			}
+			if err := saberi(cursor); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if do.After(danas) {
+		pocetak := od
+		if pocetak.Before(danas) {
+			pocetak = danas
+		}
+		pipeline := append(pripremiPrelazeZaStatistiku(pocetak, do), grupisiStatistiku(grupisanje))
+		cursor, err := db.Collection("prelazi").Aggregate(ctx, pipeline)
+		if err != nil {
+			return nil, err
+		}
+		if err := saberi(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	rezultat := make([]StavkaStatistike, 0, len(ukupno))
+	for _, stavka := range ukupno {
+		rezultat = append(rezultat, *stavka)
+	}
+	sort.Slice(rezultat, func(i, j int) bool {
+		return rezultat[i].Kljuc < rezultat[j].Kljuc
+	})
+
+	return rezultat, nil
+}
+
+// PokreniDnevnuStatistiku periodicno obradjuje zavrsene dane, pocevsi od prvog evidentiranog prelaza
+func (pr *GranicnaPolicijaRepo) PokreniDnevnuStatistiku(interval time.Duration) {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+
+		var prvi Prelaz
+		opts := options.FindOne().SetSort(bson.D{{Key: "datum", Value: 1}})
+		err := pr.cli.Database("granicna_policija_db").Collection("prelazi").FindOne(ctx, bson.M{}, opts).Decode(&prvi)
+		if err == nil {
+			err = pr.DopuniDnevnuStatistiku(ctx, prvi.Datum.Time(), time.Now())
+		}
+		if err != nil && err != mongo.ErrNoDocuments {
+			pr.logger.Println("Greska prilikom racunanja dnevne statistike:", err)
+		}
+		cancel()
+
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,29 @@
+package data
+
+import "prijave"
+
+// IzvorPrijave oznacava servis u kome je prijava podneta
+const IzvorPrijave = "GRANICNA_POLICIJA"
+
+// Statusi krivicne prijave i dozvoljeni prelazi su zajednicki za sve servise
+type StatusPrijave = prijave.StatusPrijave
+
+const (
+	PODNETA                = prijave.PODNETA
+	PRIMLJENA_U_TUZILASTVU = prijave.PRIMLJENA_U_TUZILASTVU
+	ODBACENA               = prijave.ODBACENA
+	U_POSTUPKU             = prijave.U_POSTUPKU
+	ZAVRSENA               = prijave.ZAVRSENA
+)
+
+// TrenutniStatus vraca status prijave, prijave kreirane pre uvodjenja statusa se smatraju podnetim
+func (k *KrivicnaPrijava) TrenutniStatus() StatusPrijave {
+	if k.Status == "" {
+		return PODNETA
+	}
+	return k.Status
+}
+
+func DozvoljenaPromenaStatusa(iz StatusPrijave, u StatusPrijave) bool {
+	return prijave.DozvoljenaPromenaStatusa(iz, u)
+}
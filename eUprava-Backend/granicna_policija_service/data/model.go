@@ -32,6 +32,72 @@ const (
 	Tuzioc            = "Tuzioc"
 	Istrazitelj       = "Istrazitelj"
 	Sudija            = "Sudija"
+	PredsednikSuda    = "PredsednikSuda"
+	GlavniTuzilac     = "GlavniTuzilac"
+)
+
+type Smer string
+
+const (
+	ULAZ  = "ULAZ"
+	IZLAZ = "IZLAZ"
+)
+
+type TipPogotka string
+
+const (
+	NALOG_ZA_PRACENJE = "NALOG_ZA_PRACENJE"
+	SUMNJIVO_LICE     = "SUMNJIVO_LICE"
+	// Putnik bez JMBG-a cije ime lici na oznacenu osobu, sluzbenik potvrdjuje da li je ista osoba
+	MOGUCE_SUMNJIVO_LICE = "MOGUCE_SUMNJIVO_LICE"
+	// Nalozi za pracenje nisu mogli da se provere, pa putnika mora rucno da proveri sluzbenik
+	LISTA_NEDOSTUPNA = "LISTA_NEDOSTUPNA"
+)
+
+type AkcijaPogotka string
+
+const (
+	DOZVOLI_I_OZNACI = "DOZVOLI_I_OZNACI"
+	ZADRZI           = "ZADRZI"
+	ODBIJ            = "ODBIJ"
+)
+
+type RazlogOdbijanja string
+
+const (
+	DOKUMENTI_NISU_VALIDNI = "DOKUMENTI_NISU_VALIDNI"
+	PREKORACEN_BORAVAK     = "PREKORACEN_BORAVAK"
+	LISTA_ZA_PRACENJE      = "LISTA_ZA_PRACENJE"
+)
+
+type TipIncidenta string
+
+const (
+	RUCNA_PRIJAVA        = "RUCNA_PRIJAVA"
+	PONOVLJENA_ODBIJANJA = "PONOVLJENA_ODBIJANJA"
+	ZAPLENA              = "ZAPLENA"
+)
+
+type StatusPutnika string
+
+const (
+	ODOBREN = "ODOBREN"
+	ZADRZAN = "ZADRZAN"
+	ODBIJEN = "ODBIJEN"
+	GRESKA  = "GRESKA"
+)
+
+type GrupisanjeStatistike string
+
+// Najduzi period za koji se statistika prelaza racuna u jednom zahtevu
+const MaxPeriodStatistikeDana = 366
+
+const (
+	PO_DANU              = "dan"
+	PO_SATU              = "sat"
+	PO_DRZAVLJANSTVU     = "drzavljanstvo"
+	PO_SVRSI_PUTOVANJA   = "svrhaPutovanja"
+	PO_GRANICNOM_PRELAZU = "granicniPrelaz"
 )
 
 type Korisnik struct {
@@ -63,32 +129,274 @@ type Pasos struct {
 }
 
 type Prelaz struct {
-	ID                    primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Datum                 primitive.DateTime `bson:"datum,omitempty" json:"datum"`
-	ImePutnika            string             `bson:"imePutnika,omitempty" json:"imePutnika"`
-	PrezimePutnika        string             `bson:"prezimePutnika,omitempty" json:"prezimePutnika"`
-	JMBGPutnika           string             `bson:"JMBGPutnika,omitempty" json:"JMBGPutnika"`
-	BrojLicneKartePutnika string             `bson:"brojLicneKartePutnika,omitempty" json:"brojLicneKartePutnika,omitempty"`
-	BrojPasosaPutnika     string             `bson:"brojPasosaPutnika,omitempty" json:"brojPasosaPutnika,omitempty"`
-	DrzavljanstvoPutnika  string             `bson:"drzavljanstvoPutnika,omitempty" json:"drzavljanstvoPutnika"`
-	MarkaVozila           string             `bson:"markaVozila,omitempty" json:"markaVozila"`
-	ModelVozila           string             `bson:"modelVozila,omitempty" json:"modelVozila"`
-	SvrhaPutovanja        string             `bson:"svrhaPutovanja,omitempty" json:"svrhaPutovanja"`
-	Odobren               bool               `bson:"odobren,omitempty" json:"odobren"`
+	ID                    primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Datum                 primitive.DateTime   `bson:"datum,omitempty" json:"datum"`
+	ImePutnika            string               `bson:"imePutnika,omitempty" json:"imePutnika"`
+	PrezimePutnika        string               `bson:"prezimePutnika,omitempty" json:"prezimePutnika"`
+	JMBGPutnika           string               `bson:"JMBGPutnika,omitempty" json:"JMBGPutnika"`
+	BrojLicneKartePutnika string               `bson:"brojLicneKartePutnika,omitempty" json:"brojLicneKartePutnika,omitempty"`
+	BrojPasosaPutnika     string               `bson:"brojPasosaPutnika,omitempty" json:"brojPasosaPutnika,omitempty"`
+	DrzavljanstvoPutnika  string               `bson:"drzavljanstvoPutnika,omitempty" json:"drzavljanstvoPutnika"`
+	MarkaVozila           string               `bson:"markaVozila,omitempty" json:"markaVozila"`
+	ModelVozila           string               `bson:"modelVozila,omitempty" json:"modelVozila"`
+	SvrhaPutovanja        string               `bson:"svrhaPutovanja,omitempty" json:"svrhaPutovanja"`
+	Odobren               bool                 `bson:"odobren" json:"odobren"`
+	Odbijanje             *Odbijanje           `bson:"odbijanje,omitempty" json:"odbijanje,omitempty"`
+	Smer                  Smer                 `bson:"smer,omitempty" json:"smer"`
+	GranicniPrelazId      primitive.ObjectID   `bson:"granicniPrelazId,omitempty" json:"granicniPrelazId"`
+	Traka                 string               `bson:"traka,omitempty" json:"traka,omitempty"`
+	IdSluzbenika          primitive.ObjectID   `bson:"idSluzbenika,omitempty" json:"idSluzbenika"`
+	Upozorenja            []string             `bson:"upozorenja,omitempty" json:"upozorenja,omitempty"`
+	Pogoci                []PogodakListe       `bson:"pogoci,omitempty" json:"pogoci,omitempty"`
+	Zadrzan               bool                 `bson:"zadrzan,omitempty" json:"zadrzan"`
+	GrupniPrelazId        primitive.ObjectID   `bson:"grupniPrelazId,omitempty" json:"grupniPrelazId,omitempty"`
+	CarinskaDeklaracija   *CarinskaDeklaracija `bson:"carinskaDeklaracija,omitempty" json:"carinskaDeklaracija,omitempty"`
+	ZaPregled             bool                 `bson:"zaPregled,omitempty" json:"zaPregled"`
+	FotografijaPutnika    string               `bson:"-" json:"fotografijaPutnika,omitempty"`
+}
+
+type CarinskaDeklaracija struct {
+	Datum           primitive.DateTime  `bson:"datum,omitempty" json:"datum"`
+	Stavke          []StavkaDeklaracije `bson:"stavke,omitempty" json:"stavke"`
+	RazloziPregleda []string            `bson:"razloziPregleda,omitempty" json:"razloziPregleda,omitempty"`
+	Zaplene         []Zaplena           `bson:"zaplene,omitempty" json:"zaplene,omitempty"`
+}
+
+type StavkaDeklaracije struct {
+	Naziv        string         `bson:"naziv,omitempty" json:"naziv"`
+	Kategorija   KategorijaRobe `bson:"kategorija,omitempty" json:"kategorija"`
+	Kolicina     float64        `bson:"kolicina,omitempty" json:"kolicina"`
+	JedinicaMere string         `bson:"jedinicaMere,omitempty" json:"jedinicaMere"`
+	Vrednost     float64        `bson:"vrednost,omitempty" json:"vrednost"`
+	Valuta       string         `bson:"valuta,omitempty" json:"valuta"`
+}
+
+type Zaplena struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Datum             primitive.DateTime `bson:"datum,omitempty" json:"datum"`
+	IdSluzbenika      primitive.ObjectID `bson:"idSluzbenika,omitempty" json:"idSluzbenika"`
+	Opis              string             `bson:"opis,omitempty" json:"opis"`
+	Kategorija        KategorijaRobe     `bson:"kategorija,omitempty" json:"kategorija"`
+	Kolicina          float64            `bson:"kolicina,omitempty" json:"kolicina"`
+	JedinicaMere      string             `bson:"jedinicaMere,omitempty" json:"jedinicaMere"`
+	Vrednost          float64            `bson:"vrednost,omitempty" json:"vrednost"`
+	Valuta            string             `bson:"valuta,omitempty" json:"valuta"`
+	KrivicnaPrijavaId primitive.ObjectID `bson:"krivicnaPrijavaId,omitempty" json:"krivicnaPrijavaId,omitempty"`
+}
+
+// ZahtevZaZaplenu opciono sadrzi krivicnu prijavu koja se podnosi zajedno sa zaplenom
+type ZahtevZaZaplenu struct {
+	Zaplena         Zaplena          `json:"zaplena"`
+	KrivicnaPrijava *KrivicnaPrijava `json:"krivicnaPrijava,omitempty"`
+}
+
+// GrupniPrelaz je prelaz vozila (npr. autobusa) sa spiskom putnika, svaki putnik dobija svoj Prelaz
+type GrupniPrelaz struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Datum             primitive.DateTime `bson:"datum,omitempty" json:"datum"`
+	MarkaVozila       string             `bson:"markaVozila,omitempty" json:"markaVozila"`
+	ModelVozila       string             `bson:"modelVozila,omitempty" json:"modelVozila"`
+	RegistarskaOznaka string             `bson:"registarskaOznaka,omitempty" json:"registarskaOznaka"`
+	Prevoznik         string             `bson:"prevoznik,omitempty" json:"prevoznik"`
+	Smer              Smer               `bson:"smer,omitempty" json:"smer"`
+	GranicniPrelazId  primitive.ObjectID `bson:"granicniPrelazId,omitempty" json:"granicniPrelazId"`
+	Traka             string             `bson:"traka,omitempty" json:"traka,omitempty"`
+	IdSluzbenika      primitive.ObjectID `bson:"idSluzbenika,omitempty" json:"idSluzbenika"`
+	BrojPutnika       int                `bson:"brojPutnika" json:"brojPutnika"`
+	BrojOdobrenih     int                `bson:"brojOdobrenih" json:"brojOdobrenih"`
+	BrojZadrzanih     int                `bson:"brojZadrzanih" json:"brojZadrzanih"`
+	BrojOdbijenih     int                `bson:"brojOdbijenih" json:"brojOdbijenih"`
+	BrojGresaka       int                `bson:"brojGresaka" json:"brojGresaka"`
+	Putnici           []Prelaz           `bson:"-" json:"putnici,omitempty"`
+	Rezultati         []RezultatPutnika  `bson:"-" json:"rezultati,omitempty"`
+}
+
+type RezultatPutnika struct {
+	RedniBroj int           `json:"redniBroj"`
+	Status    StatusPutnika `json:"status"`
+	Poruka    string        `json:"poruka,omitempty"`
+	Prelaz    *Prelaz       `json:"prelaz,omitempty"`
 }
 
+type Odbijanje struct {
+	Razlog             RazlogOdbijanja `bson:"razlog,omitempty" json:"razlog"`
+	Opis               string          `bson:"opis,omitempty" json:"opis"`
+	IzvestajValidacije string          `bson:"izvestajValidacije,omitempty" json:"izvestajValidacije,omitempty"`
+}
+
+// ZapisOdbijanja je zapis u evidenciji odbijanja: ko je, kada i na kom granicnom prelazu odbio putnika i zbog cega.
+// Zapis ima id odbijenog prelaza, pa za jedan prelaz postoji najvise jedan zapis.
+type ZapisOdbijanja struct {
+	PrelazId          primitive.ObjectID `bson:"_id" json:"prelazId"`
+	Datum             primitive.DateTime `bson:"datum" json:"datum"`
+	IdSluzbenika      primitive.ObjectID `bson:"idSluzbenika,omitempty" json:"idSluzbenika"`
+	GranicniPrelazId  primitive.ObjectID `bson:"granicniPrelazId,omitempty" json:"granicniPrelazId"`
+	Smer              Smer               `bson:"smer,omitempty" json:"smer"`
+	ImePutnika        string             `bson:"imePutnika,omitempty" json:"imePutnika"`
+	PrezimePutnika    string             `bson:"prezimePutnika,omitempty" json:"prezimePutnika"`
+	JMBGPutnika       string             `bson:"JMBGPutnika,omitempty" json:"JMBGPutnika,omitempty"`
+	BrojPasosaPutnika string             `bson:"brojPasosaPutnika,omitempty" json:"brojPasosaPutnika,omitempty"`
+	Razlog            RazlogOdbijanja    `bson:"razlog" json:"razlog"`
+	Opis              string             `bson:"opis,omitempty" json:"opis"`
+}
+
+func NoviZapisOdbijanja(prelaz *Prelaz) *ZapisOdbijanja {
+	zapis := &ZapisOdbijanja{
+		PrelazId:          prelaz.ID,
+		Datum:             prelaz.Datum,
+		IdSluzbenika:      prelaz.IdSluzbenika,
+		GranicniPrelazId:  prelaz.GranicniPrelazId,
+		Smer:              prelaz.Smer,
+		ImePutnika:        prelaz.ImePutnika,
+		PrezimePutnika:    prelaz.PrezimePutnika,
+		JMBGPutnika:       prelaz.JMBGPutnika,
+		BrojPasosaPutnika: prelaz.BrojPasosaPutnika,
+	}
+	if prelaz.Odbijanje != nil {
+		zapis.Razlog = prelaz.Odbijanje.Razlog
+		zapis.Opis = prelaz.Odbijanje.Opis
+	}
+	return zapis
+}
+
+type GranicniPrelaz struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Sifra         string             `bson:"sifra,omitempty" json:"sifra"`
+	Naziv         string             `bson:"naziv,omitempty" json:"naziv"`
+	SusednaDrzava string             `bson:"susednaDrzava,omitempty" json:"susednaDrzava"`
+}
+
+// DnevnaStatistika je zbirni broj prelaza za jedan dan i jednu kombinaciju dimenzija
+type DnevnaStatistika struct {
+	Dan                  string             `bson:"dan" json:"dan"`
+	Sat                  string             `bson:"sat" json:"sat"`
+	GranicniPrelazId     primitive.ObjectID `bson:"granicniPrelazId,omitempty" json:"granicniPrelazId"`
+	DrzavljanstvoPutnika string             `bson:"drzavljanstvoPutnika" json:"drzavljanstvoPutnika"`
+	SvrhaPutovanja       string             `bson:"svrhaPutovanja" json:"svrhaPutovanja"`
+	Smer                 Smer               `bson:"smer" json:"smer"`
+	Odbijen              bool               `bson:"odbijen" json:"odbijen"`
+	Broj                 int                `bson:"broj" json:"broj"`
+}
+
+type StavkaStatistike struct {
+	Kljuc    string `bson:"_id" json:"kljuc"`
+	Naziv    string `bson:"-" json:"naziv,omitempty"`
+	Ukupno   int    `bson:"ukupno" json:"ukupno"`
+	Ulazi    int    `bson:"ulazi" json:"ulazi"`
+	Izlazi   int    `bson:"izlazi" json:"izlazi"`
+	Odbijeni int    `bson:"odbijeni" json:"odbijeni"`
+}
+
+// SumnjivoLice je zapis o osobi koji objedinjuje sve incidente i prelaze iste osobe,
+// osoba se prepoznaje po JMBG-u, broju pasosa ili, ako oni nedostaju, po slicnosti imena
 type SumnjivoLice struct {
-	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Prelaz Prelaz             `bson:"prelaz,omitempty" json:"prelaz"`
-	Opis   string             `bson:"opis,omitempty" json:"opis"`
+	ID             primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	JMBG           string               `bson:"jmbg,omitempty" json:"jmbg,omitempty"`
+	BrojeviPasosa  []string             `bson:"brojeviPasosa,omitempty" json:"brojeviPasosa,omitempty"`
+	Ime            string               `bson:"ime,omitempty" json:"ime"`
+	Prezime        string               `bson:"prezime,omitempty" json:"prezime"`
+	Drzavljanstvo  string               `bson:"drzavljanstvo,omitempty" json:"drzavljanstvo"`
+	VarijanteImena []string             `bson:"varijanteImena,omitempty" json:"varijanteImena,omitempty"`
+	KljuceviImena  []string             `bson:"kljuceviImena,omitempty" json:"-"`
+	Opis           string               `bson:"opis,omitempty" json:"opis"`
+	Oznaceno       bool                 `bson:"oznaceno" json:"oznaceno"`
+	Rizik          int                  `bson:"rizik" json:"rizik"`
+	Incidenti      []Incident           `bson:"incidenti,omitempty" json:"incidenti"`
+	PrelaziIds     []primitive.ObjectID `bson:"prelaziIds,omitempty" json:"prelaziIds"`
+	IstorijaOznaka []PromenaOznake      `bson:"istorijaOznaka,omitempty" json:"istorijaOznaka"`
+	Azurirano      primitive.DateTime   `bson:"azurirano,omitempty" json:"azurirano"`
+	Prelazi        []Prelaz             `bson:"-" json:"prelazi,omitempty"`
+}
+
+type Incident struct {
+	Tip          TipIncidenta       `bson:"tip,omitempty" json:"tip"`
+	Datum        primitive.DateTime `bson:"datum,omitempty" json:"datum"`
+	Opis         string             `bson:"opis,omitempty" json:"opis"`
+	PrelazId     primitive.ObjectID `bson:"prelazId,omitempty" json:"prelazId"`
+	IdSluzbenika primitive.ObjectID `bson:"idSluzbenika,omitempty" json:"idSluzbenika,omitempty"`
+}
+
+type PromenaOznake struct {
+	Oznaceno     bool               `bson:"oznaceno" json:"oznaceno"`
+	Datum        primitive.DateTime `bson:"datum,omitempty" json:"datum"`
+	Razlog       string             `bson:"razlog,omitempty" json:"razlog"`
+	IdSluzbenika primitive.ObjectID `bson:"idSluzbenika,omitempty" json:"idSluzbenika,omitempty"`
 }
 
 type KrivicnaPrijava struct {
-	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Datum  primitive.DateTime `bson:"datum,omitempty" json:"datum"`
-	Opis   string             `bson:"opis,omitempty" json:"opis"`
-	Prelaz Prelaz             `bson:"prelaz,omitempty" json:"prelaz"`
+	ID                  primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Datum               primitive.DateTime  `bson:"datum,omitempty" json:"datum"`
+	Opis                string              `bson:"opis,omitempty" json:"opis"`
+	Izvor               string              `bson:"izvor,omitempty" json:"izvor"`
+	Prelaz              Prelaz              `bson:"prelaz,omitempty" json:"prelaz"`
+	Status              StatusPrijave       `bson:"status,omitempty" json:"status"`
+	IdSluzbenika        primitive.ObjectID  `bson:"idSluzbenika,omitempty" json:"idSluzbenika"`
+	PravnaKvalifikacija PravnaKvalifikacija `bson:"pravnaKvalifikacija,omitempty" json:"pravnaKvalifikacija"`
+	Osumnjiceni         Osumnjiceni         `bson:"osumnjiceni,omitempty" json:"osumnjiceni"`
+	Svedoci             []Svedok            `bson:"svedoci,omitempty" json:"svedoci"`
+	IstorijaStatusa     []PromenaStatusa    `bson:"istorijaStatusa,omitempty" json:"istorijaStatusa"`
+}
+
+// PravnaKvalifikacija oznacava krivicno delo po clanu zakona, npr. Krivicni zakonik, clan 230
+type PravnaKvalifikacija struct {
+	Zakon string `bson:"zakon,omitempty" json:"zakon"`
+	Clan  string `bson:"clan,omitempty" json:"clan"`
+	Stav  string `bson:"stav,omitempty" json:"stav,omitempty"`
+	Naziv string `bson:"naziv,omitempty" json:"naziv"`
+}
+
+type Osumnjiceni struct {
+	Ime           string `bson:"ime,omitempty" json:"ime"`
+	Prezime       string `bson:"prezime,omitempty" json:"prezime"`
+	JMBG          string `bson:"jmbg,omitempty" json:"jmbg,omitempty"`
+	BrojPasosa    string `bson:"brojPasosa,omitempty" json:"brojPasosa,omitempty"`
+	Drzavljanstvo string `bson:"drzavljanstvo,omitempty" json:"drzavljanstvo"`
+	Adresa        string `bson:"adresa,omitempty" json:"adresa,omitempty"`
 }
+
+type Svedok struct {
+	Ime     string `bson:"ime,omitempty" json:"ime"`
+	Prezime string `bson:"prezime,omitempty" json:"prezime"`
+	JMBG    string `bson:"jmbg,omitempty" json:"jmbg,omitempty"`
+	Kontakt string `bson:"kontakt,omitempty" json:"kontakt,omitempty"`
+	Izjava  string `bson:"izjava,omitempty" json:"izjava,omitempty"`
+}
+
+type PromenaStatusa struct {
+	Status   StatusPrijave      `bson:"status" json:"status"`
+	Datum    primitive.DateTime `bson:"datum" json:"datum"`
+	Napomena string             `bson:"napomena,omitempty" json:"napomena,omitempty"`
+}
+type StranicaKrivicnihPrijava struct {
+	Prijave    []KrivicnaPrijava `json:"prijave"`
+	Ukupno     int64             `json:"ukupno"`
+	Stranica   int64             `json:"stranica"`
+	Velicina   int64             `json:"velicina"`
+	BrojStrana int64             `json:"brojStrana"`
+}
+
+type NalogZaPracenje struct {
+	ID    primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Opis  string             `bson:"opis,omitempty" json:"opis"`
+	Datum primitive.DateTime `bson:"datum,omitempty" json:"datum"`
+}
+
+type PogodakListe struct {
+	Tip         TipPogotka         `bson:"tip,omitempty" json:"tip"`
+	ReferencaId primitive.ObjectID `bson:"referencaId,omitempty" json:"referencaId"`
+	Opis        string             `bson:"opis,omitempty" json:"opis"`
+	Akcija      AkcijaPogotka      `bson:"akcija,omitempty" json:"akcija"`
+}
+
+type Alarm struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	IdSluzbenika primitive.ObjectID `bson:"idSluzbenika,omitempty" json:"idSluzbenika"`
+	PrelazId     primitive.ObjectID `bson:"prelazId,omitempty" json:"prelazId"`
+	Datum        primitive.DateTime `bson:"datum,omitempty" json:"datum"`
+	Pogodak      PogodakListe       `bson:"pogodak,omitempty" json:"pogodak"`
+	Procitan     bool               `bson:"procitan" json:"procitan"`
+}
+
 type PodaciZaValidaciju struct {
 	JMBG           string `bson:"jmbg,omitempty" json:"jmbg,omitempty"`
 	Ime            string `bson:"ime,omitempty" json:"ime"`
@@ -171,3 +479,13 @@ func (o *PodaciZaValidaciju) FromJSON(r io.Reader) error {
 	d := json.NewDecoder(r)
 	return d.Decode(o)
 }
+
+func (o *GranicniPrelaz) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
+
+func (o *GranicniPrelaz) FromJSON(r io.Reader) error {
+	d := json.NewDecoder(r)
+	return d.Decode(o)
+}
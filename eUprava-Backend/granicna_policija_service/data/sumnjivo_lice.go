@@ -0,0 +1,269 @@
+package data
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	MaxRizik = 100
+	// Incidenti stariji od godinu dana ulaze u procenu rizika sa upola manjom tezinom
+	PeriodPunogRizikaDana = 365
+	RizikPoPrelazu        = 2
+	MaxRizikPrelaza       = 20
+)
+
+var tezinaIncidenta = map[TipIncidenta]int{
+	RUCNA_PRIJAVA:        30,
+	PONOVLJENA_ODBIJANJA: 40,
+	ZAPLENA:              35,
+}
+
+var zamenaLatinice = strings.NewReplacer("š", "s", "đ", "dj", "č", "c", "ć", "c", "ž", "z")
+
+// NovoSumnjivoLice pravi zapis o osobi na osnovu podataka putnika sa prelaza
+func NovoSumnjivoLice(prelaz *Prelaz) *SumnjivoLice {
+	return &SumnjivoLice{
+		ID:            primitive.NewObjectID(),
+		JMBG:          prelaz.JMBGPutnika,
+		Ime:           prelaz.ImePutnika,
+		Prezime:       prelaz.PrezimePutnika,
+		Drzavljanstvo: prelaz.DrzavljanstvoPutnika,
+	}
+}
+
+// PovezPrelaz dodaje prelaz osobi i pamti nove identifikatore i varijante imena sa tog prelaza
+func (s *SumnjivoLice) PovezPrelaz(prelaz *Prelaz) {
+	if s.JMBG == "" {
+		s.JMBG = prelaz.JMBGPutnika
+	}
+	if s.Drzavljanstvo == "" {
+		s.Drzavljanstvo = prelaz.DrzavljanstvoPutnika
+	}
+	if prelaz.BrojPasosaPutnika != "" && !sadrzi(s.BrojeviPasosa, prelaz.BrojPasosaPutnika) {
+		s.BrojeviPasosa = append(s.BrojeviPasosa, prelaz.BrojPasosaPutnika)
+	}
+	if varijanta := NormalizujIme(prelaz.ImePutnika, prelaz.PrezimePutnika); varijanta != "" && !sadrzi(s.VarijanteImena, varijanta) {
+		s.VarijanteImena = append(s.VarijanteImena, varijanta)
+	}
+	s.dodajKljuceveImena()
+	if prelaz.ID.IsZero() {
+		return
+	}
+	for _, id := range s.PrelaziIds {
+		if id == prelaz.ID {
+			return
+		}
+	}
+	s.PrelaziIds = append(s.PrelaziIds, prelaz.ID)
+}
+
+// KljuceviImena su delovi normalizovanog imena po kojima se u bazi traze kandidati za poredjenje imena
+func KljuceviImena(normalizovanoIme string) []string {
+	return strings.Fields(normalizovanoIme)
+}
+
+func (s *SumnjivoLice) dodajKljuceveImena() {
+	varijante := append([]string{NormalizujIme(s.Ime, s.Prezime)}, s.VarijanteImena...)
+	for _, varijanta := range varijante {
+		for _, kljuc := range KljuceviImena(varijanta) {
+			if !sadrzi(s.KljuceviImena, kljuc) {
+				s.KljuceviImena = append(s.KljuceviImena, kljuc)
+			}
+		}
+	}
+}
+
+// Spoji prenosi identifikatore, prelaze, incidente i istoriju oznaka duplog zapisa iste osobe
+func (s *SumnjivoLice) Spoji(dupli *SumnjivoLice, sada time.Time) {
+	for _, brojPasosa := range dupli.BrojeviPasosa {
+		if !sadrzi(s.BrojeviPasosa, brojPasosa) {
+			s.BrojeviPasosa = append(s.BrojeviPasosa, brojPasosa)
+		}
+	}
+	for _, varijanta := range append([]string{NormalizujIme(dupli.Ime, dupli.Prezime)}, dupli.VarijanteImena...) {
+		if varijanta != "" && !sadrzi(s.VarijanteImena, varijanta) {
+			s.VarijanteImena = append(s.VarijanteImena, varijanta)
+		}
+	}
+	s.dodajKljuceveImena()
+	for _, id := range dupli.PrelaziIds {
+		povezan := false
+		for _, postojeci := range s.PrelaziIds {
+			povezan = povezan || postojeci == id
+		}
+		if !povezan {
+			s.PrelaziIds = append(s.PrelaziIds, id)
+		}
+	}
+	if s.Drzavljanstvo == "" {
+		s.Drzavljanstvo = dupli.Drzavljanstvo
+	}
+	s.Incidenti = append(s.Incidenti, dupli.Incidenti...)
+	sort.SliceStable(s.Incidenti, func(i, j int) bool {
+		return s.Incidenti[i].Datum < s.Incidenti[j].Datum
+	})
+	s.IstorijaOznaka = append(s.IstorijaOznaka, dupli.IstorijaOznaka...)
+	sort.SliceStable(s.IstorijaOznaka, func(i, j int) bool {
+		return s.IstorijaOznaka[i].Datum < s.IstorijaOznaka[j].Datum
+	})
+	if len(s.IstorijaOznaka) > 0 {
+		s.Oznaceno = s.IstorijaOznaka[len(s.IstorijaOznaka)-1].Oznaceno
+	}
+	if len(s.Incidenti) > 0 {
+		s.Opis = s.Incidenti[len(s.Incidenti)-1].Opis
+	}
+	s.IzracunajRizik(sada)
+}
+
+// DodajIncident evidentira incident, a osoba koja nije oznacena ponovo postaje oznacena
+func (s *SumnjivoLice) DodajIncident(incident Incident, sada time.Time) {
+	s.Incidenti = append(s.Incidenti, incident)
+	s.Opis = incident.Opis
+	if !s.Oznaceno {
+		s.PromeniOznaku(PromenaOznake{
+			Oznaceno:     true,
+			Razlog:       incident.Opis,
+			IdSluzbenika: incident.IdSluzbenika,
+		}, sada)
+	}
+	s.IzracunajRizik(sada)
+}
+
+// PromeniOznaku vraca false ako je osoba vec u trazenom stanju
+func (s *SumnjivoLice) PromeniOznaku(promena PromenaOznake, sada time.Time) bool {
+	if len(s.IstorijaOznaka) > 0 && s.Oznaceno == promena.Oznaceno {
+		return false
+	}
+	promena.Datum = primitive.NewDateTimeFromTime(sada)
+	s.Oznaceno = promena.Oznaceno
+	s.IstorijaOznaka = append(s.IstorijaOznaka, promena)
+	return true
+}
+
+// IzracunajRizik procenjuje rizik od 0 do MaxRizik na osnovu tezine i starosti incidenata i broja prelaza
+func (s *SumnjivoLice) IzracunajRizik(sada time.Time) int {
+	rizik := 0
+	granica := sada.AddDate(0, 0, -PeriodPunogRizikaDana)
+	for _, incident := range s.Incidenti {
+		tezina := tezinaIncidenta[incident.Tip]
+		if incident.Datum.Time().Before(granica) {
+			tezina /= 2
+		}
+		rizik += tezina
+	}
+
+	rizikPrelaza := len(s.PrelaziIds) * RizikPoPrelazu
+	if rizikPrelaza > MaxRizikPrelaza {
+		rizikPrelaza = MaxRizikPrelaza
+	}
+	rizik += rizikPrelaza
+
+	if rizik > MaxRizik {
+		rizik = MaxRizik
+	}
+	s.Rizik = rizik
+	return rizik
+}
+
+// MoguceOdgovaraPutniku proverava da li je putnik bez JMBG-a mozda ista osoba po imenu. Takvo poklapanje
+// je samo moguce i mora da ga potvrdi sluzbenik. Putnik sa JMBG-om se trazi iskljucivo po identifikatorima,
+// a osobe sa razlicitim drzavljanstvom se nikada ne porede (isto poredjenje kao u upitu repozitorijuma).
+func (s *SumnjivoLice) MoguceOdgovaraPutniku(prelaz *Prelaz) bool {
+	if prelaz.JMBGPutnika != "" {
+		return false
+	}
+	if s.Drzavljanstvo != "" && prelaz.DrzavljanstvoPutnika != "" && s.Drzavljanstvo != prelaz.DrzavljanstvoPutnika {
+		return false
+	}
+
+	ime := NormalizujIme(prelaz.ImePutnika, prelaz.PrezimePutnika)
+	if ime == "" {
+		return false
+	}
+	if SlicnaImena(NormalizujIme(s.Ime, s.Prezime), ime) {
+		return true
+	}
+	for _, varijanta := range s.VarijanteImena {
+		if SlicnaImena(varijanta, ime) {
+			return true
+		}
+	}
+	return false
+}
+
+// MogucePoklapanje vraca da li je prelaz pri kontroli mozda pripisan osobi i ceka potvrdu sluzbenika
+func (p *Prelaz) MogucePoklapanje(sumnjivoLiceId primitive.ObjectID) bool {
+	for _, pogodak := range p.Pogoci {
+		if pogodak.Tip == MOGUCE_SUMNJIVO_LICE && pogodak.ReferencaId == sumnjivoLiceId {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizujIme svodi ime na mala slova bez dijakritika i znakova interpunkcije,
+// delovi imena se sortiraju kako redosled imena i prezimena ne bi uticao na poredjenje
+func NormalizujIme(ime string, prezime string) string {
+	tekst := zamenaLatinice.Replace(strings.ToLower(ime + " " + prezime))
+	delovi := strings.FieldsFunc(tekst, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	sort.Strings(delovi)
+	return strings.Join(delovi, " ")
+}
+
+// SlicnaImena dozvoljava jednu gresku na svakih pet slova, npr. Jovanovic i Jovanovich
+func SlicnaImena(a string, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	duzina := len([]rune(a))
+	if d := len([]rune(b)); d > duzina {
+		duzina = d
+	}
+	dozvoljeno := duzina / 5
+	if dozvoljeno < 1 {
+		dozvoljeno = 1
+	}
+	return levenshtein(a, b) <= dozvoljeno
+}
+
+func levenshtein(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prethodni := make([]int, len(rb)+1)
+	tekuci := make([]int, len(rb)+1)
+	for j := range prethodni {
+		prethodni[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		tekuci[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cena := 1
+			if ra[i-1] == rb[j-1] {
+				cena = 0
+			}
+			tekuci[j] = prethodni[j] + 1
+			if tekuci[j-1]+1 < tekuci[j] {
+				tekuci[j] = tekuci[j-1] + 1
+			}
+			if prethodni[j-1]+cena < tekuci[j] {
+				tekuci[j] = prethodni[j-1] + cena
+			}
+		}
+		prethodni, tekuci = tekuci, prethodni
+	}
+	return prethodni[len(rb)]
+}
+
+func sadrzi(vrednosti []string, vrednost string) bool {
+	for _, v := range vrednosti {
+		if v == vrednost {
+			return true
+		}
+	}
+	return false
+}
@@ -11,6 +11,8 @@ require (
 	go.opentelemetry.io/otel/exporters/jaeger v1.11.2
 	go.opentelemetry.io/otel/sdk v1.11.2
 	go.opentelemetry.io/otel/trace v1.11.2
+	drzavljanstvo v0.0.0
+	prijave v0.0.0
 )
 
 require (
@@ -30,3 +32,8 @@ require (
 	golang.org/x/text v0.14.0 // indirect
 
 )
+
+replace (
+	drzavljanstvo => ../drzavljanstvo
+	prijave => ../prijave
+)
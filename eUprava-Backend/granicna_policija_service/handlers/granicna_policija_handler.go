@@ -3,16 +3,25 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"drzavljanstvo"
+	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.opentelemetry.io/otel/trace"
 	"granicna_policija_service/data"
+	"granicna_policija_service/helper"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"sync"
 	"time"
 )
 
@@ -21,6 +30,39 @@ var (
 	muphServicePort = os.Getenv("MUP_SERVICE_PORT")
 )
 
+// Akcija koja se primenjuje kada putnik bude pronadjen na listi, podesiva po tipu pogotka
+var akcijePoTipuPogotka = map[data.TipPogotka]data.AkcijaPogotka{
+	data.NALOG_ZA_PRACENJE: akcijaIzOkruzenja("AKCIJA_NALOG_ZA_PRACENJE", data.ZADRZI),
+	data.SUMNJIVO_LICE:     akcijaIzOkruzenja("AKCIJA_SUMNJIVO_LICE", data.DOZVOLI_I_OZNACI),
+	// moguce poklapanje samo upozorava sluzbenika, putnik se ne zadrzava zbog slicnog imena
+	data.MOGUCE_SUMNJIVO_LICE: data.DOZVOLI_I_OZNACI,
+	// putnik koji nije mogao da se proveri ne prolazi bez rucne provere
+	data.LISTA_NEDOSTUPNA: data.ZADRZI,
+}
+
+var prioritetAkcije = map[data.AkcijaPogotka]int{
+	data.DOZVOLI_I_OZNACI: 1,
+	data.ZADRZI:           2,
+	data.ODBIJ:            3,
+}
+
+const (
+	podrazumevanaVelicinaStranice = 20
+	maxVelicinaStranice           = 100
+	maxPutnikaUGrupi              = 100
+	maxParalelnihKontrola         = 8
+	pragOdbijanjaZaSumnjivoLice   = 3
+	periodOdbijanjaDana           = 30
+)
+
+func akcijaIzOkruzenja(kljuc string, podrazumevana data.AkcijaPogotka) data.AkcijaPogotka {
+	akcija := data.AkcijaPogotka(os.Getenv(kljuc))
+	if _, ok := prioritetAkcije[akcija]; !ok {
+		return podrazumevana
+	}
+	return akcija
+}
+
 type GranicnaPolicijaHandler struct {
 	logger               *log.Logger
 	granicnaPolicijaRepo *data.GranicnaPolicijaRepo
@@ -50,17 +92,21 @@ func (h *GranicnaPolicijaHandler) CreateSumnjivoLiceHandler(w http.ResponseWrite
 		return
 	}
 
-	var sumnjivoLice data.SumnjivoLice
-	if err := json.NewDecoder(r.Body).Decode(&sumnjivoLice); err != nil {
+	var zahtev data.SumnjivoLice
+	if err := json.NewDecoder(r.Body).Decode(&zahtev); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		w.Write([]byte("Pogresan format zahteva"))
 		return
 	}
 
-	sumnjivoLice.ID = primitive.NewObjectID()
-	sumnjivoLice.Prelaz = *prelaz
+	// Sluzbenik iz tokena nije obavezan jer se ruta koristi i bez prijave
+	sluzbenikId, _ := primitive.ObjectIDFromHex(helper.ExtractClaims(r)["id"])
 
-	err = h.granicnaPolicijaRepo.CreateSumnjivoLice(ctx, &sumnjivoLice)
+	sumnjivoLice, err := h.evidentirajIncident(ctx, prelaz, data.Incident{
+		Tip:          data.RUCNA_PRIJAVA,
+		Opis:         zahtev.Opis,
+		IdSluzbenika: sluzbenikId,
+	})
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte("Greska prilikom kreiranja sumnjivog lica"))
@@ -68,9 +114,142 @@ func (h *GranicnaPolicijaHandler) CreateSumnjivoLiceHandler(w http.ResponseWrite
 	}
 
 	w.WriteHeader(http.StatusCreated)
+	sumnjivoLice.ToJSON(w)
+}
+
+// evidentirajIncident dodaje incident postojecem zapisu o osobi sa prelaza ili kreira novi zapis
+func (h *GranicnaPolicijaHandler) evidentirajIncident(ctx context.Context, prelaz *data.Prelaz, incident data.Incident) (*data.SumnjivoLice, error) {
+	incident.Datum = primitive.NewDateTimeFromTime(time.Now())
+	incident.PrelazId = prelaz.ID
+	return h.granicnaPolicijaRepo.EvidentirajIncidentSumnjivogLica(ctx, prelaz, incident)
+}
+
+func (h *GranicnaPolicijaHandler) GetSumnjivoLiceHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id sumnjivog lica nije procitan"))
+		return
+	}
+
+	sumnjivoLice, err := h.granicnaPolicijaRepo.GetSumnjivoLiceByID(ctx, id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Sumnjivo lice ne postoji", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Greska prilikom dobavljanja sumnjivog lica", http.StatusInternalServerError)
+		return
+	}
+
+	sumnjivoLice.Prelazi, err = h.granicnaPolicijaRepo.GetPrelaziByIDs(ctx, sumnjivoLice.PrelaziIds)
+	if err != nil {
+		http.Error(w, "Greska prilikom dobavljanja prelaza", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	sumnjivoLice.ToJSON(w)
+}
+
+// PromeniOznakuSumnjivogLicaHandler oznacava osobu ili skida oznaku, svaka promena se cuva u istoriji oznaka
+func (h *GranicnaPolicijaHandler) PromeniOznakuSumnjivogLicaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id sumnjivog lica nije procitan"))
+		return
+	}
+
+	var promena data.PromenaOznake
+	if err := json.NewDecoder(r.Body).Decode(&promena); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if promena.Razlog == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Razlog promene oznake je obavezan"))
+		return
+	}
+	promena.IdSluzbenika, _ = primitive.ObjectIDFromHex(helper.ExtractClaims(r)["id"])
+
+	sumnjivoLice, promenjeno, err := h.granicnaPolicijaRepo.PromeniOznakuSumnjivogLica(ctx, id, promena)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Sumnjivo lice ne postoji", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Greska prilikom promene oznake", http.StatusInternalServerError)
+		return
+	}
+	if !promenjeno {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("Sumnjivo lice je vec u trazenom stanju"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	sumnjivoLice.ToJSON(w)
+}
+
+// PotvrdiPoklapanjeHandler pripisuje osobi prelaz koji je pri kontroli oznacen kao moguce poklapanje po imenu
+func (h *GranicnaPolicijaHandler) PotvrdiPoklapanjeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	id, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id sumnjivog lica nije procitan"))
+		return
+	}
+	prelazId, err := primitive.ObjectIDFromHex(vars["prelazId"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id prelaza nije procitan"))
+		return
+	}
+
+	prelaz, err := h.granicnaPolicijaRepo.GetPrelazByID(ctx, prelazId)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Prelaz ne postoji", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Greska prilikom dobavljanja prelaza", http.StatusInternalServerError)
+		return
+	}
+	if !prelaz.MogucePoklapanje(id) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("Prelaz nije oznacen kao moguce poklapanje sa ovom osobom"))
+		return
+	}
+
+	sumnjivoLice, err := h.granicnaPolicijaRepo.PovezPrelazSaSumnjivimLicem(ctx, id, prelaz)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Sumnjivo lice ne postoji", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Greska prilikom povezivanja prelaza sa sumnjivim licem", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	sumnjivoLice.ToJSON(w)
 }
 
-func validateDocuments(prelaz *data.Prelaz) (bool, error) {
+// validateDocuments vraca da li su dokumenti validni i izvestaj mup servisa.
+// Greska se vraca samo ako validacija nije mogla da se izvrsi.
+func validateDocuments(ctx context.Context, prelaz *data.Prelaz) (bool, string, error) {
 	validirajDokumenteEndpoint := fmt.Sprintf("http://%s:%s/validirajDokumente", mupServiceHost, muphServicePort)
 
 	podaciZaValidaciju := data.PodaciZaValidaciju{
@@ -85,13 +264,13 @@ func validateDocuments(prelaz *data.Prelaz) (bool, error) {
 	requestBody, err := json.Marshal(podaciZaValidaciju)
 	if err != nil {
 		fmt.Println("Greska prilikom serijalizacije podataka:", err)
-		return false, err
+		return false, "", err
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), "POST", validirajDokumenteEndpoint, bytes.NewBuffer(requestBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", validirajDokumenteEndpoint, bytes.NewBuffer(requestBody))
 	if err != nil {
 		fmt.Println("Greska prilikom kreiranja zahteva:", err)
-		return false, err
+		return false, "", err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
@@ -99,27 +278,60 @@ func validateDocuments(prelaz *data.Prelaz) (bool, error) {
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		fmt.Println("Greska prilikom kreiranja zahteva:", err)
-		return false, err
+		return false, "", err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return false, "", fmt.Errorf("mup servis je vratio status %d", resp.StatusCode)
+	}
+
 	// Check the response
 	if resp.StatusCode != http.StatusOK {
 		// Read the response body
 		body, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
 			fmt.Println("Greska prilikom citanja tela odgovora:", err)
-			return false, err
+			return false, "", err
 		}
-		return false, fmt.Errorf(string(body))
+		return false, string(body), nil
 	}
 
 	// Check the response status code
 	if resp.StatusCode == http.StatusOK {
-		return true, nil
+		return true, "", nil
+	}
+
+	return false, "", nil
+}
+
+// dobaviFotografijuPutnika preuzima fotografiju nosioca dokumenta iz mup servisa
+// kako bi je sluzbenik uporedio sa putnikom na prelazu
+func dobaviFotografijuPutnika(ctx context.Context, jmbg string, bearer string) (string, error) {
+	fotografijaEndpoint := fmt.Sprintf("http://%s:%s/fotografija/%s", mupServiceHost, muphServicePort, jmbg)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fotografijaEndpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", bearer)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Greska prilikom dobavljanja fotografije: %d", resp.StatusCode)
+	}
+
+	slika, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
 	}
 
-	return false, nil
+	return "data:" + resp.Header.Get("Content-Type") + ";base64," + base64.StdEncoding.EncodeToString(slika), nil
 }
 
 func (h *GranicnaPolicijaHandler) CreatePrelazHandler(w http.ResponseWriter, r *http.Request) {
@@ -131,123 +343,1235 @@ func (h *GranicnaPolicijaHandler) CreatePrelazHandler(w http.ResponseWriter, r *
 		return
 	}
 
-	// Validiraj dokumente prije kreiranja Prelaza
-	validno, validacijaError := validateDocuments(&prelaz)
-	if !validno {
-		errorMsg := "Dokumenti nisu validni: " + validacijaError.Error()
-		w.WriteHeader(http.StatusForbidden)
-		w.Write([]byte(errorMsg))
+	if prelaz.Smer != data.ULAZ && prelaz.Smer != data.IZLAZ {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Smer prelaza mora biti ULAZ ili IZLAZ"))
 		return
 	}
 
-	prelaz.ID = primitive.NewObjectID()
-	prelaz.Datum = primitive.NewDateTimeFromTime(time.Now())
+	claims := helper.ExtractClaims(r)
+	sluzbenikId, err := primitive.ObjectIDFromHex(claims["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id sluzbenika nije procitan"))
+		return
+	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	err := h.granicnaPolicijaRepo.CreatePrelaz(ctx, &prelaz)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("Greška prilikom kreiranja prelaza"))
+	if _, err := h.granicnaPolicijaRepo.GetGranicniPrelazByID(ctx, prelaz.GranicniPrelazId); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Granicni prelaz ne postoji"))
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
+	prelaz.IdSluzbenika = sluzbenikId
+
+	// Fotografija se dobavlja uporedo sa kontrolom, pa je sluzbenik dobija uz svaku odluku,
+	// i kada je putnik odbijen ili zadrzan
+	fotografijaPutnika := make(chan string, 1)
+	go func(jmbg string) {
+		if jmbg == "" {
+			fotografijaPutnika <- ""
+			return
+		}
+		fotografija, err := dobaviFotografijuPutnika(ctx, jmbg, r.Header.Get("Authorization"))
+		if err != nil {
+			h.logger.Println("Fotografija putnika nije dobavljena:", err)
+		}
+		fotografijaPutnika <- fotografija
+	}(prelaz.JMBGPutnika)
+
+	if err := h.kontrolisiPrelaz(ctx, &prelaz, r.Header.Get("Authorization")); err != nil {
+		var greska *greskaKontrole
+		if !errors.As(err, &greska) {
+			greska = &greskaKontrole{http.StatusInternalServerError, "Greska prilikom kontrole prelaza"}
+		}
+		w.WriteHeader(greska.status)
+		w.Write([]byte(greska.poruka))
+		return
+	}
+	prelaz.FotografijaPutnika = <-fotografijaPutnika
+
+	if prelaz.Odbijanje != nil {
+		w.WriteHeader(http.StatusForbidden)
+		prelaz.ToJSON(w)
+		return
+	}
+
+	if prelaz.Zadrzan {
+		w.WriteHeader(http.StatusAccepted)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
+	prelaz.ToJSON(w)
 }
 
-//func (h *GranicnaPolicijaHandler) CreatePrelazHandler(w http.ResponseWriter, r *http.Request) {
-//
-//	var prelaz data.Prelaz
-//
-//	if err := json.NewDecoder(r.Body).Decode(&prelaz); err != nil {
-//		w.WriteHeader(http.StatusBadRequest)
-//		w.Write([]byte("Pogresan format zahteva"))
-//		return
-//	}
-//
-//	prelaz.ID = primitive.NewObjectID()
-//	prelaz.Datum = primitive.NewDateTimeFromTime(time.Now())
-//
-//	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-//	defer cancel()
-//
-//	err := h.granicnaPolicijaRepo.CreatePrelaz(ctx, &prelaz)
-//	if err != nil {
-//		w.WriteHeader(http.StatusInternalServerError)
-//		w.Write([]byte("Greska prilikom kreiranja prelaza"))
-//		return
-//	}
-//
-//	w.WriteHeader(http.StatusCreated)
-//}
+// CreateGrupniPrelazHandler obradjuje prelaz vozila sa spiskom putnika. Putnici se kontrolisu
+// paralelno (najvise maxParalelnihKontrola istovremeno), a rezultat se vraca za svakog putnika posebno.
+func (h *GranicnaPolicijaHandler) CreateGrupniPrelazHandler(w http.ResponseWriter, r *http.Request) {
+	var grupniPrelaz data.GrupniPrelaz
 
-func (h *GranicnaPolicijaHandler) CreateKrivicnaPrijavaHandler(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-	defer cancel()
+	if err := json.NewDecoder(r.Body).Decode(&grupniPrelaz); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Pogrešan format zahtjeva"))
+		return
+	}
 
-	vars := mux.Vars(r)
-	prelazId, err := primitive.ObjectIDFromHex(vars["id"])
-	if err != nil {
+	if grupniPrelaz.Smer != data.ULAZ && grupniPrelaz.Smer != data.IZLAZ {
 		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Id prelaza nije procitan"))
+		w.Write([]byte("Smer prelaza mora biti ULAZ ili IZLAZ"))
+		return
+	}
+	if len(grupniPrelaz.Putnici) == 0 || len(grupniPrelaz.Putnici) > maxPutnikaUGrupi {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Spisak putnika mora imati izmedju 1 i %d putnika", maxPutnikaUGrupi)))
 		return
 	}
 
-	prelaz, err := h.granicnaPolicijaRepo.GetPrelazByID(ctx, prelazId)
+	claims := helper.ExtractClaims(r)
+	sluzbenikId, err := primitive.ObjectIDFromHex(claims["id"])
 	if err != nil {
-		w.WriteHeader(http.StatusForbidden)
-		w.Write([]byte("Prelaz ne postoji"))
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id sluzbenika nije procitan"))
 		return
 	}
 
-	var krivicnaPrijava data.KrivicnaPrijava
-	if err := json.NewDecoder(r.Body).Decode(&krivicnaPrijava); err != nil {
+	ctx, cancel := context.WithTimeout(r.Context(), 9*time.Second)
+	defer cancel()
+
+	if _, err := h.granicnaPolicijaRepo.GetGranicniPrelazByID(ctx, grupniPrelaz.GranicniPrelazId); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Pogresan format zahteva"))
+		w.Write([]byte("Granicni prelaz ne postoji"))
 		return
 	}
 
-	krivicnaPrijava.ID = primitive.NewObjectID()
-	krivicnaPrijava.Datum = primitive.NewDateTimeFromTime(time.Now())
-	krivicnaPrijava.Prelaz = *prelaz
+	putnici := grupniPrelaz.Putnici
+	grupniPrelaz.ID = primitive.NewObjectID()
+	grupniPrelaz.Datum = primitive.NewDateTimeFromTime(time.Now())
+	grupniPrelaz.IdSluzbenika = sluzbenikId
+	grupniPrelaz.BrojPutnika = len(putnici)
+	grupniPrelaz.Putnici = nil
 
-	err = h.granicnaPolicijaRepo.CreateKrivicnaPrijava(ctx, &krivicnaPrijava)
-	if err != nil {
+	if err := h.granicnaPolicijaRepo.CreateGrupniPrelaz(ctx, &grupniPrelaz); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("Greska prilikom kreiranja krivicne prijave"))
+		w.Write([]byte("Greska prilikom kreiranja grupnog prelaza"))
 		return
 	}
 
+	rezultati := make([]data.RezultatPutnika, len(putnici))
+	slobodnaMesta := make(chan struct{}, maxParalelnihKontrola)
+	var wg sync.WaitGroup
+
+	for i := range putnici {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			slobodnaMesta <- struct{}{}
+			defer func() { <-slobodnaMesta }()
+
+			prelaz := putnici[i]
+			prelaz.Smer = grupniPrelaz.Smer
+			prelaz.GranicniPrelazId = grupniPrelaz.GranicniPrelazId
+			prelaz.Traka = grupniPrelaz.Traka
+			prelaz.MarkaVozila = grupniPrelaz.MarkaVozila
+			prelaz.ModelVozila = grupniPrelaz.ModelVozila
+			prelaz.IdSluzbenika = sluzbenikId
+			prelaz.GrupniPrelazId = grupniPrelaz.ID
+
+			rezultat := data.RezultatPutnika{RedniBroj: i + 1}
+			if err := h.kontrolisiPrelaz(ctx, &prelaz, r.Header.Get("Authorization")); err != nil {
+				rezultat.Status = data.GRESKA
+				rezultat.Poruka = err.Error()
+			} else {
+				rezultat.Prelaz = &prelaz
+				switch {
+				case prelaz.Odbijanje != nil:
+					rezultat.Status = data.ODBIJEN
+					rezultat.Poruka = prelaz.Odbijanje.Opis
+				case prelaz.Zadrzan:
+					rezultat.Status = data.ZADRZAN
+				default:
+					rezultat.Status = data.ODOBREN
+				}
+			}
+			rezultati[i] = rezultat
+		}(i)
+	}
+	wg.Wait()
+
+	for _, rezultat := range rezultati {
+		switch rezultat.Status {
+		case data.ODOBREN:
+			grupniPrelaz.BrojOdobrenih++
+		case data.ZADRZAN:
+			grupniPrelaz.BrojZadrzanih++
+		case data.ODBIJEN:
+			grupniPrelaz.BrojOdbijenih++
+		default:
+			grupniPrelaz.BrojGresaka++
+		}
+	}
+
+	// Brojaci se upisuju i kada je vreme za kontrolu putnika isteklo, ali ne i kada je zahtev prekinut
+	if err := h.granicnaPolicijaRepo.UpdateGrupniPrelaz(r.Context(), &grupniPrelaz); err != nil {
+		h.logger.Println("Greska prilikom azuriranja grupnog prelaza:", err)
+	}
+
+	grupniPrelaz.Rezultati = rezultati
 	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(grupniPrelaz)
 }
 
-func (h *GranicnaPolicijaHandler) GetSumnjivaLicaHandler(w http.ResponseWriter, r *http.Request) {
+func (h *GranicnaPolicijaHandler) GetGrupniPrelazHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	sumnjivaLica, err := h.granicnaPolicijaRepo.GetSumnjivaLica(ctx)
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
 	if err != nil {
-		http.Error(w, "Error getting Sumnjiva lica", http.StatusInternalServerError)
+		http.Error(w, "Neispravan id grupnog prelaza", http.StatusBadRequest)
+		return
+	}
+
+	grupniPrelaz, err := h.granicnaPolicijaRepo.GetGrupniPrelazByID(ctx, id)
+	if err != nil {
+		http.Error(w, "Grupni prelaz ne postoji", http.StatusNotFound)
+		return
+	}
+
+	grupniPrelaz.Putnici, err = h.granicnaPolicijaRepo.GetPrelaziByGrupniPrelaz(ctx, id)
+	if err != nil {
+		http.Error(w, "Error getting Prelazi", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(sumnjivaLica)
+	json.NewEncoder(w).Encode(grupniPrelaz)
 }
 
-func (h *GranicnaPolicijaHandler) GetPrelaziHandler(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-	defer cancel()
+// greskaKontrole oznacava da kontrola putnika nije mogla da se zavrsi, za razliku od odbijanja
+type greskaKontrole struct {
+	status int
+	poruka string
+}
 
-	prelazi, err := h.granicnaPolicijaRepo.GetPrelazi(ctx)
+func (g *greskaKontrole) Error() string {
+	return g.poruka
+}
+
+// kontrolisiPrelaz proverava dokumente, dozvoljeni boravak i liste za pracenje i cuva prelaz.
+// Odbijeni prelaz se takodje cuva, sa popunjenim razlogom odbijanja.
+func (h *GranicnaPolicijaHandler) kontrolisiPrelaz(ctx context.Context, prelaz *data.Prelaz, bearer string) error {
+	// Ishod kontrole odredjuje server, vrednosti iz zahteva se ignorisu
+	prelaz.Odobren = false
+	prelaz.Odbijanje = nil
+	prelaz.Zadrzan = false
+	prelaz.Pogoci = nil
+	prelaz.Upozorenja = nil
+	prelaz.DrzavljanstvoPutnika = drzavljanstvo.Normalizuj(prelaz.DrzavljanstvoPutnika)
+
+	prelaz.ID = primitive.NewObjectID()
+	prelaz.Datum = primitive.NewDateTimeFromTime(time.Now())
+
+	// Validiraj dokumente prije kreiranja Prelaza
+	validno, izvestaj, err := validateDocuments(ctx, prelaz)
 	if err != nil {
-		http.Error(w, "Error getting Prelazi", http.StatusInternalServerError)
+		return &greskaKontrole{http.StatusServiceUnavailable, "Validacija dokumenata trenutno nije moguca"}
+	}
+	if !validno {
+		return h.odbijPrelaz(ctx, prelaz, data.Odbijanje{
+			Razlog:             data.DOKUMENTI_NISU_VALIDNI,
+			Opis:               "Dokumenti nisu validni",
+			IzvestajValidacije: izvestaj,
+		})
+	}
+
+	// Dozvoljeni boravak se proverava strancima. Drzavljanstvo koje nije evidentirano ili prepoznato je nepoznato,
+	// a ne strano, pa ga sluzbenik proverava rucno.
+	if prelaz.Smer == data.ULAZ && !drzavljanstvo.Poznato(prelaz.DrzavljanstvoPutnika) {
+		prelaz.Upozorenja = append(prelaz.Upozorenja, "Drzavljanstvo putnika nije prepoznato, dozvoljeni boravak nije proveren")
+	}
+	if prelaz.Smer == data.ULAZ && drzavljanstvo.JeStrano(prelaz.DrzavljanstvoPutnika) {
+		dozvoljen, err := h.proveriDozvoljenBoravak(ctx, prelaz)
+		if err != nil {
+			return &greskaKontrole{http.StatusInternalServerError, "Greska prilikom provere dozvoljenog boravka"}
+		}
+		if !dozvoljen {
+			return h.odbijPrelaz(ctx, prelaz, data.Odbijanje{
+				Razlog: data.PREKORACEN_BORAVAK,
+				Opis:   fmt.Sprintf("Prekoracen dozvoljeni boravak od %d dana u periodu od %d dana", data.MaxDanaBoravka, data.PeriodBoravkaDana),
+			})
+		}
+	}
+
+	akcija, err := h.proveriListeZaPracenje(ctx, prelaz, bearer)
+	if err != nil {
+		return &greskaKontrole{http.StatusInternalServerError, "Greska prilikom provere liste za pracenje"}
+	}
+	if akcija == data.ODBIJ {
+		return h.odbijPrelaz(ctx, prelaz, data.Odbijanje{
+			Razlog: data.LISTA_ZA_PRACENJE,
+			Opis:   "Putnik se nalazi na listi za pracenje",
+		})
+	}
+	prelaz.Zadrzan = akcija == data.ZADRZI
+	prelaz.Odobren = true
+
+	if err := h.granicnaPolicijaRepo.CreatePrelaz(ctx, prelaz); err != nil {
+		return &greskaKontrole{http.StatusInternalServerError, "Greška prilikom kreiranja prelaza"}
+	}
+	h.obradiPogotke(ctx, prelaz)
+	return nil
+}
+
+// odbijPrelaz cuva odbijeni prelaz sa razlogom odbijanja kako bi ostao trag u evidenciji
+func (h *GranicnaPolicijaHandler) odbijPrelaz(ctx context.Context, prelaz *data.Prelaz, odbijanje data.Odbijanje) error {
+	prelaz.Odobren = false
+	prelaz.Odbijanje = &odbijanje
+
+	err := h.granicnaPolicijaRepo.CreatePrelaz(ctx, prelaz)
+	if err != nil {
+		return &greskaKontrole{http.StatusInternalServerError, "Greška prilikom evidentiranja odbijenog prelaza"}
+	}
+	h.obradiPogotke(ctx, prelaz)
+	// Odbijen prelaz je sacuvan, pa se zapis koji ovde nije upisan dopunjuje pri sledecem pokretanju servisa
+	if err := h.granicnaPolicijaRepo.EvidentirajOdbijanje(ctx, data.NoviZapisOdbijanja(prelaz)); err != nil {
+		h.logger.Println("Greska prilikom upisa u evidenciju odbijanja:", err)
+	}
+
+	h.proveriPonovljenaOdbijanja(ctx, prelaz)
+	return nil
+}
+
+// proveriPonovljenaOdbijanja kreira sumnjivo lice kada je putnik vise puta odbijen u kratkom periodu
+func (h *GranicnaPolicijaHandler) proveriPonovljenaOdbijanja(ctx context.Context, prelaz *data.Prelaz) {
+	filter := data.FilterOdbijenihPrelaza{Od: time.Now().AddDate(0, 0, -periodOdbijanjaDana)}
+	if prelaz.JMBGPutnika != "" {
+		filter.JMBG = prelaz.JMBGPutnika
+	} else if prelaz.BrojPasosaPutnika != "" {
+		filter.BrojPasosa = prelaz.BrojPasosaPutnika
+	} else {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(prelazi)
+	odbijeni, err := h.granicnaPolicijaRepo.GetOdbijeniPrelazi(ctx, filter)
+	if err != nil {
+		h.logger.Println("Greska prilikom provere ponovljenih odbijanja:", err)
+		return
+	}
+	if len(odbijeni) < pragOdbijanjaZaSumnjivoLice {
+		return
+	}
+
+	postojece, err := h.granicnaPolicijaRepo.PronadjiSumnjivoLice(ctx, prelaz)
+	if err != nil {
+		h.logger.Println("Greska prilikom pretrage sumnjivih lica:", err)
+		return
+	}
+	// Ponovljena odbijanja se evidentiraju jednom u toku perioda
+	if postojece != nil {
+		for _, incident := range postojece.Incidenti {
+			if incident.Tip == data.PONOVLJENA_ODBIJANJA && incident.Datum.Time().After(filter.Od) {
+				return
+			}
+		}
+	}
+
+	_, err = h.evidentirajIncident(ctx, prelaz, data.Incident{
+		Tip:  data.PONOVLJENA_ODBIJANJA,
+		Opis: fmt.Sprintf("Automatski kreirano: %d odbijenih prelaza u poslednjih %d dana", len(odbijeni), periodOdbijanjaDana),
+	})
+	if err != nil {
+		h.logger.Println("Greska prilikom kreiranja sumnjivog lica:", err)
+	}
+}
+
+// dobaviNalogZaPracenje vraca nil samo kada mup odgovori da nalog ne postoji, svaki drugi odgovor je greska
+func dobaviNalogZaPracenje(ctx context.Context, jmbg string, bearer string) (*data.NalogZaPracenje, error) {
+	nalogEndpoint := fmt.Sprintf("http://%s:%s/dobaviNalogZaPracenje/%s", mupServiceHost, muphServicePort, url.PathEscape(jmbg))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", nalogEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", bearer)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Greska prilikom dobavljanja naloga za pracenje: %d", resp.StatusCode)
+	}
+
+	var nalog data.NalogZaPracenje
+	if err := json.NewDecoder(resp.Body).Decode(&nalog); err != nil {
+		return nil, err
+	}
+
+	return &nalog, nil
+}
+
+// proveriListeZaPracenje trazi putnika medju nalozima za pracenje u mup servisu i medju sumnjivim licima,
+// dodaje pogotke prelazu i vraca najstrozu akciju za pronadjene pogotke. Prelaz se sa osobom povezuje i alarm
+// salje tek kada je prelaz sacuvan, u obradiPogotke. Ako nalozi ne mogu da se provere, putnik se zadrzava
+// radi rucne provere.
+func (h *GranicnaPolicijaHandler) proveriListeZaPracenje(ctx context.Context, prelaz *data.Prelaz, bearer string) (data.AkcijaPogotka, error) {
+	var pogoci []data.PogodakListe
+
+	if prelaz.JMBGPutnika != "" {
+		nalog, err := dobaviNalogZaPracenje(ctx, prelaz.JMBGPutnika, bearer)
+		if err != nil {
+			h.logger.Println("Nalozi za pracenje nisu provereni:", err)
+			pogoci = append(pogoci, data.PogodakListe{
+				Tip:  data.LISTA_NEDOSTUPNA,
+				Opis: "Nalozi za pracenje trenutno nisu dostupni, potrebna je rucna provera putnika",
+			})
+		} else if nalog != nil {
+			pogoci = append(pogoci, data.PogodakListe{
+				Tip:         data.NALOG_ZA_PRACENJE,
+				ReferencaId: nalog.ID,
+				Opis:        nalog.Opis,
+			})
+		}
+	}
+
+	sumnjivoLice, err := h.granicnaPolicijaRepo.PronadjiSumnjivoLice(ctx, prelaz)
+	if err != nil {
+		return "", err
+	}
+	if sumnjivoLice != nil && sumnjivoLice.Oznaceno {
+		pogoci = append(pogoci, data.PogodakListe{
+			Tip:         data.SUMNJIVO_LICE,
+			ReferencaId: sumnjivoLice.ID,
+			Opis:        fmt.Sprintf("%s (rizik %d)", sumnjivoLice.Opis, sumnjivoLice.Rizik),
+		})
+	}
+
+	// Poklapanje samo po imenu se ne pripisuje osobi dok ga sluzbenik ne potvrdi
+	moguca, err := h.granicnaPolicijaRepo.PronadjiMogucaSumnjivaLica(ctx, prelaz)
+	if err != nil {
+		return "", err
+	}
+	for _, moguce := range moguca {
+		if sumnjivoLice != nil && sumnjivoLice.ID == moguce.ID {
+			continue
+		}
+		pogoci = append(pogoci, data.PogodakListe{
+			Tip:         data.MOGUCE_SUMNJIVO_LICE,
+			ReferencaId: moguce.ID,
+			Opis:        fmt.Sprintf("Moguce poklapanje sa osobom %s %s: %s (rizik %d)", moguce.Ime, moguce.Prezime, moguce.Opis, moguce.Rizik),
+		})
+	}
+
+	var akcija data.AkcijaPogotka
+	for i := range pogoci {
+		pogoci[i].Akcija = akcijePoTipuPogotka[pogoci[i].Tip]
+		if prioritetAkcije[pogoci[i].Akcija] > prioritetAkcije[akcija] {
+			akcija = pogoci[i].Akcija
+		}
+	}
+	prelaz.Pogoci = pogoci
+
+	return akcija, nil
+}
+
+// obradiPogotke povezuje sacuvani prelaz sa oznacenom osobom i za svaki pogodak salje alarm sluzbeniku
+func (h *GranicnaPolicijaHandler) obradiPogotke(ctx context.Context, prelaz *data.Prelaz) {
+	for _, pogodak := range prelaz.Pogoci {
+		if pogodak.Tip == data.SUMNJIVO_LICE {
+			if _, err := h.granicnaPolicijaRepo.PovezPrelazSaSumnjivimLicem(ctx, pogodak.ReferencaId, prelaz); err != nil {
+				h.logger.Println("Greska prilikom povezivanja prelaza sa sumnjivim licem:", err)
+			}
+		}
+
+		alarm := data.Alarm{
+			ID:           primitive.NewObjectID(),
+			IdSluzbenika: prelaz.IdSluzbenika,
+			PrelazId:     prelaz.ID,
+			Datum:        primitive.NewDateTimeFromTime(time.Now()),
+			Pogodak:      pogodak,
+		}
+		if err := h.granicnaPolicijaRepo.CreateAlarm(ctx, &alarm); err != nil {
+			h.logger.Println("Greska prilikom kreiranja alarma:", err)
+		}
+	}
+}
+
+func (h *GranicnaPolicijaHandler) GetMojiAlarmiHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	sluzbenikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id sluzbenika nije procitan"))
+		return
+	}
+
+	alarmi, err := h.granicnaPolicijaRepo.GetAlarmiBySluzbenik(ctx, sluzbenikId)
+	if err != nil {
+		http.Error(w, "Error getting Alarmi", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(alarmi)
+}
+
+func (h *GranicnaPolicijaHandler) OznaciAlarmProcitanHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	alarmId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id alarma nije procitan"))
+		return
+	}
+
+	sluzbenikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id sluzbenika nije procitan"))
+		return
+	}
+
+	err = h.granicnaPolicijaRepo.OznaciAlarmProcitan(ctx, alarmId, sluzbenikId)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Alarm ne postoji"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+//func (h *GranicnaPolicijaHandler) CreatePrelazHandler(w http.ResponseWriter, r *http.Request) {
+//
+//	var prelaz data.Prelaz
+//
+//	if err := json.NewDecoder(r.Body).Decode(&prelaz); err != nil {
+//		w.WriteHeader(http.StatusBadRequest)
+//		w.Write([]byte("Pogresan format zahteva"))
+//		return
+//	}
+//
+//	prelaz.ID = primitive.NewObjectID()
+//	prelaz.Datum = primitive.NewDateTimeFromTime(time.Now())
+//
+//	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+//	defer cancel()
+//
+//	err := h.granicnaPolicijaRepo.CreatePrelaz(ctx, &prelaz)
+//	if err != nil {
+//		w.WriteHeader(http.StatusInternalServerError)
+//		w.Write([]byte("Greska prilikom kreiranja prelaza"))
+//		return
+//	}
+//
+//	w.WriteHeader(http.StatusCreated)
+//}
+
+// proveriDozvoljenBoravak primenjuje pravilo 90 dana u periodu od 180 dana za strane drzavljane.
+// Ako je putniku ostalo malo dana boravka, prelazu se dodaje upozorenje.
+func (h *GranicnaPolicijaHandler) proveriDozvoljenBoravak(ctx context.Context, prelaz *data.Prelaz) (bool, error) {
+	var prethodni []data.Prelaz
+	var err error
+	if prelaz.BrojPasosaPutnika != "" {
+		prethodni, err = h.granicnaPolicijaRepo.GetPrelaziByBrojPasosa(ctx, prelaz.BrojPasosaPutnika)
+	} else {
+		prethodni, err = h.granicnaPolicijaRepo.GetPrelaziByJMBG(ctx, prelaz.JMBGPutnika)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	istorija := data.NapraviIstorijuPutnika(append(prethodni, *prelaz), prelaz.Datum.Time())
+	if istorija.DanaUPeriodu > data.MaxDanaBoravka {
+		return false, nil
+	}
+
+	if istorija.PreostaloDana <= data.PragUpozorenjaDana {
+		prelaz.Upozorenja = append(prelaz.Upozorenja, fmt.Sprintf("Putniku je preostalo %d dana dozvoljenog boravka", istorija.PreostaloDana))
+	}
+
+	return true, nil
+}
+
+func (h *GranicnaPolicijaHandler) CreateKrivicnaPrijavaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	prelazId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id prelaza nije procitan"))
+		return
+	}
+
+	prelaz, err := h.granicnaPolicijaRepo.GetPrelazByID(ctx, prelazId)
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("Prelaz ne postoji"))
+		return
+	}
+
+	var krivicnaPrijava data.KrivicnaPrijava
+	if err := json.NewDecoder(r.Body).Decode(&krivicnaPrijava); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	claims := helper.ExtractClaims(r)
+	sluzbenikId, err := primitive.ObjectIDFromHex(claims["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id sluzbenika nije procitan"))
+		return
+	}
+	krivicnaPrijava.IdSluzbenika = sluzbenikId
+
+	err = h.podnesiKrivicnuPrijavu(ctx, prelaz, &krivicnaPrijava)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Greska prilikom kreiranja krivicne prijave"))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	krivicnaPrijava.ToJSON(w)
+}
+
+func (h *GranicnaPolicijaHandler) podnesiKrivicnuPrijavu(ctx context.Context, prelaz *data.Prelaz, krivicnaPrijava *data.KrivicnaPrijava) error {
+	if krivicnaPrijava.ID.IsZero() {
+		krivicnaPrijava.ID = primitive.NewObjectID()
+	}
+	krivicnaPrijava.Datum = primitive.NewDateTimeFromTime(time.Now())
+	krivicnaPrijava.Prelaz = *prelaz
+	krivicnaPrijava.Izvor = data.IzvorPrijave
+	krivicnaPrijava.Status = data.PODNETA
+	krivicnaPrijava.IstorijaStatusa = []data.PromenaStatusa{{Status: data.PODNETA, Datum: krivicnaPrijava.Datum}}
+
+	// Osumnjiceni se preuzima sa prelaza samo ako nije posebno naveden
+	if krivicnaPrijava.Osumnjiceni == (data.Osumnjiceni{}) {
+		krivicnaPrijava.Osumnjiceni = data.Osumnjiceni{
+			Ime:           prelaz.ImePutnika,
+			Prezime:       prelaz.PrezimePutnika,
+			JMBG:          prelaz.JMBGPutnika,
+			BrojPasosa:    prelaz.BrojPasosaPutnika,
+			Drzavljanstvo: prelaz.DrzavljanstvoPutnika,
+		}
+	}
+
+	return h.granicnaPolicijaRepo.CreateKrivicnaPrijava(ctx, krivicnaPrijava)
+}
+
+// UpdateKrivicnaPrijavaHandler menja sadrzaj prijave dok je jos nije preuzelo tuzilastvo
+func (h *GranicnaPolicijaHandler) UpdateKrivicnaPrijavaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id krivicne prijave nije procitan"))
+		return
+	}
+
+	krivicnaPrijava, err := h.granicnaPolicijaRepo.GetKrivicnaPrijavaByID(ctx, id)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Krivicna prijava ne postoji"))
+		return
+	}
+
+	var izmena data.KrivicnaPrijava
+	if err := json.NewDecoder(r.Body).Decode(&izmena); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	krivicnaPrijava.Opis = izmena.Opis
+	krivicnaPrijava.PravnaKvalifikacija = izmena.PravnaKvalifikacija
+	krivicnaPrijava.Osumnjiceni = izmena.Osumnjiceni
+	krivicnaPrijava.Svedoci = izmena.Svedoci
+
+	err = h.granicnaPolicijaRepo.UpdateSadrzajKrivicnePrijave(ctx, krivicnaPrijava)
+	if err == mongo.ErrNoDocuments {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("Krivicna prijava se moze menjati samo dok je u statusu PODNETA"))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Greska prilikom izmene krivicne prijave"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	krivicnaPrijava.ToJSON(w)
+}
+
+// PromeniStatusKrivicnePrijaveHandler pomera prijavu kroz zivotni ciklus, nedozvoljeni prelazi se odbijaju
+func (h *GranicnaPolicijaHandler) PromeniStatusKrivicnePrijaveHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id krivicne prijave nije procitan"))
+		return
+	}
+
+	var promena data.PromenaStatusa
+	if err := json.NewDecoder(r.Body).Decode(&promena); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	krivicnaPrijava, err := h.granicnaPolicijaRepo.GetKrivicnaPrijavaByID(ctx, id)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Krivicna prijava ne postoji"))
+		return
+	}
+
+	trenutni := krivicnaPrijava.TrenutniStatus()
+	if !data.DozvoljenaPromenaStatusa(trenutni, promena.Status) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(fmt.Sprintf("Prelaz iz statusa %s u status %s nije dozvoljen", trenutni, promena.Status)))
+		return
+	}
+
+	promena.Datum = primitive.NewDateTimeFromTime(time.Now())
+	err = h.granicnaPolicijaRepo.PromeniStatusKrivicnePrijave(ctx, id, trenutni, promena)
+	if err == mongo.ErrNoDocuments {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("Status krivicne prijave je u medjuvremenu promenjen"))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Greska prilikom promene statusa krivicne prijave"))
+		return
+	}
+
+	krivicnaPrijava.Status = promena.Status
+	krivicnaPrijava.IstorijaStatusa = append(krivicnaPrijava.IstorijaStatusa, promena)
+	w.WriteHeader(http.StatusOK)
+	krivicnaPrijava.ToJSON(w)
+}
+
+// UpdateCarinskaDeklaracijaHandler upisuje deklarisanu robu i gotovinu i primenjuje pravila o limitima.
+// Prelaz se upucuje na pregled ako je bilo koji limit prekoracen.
+func (h *GranicnaPolicijaHandler) UpdateCarinskaDeklaracijaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	prelazId, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id prelaza nije procitan"))
+		return
+	}
+
+	var deklaracija data.CarinskaDeklaracija
+	if err := json.NewDecoder(r.Body).Decode(&deklaracija); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	for _, stavka := range deklaracija.Stavke {
+		if stavka.Kategorija == "" || stavka.Kolicina < 0 || stavka.Vrednost < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Stavka deklaracije mora imati kategoriju i nenegativnu kolicinu i vrednost"))
+			return
+		}
+	}
+
+	deklaracija.Datum = primitive.NewDateTimeFromTime(time.Now())
+	deklaracija.RazloziPregleda = data.ProveriDeklaraciju(&deklaracija)
+
+	azuriran, err := h.granicnaPolicijaRepo.UpdateCarinskaDeklaracija(ctx, prelazId, &deklaracija)
+	if err == mongo.ErrNoDocuments {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Prelaz ne postoji"))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Greska prilikom cuvanja carinske deklaracije"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	azuriran.ToJSON(w)
+}
+
+// CreateZaplenaHandler evidentira zaplenu robe na prelazu i, ako je zahtev sadrzi, podnosi krivicnu prijavu
+func (h *GranicnaPolicijaHandler) CreateZaplenaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	prelazId, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id prelaza nije procitan"))
+		return
+	}
+
+	claims := helper.ExtractClaims(r)
+	sluzbenikId, err := primitive.ObjectIDFromHex(claims["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Id sluzbenika nije procitan"))
+		return
+	}
+
+	prelaz, err := h.granicnaPolicijaRepo.GetPrelazByID(ctx, prelazId)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Prelaz ne postoji"))
+		return
+	}
+
+	var zahtev data.ZahtevZaZaplenu
+	if err := json.NewDecoder(r.Body).Decode(&zahtev); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	zaplena := zahtev.Zaplena
+	zaplena.ID = primitive.NewObjectID()
+	zaplena.Datum = primitive.NewDateTimeFromTime(time.Now())
+	zaplena.IdSluzbenika = sluzbenikId
+
+	// Zaplena se upisuje pre prijave koja je na nju vezana, pa se zaplena uklanja ako prijava ne uspe
+	if zahtev.KrivicnaPrijava != nil {
+		zahtev.KrivicnaPrijava.ID = primitive.NewObjectID()
+		zaplena.KrivicnaPrijavaId = zahtev.KrivicnaPrijava.ID
+	}
+
+	err = h.granicnaPolicijaRepo.DodajZaplenu(ctx, prelazId, &zaplena)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Greska prilikom evidentiranja zaplene"))
+		return
+	}
+
+	if zahtev.KrivicnaPrijava != nil {
+		if zahtev.KrivicnaPrijava.Opis == "" {
+			zahtev.KrivicnaPrijava.Opis = "Zaplena: " + zaplena.Opis
+		}
+		zahtev.KrivicnaPrijava.IdSluzbenika = sluzbenikId
+		if err := h.podnesiKrivicnuPrijavu(ctx, prelaz, zahtev.KrivicnaPrijava); err != nil {
+			if err := h.granicnaPolicijaRepo.UkloniZaplenu(ctx, prelazId, zaplena.ID); err != nil {
+				h.logger.Println("Greska prilikom ponistavanja zaplene bez krivicne prijave:", err)
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("Greska prilikom kreiranja krivicne prijave"))
+			return
+		}
+	}
+
+	_, err = h.evidentirajIncident(ctx, prelaz, data.Incident{
+		Tip:          data.ZAPLENA,
+		Opis:         "Zaplena: " + zaplena.Opis,
+		IdSluzbenika: sluzbenikId,
+	})
+	if err != nil {
+		h.logger.Println("Greska prilikom evidentiranja incidenta zaplene:", err)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(zaplena)
+}
+
+func (h *GranicnaPolicijaHandler) CreateGranicniPrelazHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	var granicniPrelaz data.GranicniPrelaz
+	if err := json.NewDecoder(r.Body).Decode(&granicniPrelaz); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	if granicniPrelaz.Sifra == "" || granicniPrelaz.Naziv == "" || granicniPrelaz.SusednaDrzava == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Sifra, naziv i susedna drzava su obavezni"))
+		return
+	}
+
+	if postojeci, _ := h.granicnaPolicijaRepo.GetGranicniPrelazBySifra(ctx, granicniPrelaz.Sifra); postojeci != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("Granicni prelaz sa datom sifrom vec postoji"))
+		return
+	}
+
+	granicniPrelaz.ID = primitive.NewObjectID()
+
+	err := h.granicnaPolicijaRepo.CreateGranicniPrelaz(ctx, &granicniPrelaz)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Greska prilikom kreiranja granicnog prelaza"))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	granicniPrelaz.ToJSON(w)
+}
+
+func (h *GranicnaPolicijaHandler) GetGranicniPrelaziHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	granicniPrelazi, err := h.granicnaPolicijaRepo.GetGranicniPrelazi(ctx)
+	if err != nil {
+		http.Error(w, "Error getting Granicni prelazi", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(granicniPrelazi)
+}
+
+func (h *GranicnaPolicijaHandler) GetSumnjivaLicaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	sumnjivaLica, err := h.granicnaPolicijaRepo.GetSumnjivaLica(ctx)
+	if err != nil {
+		http.Error(w, "Error getting Sumnjiva lica", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sumnjivaLica)
+}
+
+func (h *GranicnaPolicijaHandler) GetPrelaziHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	prelazi, err := h.granicnaPolicijaRepo.GetPrelazi(ctx)
+	if err != nil {
+		http.Error(w, "Error getting Prelazi", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prelazi)
+}
+
+func (h *GranicnaPolicijaHandler) GetIstorijaPutnikaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	prelazi, err := h.granicnaPolicijaRepo.GetPrelaziByJMBG(ctx, vars["jmbg"])
+	if err != nil {
+		http.Error(w, "Error getting Prelazi", http.StatusInternalServerError)
+		return
+	}
+
+	istorija := data.NapraviIstorijuPutnika(prelazi, time.Now())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(istorija)
+}
+
+func (h *GranicnaPolicijaHandler) GetIstorijaPutnikaPoPasosuHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	vars := mux.Vars(r)
+	prelazi, err := h.granicnaPolicijaRepo.GetPrelaziByBrojPasosa(ctx, vars["brojPasosa"])
+	if err != nil {
+		http.Error(w, "Error getting Prelazi", http.StatusInternalServerError)
+		return
+	}
+
+	istorija := data.NapraviIstorijuPutnika(prelazi, time.Now())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(istorija)
+}
+
+func (h *GranicnaPolicijaHandler) GetOdbijeniPrelaziHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	query := r.URL.Query()
+	filter := data.FilterOdbijenihPrelaza{
+		JMBG:       query.Get("jmbg"),
+		BrojPasosa: query.Get("brojPasosa"),
+		Razlog:     data.RazlogOdbijanja(query.Get("razlog")),
+	}
+
+	var err error
+	if od := query.Get("od"); od != "" {
+		if filter.Od, err = time.Parse("2006-01-02", od); err != nil {
+			http.Error(w, "Datum od nije u formatu YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	if do := query.Get("do"); do != "" {
+		if filter.Do, err = time.Parse("2006-01-02", do); err != nil {
+			http.Error(w, "Datum do nije u formatu YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		filter.Do = filter.Do.AddDate(0, 0, 1)
+	}
+
+	prelazi, err := h.granicnaPolicijaRepo.GetOdbijeniPrelazi(ctx, filter)
+	if err != nil {
+		http.Error(w, "Error getting Odbijeni prelazi", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prelazi)
+}
+
+// GetEvidencijaOdbijanjaHandler vraca evidenciju odbijanja, sa filterima po sluzbeniku, granicnom prelazu,
+// razlogu i periodu (od i do ukljucivo)
+func (h *GranicnaPolicijaHandler) GetEvidencijaOdbijanjaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	query := r.URL.Query()
+	filter := data.FilterEvidencijeOdbijanja{
+		Razlog: data.RazlogOdbijanja(query.Get("razlog")),
+	}
+
+	var err error
+	if id := query.Get("sluzbenikId"); id != "" {
+		if filter.IdSluzbenika, err = primitive.ObjectIDFromHex(id); err != nil {
+			http.Error(w, "Id sluzbenika nije validan", http.StatusBadRequest)
+			return
+		}
+	}
+	if id := query.Get("granicniPrelazId"); id != "" {
+		if filter.GranicniPrelazId, err = primitive.ObjectIDFromHex(id); err != nil {
+			http.Error(w, "Id granicnog prelaza nije validan", http.StatusBadRequest)
+			return
+		}
+	}
+	if od := query.Get("od"); od != "" {
+		if filter.Od, err = time.Parse("2006-01-02", od); err != nil {
+			http.Error(w, "Datum od nije u formatu YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	if do := query.Get("do"); do != "" {
+		if filter.Do, err = time.Parse("2006-01-02", do); err != nil {
+			http.Error(w, "Datum do nije u formatu YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		filter.Do = filter.Do.AddDate(0, 0, 1)
+	}
+
+	zapisi, err := h.granicnaPolicijaRepo.GetEvidencijaOdbijanja(ctx, filter)
+	if err != nil {
+		http.Error(w, "Greska prilikom dobavljanja evidencije odbijanja", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(zapisi)
+}
+
+// GetStatistikaPrelazaHandler vraca broj prelaza po danu, satu, drzavljanstvu, svrsi putovanja ili granicnom prelazu.
+// Period se zadaje parametrima od i do (ukljucivo), a format=csv vraca rezultat kao CSV.
+func (h *GranicnaPolicijaHandler) GetStatistikaPrelazaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 8*time.Second)
+	defer cancel()
+
+	query := r.URL.Query()
+	danas := data.PocetakDana(time.Now())
+	od := danas.AddDate(0, 0, -30)
+	do := danas
+
+	var err error
+	if vrednost := query.Get("od"); vrednost != "" {
+		if od, err = time.ParseInLocation("2006-01-02", vrednost, data.LokalnaZona); err != nil {
+			http.Error(w, "Datum od nije u formatu YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	if vrednost := query.Get("do"); vrednost != "" {
+		if do, err = time.ParseInLocation("2006-01-02", vrednost, data.LokalnaZona); err != nil {
+			http.Error(w, "Datum do nije u formatu YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	if do.Before(od) {
+		http.Error(w, "Datum do je pre datuma od", http.StatusBadRequest)
+		return
+	}
+	if do.After(od.AddDate(0, 0, data.MaxPeriodStatistikeDana-1)) {
+		http.Error(w, fmt.Sprintf("Period statistike moze biti najduze %d dana", data.MaxPeriodStatistikeDana), http.StatusBadRequest)
+		return
+	}
+
+	grupisanje := data.GrupisanjeStatistike(query.Get("grupisanje"))
+	if grupisanje == "" {
+		grupisanje = data.PO_DANU
+	}
+	if !data.PodrzanoGrupisanje(grupisanje) {
+		http.Error(w, "Nepodrzano grupisanje", http.StatusBadRequest)
+		return
+	}
+
+	stavke, err := h.granicnaPolicijaRepo.GetStatistikaPrelaza(ctx, od, do.AddDate(0, 0, 1), grupisanje)
+	if err != nil {
+		http.Error(w, "Error getting Statistika prelaza", http.StatusInternalServerError)
+		return
+	}
+
+	if grupisanje == data.PO_GRANICNOM_PRELAZU {
+		granicniPrelazi, err := h.granicnaPolicijaRepo.GetGranicniPrelazi(ctx)
+		if err != nil {
+			http.Error(w, "Error getting Granicni prelazi", http.StatusInternalServerError)
+			return
+		}
+		nazivi := make(map[string]string, len(granicniPrelazi))
+		for _, granicniPrelaz := range granicniPrelazi {
+			nazivi[granicniPrelaz.ID.Hex()] = granicniPrelaz.Naziv
+		}
+		for i := range stavke {
+			stavke[i].Naziv = nazivi[stavke[i].Kljuc]
+		}
+	}
+
+	if query.Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"statistika_%s_%s_%s.csv\"", grupisanje, od.Format("2006-01-02"), do.Format("2006-01-02")))
+
+		writer := csv.NewWriter(w)
+		writer.Write([]string{string(grupisanje), "naziv", "ukupno", "ulazi", "izlazi", "odbijeni"})
+		for _, stavka := range stavke {
+			writer.Write([]string{
+				stavka.Kljuc,
+				stavka.Naziv,
+				strconv.Itoa(stavka.Ukupno),
+				strconv.Itoa(stavka.Ulazi),
+				strconv.Itoa(stavka.Izlazi),
+				strconv.Itoa(stavka.Odbijeni),
+			})
+		}
+		writer.Flush()
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stavke)
+}
+
+func (h *GranicnaPolicijaHandler) GetKrivicnaPrijavaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Id krivicne prijave nije procitan", http.StatusBadRequest)
+		return
+	}
+
+	krivicnaPrijava, err := h.granicnaPolicijaRepo.GetKrivicnaPrijavaByID(ctx, id)
+	if err == mongo.ErrNoDocuments {
+		http.Error(w, "Krivicna prijava ne postoji", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error getting Krivicna prijava", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	krivicnaPrijava.ToJSON(w)
+}
+
+// PretraziKrivicnePrijaveHandler filtrira prijave po statusu, periodu (od, do), JMBG-u osumnjicenog
+// i granicnom prelazu, uz stranicenje parametrima stranica i velicina
+func (h *GranicnaPolicijaHandler) PretraziKrivicnePrijaveHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	query := r.URL.Query()
+	filter := data.FilterKrivicnihPrijava{
+		Status:   data.StatusPrijave(query.Get("status")),
+		JMBG:     query.Get("jmbg"),
+		Stranica: 1,
+		Velicina: podrazumevanaVelicinaStranice,
+	}
+
+	var err error
+	if od := query.Get("od"); od != "" {
+		if filter.Od, err = time.Parse("2006-01-02", od); err != nil {
+			http.Error(w, "Datum od nije u formatu YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	if do := query.Get("do"); do != "" {
+		if filter.Do, err = time.Parse("2006-01-02", do); err != nil {
+			http.Error(w, "Datum do nije u formatu YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		filter.Do = filter.Do.AddDate(0, 0, 1)
+	}
+	if granicniPrelazId := query.Get("granicniPrelazId"); granicniPrelazId != "" {
+		if filter.GranicniPrelazId, err = primitive.ObjectIDFromHex(granicniPrelazId); err != nil {
+			http.Error(w, "Id granicnog prelaza nije ispravan", http.StatusBadRequest)
+			return
+		}
+	}
+	if stranica := query.Get("stranica"); stranica != "" {
+		if filter.Stranica, err = strconv.ParseInt(stranica, 10, 64); err != nil || filter.Stranica < 1 {
+			http.Error(w, "Stranica mora biti pozitivan broj", http.StatusBadRequest)
+			return
+		}
+	}
+	if velicina := query.Get("velicina"); velicina != "" {
+		if filter.Velicina, err = strconv.ParseInt(velicina, 10, 64); err != nil || filter.Velicina < 1 || filter.Velicina > maxVelicinaStranice {
+			http.Error(w, fmt.Sprintf("Velicina stranice mora biti izmedju 1 i %d", maxVelicinaStranice), http.StatusBadRequest)
+			return
+		}
+	}
+
+	krivicnePrijave, ukupno, err := h.granicnaPolicijaRepo.PretraziKrivicnePrijave(ctx, filter)
+	if err != nil {
+		http.Error(w, "Error getting Krivicne prijave", http.StatusInternalServerError)
+		return
+	}
+
+	stranica := data.StranicaKrivicnihPrijava{
+		Prijave:    krivicnePrijave,
+		Ukupno:     ukupno,
+		Stranica:   filter.Stranica,
+		Velicina:   filter.Velicina,
+		BrojStrana: (ukupno + filter.Velicina - 1) / filter.Velicina,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stranica)
 }
 
 func (h *GranicnaPolicijaHandler) GetKrivicnePrijaveHandler(w http.ResponseWriter, r *http.Request) {
@@ -53,6 +53,22 @@ func main() {
 	}
 	defer store.DisconnectMongo(timeoutContext)
 	store.Ping()
+	if err := store.MigrirajSumnjivaLica(timeoutContext); err != nil {
+		logger.Println("Greska prilikom migracije sumnjivih lica:", err)
+	}
+	if err := store.KreirajIndekseSumnjivihLica(timeoutContext); err != nil {
+		logger.Fatal("Greska prilikom kreiranja indeksa sumnjivih lica: ", err)
+	}
+	if err := store.KreirajIndekseEvidencijeOdbijanja(timeoutContext); err != nil {
+		logger.Fatal("Greska prilikom kreiranja evidencije odbijanja: ", err)
+	}
+	if err := store.MigrirajDrzavljanstva(timeoutContext); err != nil {
+		logger.Fatal("Greska prilikom migracije drzavljanstava: ", err)
+	}
+	if err := store.KreirajIndekseStatistike(timeoutContext); err != nil {
+		logger.Fatal("Greska prilikom kreiranja indeksa dnevne statistike: ", err)
+	}
+	go store.PokreniDnevnuStatistiku(time.Hour)
 
 	granicnaPolicijaHandler := handlers.NewGranicnaPolicijaHandler(logger, store, tracer)
 	//Initialize the router and add a middleware for all the requests
@@ -77,6 +93,13 @@ func main() {
 
 	dobaviSumnjivaLica := router.Methods(http.MethodGet).Subrouter()
 	dobaviSumnjivaLica.HandleFunc("/sumnjivo-lice/all", granicnaPolicijaHandler.GetSumnjivaLicaHandler)
+	dobaviSumnjivaLica.HandleFunc("/sumnjivo-lice/{id}", granicnaPolicijaHandler.GetSumnjivoLiceHandler)
+
+	promeniOznakuSumnjivogLica := router.Methods(http.MethodPut).Subrouter()
+	promeniOznakuSumnjivogLica.HandleFunc("/sumnjivo-lice/{id}/oznaka", granicnaPolicijaHandler.PromeniOznakuSumnjivogLicaHandler)
+
+	potvrdiPoklapanje := router.Methods(http.MethodPut).Subrouter()
+	potvrdiPoklapanje.HandleFunc("/sumnjivo-lice/{id}/prelaz/{prelazId}", granicnaPolicijaHandler.PotvrdiPoklapanjeHandler)
 
 	dobaviPrelaze := router.Methods(http.MethodGet).Subrouter()
 	dobaviPrelaze.HandleFunc("/prelaz/all", granicnaPolicijaHandler.GetPrelaziHandler)
@@ -84,6 +107,57 @@ func main() {
 	dobaviKrivicnePrijave := router.Methods(http.MethodGet).Subrouter()
 	dobaviKrivicnePrijave.HandleFunc("/krivicna-prijava/all", granicnaPolicijaHandler.GetKrivicnePrijaveHandler)
 
+	kreirajGranicniPrelaz := router.Methods(http.MethodPost).Subrouter()
+	kreirajGranicniPrelaz.HandleFunc("/granicni-prelaz/new", granicnaPolicijaHandler.CreateGranicniPrelazHandler)
+
+	dobaviGranicnePrelaze := router.Methods(http.MethodGet).Subrouter()
+	dobaviGranicnePrelaze.HandleFunc("/granicni-prelaz/all", granicnaPolicijaHandler.GetGranicniPrelaziHandler)
+
+	dobaviIstorijuPutnika := router.Methods(http.MethodGet).Subrouter()
+	dobaviIstorijuPutnika.HandleFunc("/prelaz/putnik/{jmbg}", granicnaPolicijaHandler.GetIstorijaPutnikaHandler)
+
+	dobaviIstorijuPutnikaPoPasosu := router.Methods(http.MethodGet).Subrouter()
+	dobaviIstorijuPutnikaPoPasosu.HandleFunc("/prelaz/pasos/{brojPasosa}", granicnaPolicijaHandler.GetIstorijaPutnikaPoPasosuHandler)
+
+	dobaviMojeAlarme := router.Methods(http.MethodGet).Subrouter()
+	dobaviMojeAlarme.HandleFunc("/alarm/moji", granicnaPolicijaHandler.GetMojiAlarmiHandler)
+
+	oznaciAlarmProcitan := router.Methods(http.MethodPut).Subrouter()
+	oznaciAlarmProcitan.HandleFunc("/alarm/{id}/procitan", granicnaPolicijaHandler.OznaciAlarmProcitanHandler)
+
+	dobaviOdbijenePrelaze := router.Methods(http.MethodGet).Subrouter()
+	dobaviOdbijenePrelaze.HandleFunc("/prelaz/odbijeni", granicnaPolicijaHandler.GetOdbijeniPrelaziHandler)
+
+	dobaviEvidencijuOdbijanja := router.Methods(http.MethodGet).Subrouter()
+	dobaviEvidencijuOdbijanja.HandleFunc("/prelaz/odbijeni/evidencija", granicnaPolicijaHandler.GetEvidencijaOdbijanjaHandler)
+
+	dobaviStatistikuPrelaza := router.Methods(http.MethodGet).Subrouter()
+	dobaviStatistikuPrelaza.HandleFunc("/statistika/prelazi", granicnaPolicijaHandler.GetStatistikaPrelazaHandler)
+
+	kreirajGrupniPrelaz := router.Methods(http.MethodPost).Subrouter()
+	kreirajGrupniPrelaz.HandleFunc("/grupni-prelaz/new", granicnaPolicijaHandler.CreateGrupniPrelazHandler)
+
+	dobaviGrupniPrelaz := router.Methods(http.MethodGet).Subrouter()
+	dobaviGrupniPrelaz.HandleFunc("/grupni-prelaz/{id}", granicnaPolicijaHandler.GetGrupniPrelazHandler)
+
+	azurirajCarinskuDeklaraciju := router.Methods(http.MethodPut).Subrouter()
+	azurirajCarinskuDeklaraciju.HandleFunc("/prelaz/{id}/deklaracija", granicnaPolicijaHandler.UpdateCarinskaDeklaracijaHandler)
+
+	kreirajZaplenu := router.Methods(http.MethodPost).Subrouter()
+	kreirajZaplenu.HandleFunc("/prelaz/{id}/zaplena", granicnaPolicijaHandler.CreateZaplenaHandler)
+
+	azurirajKrivicnuPrijavu := router.Methods(http.MethodPut).Subrouter()
+	azurirajKrivicnuPrijavu.HandleFunc("/krivicna-prijava/{id}", granicnaPolicijaHandler.UpdateKrivicnaPrijavaHandler)
+
+	promeniStatusKrivicnePrijave := router.Methods(http.MethodPut).Subrouter()
+	promeniStatusKrivicnePrijave.HandleFunc("/krivicna-prijava/{id}/status", granicnaPolicijaHandler.PromeniStatusKrivicnePrijaveHandler)
+
+	pretraziKrivicnePrijave := router.Methods(http.MethodGet).Subrouter()
+	pretraziKrivicnePrijave.HandleFunc("/krivicna-prijava/pretraga", granicnaPolicijaHandler.PretraziKrivicnePrijaveHandler)
+
+	dobaviKrivicnuPrijavu := router.Methods(http.MethodGet).Subrouter()
+	dobaviKrivicnuPrijavu.HandleFunc("/krivicna-prijava/{id}", granicnaPolicijaHandler.GetKrivicnaPrijavaHandler)
+
 	//Initialize the server
 	server := http.Server{
 		Addr:         ":" + port,
@@ -54,7 +54,7 @@ func extractUserType(r *http.Request) (string, error) {
 	}
 
 	claims := extractClaims(token)
-	return claims["userType"], nil
+	return claims["rola"], nil
 }
 
 func extractClaims(token *jwt.Token) map[string]string {
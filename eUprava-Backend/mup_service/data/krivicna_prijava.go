@@ -0,0 +1,18 @@
+package data
+
+import "prijave"
+
+// Statusi krivicne prijave i dozvoljeni prelazi su zajednicki za sve servise
+type StatusPrijave = prijave.StatusPrijave
+
+const (
+	PODNETA                = prijave.PODNETA
+	PRIMLJENA_U_TUZILASTVU = prijave.PRIMLJENA_U_TUZILASTVU
+	ODBACENA               = prijave.ODBACENA
+	U_POSTUPKU             = prijave.U_POSTUPKU
+	ZAVRSENA               = prijave.ZAVRSENA
+)
+
+func DozvoljenaPromenaStatusa(iz StatusPrijave, u StatusPrijave) bool {
+	return prijave.DozvoljenaPromenaStatusa(iz, u)
+}
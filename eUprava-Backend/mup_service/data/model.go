@@ -25,6 +25,8 @@ const (
 	Tuzioc            = "Tuzioc"
 	Istrazitelj       = "Istrazitelj"
 	Sudija            = "Sudija"
+	PredsednikSuda    = "PredsednikSuda"
+	GlavniTuzilac     = "GlavniTuzilac"
 )
 
 type Pol string
@@ -51,6 +53,13 @@ const (
 	ZAVRSEN = "ZAVRSEN"
 )
 
+type TipSlike string
+
+const (
+	FOTOGRAFIJA = "fotografija"
+	POTPIS      = "potpis"
+)
+
 type Korisnik struct {
 	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Ime           string             `bson:"ime,omitempty" json:"ime"`
@@ -80,6 +89,8 @@ type LicnaKarta struct {
 	Pol            Pol                `bson:"pol,omitempty" json:"pol"`
 	JMBG           string             `bson:"jmbg,omitempty" json:"jmbg,omitempty"`
 	BrojLicneKarte string             `bson:"brojLicneKarte,omitempty" json:"brojLicneKarte,omitempty"`
+	FotografijaId  primitive.ObjectID `bson:"fotografijaId,omitempty" json:"fotografijaId,omitempty"`
+	PotpisId       primitive.ObjectID `bson:"potpisId,omitempty" json:"potpisId,omitempty"`
 }
 
 type Pasos struct {
@@ -88,6 +99,8 @@ type Pasos struct {
 	Pol           Pol                `bson:"pol,omitempty" json:"pol"`
 	Drzavljanstvo string             `bson:"drzavljanstvo,omitempty" json:"drzavljanstvo"`
 	BrojPasosa    string             `bson:"brojPasosa,omitempty" json:"brojPasosa,omitempty"`
+	FotografijaId primitive.ObjectID `bson:"fotografijaId,omitempty" json:"fotografijaId,omitempty"`
+	PotpisId      primitive.ObjectID `bson:"potpisId,omitempty" json:"potpisId,omitempty"`
 }
 
 type Vozacka struct {
@@ -134,10 +147,15 @@ type NalogZaPracenje struct {
 	Datum     primitive.DateTime `bson:"datum,omitempty" json:"datum"`
 }
 
+// SumnjivoLice je zapis granicne policije o osobi, nalog za pracenje se vodi samo za osobe sa JMBG-om
 type SumnjivoLice struct {
-	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Prelaz Prelaz             `bson:"prelaz,omitempty" json:"prelaz"`
-	Opis   string             `bson:"opis,omitempty" json:"opis"`
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	JMBG          string             `bson:"jmbg,omitempty" json:"jmbg,omitempty"`
+	BrojeviPasosa []string           `bson:"brojeviPasosa,omitempty" json:"brojeviPasosa,omitempty"`
+	Ime           string             `bson:"ime,omitempty" json:"ime"`
+	Prezime       string             `bson:"prezime,omitempty" json:"prezime"`
+	Opis          string             `bson:"opis,omitempty" json:"opis"`
+	Oznaceno      bool               `bson:"oznaceno" json:"oznaceno"`
 }
 
 type PodaciZaValidaciju struct {
@@ -149,8 +167,71 @@ type PodaciZaValidaciju struct {
 	Drzavljanstvo  string `bson:"drzavljanstvo,omitempty" json:"drzavljanstvo"`
 }
 
+type Slika struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	DokumentId  primitive.ObjectID `bson:"dokumentId,omitempty" json:"dokumentId"`
+	KorisnikId  primitive.ObjectID `bson:"korisnikId,omitempty" json:"korisnikId"`
+	Tip         TipSlike           `bson:"tip,omitempty" json:"tip"`
+	ContentType string             `bson:"contentType,omitempty" json:"contentType"`
+	Sadrzaj     []byte             `bson:"-" json:"-"`
+}
+
+type IzvorPrijave string
+
+const (
+	IZVOR_MUP               = "MUP"
+	IZVOR_GRANICNA_POLICIJA = "GRANICNA_POLICIJA"
+)
+
+// KrivicnaPrijava koju podnosi policija, nezavisno od granicnog prelaza.
+// Sema je ista kao za prijave granicne policije kako bi ih tuzilastvo obradjivalo zajedno.
+type KrivicnaPrijava struct {
+	ID                  primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Datum               primitive.DateTime  `bson:"datum,omitempty" json:"datum"`
+	Opis                string              `bson:"opis,omitempty" json:"opis"`
+	Izvor               IzvorPrijave        `bson:"izvor,omitempty" json:"izvor"`
+	Prelaz              *Prelaz             `bson:"prelaz,omitempty" json:"prelaz,omitempty"`
+	Status              StatusPrijave       `bson:"status,omitempty" json:"status"`
+	IdSluzbenika        primitive.ObjectID  `bson:"idSluzbenika,omitempty" json:"idSluzbenika"`
+	PravnaKvalifikacija PravnaKvalifikacija `bson:"pravnaKvalifikacija,omitempty" json:"pravnaKvalifikacija"`
+	Osumnjiceni         Osumnjiceni         `bson:"osumnjiceni,omitempty" json:"osumnjiceni"`
+	Svedoci             []Svedok            `bson:"svedoci,omitempty" json:"svedoci"`
+	IstorijaStatusa     []PromenaStatusa    `bson:"istorijaStatusa,omitempty" json:"istorijaStatusa"`
+}
+
+type PravnaKvalifikacija struct {
+	Zakon string `bson:"zakon,omitempty" json:"zakon"`
+	Clan  string `bson:"clan,omitempty" json:"clan"`
+	Stav  string `bson:"stav,omitempty" json:"stav,omitempty"`
+	Naziv string `bson:"naziv,omitempty" json:"naziv"`
+}
+
+type Osumnjiceni struct {
+	Ime           string `bson:"ime,omitempty" json:"ime"`
+	Prezime       string `bson:"prezime,omitempty" json:"prezime"`
+	JMBG          string `bson:"jmbg,omitempty" json:"jmbg,omitempty"`
+	BrojPasosa    string `bson:"brojPasosa,omitempty" json:"brojPasosa,omitempty"`
+	Drzavljanstvo string `bson:"drzavljanstvo,omitempty" json:"drzavljanstvo"`
+	Adresa        string `bson:"adresa,omitempty" json:"adresa,omitempty"`
+}
+
+type Svedok struct {
+	Ime     string `bson:"ime,omitempty" json:"ime"`
+	Prezime string `bson:"prezime,omitempty" json:"prezime"`
+	JMBG    string `bson:"jmbg,omitempty" json:"jmbg,omitempty"`
+	Kontakt string `bson:"kontakt,omitempty" json:"kontakt,omitempty"`
+	Izjava  string `bson:"izjava,omitempty" json:"izjava,omitempty"`
+}
+
+type PromenaStatusa struct {
+	Status   StatusPrijave      `bson:"status" json:"status"`
+	Datum    primitive.DateTime `bson:"datum" json:"datum"`
+	Napomena string             `bson:"napomena,omitempty" json:"napomena,omitempty"`
+}
+
 type Korisnici []*Korisnik
 type NaloziZaPracenje []*NalogZaPracenje
+type KrivicnePrijave []*KrivicnaPrijava
 
 //TODO: uraditi za ostale entitete ToJSON i FromJSON
 
@@ -193,3 +274,18 @@ func (o *NaloziZaPracenje) FromJSON(r io.Reader) error {
 	d := json.NewDecoder(r)
 	return d.Decode(o)
 }
+
+func (o *KrivicnaPrijava) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
+
+func (o *KrivicnaPrijava) FromJSON(r io.Reader) error {
+	d := json.NewDecoder(r)
+	return d.Decode(o)
+}
+
+func (o *KrivicnePrijave) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
@@ -1,11 +1,13 @@
 package data
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"log"
@@ -18,6 +20,8 @@ const (
 	DATABASE                  = "mup"
 	COLLECTIONKORISNICI       = "korisnici"
 	COLLECTIONNALOGZAPRACENJE = "nalogZaPracenje"
+	BUCKETSLIKE               = "slike"
+	COLLECTIONKRIVICNEPRIJAVE = "krivicnePrijave"
 )
 
 type MupRepo struct {
@@ -275,3 +279,142 @@ func (rr *MupRepo) DobaviJmbgKorisnika(ctx context.Context, id primitive.ObjectI
 
 	return korisnik.LicnaKarta.JMBG, nil
 }
+
+func (rr *MupRepo) DobaviKorisnikaPoDokumentu(ctx context.Context, dokumentId primitive.ObjectID) (*Korisnik, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"licnaKarta._id": dokumentId},
+		{"pasos._id": dokumentId},
+	}}
+	var korisnik Korisnik
+
+	err := rr.tabela.Collection(COLLECTIONKORISNICI).FindOne(ctx, filter).Decode(&korisnik)
+	if err != nil {
+		return nil, err
+	}
+
+	return &korisnik, nil
+}
+
+func (rr *MupRepo) slikeBucket() (*gridfs.Bucket, error) {
+	return gridfs.NewBucket(rr.tabela, options.GridFSBucket().SetName(BUCKETSLIKE))
+}
+
+// Slika se cuva u GridFS, a podaci o dokumentu kojem pripada u metadata polju fajla
+func (rr *MupRepo) SacuvajSliku(ctx context.Context, slika *Slika) error {
+	bucket, err := rr.slikeBucket()
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		bucket.SetWriteDeadline(deadline)
+	}
+
+	naziv := fmt.Sprintf("%s_%s", slika.DokumentId.Hex(), slika.Tip)
+	opts := options.GridFSUpload().SetMetadata(slika)
+
+	id, err := bucket.UploadFromStream(naziv, bytes.NewReader(slika.Sadrzaj), opts)
+	if err != nil {
+		log.Println("Greska prilikom cuvanja slike")
+		return err
+	}
+	slika.ID = id
+	return nil
+}
+
+func (rr *MupRepo) DobaviSliku(ctx context.Context, id primitive.ObjectID) (*Slika, error) {
+	bucket, err := rr.slikeBucket()
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := bucket.FindContext(ctx, bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	var fajl struct {
+		Metadata Slika `bson:"metadata"`
+	}
+	if err := cursor.Decode(&fajl); err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		bucket.SetReadDeadline(deadline)
+	}
+
+	var sadrzaj bytes.Buffer
+	if _, err := bucket.DownloadToStream(id, &sadrzaj); err != nil {
+		return nil, err
+	}
+
+	slika := fajl.Metadata
+	slika.ID = id
+	slika.Sadrzaj = sadrzaj.Bytes()
+	return &slika, nil
+}
+
+func (rr *MupRepo) ObrisiSliku(ctx context.Context, id primitive.ObjectID) error {
+	bucket, err := rr.slikeBucket()
+	if err != nil {
+		return err
+	}
+
+	return bucket.DeleteContext(ctx, id)
+}
+
+func (rr *MupRepo) DodajKrivicnuPrijavu(ctx context.Context, prijava *KrivicnaPrijava) error {
+	_, err := rr.tabela.Collection(COLLECTIONKRIVICNEPRIJAVE).InsertOne(ctx, prijava)
+	if err != nil {
+		log.Println("Greska prilikom dodavanja krivicne prijave")
+		return err
+	}
+	return nil
+}
+
+func (rr *MupRepo) DobaviKrivicnePrijave(ctx context.Context) (KrivicnePrijave, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "datum", Value: -1}})
+	cursor, err := rr.tabela.Collection(COLLECTIONKRIVICNEPRIJAVE).Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	prijave := KrivicnePrijave{}
+	if err := cursor.All(ctx, &prijave); err != nil {
+		return nil, err
+	}
+	return prijave, nil
+}
+
+func (rr *MupRepo) DobaviKrivicnuPrijavu(ctx context.Context, id primitive.ObjectID) (*KrivicnaPrijava, error) {
+	var prijava KrivicnaPrijava
+	err := rr.tabela.Collection(COLLECTIONKRIVICNEPRIJAVE).FindOne(ctx, bson.M{"_id": id}).Decode(&prijava)
+	if err != nil {
+		return nil, err
+	}
+	return &prijava, nil
+}
+
+// PromeniStatusKrivicnePrijave menja status samo ako je prijava i dalje u statusu iz kog se prelazi
+func (rr *MupRepo) PromeniStatusKrivicnePrijave(ctx context.Context, id primitive.ObjectID, iz StatusPrijave, promena PromenaStatusa) error {
+	filter := bson.M{"_id": id, "status": iz}
+	update := bson.M{
+		"$set":  bson.M{"status": promena.Status},
+		"$push": bson.M{"istorijaStatusa": promena},
+	}
+	rezultat, err := rr.tabela.Collection(COLLECTIONKRIVICNEPRIJAVE).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -2,16 +2,19 @@ package handlers
 
 import (
 	"context"
+	"drzavljanstvo"
 	"encoding/json"
 	"fmt"
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"io/ioutil"
 	"log"
 	"math/rand"
 	"mup_service/data"
+	"mup_service/helper"
 	"net/http"
 	"os"
 	"time"
@@ -386,6 +389,7 @@ func (h *MupHandler) KreirajPasos(writer http.ResponseWriter, req *http.Request)
 	}
 
 	pasos.ID = primitive.NewObjectID()
+	pasos.Drzavljanstvo = drzavljanstvo.Normalizuj(pasos.Drzavljanstvo)
 	pasos.Dokument.ID = primitive.NewObjectID()
 	pasos.Dokument.Izdato = primitive.NewDateTimeFromTime(time.Now().Truncate(24 * time.Hour))
 
@@ -496,7 +500,7 @@ func (h *MupHandler) ValidirajDokumente(writer http.ResponseWriter, req *http.Re
 			writer.WriteHeader(http.StatusForbidden)
 			writer.Write([]byte("Broj pasosa nije validan"))
 			return
-		} else if korisnik.Pasos.Drzavljanstvo != podaciZaValidaciju.Drzavljanstvo {
+		} else if drzavljanstvo.Normalizuj(korisnik.Pasos.Drzavljanstvo) != drzavljanstvo.Normalizuj(podaciZaValidaciju.Drzavljanstvo) {
 			span.SetStatus(codes.Error, "Drzavljanstvo nije validno")
 			writer.WriteHeader(http.StatusForbidden)
 			writer.Write([]byte("Drzavljanstvo nije validno"))
@@ -537,10 +541,14 @@ func (h *MupHandler) DodajNalogZaPracenje(lice data.SumnjivoLice) error {
 
 	noviNalog.ID = primitive.NewObjectID()
 
-	korisnik, err := h.mupRepo.DobaviKorisnikaPoJmbg(context.Background(), lice.Prelaz.JMBGPutnika)
+	korisnik, err := h.mupRepo.DobaviKorisnikaPoJmbg(context.Background(), lice.JMBG)
 	if err != nil {
 		return err
 	}
+	// Osoba koja nije gradjanin u evidenciji MUP-a nema za koga da dobije nalog
+	if korisnik == nil {
+		return nil
+	}
 
 	noviNalog.Gradjanin = korisnik
 	noviNalog.Opis = lice.Opis
@@ -619,8 +627,12 @@ func (h *MupHandler) KreirajNalogZaPracenje(rw http.ResponseWriter, r *http.Requ
 	}
 
 	for _, sumnjivolice := range sumnjivoLiceMap {
+		// Nalog se vodi po JMBG-u, pa se osobe bez JMBG-a i osobe kojima je oznaka skinuta preskacu
+		if sumnjivolice.JMBG == "" || !sumnjivolice.Oznaceno {
+			continue
+		}
 
-		if nalog, _ := h.mupRepo.DobaviNalogPoSumjivomLicu(ctx, sumnjivolice.Prelaz.JMBGPutnika); nalog != nil {
+		if nalog, _ := h.mupRepo.DobaviNalogPoSumjivomLicu(ctx, sumnjivolice.JMBG); nalog != nil {
 			continue
 		} else {
 			// Pozivamo funkciju za kreiranje novog naloga u bazi podataka
@@ -660,6 +672,31 @@ func (h *MupHandler) DobaviNalogeZaPracenje(rw http.ResponseWriter, r *http.Requ
 	}
 }
 
+func (h *MupHandler) DobaviNalogZaPracenjePoJmbg(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "MupHandler.DobaviNalogZaPracenjePoJmbg")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	nalog, err := h.mupRepo.DobaviNalogPoSumjivomLicu(ctx, vars["jmbg"])
+	if err != nil {
+		// granicna policija 404 tumaci kao da putnik nije na listi, pa se samo nepostojeci nalog tako prijavljuje
+		if err == mongo.ErrNoDocuments {
+			span.SetStatus(codes.Error, "Nalog za pracenje nije pronadjen")
+			http.Error(rw, "Nalog za pracenje nije pronadjen", http.StatusNotFound)
+			return
+		}
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja naloga za pracenje")
+		http.Error(rw, "Greska prilikom dobavljanja naloga za pracenje", http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(nalog); err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+		http.Error(rw, "Greska prilikom konvertovanja u JSON", http.StatusInternalServerError)
+	}
+}
+
 func (h *MupHandler) DobaviJmbgKorisnika(rw http.ResponseWriter, r *http.Request) {
 	ctx, span := h.tracer.Start(r.Context(), "MupHandler.DobaviJmbgKorisnika")
 	defer span.End()
@@ -697,3 +734,394 @@ func (h *MupHandler) DobaviJmbgKorisnika(rw http.ResponseWriter, r *http.Request
 		http.Error(rw, "Greska prilikom konvertovanja u JSON", http.StatusInternalServerError)
 	}
 }
+
+//SLIKE DOKUMENATA
+
+func (h *MupHandler) DodajSlikuDokumenta(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "MupHandler.DodajSlikuDokumenta")
+	defer span.End()
+
+	vars := mux.Vars(req)
+	dokumentId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id dokumenta nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id dokumenta nije procitan"))
+		return
+	}
+
+	tip := data.TipSlike(vars["tip"])
+	if tip != data.FOTOGRAFIJA && tip != data.POTPIS {
+		span.SetStatus(codes.Error, "Nepoznat tip slike")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Nepoznat tip slike"))
+		return
+	}
+
+	req.Body = http.MaxBytesReader(writer, req.Body, helper.MaxVelicinaSlike+(1<<20))
+	if err := req.ParseMultipartForm(helper.MaxVelicinaSlike); err != nil {
+		span.SetStatus(codes.Error, "Slika je prevelika ili zahtev nije validan")
+		writer.WriteHeader(http.StatusRequestEntityTooLarge)
+		writer.Write([]byte("Slika je prevelika ili zahtev nije validan"))
+		return
+	}
+
+	fajl, _, err := req.FormFile("slika")
+	if err != nil {
+		span.SetStatus(codes.Error, "Slika nije prosledjena")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Slika nije prosledjena"))
+		return
+	}
+	defer fajl.Close()
+
+	sadrzaj, err := ioutil.ReadAll(fajl)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom citanja slike")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Greska prilikom citanja slike"))
+		return
+	}
+
+	ociscenaSlika, contentType, err := helper.ObradiSliku(sadrzaj)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writer.WriteHeader(http.StatusUnsupportedMediaType)
+		writer.Write([]byte(err.Error()))
+		return
+	}
+
+	korisnik, err := h.mupRepo.DobaviKorisnikaPoDokumentu(ctx, dokumentId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Dokument ne postoji")
+		writer.WriteHeader(http.StatusNotFound)
+		writer.Write([]byte("Dokument ne postoji"))
+		return
+	}
+
+	slika := data.Slika{
+		DokumentId:  dokumentId,
+		KorisnikId:  korisnik.ID,
+		Tip:         tip,
+		ContentType: contentType,
+		Sadrzaj:     ociscenaSlika,
+	}
+
+	err = h.mupRepo.SacuvajSliku(ctx, &slika)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom cuvanja slike")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom cuvanja slike"))
+		return
+	}
+
+	staraSlikaId := poveziSlikuSaDokumentom(korisnik, dokumentId, tip, slika.ID)
+
+	err = h.mupRepo.AzurirajKorisnika(ctx, korisnik)
+	if err != nil {
+		h.mupRepo.ObrisiSliku(ctx, slika.ID)
+		span.SetStatus(codes.Error, "Greška prilikom ažuriranja korisnika")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greška prilikom ažuriranja korisnika"))
+		return
+	}
+
+	if !staraSlikaId.IsZero() {
+		if err := h.mupRepo.ObrisiSliku(ctx, staraSlikaId); err != nil {
+			h.logger.Println("Greska prilikom brisanja stare slike:", err)
+		}
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(&slika)
+}
+
+// poveziSlikuSaDokumentom upisuje id nove slike u licnu kartu ili pasos i vraca id slike koju zamenjuje
+func poveziSlikuSaDokumentom(korisnik *data.Korisnik, dokumentId primitive.ObjectID, tip data.TipSlike, slikaId primitive.ObjectID) primitive.ObjectID {
+	var fotografijaId, potpisId *primitive.ObjectID
+	if korisnik.LicnaKarta != nil && korisnik.LicnaKarta.ID == dokumentId {
+		fotografijaId, potpisId = &korisnik.LicnaKarta.FotografijaId, &korisnik.LicnaKarta.PotpisId
+	} else if korisnik.Pasos != nil && korisnik.Pasos.ID == dokumentId {
+		fotografijaId, potpisId = &korisnik.Pasos.FotografijaId, &korisnik.Pasos.PotpisId
+	} else {
+		return primitive.NilObjectID
+	}
+
+	polje := fotografijaId
+	if tip == data.POTPIS {
+		polje = potpisId
+	}
+
+	staraSlikaId := *polje
+	*polje = slikaId
+	return staraSlikaId
+}
+
+func (h *MupHandler) DobaviSlikuDokumenta(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "MupHandler.DobaviSlikuDokumenta")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	dokumentId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id dokumenta nije procitan")
+		http.Error(rw, "Id dokumenta nije procitan", http.StatusBadRequest)
+		return
+	}
+
+	tip := data.TipSlike(vars["tip"])
+	if tip != data.FOTOGRAFIJA && tip != data.POTPIS {
+		span.SetStatus(codes.Error, "Nepoznat tip slike")
+		http.Error(rw, "Nepoznat tip slike", http.StatusBadRequest)
+		return
+	}
+
+	korisnik, err := h.mupRepo.DobaviKorisnikaPoDokumentu(ctx, dokumentId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Dokument ne postoji")
+		http.Error(rw, "Dokument ne postoji", http.StatusNotFound)
+		return
+	}
+
+	if !pristupSlikamaDozvoljen(helper.ExtractClaims(r), korisnik) {
+		span.SetStatus(codes.Error, "Nemate pravo pristupa slici")
+		http.Error(rw, "Nemate pravo pristupa slici", http.StatusForbidden)
+		return
+	}
+
+	var slikaId primitive.ObjectID
+	if korisnik.LicnaKarta != nil && korisnik.LicnaKarta.ID == dokumentId {
+		slikaId = korisnik.LicnaKarta.FotografijaId
+		if tip == data.POTPIS {
+			slikaId = korisnik.LicnaKarta.PotpisId
+		}
+	} else if korisnik.Pasos != nil && korisnik.Pasos.ID == dokumentId {
+		slikaId = korisnik.Pasos.FotografijaId
+		if tip == data.POTPIS {
+			slikaId = korisnik.Pasos.PotpisId
+		}
+	}
+
+	h.posaljiSliku(ctx, rw, span, slikaId)
+}
+
+func (h *MupHandler) DobaviFotografijuPoJmbg(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "MupHandler.DobaviFotografijuPoJmbg")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	korisnik, err := h.mupRepo.DobaviKorisnikaPoJmbg(ctx, vars["jmbg"])
+	if err != nil || korisnik == nil {
+		span.SetStatus(codes.Error, "Korisnik nije pronadjen")
+		http.Error(rw, "Korisnik nije pronadjen", http.StatusNotFound)
+		return
+	}
+
+	if !pristupSlikamaDozvoljen(helper.ExtractClaims(r), korisnik) {
+		span.SetStatus(codes.Error, "Nemate pravo pristupa slici")
+		http.Error(rw, "Nemate pravo pristupa slici", http.StatusForbidden)
+		return
+	}
+
+	// Za granicnu kontrolu prednost ima fotografija iz pasosa
+	var slikaId primitive.ObjectID
+	if korisnik.Pasos != nil && !korisnik.Pasos.FotografijaId.IsZero() {
+		slikaId = korisnik.Pasos.FotografijaId
+	} else if korisnik.LicnaKarta != nil {
+		slikaId = korisnik.LicnaKarta.FotografijaId
+	}
+
+	h.posaljiSliku(ctx, rw, span, slikaId)
+}
+
+func (h *MupHandler) posaljiSliku(ctx context.Context, rw http.ResponseWriter, span trace.Span, slikaId primitive.ObjectID) {
+	if slikaId.IsZero() {
+		span.SetStatus(codes.Error, "Slika nije pronadjena")
+		http.Error(rw, "Slika nije pronadjena", http.StatusNotFound)
+		return
+	}
+
+	slika, err := h.mupRepo.DobaviSliku(ctx, slikaId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja slike")
+		http.Error(rw, "Greska prilikom dobavljanja slike", http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", slika.ContentType)
+	rw.Header().Set("Cache-Control", "private, no-store")
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(slika.Sadrzaj)
+}
+
+// Slikama pristupa vlasnik dokumenta, policija i granicni sluzbenici
+func pristupSlikamaDozvoljen(claims map[string]string, korisnik *data.Korisnik) bool {
+	if claims == nil {
+		return false
+	}
+	switch claims["rola"] {
+	case data.Policajac, data.GranicniSluzbenik:
+		return true
+	}
+	return claims["id"] == korisnik.ID.Hex()
+}
+
+// KreirajKrivicnuPrijavu omogucava policiji da podnese krivicnu prijavu koja nije vezana za granicni prelaz.
+// Osumnjiceni se identifikuje po JMBG-u i mora postojati u evidenciji.
+func (h *MupHandler) KreirajKrivicnuPrijavu(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "MupHandler.KreirajKrivicnuPrijavu")
+	defer span.End()
+
+	var prijava data.KrivicnaPrijava
+	if err := prijava.FromJSON(req.Body); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	if prijava.Osumnjiceni.JMBG == "" {
+		span.SetStatus(codes.Error, "JMBG osumnjicenog je obavezan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("JMBG osumnjicenog je obavezan"))
+		return
+	}
+
+	osumnjiceni, err := h.mupRepo.DobaviKorisnikaPoJmbg(ctx, prijava.Osumnjiceni.JMBG)
+	if err != nil || osumnjiceni == nil {
+		span.SetStatus(codes.Error, "Osumnjiceni sa datim JMBG-om ne postoji")
+		writer.WriteHeader(http.StatusNotFound)
+		writer.Write([]byte("Osumnjiceni sa datim JMBG-om ne postoji"))
+		return
+	}
+
+	claims := helper.ExtractClaims(req)
+	policajacId, err := primitive.ObjectIDFromHex(claims["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	prijava.ID = primitive.NewObjectID()
+	prijava.Datum = primitive.NewDateTimeFromTime(time.Now())
+	prijava.Izvor = data.IZVOR_MUP
+	prijava.IdSluzbenika = policajacId
+	prijava.Status = data.PODNETA
+	prijava.IstorijaStatusa = []data.PromenaStatusa{{Status: data.PODNETA, Datum: prijava.Datum}}
+	prijava.Osumnjiceni.Ime = osumnjiceni.Ime
+	prijava.Osumnjiceni.Prezime = osumnjiceni.Prezime
+	if osumnjiceni.Pasos != nil {
+		prijava.Osumnjiceni.BrojPasosa = osumnjiceni.Pasos.BrojPasosa
+		prijava.Osumnjiceni.Drzavljanstvo = drzavljanstvo.Normalizuj(osumnjiceni.Pasos.Drzavljanstvo)
+	}
+
+	err = h.mupRepo.DodajKrivicnuPrijavu(ctx, &prijava)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom kreiranja krivicne prijave")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom kreiranja krivicne prijave"))
+		return
+	}
+
+	writer.WriteHeader(http.StatusCreated)
+	prijava.ToJSON(writer)
+}
+
+func (h *MupHandler) DobaviKrivicnePrijave(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "MupHandler.DobaviKrivicnePrijave")
+	defer span.End()
+
+	prijave, err := h.mupRepo.DobaviKrivicnePrijave(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja krivicnih prijava")
+		http.Error(rw, "Greska prilikom dobavljanja krivicnih prijava", http.StatusInternalServerError)
+		return
+	}
+
+	err = prijave.ToJSON(rw)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+		http.Error(rw, "Greska prilikom konvertovanja u JSON", http.StatusInternalServerError)
+	}
+}
+
+func (h *MupHandler) DobaviKrivicnuPrijavu(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "MupHandler.DobaviKrivicnuPrijavu")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	prijavaId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id krivicne prijave nije procitan")
+		http.Error(rw, "Id krivicne prijave nije procitan", http.StatusBadRequest)
+		return
+	}
+
+	prijava, err := h.mupRepo.DobaviKrivicnuPrijavu(ctx, prijavaId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Krivicna prijava ne postoji")
+		http.Error(rw, "Krivicna prijava ne postoji", http.StatusNotFound)
+		return
+	}
+
+	err = prijava.ToJSON(rw)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+		http.Error(rw, "Greska prilikom konvertovanja u JSON", http.StatusInternalServerError)
+	}
+}
+
+func (h *MupHandler) PromeniStatusKrivicnePrijave(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "MupHandler.PromeniStatusKrivicnePrijave")
+	defer span.End()
+
+	vars := mux.Vars(req)
+	prijavaId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id krivicne prijave nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id krivicne prijave nije procitan"))
+		return
+	}
+
+	var promena data.PromenaStatusa
+	if err := json.NewDecoder(req.Body).Decode(&promena); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	prijava, err := h.mupRepo.DobaviKrivicnuPrijavu(ctx, prijavaId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Krivicna prijava ne postoji")
+		writer.WriteHeader(http.StatusNotFound)
+		writer.Write([]byte("Krivicna prijava ne postoji"))
+		return
+	}
+
+	if !data.DozvoljenaPromenaStatusa(prijava.Status, promena.Status) {
+		poruka := fmt.Sprintf("Prelaz iz statusa %s u status %s nije dozvoljen", prijava.Status, promena.Status)
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte(poruka))
+		return
+	}
+
+	promena.Datum = primitive.NewDateTimeFromTime(time.Now())
+	err = h.mupRepo.PromeniStatusKrivicnePrijave(ctx, prijavaId, prijava.Status, promena)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom promene statusa krivicne prijave")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Greska prilikom promene statusa krivicne prijave"))
+		return
+	}
+
+	prijava.Status = promena.Status
+	prijava.IstorijaStatusa = append(prijava.IstorijaStatusa, promena)
+	writer.WriteHeader(http.StatusOK)
+	prijava.ToJSON(writer)
+}
@@ -0,0 +1,51 @@
+package helper
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const MaxVelicinaSlike = 2 << 20
+
+var dozvoljeniTipoviSlika = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+}
+
+// ObradiSliku proverava tip i velicinu slike i ponovo je enkodira,
+// cime se uklanjaju EXIF i ostali metapodaci iz originalnog fajla.
+func ObradiSliku(sadrzaj []byte) ([]byte, string, error) {
+	if len(sadrzaj) == 0 {
+		return nil, "", errors.New("slika je prazna")
+	}
+	if len(sadrzaj) > MaxVelicinaSlike {
+		return nil, "", errors.New("slika je prevelika")
+	}
+
+	contentType := http.DetectContentType(sadrzaj)
+	if !dozvoljeniTipoviSlika[contentType] {
+		return nil, "", errors.New("nedozvoljen tip slike")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(sadrzaj))
+	if err != nil {
+		return nil, "", errors.New("slika nije validna")
+	}
+
+	var ociscena bytes.Buffer
+	switch contentType {
+	case "image/png":
+		err = png.Encode(&ociscena, img)
+	default:
+		err = jpeg.Encode(&ociscena, img, &jpeg.Options{Quality: 90})
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return ociscena.Bytes(), contentType, nil
+}
@@ -85,6 +85,9 @@ func main() {
 	dobaviNalogeZaPracenje := router.Methods(http.MethodGet).Subrouter()
 	dobaviNalogeZaPracenje.HandleFunc("/dobaviNalogeZaPracenje", mupHandler.DobaviNalogeZaPracenje)
 
+	dobaviNalogZaPracenjePoJmbg := router.Methods(http.MethodGet).Subrouter()
+	dobaviNalogZaPracenjePoJmbg.HandleFunc("/dobaviNalogZaPracenje/{jmbg}", mupHandler.DobaviNalogZaPracenjePoJmbg)
+
 	kreirajPasos := router.Methods(http.MethodPut).Subrouter()
 	kreirajPasos.HandleFunc("/kreirajPasos/{id}", mupHandler.KreirajPasos)
 
@@ -94,6 +97,27 @@ func main() {
 	dobaviJmbgKorisnika := router.Methods(http.MethodGet).Subrouter()
 	dobaviJmbgKorisnika.HandleFunc("/dobaviJmbgKorisnika/{id}", mupHandler.DobaviJmbgKorisnika)
 
+	dodajSlikuDokumenta := router.Methods(http.MethodPut).Subrouter()
+	dodajSlikuDokumenta.HandleFunc("/dokument/{id}/slika/{tip}", mupHandler.DodajSlikuDokumenta)
+
+	dobaviSlikuDokumenta := router.Methods(http.MethodGet).Subrouter()
+	dobaviSlikuDokumenta.HandleFunc("/dokument/{id}/slika/{tip}", mupHandler.DobaviSlikuDokumenta)
+
+	dobaviFotografijuPoJmbg := router.Methods(http.MethodGet).Subrouter()
+	dobaviFotografijuPoJmbg.HandleFunc("/fotografija/{jmbg}", mupHandler.DobaviFotografijuPoJmbg)
+
+	kreirajKrivicnuPrijavu := router.Methods(http.MethodPost).Subrouter()
+	kreirajKrivicnuPrijavu.HandleFunc("/kreirajKrivicnuPrijavu", mupHandler.KreirajKrivicnuPrijavu)
+
+	dobaviKrivicnePrijave := router.Methods(http.MethodGet).Subrouter()
+	dobaviKrivicnePrijave.HandleFunc("/dobaviKrivicnePrijave", mupHandler.DobaviKrivicnePrijave)
+
+	dobaviKrivicnuPrijavu := router.Methods(http.MethodGet).Subrouter()
+	dobaviKrivicnuPrijavu.HandleFunc("/dobaviKrivicnuPrijavu/{id}", mupHandler.DobaviKrivicnuPrijavu)
+
+	promeniStatusKrivicnePrijave := router.Methods(http.MethodPut).Subrouter()
+	promeniStatusKrivicnePrijave.HandleFunc("/promeniStatusKrivicnePrijave/{id}", mupHandler.PromeniStatusKrivicnePrijave)
+
 	//Initialize the server
 	server := http.Server{
 		Addr:         ":" + port,
@@ -0,0 +1,3 @@
+module prijave
+
+go 1.20
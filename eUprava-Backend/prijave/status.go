@@ -0,0 +1,55 @@
+package prijave
+
+// StatusPrijave je status krivicne prijave, isti u svim servisima koji podnose ili obradjuju prijave
+type StatusPrijave string
+
+const (
+	PODNETA                = "PODNETA"
+	PRIMLJENA_U_TUZILASTVU = "PRIMLJENA_U_TUZILASTVU"
+	ODBACENA               = "ODBACENA"
+	U_POSTUPKU             = "U_POSTUPKU"
+	ZAVRSENA               = "ZAVRSENA"
+)
+
+// Dozvoljeni prelazi izmedju statusa krivicne prijave
+var sledeciStatusi = map[StatusPrijave][]StatusPrijave{
+	PODNETA:                {PRIMLJENA_U_TUZILASTVU, ODBACENA},
+	PRIMLJENA_U_TUZILASTVU: {U_POSTUPKU, ODBACENA},
+	U_POSTUPKU:             {ZAVRSENA, ODBACENA},
+}
+
+func DozvoljenaPromenaStatusa(iz StatusPrijave, u StatusPrijave) bool {
+	for _, status := range sledeciStatusi[iz] {
+		if status == u {
+			return true
+		}
+	}
+	return false
+}
+
+// KoraciDoStatusa vraca statuse kroz koje prijava redom prolazi od statusa iz do statusa u. Prazan rezultat
+// znaci da je prijava vec u statusu u ili da u njega vise ne moze da stigne.
+func KoraciDoStatusa(iz StatusPrijave, u StatusPrijave) []StatusPrijave {
+	prethodni := map[StatusPrijave]StatusPrijave{iz: iz}
+	red := []StatusPrijave{iz}
+	for len(red) > 0 && iz != u {
+		trenutni := red[0]
+		red = red[1:]
+		for _, sledeci := range sledeciStatusi[trenutni] {
+			if _, obidjen := prethodni[sledeci]; obidjen {
+				continue
+			}
+			prethodni[sledeci] = trenutni
+			if sledeci != u {
+				red = append(red, sledeci)
+				continue
+			}
+			koraci := []StatusPrijave{}
+			for status := u; status != iz; status = prethodni[status] {
+				koraci = append([]StatusPrijave{status}, koraci...)
+			}
+			return koraci
+		}
+	}
+	return nil
+}
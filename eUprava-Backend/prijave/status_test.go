@@ -0,0 +1,61 @@
+package prijave
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDozvoljenaPromenaStatusa(t *testing.T) {
+	tests := []struct {
+		iz   StatusPrijave
+		u    StatusPrijave
+		want bool
+	}{
+		{PODNETA, PRIMLJENA_U_TUZILASTVU, true},
+		{PODNETA, ODBACENA, true},
+		{PODNETA, U_POSTUPKU, false},
+		{PRIMLJENA_U_TUZILASTVU, U_POSTUPKU, true},
+		{U_POSTUPKU, ZAVRSENA, true},
+		{U_POSTUPKU, PODNETA, false},
+		{ZAVRSENA, ODBACENA, false},
+		{ODBACENA, PODNETA, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.iz)+"->"+string(tt.u), func(t *testing.T) {
+			if got := DozvoljenaPromenaStatusa(tt.iz, tt.u); got != tt.want {
+				t.Errorf("DozvoljenaPromenaStatusa(%s, %s) = %v, ocekivano %v", tt.iz, tt.u, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKoraciDoStatusa(t *testing.T) {
+	tests := []struct {
+		name string
+		iz   StatusPrijave
+		u    StatusPrijave
+		want []StatusPrijave
+	}{
+		{"vec u statusu", U_POSTUPKU, U_POSTUPKU, nil},
+		{"jedan korak", PODNETA, PRIMLJENA_U_TUZILASTVU, []StatusPrijave{PRIMLJENA_U_TUZILASTVU}},
+		{"odbacivanje direktno", PODNETA, ODBACENA, []StatusPrijave{ODBACENA}},
+		{"od podnete do zavrsene", PODNETA, ZAVRSENA, []StatusPrijave{PRIMLJENA_U_TUZILASTVU, U_POSTUPKU, ZAVRSENA}},
+		{"od primljene do zavrsene", PRIMLJENA_U_TUZILASTVU, ZAVRSENA, []StatusPrijave{U_POSTUPKU, ZAVRSENA}},
+		{"unazad se ne moze", U_POSTUPKU, PRIMLJENA_U_TUZILASTVU, nil},
+		{"iz zavrsnog statusa se ne izlazi", ODBACENA, ZAVRSENA, nil},
+		{"nepoznat status", "NEPOZNAT", ZAVRSENA, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := KoraciDoStatusa(tt.iz, tt.u)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("KoraciDoStatusa(%s, %s) = %v, ocekivano %v", tt.iz, tt.u, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package rokovi
+
+import (
+	"encoding/json"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"io"
+	"time"
+)
+
+// KorakPostupka je korak postupka za koji tece rok, svaki servis definise svoje korake
+type KorakPostupka string
+
+// PraviloRoka odredjuje koliko dana posle pocetka koraka postupka istice rok. Pravilo sa zakonom i clanom vazi
+// samo za to krivicno delo i ima prednost nad opstim pravilom za isti korak.
+type PraviloRoka struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Korak          KorakPostupka      `bson:"korak" json:"korak"`
+	Naziv          string             `bson:"naziv" json:"naziv"`
+	Zakon          string             `bson:"zakon,omitempty" json:"zakon,omitempty"`
+	Clan           string             `bson:"clan,omitempty" json:"clan,omitempty"`
+	BrojDana       int                `bson:"brojDana" json:"brojDana"`
+	UpozorenjeDana int                `bson:"upozorenjeDana" json:"upozorenjeDana"`
+	Aktivno        bool               `bson:"aktivno" json:"aktivno"`
+}
+
+type PravilaRokova []*PraviloRoka
+
+type NivoUpozorenja string
+
+const (
+	ROK_UPOZORENJE = "UPOZORENJE"
+	ROK_HITNO      = "HITNO"
+	ROK_ISTEKAO    = "ISTEKAO"
+	// Obavestenje koje ne upozorava na rok, vec javlja promenu u postupku
+	OBAVESTENJE_INFORMACIJA = "INFORMACIJA"
+)
+
+// Obavestenje upozorava korisnika na rok ili ga obavestava o promeni u postupku, za isti kljuc i nivo
+// korisnik dobija samo jedno obavestenje
+type Obavestenje struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	IdKorisnika primitive.ObjectID `bson:"idKorisnika" json:"idKorisnika"`
+	KljucRoka   string             `bson:"kljucRoka" json:"kljucRoka"`
+	Nivo        NivoUpozorenja     `bson:"nivo" json:"nivo"`
+	PredmetId   primitive.ObjectID `bson:"predmetId" json:"predmetId"`
+	Tekst       string             `bson:"tekst" json:"tekst"`
+	Istice      time.Time          `bson:"istice" json:"istice"`
+	Kreirano    time.Time          `bson:"kreirano" json:"kreirano"`
+	Procitano   *time.Time         `bson:"procitano,omitempty" json:"procitano,omitempty"`
+}
+
+type Obavestenja []*Obavestenje
+
+func (o *PravilaRokova) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
+
+func (o *Obavestenja) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
@@ -0,0 +1,103 @@
+package rokovi
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strings"
+	"time"
+)
+
+// Rok je hitan kada do isteka ostane DanaDoHitnogRoka ili manje dana
+const DanaDoHitnogRoka = 3
+
+// primenljivost vraca koliko je pravilo odredjeno za krivicno delo, ili -1 ako se na njega ne odnosi
+func (p *PraviloRoka) primenljivost(zakon string, clan string) int {
+	if !p.Aktivno {
+		return -1
+	}
+	if p.Zakon == "" {
+		return 0
+	}
+	if !strings.EqualFold(p.Zakon, zakon) {
+		return -1
+	}
+	if p.Clan == "" {
+		return 1
+	}
+	if !strings.EqualFold(p.Clan, clan) {
+		return -1
+	}
+	return 2
+}
+
+// IzaberiPraviloRoka vraca najodredjenije aktivno pravilo za korak i krivicno delo ili nil ako pravilo ne postoji
+func IzaberiPraviloRoka(pravila PravilaRokova, korak KorakPostupka, zakon string, clan string) *PraviloRoka {
+	var izabrano *PraviloRoka
+	najbolje := -1
+	for _, pravilo := range pravila {
+		if pravilo.Korak != korak {
+			continue
+		}
+		if primenljivost := pravilo.primenljivost(zakon, clan); primenljivost > najbolje {
+			izabrano, najbolje = pravilo, primenljivost
+		}
+	}
+	return izabrano
+}
+
+// ProveriPravilo vraca razlog zbog koga pravilo ne moze da se sacuva, ili prazan string ako je pravilo ispravno
+func ProveriPravilo(pravilo *PraviloRoka, poznatKorak func(KorakPostupka) bool) string {
+	if !poznatKorak(pravilo.Korak) {
+		return "Nepoznat korak postupka"
+	}
+	if pravilo.Naziv == "" || pravilo.BrojDana <= 0 || pravilo.UpozorenjeDana < 0 {
+		return "Naziv i pozitivan broj dana su obavezni"
+	}
+	if pravilo.Zakon == "" && pravilo.Clan != "" {
+		return "Pravilo za clan mora navesti i zakon"
+	}
+	return ""
+}
+
+// NivoRoka odredjuje koliko je rok blizu isteka, prazan nivo znaci da upozorenje jos nije potrebno
+func NivoRoka(istice time.Time, upozorenjeDana int, sada time.Time) NivoUpozorenja {
+	switch {
+	case !sada.Before(istice):
+		return ROK_ISTEKAO
+	case istice.Sub(sada) <= DanaDoHitnogRoka*24*time.Hour:
+		return ROK_HITNO
+	case istice.Sub(sada) <= time.Duration(upozorenjeDana)*24*time.Hour:
+		return ROK_UPOZORENJE
+	}
+	return ""
+}
+
+// PreostaloDana vraca broj celih dana do isteka roka, za istekao rok broj je negativan
+func PreostaloDana(istice time.Time, sada time.Time) int {
+	razlika := istice.Sub(sada)
+	dana := int(razlika / (24 * time.Hour))
+	if razlika < 0 && razlika%(24*time.Hour) != 0 {
+		dana--
+	}
+	return dana
+}
+
+// PrimaociObavestenja vraca kome se salje obavestenje o roku. Upozorenje dobijaju odgovorni za predmet, a hitan
+// i istekao rok se eskalira i nadredjenima. Predmet bez odgovornih odmah dobijaju nadredjeni.
+func PrimaociObavestenja(odgovorni []primitive.ObjectID, nadredjeni []primitive.ObjectID, nivo NivoUpozorenja) []primitive.ObjectID {
+	primaoci := []primitive.ObjectID{}
+	vec := make(map[primitive.ObjectID]bool)
+	dodaj := func(ids []primitive.ObjectID) {
+		for _, id := range ids {
+			if !id.IsZero() && !vec[id] {
+				vec[id] = true
+				primaoci = append(primaoci, id)
+			}
+		}
+	}
+
+	dodaj(odgovorni)
+	if nivo == ROK_HITNO || nivo == ROK_ISTEKAO || len(primaoci) == 0 {
+		dodaj(nadredjeni)
+	}
+	return primaoci
+}
@@ -0,0 +1,161 @@
+package rokovi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	korakIstrage   KorakPostupka = "ZAVRSETAK_ISTRAGE"
+	korakOptuznice KorakPostupka = "PODIZANJE_OPTUZNICE"
+)
+
+func TestIzaberiPraviloRoka(t *testing.T) {
+	opste := &PraviloRoka{Naziv: "opste", Korak: korakIstrage, Aktivno: true}
+	zaZakon := &PraviloRoka{Naziv: "zakon", Korak: korakIstrage, Zakon: "KZ", Aktivno: true}
+	zaClan := &PraviloRoka{Naziv: "clan", Korak: korakIstrage, Zakon: "KZ", Clan: "203", Aktivno: true}
+	neaktivno := &PraviloRoka{Naziv: "neaktivno", Korak: korakIstrage, Zakon: "ZKP", Aktivno: false}
+	drugiKorak := &PraviloRoka{Naziv: "drugi korak", Korak: korakOptuznice, Aktivno: true}
+	pravila := PravilaRokova{opste, zaZakon, zaClan, neaktivno, drugiKorak}
+
+	tests := []struct {
+		name    string
+		pravila PravilaRokova
+		korak   KorakPostupka
+		zakon   string
+		clan    string
+		want    *PraviloRoka
+	}{
+		{"pravilo za clan ima prednost", pravila, korakIstrage, "KZ", "203", zaClan},
+		{"zakon i clan bez obzira na velika slova", pravila, korakIstrage, "kz", "203", zaClan},
+		{"pravilo za zakon kada clan nema pravilo", pravila, korakIstrage, "KZ", "100", zaZakon},
+		{"opste pravilo za drugi zakon", pravila, korakIstrage, "ZOP", "1", opste},
+		{"neaktivno pravilo se ne primenjuje", pravila, korakIstrage, "ZKP", "1", opste},
+		{"pravilo drugog koraka", pravila, korakOptuznice, "KZ", "203", drugiKorak},
+		{"bez pravila za korak", pravila, "NEPOZNAT", "KZ", "203", nil},
+		{"bez pravila", nil, korakIstrage, "KZ", "203", nil},
+		{"samo neaktivno pravilo", PravilaRokova{neaktivno}, korakIstrage, "ZKP", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IzaberiPraviloRoka(tt.pravila, tt.korak, tt.zakon, tt.clan)
+			if got != tt.want {
+				t.Errorf("IzaberiPraviloRoka() = %+v, ocekivano %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProveriPravilo(t *testing.T) {
+	poznatKorak := func(korak KorakPostupka) bool { return korak == korakIstrage }
+
+	tests := []struct {
+		name    string
+		pravilo PraviloRoka
+		want    string
+	}{
+		{"ispravno opste pravilo", PraviloRoka{Korak: korakIstrage, Naziv: "Istraga", BrojDana: 180, UpozorenjeDana: 14}, ""},
+		{"ispravno pravilo za clan", PraviloRoka{Korak: korakIstrage, Naziv: "Istraga", Zakon: "KZ", Clan: "203", BrojDana: 90}, ""},
+		{"nepoznat korak", PraviloRoka{Korak: korakOptuznice, Naziv: "Optuznica", BrojDana: 365}, "Nepoznat korak postupka"},
+		{"bez naziva", PraviloRoka{Korak: korakIstrage, BrojDana: 180}, "Naziv i pozitivan broj dana su obavezni"},
+		{"nula dana", PraviloRoka{Korak: korakIstrage, Naziv: "Istraga"}, "Naziv i pozitivan broj dana su obavezni"},
+		{"negativno upozorenje", PraviloRoka{Korak: korakIstrage, Naziv: "Istraga", BrojDana: 180, UpozorenjeDana: -1}, "Naziv i pozitivan broj dana su obavezni"},
+		{"clan bez zakona", PraviloRoka{Korak: korakIstrage, Naziv: "Istraga", Clan: "203", BrojDana: 180}, "Pravilo za clan mora navesti i zakon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProveriPravilo(&tt.pravilo, poznatKorak); got != tt.want {
+				t.Errorf("ProveriPravilo() = %q, ocekivano %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNivoRoka(t *testing.T) {
+	sada := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		istice         time.Time
+		upozorenjeDana int
+		want           NivoUpozorenja
+	}{
+		{"rok je istekao", sada.Add(-time.Hour), 14, ROK_ISTEKAO},
+		{"rok istice upravo sada", sada, 14, ROK_ISTEKAO},
+		{"hitno tacno na granici", sada.AddDate(0, 0, DanaDoHitnogRoka), 14, ROK_HITNO},
+		{"hitno bez upozorenja", sada.Add(time.Hour), 0, ROK_HITNO},
+		{"upozorenje", sada.AddDate(0, 0, 10), 14, ROK_UPOZORENJE},
+		{"upozorenje tacno na granici", sada.AddDate(0, 0, 14), 14, ROK_UPOZORENJE},
+		{"jos nije vreme za upozorenje", sada.AddDate(0, 0, 15), 14, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NivoRoka(tt.istice, tt.upozorenjeDana, sada); got != tt.want {
+				t.Errorf("NivoRoka() = %q, ocekivano %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreostaloDana(t *testing.T) {
+	sada := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		istice time.Time
+		want   int
+	}{
+		{"istice sada", sada, 0},
+		{"manje od dana", sada.Add(23 * time.Hour), 0},
+		{"tacno jedan dan", sada.Add(24 * time.Hour), 1},
+		{"deset i po dana", sada.Add(10*24*time.Hour + 12*time.Hour), 10},
+		{"istekao pre sat vremena", sada.Add(-time.Hour), -1},
+		{"istekao pre tacno dva dana", sada.AddDate(0, 0, -2), -2},
+		{"istekao pre dva i po dana", sada.Add(-60 * time.Hour), -3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PreostaloDana(tt.istice, sada); got != tt.want {
+				t.Errorf("PreostaloDana() = %d, ocekivano %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrimaociObavestenja(t *testing.T) {
+	tuzioc := primitive.NewObjectID()
+	istrazitelj := primitive.NewObjectID()
+	glavni := primitive.NewObjectID()
+	zamenik := primitive.NewObjectID()
+
+	tests := []struct {
+		name       string
+		odgovorni  []primitive.ObjectID
+		nadredjeni []primitive.ObjectID
+		nivo       NivoUpozorenja
+		want       []primitive.ObjectID
+	}{
+		{"upozorenje samo odgovornima", []primitive.ObjectID{tuzioc, istrazitelj}, []primitive.ObjectID{glavni}, ROK_UPOZORENJE, []primitive.ObjectID{tuzioc, istrazitelj}},
+		{"hitan rok i nadredjenima", []primitive.ObjectID{tuzioc}, []primitive.ObjectID{glavni, zamenik}, ROK_HITNO, []primitive.ObjectID{tuzioc, glavni, zamenik}},
+		{"istekao rok i nadredjenima", []primitive.ObjectID{tuzioc}, []primitive.ObjectID{glavni}, ROK_ISTEKAO, []primitive.ObjectID{tuzioc, glavni}},
+		{"bez odgovornih odmah nadredjenima", nil, []primitive.ObjectID{glavni}, ROK_UPOZORENJE, []primitive.ObjectID{glavni}},
+		{"prazni id-jevi se preskacu", []primitive.ObjectID{primitive.NilObjectID}, []primitive.ObjectID{glavni}, ROK_UPOZORENJE, []primitive.ObjectID{glavni}},
+		{"isti korisnik dobija jedno obavestenje", []primitive.ObjectID{glavni, glavni}, []primitive.ObjectID{glavni}, ROK_HITNO, []primitive.ObjectID{glavni}},
+		{"bez primalaca", nil, nil, ROK_ISTEKAO, []primitive.ObjectID{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PrimaociObavestenja(tt.odgovorni, tt.nadredjeni, tt.nivo); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PrimaociObavestenja() = %v, ocekivano %v", got, tt.want)
+			}
+		})
+	}
+}
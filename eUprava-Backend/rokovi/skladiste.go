@@ -0,0 +1,212 @@
+package rokovi
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	"time"
+)
+
+// ErrPraviloPostoji znaci da za isti korak i krivicno delo vec postoji drugo pravilo
+var ErrPraviloPostoji = errors.New("pravilo za korak i krivicno delo vec postoji")
+
+// Skladiste cuva pravila rokova i obavestenja o rokovima u kolekcijama servisa koji ga koristi
+type Skladiste struct {
+	pravila       *mongo.Collection
+	obavestenja   *mongo.Collection
+	podrazumevana PravilaRokova
+	logger        *log.Logger
+}
+
+func NewSkladiste(pravila *mongo.Collection, obavestenja *mongo.Collection, podrazumevana PravilaRokova, logger *log.Logger) *Skladiste {
+	return &Skladiste{
+		pravila:       pravila,
+		obavestenja:   obavestenja,
+		podrazumevana: podrazumevana,
+		logger:        logger,
+	}
+}
+
+// KreirajIndekseRokova obezbedjuje da korisnik za isti rok i nivo dobije samo jedno obavestenje i da za isti
+// korak i krivicno delo postoji samo jedno pravilo, i kada vise instanci servisa istovremeno radi sa rokovima
+func (s *Skladiste) KreirajIndekseRokova(ctx context.Context) error {
+	indeksi := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "idKorisnika", Value: 1}, {Key: "kljucRoka", Value: 1}, {Key: "nivo", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "idKorisnika", Value: 1}, {Key: "kreirano", Value: -1}},
+		},
+	}
+	if _, err := s.obavestenja.Indexes().CreateMany(ctx, indeksi); err != nil {
+		return err
+	}
+
+	if err := s.ukloniDuplaPravila(ctx); err != nil {
+		return err
+	}
+	_, err := s.pravila.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "korak", Value: 1}, {Key: "zakon", Value: 1}, {Key: "clan", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// ukloniDuplaPravila brise pravila upisana vise puta za isti korak i krivicno delo, sto je bilo moguce
+// pre jedinstvenog indeksa. Zadrzava se aktivno pravilo, a medju njima najstarije.
+func (s *Skladiste) ukloniDuplaPravila(ctx context.Context) error {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "aktivno", Value: -1}, {Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "korak", Value: "$korak"}, {Key: "zakon", Value: "$zakon"}, {Key: "clan", Value: "$clan"}}},
+			{Key: "pravila", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+		}}},
+		bson.D{{Key: "$match", Value: bson.D{{Key: "pravila.1", Value: bson.D{{Key: "$exists", Value: true}}}}}},
+	}
+	cursor, err := s.pravila.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	var grupe []struct {
+		Pravila []primitive.ObjectID `bson:"pravila"`
+	}
+	if err := cursor.All(ctx, &grupe); err != nil {
+		return err
+	}
+
+	for _, grupa := range grupe {
+		rezultat, err := s.pravila.DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: grupa.Pravila[1:]}}}})
+		if err != nil {
+			return err
+		}
+		s.logger.Printf("Pravilo roka %s zadrzano, obrisano duplikata: %d\n", grupa.Pravila[0].Hex(), rezultat.DeletedCount)
+	}
+	return nil
+}
+
+// UpisiPodrazumevanaPravilaRokova upisuje podrazumevano pravilo za korak samo ako za taj korak jos nema
+// opsteg pravila, pa ne menja pravila koja su korisnici vec podesili
+func (s *Skladiste) UpisiPodrazumevanaPravilaRokova(ctx context.Context) error {
+	for _, pravilo := range s.podrazumevana {
+		filter := bson.D{
+			{Key: "korak", Value: pravilo.Korak},
+			{Key: "zakon", Value: bson.D{{Key: "$exists", Value: false}}},
+			{Key: "clan", Value: bson.D{{Key: "$exists", Value: false}}},
+		}
+		update := bson.D{{Key: "$setOnInsert", Value: bson.D{
+			{Key: "naziv", Value: pravilo.Naziv},
+			{Key: "brojDana", Value: pravilo.BrojDana},
+			{Key: "upozorenjeDana", Value: pravilo.UpozorenjeDana},
+			{Key: "aktivno", Value: pravilo.Aktivno},
+		}}}
+
+		_, err := s.pravila.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			// duplikat znaci da je druga instanca servisa istovremeno upisala isto pravilo
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Skladiste) DobaviPravilaRokova(ctx context.Context) (PravilaRokova, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "korak", Value: 1}, {Key: "zakon", Value: 1}, {Key: "clan", Value: 1}})
+	cursor, err := s.pravila.Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	pravila := PravilaRokova{}
+	err = cursor.All(ctx, &pravila)
+	return pravila, err
+}
+
+// SacuvajPraviloRoka menja postojece pravilo ili dodaje novo ako pravilo nema id
+func (s *Skladiste) SacuvajPraviloRoka(ctx context.Context, pravilo *PraviloRoka) error {
+	if pravilo.ID.IsZero() {
+		pravilo.ID = primitive.NewObjectID()
+		_, err := s.pravila.InsertOne(ctx, pravilo)
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrPraviloPostoji
+		}
+		return err
+	}
+
+	rezultat, err := s.pravila.ReplaceOne(ctx, bson.D{{Key: "_id", Value: pravilo.ID}}, pravilo)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrPraviloPostoji
+		}
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DodajObavestenje upisuje obavestenje ako korisnik vec nije obavesten o istom roku na istom nivou
+// i vraca da li je obavestenje novo
+func (s *Skladiste) DodajObavestenje(ctx context.Context, obavestenje *Obavestenje) (bool, error) {
+	filter := bson.D{
+		{Key: "idKorisnika", Value: obavestenje.IdKorisnika},
+		{Key: "kljucRoka", Value: obavestenje.KljucRoka},
+		{Key: "nivo", Value: obavestenje.Nivo},
+	}
+	update := bson.D{{Key: "$setOnInsert", Value: bson.D{
+		{Key: "predmetId", Value: obavestenje.PredmetId},
+		{Key: "tekst", Value: obavestenje.Tekst},
+		{Key: "istice", Value: obavestenje.Istice},
+		{Key: "kreirano", Value: obavestenje.Kreirano},
+	}}}
+	opts := options.Update().SetUpsert(true)
+
+	rezultat, err := s.obavestenja.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return rezultat.UpsertedCount > 0, nil
+}
+
+// DobaviObavestenja vraca poslednjih 100 obavestenja korisnika, od najnovijeg
+func (s *Skladiste) DobaviObavestenja(ctx context.Context, idKorisnika primitive.ObjectID, samoNeprocitana bool) (Obavestenja, error) {
+	filter := bson.D{{Key: "idKorisnika", Value: idKorisnika}}
+	if samoNeprocitana {
+		filter = append(filter, bson.E{Key: "procitano", Value: bson.D{{Key: "$exists", Value: false}}})
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "kreirano", Value: -1}}).SetLimit(100)
+
+	cursor, err := s.obavestenja.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	obavestenja := Obavestenja{}
+	err = cursor.All(ctx, &obavestenja)
+	return obavestenja, err
+}
+
+func (s *Skladiste) OznaciObavestenjeProcitanim(ctx context.Context, id primitive.ObjectID, idKorisnika primitive.ObjectID) error {
+	filter := bson.D{{Key: "_id", Value: id}, {Key: "idKorisnika", Value: idKorisnika}}
+	// $min zadrzava vreme prvog citanja ako je obavestenje vec procitano
+	update := bson.D{{Key: "$min", Value: bson.D{{Key: "procitano", Value: time.Now()}}}}
+
+	rezultat, err := s.obavestenja.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
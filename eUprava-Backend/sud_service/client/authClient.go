@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/sony/gobreaker"
+	"net/http"
+	"net/url"
+	"sud_service/data"
+	"sud_service/domain"
+	"sud_service/helper"
+	"time"
+)
+
+type AuthClient struct {
+	client  *http.Client
+	address string
+	cb      *gobreaker.CircuitBreaker
+}
+
+func NewAuthClient(client *http.Client, address string, cb *gobreaker.CircuitBreaker) AuthClient {
+	return AuthClient{
+		client:  client,
+		address: address,
+		cb:      cb,
+	}
+}
+
+// DobaviKorisnika vraca korisnika iz auth servisa, za nepostojeceg korisnika vraca domain.ErrResp sa statusom 404
+func (ac AuthClient) DobaviKorisnika(ctx context.Context, id string) (*data.Korisnik, error) {
+	var korisnik data.Korisnik
+	if err := ac.dobavi(ctx, ac.address+"/korisnik/"+url.PathEscape(id), &korisnik); err != nil {
+		return nil, err
+	}
+	return &korisnik, nil
+}
+
+// DobaviKorisnikePoRoli vraca sve korisnike sa zadatom rolom
+func (ac AuthClient) DobaviKorisnikePoRoli(ctx context.Context, rola string) (data.Korisnici, error) {
+	var korisnici data.Korisnici
+	if err := ac.dobavi(ctx, ac.address+"/korisnici/rola/"+url.PathEscape(rola), &korisnici); err != nil {
+		return nil, err
+	}
+	return korisnici, nil
+}
+
+// dobavi salje GET zahtev u ime suda i dekodira odgovor u rezultat
+func (ac AuthClient) dobavi(ctx context.Context, reqUrl string, rezultat interface{}) error {
+	var timeout time.Duration
+	deadline, reqHasDeadline := ctx.Deadline()
+	if reqHasDeadline {
+		timeout = time.Until(deadline)
+	}
+
+	bearerToken, err := helper.ServisniToken()
+	if err != nil {
+		return err
+	}
+
+	_, err = ac.cb.Execute(func() (interface{}, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqUrl, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", bearerToken)
+
+		resp, err := ac.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, domain.ErrResp{
+				URL:        resp.Request.URL.String(),
+				Method:     resp.Request.Method,
+				StatusCode: resp.StatusCode,
+			}
+		}
+
+		return nil, json.NewDecoder(resp.Body).Decode(rezultat)
+	})
+	if err != nil {
+		var errResp domain.ErrResp
+		if errors.As(err, &errResp) {
+			return errResp
+		}
+		return handleHttpReqErr(err, reqUrl, http.MethodGet, timeout)
+	}
+	return nil
+}
@@ -1,13 +1,16 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"github.com/sony/gobreaker"
 	"net/http"
+	"net/url"
 	"sud_service/data"
 	"sud_service/domain"
+	"sud_service/helper"
 	"time"
 )
 
@@ -25,6 +28,7 @@ func NewTuzilastvoClient(client *http.Client, address string, cb *gobreaker.Circ
 	}
 }
 
+// DobaviAktivneZahtjeve vraca zahteve za sudski postupak ciji prijem sud jos nije potvrdio
 func (ac TuzilastvoClient) DobaviAktivneZahtjeve(ctx context.Context, bearerToken string) (data.Zahtevi, error) {
 	var timeout time.Duration
 	deadline, reqHasDeadline := ctx.Deadline()
@@ -32,9 +36,10 @@ func (ac TuzilastvoClient) DobaviAktivneZahtjeve(ctx context.Context, bearerToke
 		timeout = time.Until(deadline)
 	}
 
+	reqUrl := ac.address + "/dobaviZahteveZaSudskiPostupak?aktivni=true"
 	cbResp, err := ac.cb.Execute(func() (interface{}, error) {
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ac.address+"/dobaviZahteveZaSudskiPostupak", nil)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqUrl, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -64,7 +69,7 @@ func (ac TuzilastvoClient) DobaviAktivneZahtjeve(ctx context.Context, bearerToke
 		return zahtjevi, nil
 	})
 	if err != nil {
-		return nil, handleHttpReqErr(err, ac.address+"/dobaviZahteveZaSudskiPostupak", http.MethodGet, timeout)
+		return nil, handleHttpReqErr(err, reqUrl, http.MethodGet, timeout)
 	}
 
 	zahtjevi, ok := cbResp.(data.Zahtevi)
@@ -74,3 +79,153 @@ func (ac TuzilastvoClient) DobaviAktivneZahtjeve(ctx context.Context, bearerToke
 
 	return zahtjevi, nil
 }
+
+// PosaljiOdlukuOSporazumu dostavlja tuzilastvu odluku sudije o sporazumu, tuzilastvo prihvata ponovljeno slanje iste odluke
+func (ac TuzilastvoClient) PosaljiOdlukuOSporazumu(ctx context.Context, sporazumId string, odluka data.OdlukaOSporazumu, bearerToken string) error {
+	var timeout time.Duration
+	deadline, reqHasDeadline := ctx.Deadline()
+	if reqHasDeadline {
+		timeout = time.Until(deadline)
+	}
+
+	reqUrl := ac.address + "/odlukaSudaOSporazumu/" + url.PathEscape(sporazumId)
+	_, err := ac.cb.Execute(func() (interface{}, error) {
+		telo, err := json.Marshal(odluka)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, reqUrl, bytes.NewReader(telo))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", bearerToken)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := ac.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, domain.ErrResp{
+				URL:        resp.Request.URL.String(),
+				Method:     resp.Request.Method,
+				StatusCode: resp.StatusCode,
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		if _, ok := err.(domain.ErrResp); ok {
+			return err
+		}
+		return handleHttpReqErr(err, reqUrl, http.MethodPut, timeout)
+	}
+
+	return nil
+}
+
+// PotvrdiPrijemZahteva javlja tuzilastvu da je po zahtevu otvoren predmet, tuzilastvo prihvata ponovljenu potvrdu
+func (ac TuzilastvoClient) PotvrdiPrijemZahteva(ctx context.Context, zahtevId string, prijem data.PrijemZahteva, bearerToken string) error {
+	var timeout time.Duration
+	deadline, reqHasDeadline := ctx.Deadline()
+	if reqHasDeadline {
+		timeout = time.Until(deadline)
+	}
+
+	reqUrl := ac.address + "/prijemZahtevaZaSudskiPostupak/" + url.PathEscape(zahtevId)
+	_, err := ac.cb.Execute(func() (interface{}, error) {
+		telo, err := json.Marshal(prijem)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, reqUrl, bytes.NewReader(telo))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", bearerToken)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := ac.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, domain.ErrResp{
+				URL:        resp.Request.URL.String(),
+				Method:     resp.Request.Method,
+				StatusCode: resp.StatusCode,
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		if _, ok := err.(domain.ErrResp); ok {
+			return err
+		}
+		return handleHttpReqErr(err, reqUrl, http.MethodPut, timeout)
+	}
+
+	return nil
+}
+
+// PosaljiObavestenjeTuzilastvu prosledjuje obavestenje tuziocu u ime suda, tuzilastvo ne upisuje dva puta
+// obavestenje sa istim kljucem
+func (ac TuzilastvoClient) PosaljiObavestenjeTuzilastvu(ctx context.Context, obavestenje data.ObavestenjeTuzilastvu) error {
+	var timeout time.Duration
+	deadline, reqHasDeadline := ctx.Deadline()
+	if reqHasDeadline {
+		timeout = time.Until(deadline)
+	}
+
+	bearerToken, err := helper.ServisniToken()
+	if err != nil {
+		return err
+	}
+
+	reqUrl := ac.address + "/obavestenjeSuda"
+	_, err = ac.cb.Execute(func() (interface{}, error) {
+		telo, err := json.Marshal(obavestenje)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqUrl, bytes.NewReader(telo))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", bearerToken)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := ac.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, domain.ErrResp{
+				URL:        resp.Request.URL.String(),
+				Method:     resp.Request.Method,
+				StatusCode: resp.StatusCode,
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		if _, ok := err.(domain.ErrResp); ok {
+			return err
+		}
+		return handleHttpReqErr(err, reqUrl, http.MethodPost, timeout)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"errors"
+	"net/http"
 	"net/url"
 	"sud_service/domain"
 
@@ -34,3 +35,9 @@ func handleHttpReqErr(err error, reqUrl string, method string, timeout time.Dura
 		Err: urlErr,
 	}
 }
+
+// JeNijePronadjen proverava da li je servis odgovorio sa 404
+func JeNijePronadjen(err error) bool {
+	errResp, ok := err.(domain.ErrResp)
+	return ok && errResp.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,163 @@
+package data
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Norma sudije koji se prvi put pojavljuje u rasporedu
+const PunaNorma = 100
+
+// RolaSudija i RolaPredsednikSuda su role korisnika koji mogu biti u rasporedu za dodelu predmeta
+const RolaSudija = "Sudija"
+
+func JeRolaSudije(rola string) bool {
+	return rola == RolaSudija || rola == RolaPredsednikSuda
+}
+
+// ImeKorisnika vraca ime i prezime za prikaz, a ako ih nema korisnicko ime
+func (k *Korisnik) ImeKorisnika() string {
+	ime := strings.TrimSpace(k.Ime + " " + k.Prezime)
+	if ime == "" {
+		return k.KorisnickoIme
+	}
+	return ime
+}
+
+var ErrNemaDostupnihSudija = errors.New("nema dostupnih sudija za dodelu predmeta")
+
+// NovoSeme vraca seme za izvlacenje iz kriptografski sigurnog izvora, kako ishod ne bi mogao da se predvidi
+func NovoSeme() int64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+	return int64(binary.BigEndian.Uint64(b[:]) >> 1)
+}
+
+// Odsutan vraca razlog odsustva ako je sudija odsutan u trenutku sada
+func (s *Sudija) Odsutan(sada time.Time) (string, bool) {
+	for _, odsustvo := range s.Odsustva {
+		if !sada.Before(odsustvo.Od.Time()) && sada.Before(odsustvo.Do.Time()) {
+			return odsustvo.Razlog, true
+		}
+	}
+	return "", false
+}
+
+// Izuzet vraca razlog izuzeca ako je sudija izuzet iz predmeta
+func (p *Predmet) Izuzet(idSudije primitive.ObjectID) (string, bool) {
+	for _, izuzece := range p.Izuzeca {
+		if izuzece.IdSudije == idSudije {
+			return izuzece.Razlog, true
+		}
+	}
+	return "", false
+}
+
+// Aktivan oznacava predmet o kome jos nije odluceno, samo takvi predmeti ulaze u opterecenje sudije
+func (s *StanjePredmeta) Aktivan() bool {
+	if s.Predmet.Tip == PREDMET_POTVRDA_SPORAZUMA {
+		return s.Predmet.StatusPotvrde == NA_ODLUCIVANJU
+	}
+	return !s.ImaPresudu
+}
+
+// RazlogIskljucenja vraca zasto sudija ne moze dobiti predmet, ili prazan string ako moze
+func RazlogIskljucenja(sudija *Sudija, predmet *Predmet, sada time.Time) string {
+	if !sudija.Aktivan {
+		return "Sudija nije aktivan"
+	}
+	if sudija.Norma <= 0 {
+		return "Sudija ne prima nove predmete"
+	}
+	if razlog, izuzet := predmet.Izuzet(sudija.ID); izuzet {
+		return fmt.Sprintf("Izuzet iz predmeta: %s", razlog)
+	}
+	if razlog, odsutan := sudija.Odsutan(sada); odsutan {
+		return fmt.Sprintf("Odsutan: %s", razlog)
+	}
+	return ""
+}
+
+// PripremiKandidate deli sudije na kandidate i iskljucene. Tezina kandidata je srazmerna normi, a obrnuto
+// srazmerna broju aktivnih predmeta, pa manje optereceni sudije cesce dobijaju nove predmete.
+// Kandidati su poredjani po id-u, kako bi izvlacenje sa istim semenom uvek dalo isti ishod.
+func PripremiKandidate(sudije Sudije, opterecenje map[primitive.ObjectID]int, predmet *Predmet, sada time.Time) ([]KandidatDodele, []IskljucenSudija) {
+	kandidati := []KandidatDodele{}
+	iskljuceni := []IskljucenSudija{}
+	for _, sudija := range sudije {
+		if razlog := RazlogIskljucenja(sudija, predmet, sada); razlog != "" {
+			iskljuceni = append(iskljuceni, IskljucenSudija{IdSudije: sudija.ID, Ime: sudija.Ime, Razlog: razlog})
+			continue
+		}
+		aktivni := opterecenje[sudija.ID]
+		kandidati = append(kandidati, KandidatDodele{
+			IdSudije:        sudija.ID,
+			Ime:             sudija.Ime,
+			AktivniPredmeti: aktivni,
+			Norma:           sudija.Norma,
+			Tezina:          float64(sudija.Norma) / float64(PunaNorma) / float64(1+aktivni),
+		})
+	}
+	sort.Slice(kandidati, func(i, j int) bool {
+		return kandidati[i].IdSudije.Hex() < kandidati[j].IdSudije.Hex()
+	})
+	return kandidati, iskljuceni
+}
+
+// IzvuciSudiju bira kandidata sa verovatnocom srazmernom njegovoj tezini, ishod zavisi samo od semena i kandidata
+func IzvuciSudiju(kandidati []KandidatDodele, seme int64) (primitive.ObjectID, bool) {
+	ukupno := 0.0
+	for _, kandidat := range kandidati {
+		ukupno += kandidat.Tezina
+	}
+	if len(kandidati) == 0 || ukupno <= 0 {
+		return primitive.NilObjectID, false
+	}
+
+	izvuceno := rand.New(rand.NewSource(seme)).Float64() * ukupno
+	for _, kandidat := range kandidati {
+		izvuceno -= kandidat.Tezina
+		if izvuceno < 0 {
+			return kandidat.IdSudije, true
+		}
+	}
+	return kandidati[len(kandidati)-1].IdSudije, true
+}
+
+// SlucajnaDodela izvlaci sudiju za predmet i vraca zapis dodele sa semenom, kandidatima i iskljucenim sudijama
+func SlucajnaDodela(predmet *Predmet, sudije Sudije, opterecenje map[primitive.ObjectID]int, seme int64, sada time.Time) (*DodelaPredmeta, error) {
+	kandidati, iskljuceni := PripremiKandidate(sudije, opterecenje, predmet, sada)
+	idSudije, ok := IzvuciSudiju(kandidati, seme)
+	if !ok {
+		return nil, ErrNemaDostupnihSudija
+	}
+	return &DodelaPredmeta{
+		IdPredmeta:      predmet.ID,
+		Nacin:           DODELA_SLUCAJNA,
+		IdSudije:        idSudije,
+		PrethodniSudija: predmet.IdSudije,
+		Seme:            seme,
+		Kandidati:       kandidati,
+		Iskljuceni:      iskljuceni,
+		Datum:           sada,
+	}, nil
+}
+
+// Proveri ponavlja izvlacenje iz sacuvanog semena i kandidata i poredi ga sa zabelezenim sudijom
+func (d *DodelaPredmeta) Proveri() {
+	if d.Nacin != DODELA_SLUCAJNA {
+		return
+	}
+	idSudije, ok := IzvuciSudiju(d.Kandidati, d.Seme)
+	potvrdjena := ok && idSudije == d.IdSudije
+	d.Potvrdjena = &potvrdjena
+}
@@ -0,0 +1,251 @@
+package data
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var sadaDodele = time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+func sudijaZaDodelu(ime string, norma int) *Sudija {
+	return &Sudija{ID: primitive.NewObjectID(), Ime: ime, Aktivan: true, Norma: norma}
+}
+
+func TestRazlogIskljucenja(t *testing.T) {
+	odsutan := sudijaZaDodelu("Odsutan", PunaNorma)
+	odsutan.Odsustva = []OdsustvoSudije{{
+		Od:     primitive.NewDateTimeFromTime(sadaDodele.AddDate(0, 0, -1)),
+		Do:     primitive.NewDateTimeFromTime(sadaDodele.AddDate(0, 0, 1)),
+		Razlog: "godisnji odmor",
+	}}
+	vratioSe := sudijaZaDodelu("Vratio se", PunaNorma)
+	vratioSe.Odsustva = []OdsustvoSudije{{
+		Od: primitive.NewDateTimeFromTime(sadaDodele.AddDate(0, 0, -10)),
+		Do: primitive.NewDateTimeFromTime(sadaDodele),
+	}}
+	neaktivan := sudijaZaDodelu("Neaktivan", PunaNorma)
+	neaktivan.Aktivan = false
+	izuzet := sudijaZaDodelu("Izuzet", PunaNorma)
+	predmet := &Predmet{Izuzeca: []IzuzeceSudije{{IdSudije: izuzet.ID, Razlog: "srodstvo sa okrivljenim"}}}
+
+	tests := []struct {
+		name   string
+		sudija *Sudija
+		want   string
+	}{
+		{"dostupan sudija", sudijaZaDodelu("Dostupan", PunaNorma), ""},
+		{"umanjena norma", sudijaZaDodelu("Pola norme", PunaNorma/2), ""},
+		{"neaktivan", neaktivan, "Sudija nije aktivan"},
+		{"bez norme", sudijaZaDodelu("Bez norme", 0), "Sudija ne prima nove predmete"},
+		{"izuzet iz predmeta", izuzet, "Izuzet iz predmeta: srodstvo sa okrivljenim"},
+		{"odsutan", odsutan, "Odsutan: godisnji odmor"},
+		{"odsustvo se zavrsilo", vratioSe, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RazlogIskljucenja(tt.sudija, predmet, sadaDodele); got != tt.want {
+				t.Errorf("RazlogIskljucenja() = %q, ocekivano %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPripremiKandidate(t *testing.T) {
+	slobodan := sudijaZaDodelu("Slobodan", PunaNorma)
+	opterecen := sudijaZaDodelu("Opterecen", PunaNorma)
+	polovina := sudijaZaDodelu("Polovina", PunaNorma/2)
+	bezNorme := sudijaZaDodelu("Bez norme", 0)
+	opterecenje := map[primitive.ObjectID]int{opterecen.ID: 3}
+
+	kandidati, iskljuceni := PripremiKandidate(Sudije{polovina, bezNorme, opterecen, slobodan}, opterecenje, &Predmet{}, sadaDodele)
+
+	if len(iskljuceni) != 1 || iskljuceni[0].IdSudije != bezNorme.ID {
+		t.Errorf("iskljuceni = %+v, ocekivan samo sudija bez norme", iskljuceni)
+	}
+	tezine := map[primitive.ObjectID]float64{slobodan.ID: 1, opterecen.ID: 0.25, polovina.ID: 0.5}
+	if len(kandidati) != len(tezine) {
+		t.Fatalf("kandidati = %+v, ocekivano %d", kandidati, len(tezine))
+	}
+	for i, kandidat := range kandidati {
+		if kandidat.Tezina != tezine[kandidat.IdSudije] {
+			t.Errorf("%s: Tezina = %v, ocekivano %v", kandidat.Ime, kandidat.Tezina, tezine[kandidat.IdSudije])
+		}
+		if i > 0 && kandidati[i-1].IdSudije.Hex() >= kandidat.IdSudije.Hex() {
+			t.Errorf("kandidati nisu poredjani po id-u: %s pre %s", kandidati[i-1].IdSudije.Hex(), kandidat.IdSudije.Hex())
+		}
+	}
+}
+
+func TestIzvuciSudiju(t *testing.T) {
+	prvi := KandidatDodele{IdSudije: primitive.NewObjectID(), Tezina: 0.75}
+	drugi := KandidatDodele{IdSudije: primitive.NewObjectID(), Tezina: 0.25}
+
+	tests := []struct {
+		name      string
+		kandidati []KandidatDodele
+		ok        bool
+		// udeo izvlacenja prvog kandidata u 4000 izvlacenja sa semenima 1..4000
+		udeoPrvog float64
+	}{
+		{"bez kandidata", nil, false, 0},
+		{"kandidati bez tezine", []KandidatDodele{{IdSudije: prvi.IdSudije}, {IdSudije: drugi.IdSudije}}, false, 0},
+		{"jedini kandidat", []KandidatDodele{prvi}, true, 1},
+		{"kandidat bez tezine se ne izvlaci", []KandidatDodele{prvi, {IdSudije: drugi.IdSudije}}, true, 1},
+		{"izvlacenje srazmerno tezini", []KandidatDodele{prvi, drugi}, true, 0.75},
+	}
+
+	const izvlacenja = 4000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prvih := 0
+			for seme := int64(1); seme <= izvlacenja; seme++ {
+				idSudije, ok := IzvuciSudiju(tt.kandidati, seme)
+				if ok != tt.ok {
+					t.Fatalf("IzvuciSudiju(seme %d) ok = %v, ocekivano %v", seme, ok, tt.ok)
+				}
+				if !ok {
+					if !idSudije.IsZero() {
+						t.Fatalf("IzvuciSudiju(seme %d) = %s bez kandidata", seme, idSudije.Hex())
+					}
+					return
+				}
+				if ponovo, _ := IzvuciSudiju(tt.kandidati, seme); ponovo != idSudije {
+					t.Fatalf("IzvuciSudiju(seme %d) nije ponovljivo: %s pa %s", seme, idSudije.Hex(), ponovo.Hex())
+				}
+				if idSudije == prvi.IdSudije {
+					prvih++
+				}
+			}
+			if udeo := float64(prvih) / izvlacenja; udeo < tt.udeoPrvog-0.03 || udeo > tt.udeoPrvog+0.03 {
+				t.Errorf("udeo prvog kandidata = %.3f, ocekivano oko %.2f", udeo, tt.udeoPrvog)
+			}
+		})
+	}
+}
+
+func TestSlucajnaDodela(t *testing.T) {
+	sudija := sudijaZaDodelu("Sudija", PunaNorma)
+	drugi := sudijaZaDodelu("Drugi", PunaNorma)
+	neaktivan := sudijaZaDodelu("Neaktivan", PunaNorma)
+	neaktivan.Aktivan = false
+	prethodni := primitive.NewObjectID()
+
+	tests := []struct {
+		name    string
+		sudije  Sudije
+		predmet *Predmet
+		err     error
+	}{
+		{"bez sudija", nil, &Predmet{ID: primitive.NewObjectID()}, ErrNemaDostupnihSudija},
+		{"svi sudije iskljuceni", Sudije{neaktivan}, &Predmet{ID: primitive.NewObjectID()}, ErrNemaDostupnihSudija},
+		{"dodela predmeta", Sudije{sudija, drugi, neaktivan}, &Predmet{ID: primitive.NewObjectID()}, nil},
+		{"predmet se preraspodeljuje", Sudije{sudija, drugi}, &Predmet{ID: primitive.NewObjectID(), IdSudije: prethodni}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dodela, err := SlucajnaDodela(tt.predmet, tt.sudije, nil, 42, sadaDodele)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SlucajnaDodela() greska = %v, ocekivano %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			if dodela.IdPredmeta != tt.predmet.ID || dodela.PrethodniSudija != tt.predmet.IdSudije {
+				t.Errorf("dodela = %+v, ocekivan predmet %s i prethodni sudija %s", dodela, tt.predmet.ID.Hex(), tt.predmet.IdSudije.Hex())
+			}
+			if dodela.Nacin != DODELA_SLUCAJNA || dodela.Seme != 42 || !dodela.Datum.Equal(sadaDodele) {
+				t.Errorf("dodela = %+v, ocekivana slucajna dodela sa semenom 42", dodela)
+			}
+			if len(dodela.Kandidati)+len(dodela.Iskljuceni) != len(tt.sudije) {
+				t.Errorf("zabelezeno %d kandidata i %d iskljucenih, ocekivano ukupno %d", len(dodela.Kandidati), len(dodela.Iskljuceni), len(tt.sudije))
+			}
+
+			dodela.Proveri()
+			if dodela.Potvrdjena == nil || !*dodela.Potvrdjena {
+				t.Errorf("Proveri() nije potvrdio dodelu %+v", dodela)
+			}
+		})
+	}
+}
+
+func potvrdjena(b bool) *bool {
+	return &b
+}
+
+func TestProveriDodelu(t *testing.T) {
+	sudija := sudijaZaDodelu("Sudija", PunaNorma)
+	drugi := sudijaZaDodelu("Drugi", PunaNorma)
+
+	tests := []struct {
+		name   string
+		izmeni func(dodela *DodelaPredmeta)
+		want   *bool
+	}{
+		{"neizmenjena dodela", func(dodela *DodelaPredmeta) {}, potvrdjena(true)},
+		{
+			name: "zamenjen sudija",
+			izmeni: func(dodela *DodelaPredmeta) {
+				if dodela.IdSudije == sudija.ID {
+					dodela.IdSudije = drugi.ID
+				} else {
+					dodela.IdSudije = sudija.ID
+				}
+			},
+			want: potvrdjena(false),
+		},
+		{
+			name:   "uklonjeni kandidati",
+			izmeni: func(dodela *DodelaPredmeta) { dodela.Kandidati = nil },
+			want:   potvrdjena(false),
+		},
+		{
+			name:   "rucna dodela se ne proverava",
+			izmeni: func(dodela *DodelaPredmeta) { dodela.Nacin = DODELA_RUCNA },
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dodela, err := SlucajnaDodela(&Predmet{ID: primitive.NewObjectID()}, Sudije{sudija, drugi}, nil, NovoSeme(), sadaDodele)
+			if err != nil {
+				t.Fatalf("SlucajnaDodela() greska = %v", err)
+			}
+			tt.izmeni(dodela)
+			dodela.Proveri()
+
+			switch {
+			case tt.want == nil && dodela.Potvrdjena != nil:
+				t.Errorf("Potvrdjena = %v, ocekivano bez provere", *dodela.Potvrdjena)
+			case tt.want != nil && dodela.Potvrdjena == nil:
+				t.Errorf("Potvrdjena = nil, ocekivano %v", *tt.want)
+			case tt.want != nil && *dodela.Potvrdjena != *tt.want:
+				t.Errorf("Potvrdjena = %v, ocekivano %v", *dodela.Potvrdjena, *tt.want)
+			}
+		})
+	}
+}
+
+func TestImeKorisnika(t *testing.T) {
+	tests := []struct {
+		korisnik Korisnik
+		want     string
+	}{
+		{Korisnik{Ime: "Petar", Prezime: "Petrovic", KorisnickoIme: "pera"}, "Petar Petrovic"},
+		{Korisnik{Ime: "Petar", KorisnickoIme: "pera"}, "Petar"},
+		{Korisnik{KorisnickoIme: "pera"}, "pera"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.korisnik.ImeKorisnika(); got != tt.want {
+				t.Errorf("ImeKorisnika() = %q, ocekivano %q", got, tt.want)
+			}
+		})
+	}
+}
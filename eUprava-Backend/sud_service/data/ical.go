@@ -0,0 +1,149 @@
+package data
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// Kalendar sadrzi termine zapocete najvise IstorijaKalendaraDana unazad i sve buduce termine
+	IstorijaKalendaraDana = 90
+	prodIdKalendara       = "-//eUprava//Sud//SR"
+	domenKalendara        = "sud.euprava"
+	// RFC 5545 ogranicava red na 75 bajtova, duzi redovi se prelamaju
+	maksDuzinaRedaKalendara = 75
+	formatVremenaKalendara  = "20060102T150405Z"
+)
+
+// NoviTokenKalendara vraca slucajan token za pretplatu na kalendar i njegov hes, u bazi se cuva samo hes
+func NoviTokenKalendara() (string, string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b[:])
+	return token, HesTokenaKalendara(token), nil
+}
+
+func HesTokenaKalendara(token string) string {
+	hes := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hes[:])
+}
+
+// UidTermina je stalan identifikator termina u kalendaru, pa odlozen termin zamenjuje stari dogadjaj
+func (t *TerminSudjenja) UidTermina() string {
+	return t.ID.Hex() + "@" + domenKalendara
+}
+
+// poslednjaPromena vraca vreme poslednje promene termina, a za nepromenjen termin vreme zakazivanja
+func (t *TerminSudjenja) poslednjaPromena() time.Time {
+	if len(t.Istorija) > 0 {
+		return t.Istorija[len(t.Istorija)-1].Datum
+	}
+	return t.ID.Timestamp()
+}
+
+func vremeKalendara(trenutak time.Time) string {
+	return trenutak.UTC().Format(formatVremenaKalendara)
+}
+
+func escapeTekstKalendara(tekst string) string {
+	zamena := strings.NewReplacer("\\", "\\\\", ";", "\\;", ",", "\\,", "\r\n", "\\n", "\n", "\\n", "\r", "")
+	return zamena.Replace(tekst)
+}
+
+// pisacKalendara pise redove kalendara sa CRLF i prelama redove duze od 75 bajtova bez secenja UTF-8 znakova
+type pisacKalendara struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (p *pisacKalendara) red(naziv string, vrednost string) {
+	if p.err != nil {
+		return
+	}
+	red := naziv + ":" + vrednost
+	duzina := maksDuzinaRedaKalendara
+	for len(red) > duzina {
+		granica := duzina
+		for granica > 0 && !utf8.RuneStart(red[granica]) {
+			granica--
+		}
+		if _, p.err = p.w.WriteString(red[:granica] + "\r\n "); p.err != nil {
+			return
+		}
+		red = red[granica:]
+		// nastavak reda pocinje razmakom koji se racuna u duzinu
+		duzina = maksDuzinaRedaKalendara - 1
+	}
+	_, p.err = p.w.WriteString(red + "\r\n")
+}
+
+func (p *pisacKalendara) tekst(naziv string, vrednost string) {
+	p.red(naziv, escapeTekstKalendara(vrednost))
+}
+
+// opisTermina navodi status termina i istoriju promena, od najstarije
+func opisTermina(termin *TerminSudjenja) string {
+	redovi := []string{fmt.Sprintf("Status: %s", termin.TrenutniStatus())}
+	for _, promena := range termin.Istorija {
+		red := fmt.Sprintf("%s %s", formatirajVreme(promena.Datum), promena.Status)
+		if promena.Razlog != "" {
+			red += ": " + promena.Razlog
+		}
+		redovi = append(redovi, red)
+	}
+	return strings.Join(redovi, "\n")
+}
+
+func (p *pisacKalendara) dogadjaj(termin *TerminSudjenja) {
+	p.red("BEGIN", "VEVENT")
+	p.red("UID", termin.UidTermina())
+	p.red("DTSTAMP", vremeKalendara(termin.poslednjaPromena()))
+	p.red("CREATED", vremeKalendara(termin.ID.Timestamp()))
+	p.red("LAST-MODIFIED", vremeKalendara(termin.poslednjaPromena()))
+	// svaka promena termina povecava redni broj, pa klijent zamenjuje ranije preuzet dogadjaj
+	p.red("SEQUENCE", fmt.Sprint(len(termin.Istorija)))
+	p.red("DTSTART", vremeKalendara(termin.Pocetak()))
+	p.red("DTEND", vremeKalendara(termin.KrajTermina()))
+	naslov := "Sudjenje: " + termin.Predmet.Opis
+	status := "CONFIRMED"
+	if termin.TrenutniStatus() == TERMIN_OTKAZAN {
+		naslov = "OTKAZANO - " + naslov
+		status = "CANCELLED"
+	}
+	p.tekst("SUMMARY", naslov)
+	p.tekst("LOCATION", fmt.Sprintf("%s, prostorija %s", termin.Adresa, termin.Prostorija))
+	p.tekst("DESCRIPTION", opisTermina(termin))
+	p.red("STATUS", status)
+	p.red("END", "VEVENT")
+}
+
+// IzveziKalendar pise termine u iCalendar formatu (RFC 5545). Otkazani termini ostaju u kalendaru sa statusom
+// CANCELLED, kako bi ih klijenti uklonili iz vec preuzetog kalendara.
+func IzveziKalendar(w io.Writer, naziv string, termini TerminiSudjenja) error {
+	pisac := &pisacKalendara{w: bufio.NewWriter(w)}
+	pisac.red("BEGIN", "VCALENDAR")
+	pisac.red("VERSION", "2.0")
+	pisac.red("PRODID", prodIdKalendara)
+	pisac.red("CALSCALE", "GREGORIAN")
+	pisac.red("METHOD", "PUBLISH")
+	pisac.tekst("X-WR-CALNAME", naziv)
+	pisac.red("X-WR-TIMEZONE", ZonaSuda.String())
+	for _, termin := range termini {
+		pisac.dogadjaj(termin)
+	}
+	pisac.red("END", "VCALENDAR")
+	if pisac.err != nil {
+		return pisac.err
+	}
+	return pisac.w.Flush()
+}
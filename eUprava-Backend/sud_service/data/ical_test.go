@@ -0,0 +1,209 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestEscapeTekstKalendara(t *testing.T) {
+	tests := []struct {
+		name  string
+		tekst string
+		want  string
+	}{
+		{"obican tekst", "Sudnica 1", "Sudnica 1"},
+		{"zarez i tacka zarez", "Beograd, sud; prizemlje", "Beograd\\, sud\\; prizemlje"},
+		{"obrnuta kosa crta", "C:\\spisi", "C:\\\\spisi"},
+		{"novi red", "prvi\ndrugi", "prvi\\ndrugi"},
+		{"windows novi red", "prvi\r\ndrugi", "prvi\\ndrugi"},
+		{"samostalan povratak", "prvi\rdrugi", "prvidrugi"},
+		{"cirilica i dijakritika", "Суд у Нишу, Čačak", "Суд у Нишу\\, Čačak"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeTekstKalendara(tt.tekst); got != tt.want {
+				t.Errorf("escapeTekstKalendara(%q) = %q, ocekivano %q", tt.tekst, got, tt.want)
+			}
+		})
+	}
+}
+
+// odvijRedove spaja prelomljene redove kalendara u logicke redove
+func odvijRedove(kalendar string) []string {
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(kalendar, "\r\n ", ""), "\r\n"), "\r\n")
+}
+
+func TestPrelamanjeRedaKalendara(t *testing.T) {
+	tests := []struct {
+		name     string
+		vrednost string
+		redova   int
+	}{
+		{"kratak red", "Sudjenje", 1},
+		{"red tacno 75 bajtova", strings.Repeat("a", 75-len("SUMMARY:")), 1},
+		{"red od 76 bajtova", strings.Repeat("a", 76-len("SUMMARY:")), 2},
+		{"dugacak red", strings.Repeat("abcdefghij", 20), 3},
+		{"visebajtni znakovi se ne seku", strings.Repeat("č", 100), 3},
+		{"cirilica", strings.Repeat("Ж", 40) + strings.Repeat("ш", 40), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var izlaz bytes.Buffer
+			pisac := &pisacKalendara{w: bufio.NewWriter(&izlaz)}
+			pisac.red("SUMMARY", tt.vrednost)
+			if err := pisac.w.Flush(); err != nil || pisac.err != nil {
+				t.Fatalf("greska pri pisanju: %v %v", err, pisac.err)
+			}
+
+			kalendar := izlaz.String()
+			if !strings.HasSuffix(kalendar, "\r\n") {
+				t.Errorf("red se ne zavrsava sa CRLF: %q", kalendar)
+			}
+			fizicki := strings.Split(strings.TrimSuffix(kalendar, "\r\n"), "\r\n")
+			if len(fizicki) != tt.redova {
+				t.Errorf("broj redova = %d, ocekivano %d: %q", len(fizicki), tt.redova, kalendar)
+			}
+			for i, red := range fizicki {
+				if len(red) > maksDuzinaRedaKalendara {
+					t.Errorf("red %d ima %d bajtova", i, len(red))
+				}
+				if !utf8.ValidString(red) {
+					t.Errorf("red %d sece UTF-8 znak: %q", i, red)
+				}
+				if i > 0 && !strings.HasPrefix(red, " ") {
+					t.Errorf("nastavak reda %d ne pocinje razmakom: %q", i, red)
+				}
+			}
+			if logicki := odvijRedove(kalendar); len(logicki) != 1 || logicki[0] != "SUMMARY:"+tt.vrednost {
+				t.Errorf("odvijeni red = %q, ocekivano %q", logicki, "SUMMARY:"+tt.vrednost)
+			}
+		})
+	}
+}
+
+func TestIzveziKalendar(t *testing.T) {
+	pocetak := time.Date(2025, time.June, 2, 10, 0, 0, 0, ZonaSuda)
+	termin := func(status StatusTermina, istorija ...PromenaTermina) *TerminSudjenja {
+		return &TerminSudjenja{
+			ID:             primitive.NewObjectIDFromTimestamp(pocetak.AddDate(0, 0, -7)),
+			Adresa:         "Bulevar Nikole Tesle 42, Nis",
+			Prostorija:     "Sudnica 1",
+			Datum:          primitive.NewDateTimeFromTime(pocetak),
+			TrajanjeMinuta: 90,
+			Predmet:        Predmet{Opis: "Krijumcarenje; granicni prelaz Gradina"},
+			Status:         status,
+			Istorija:       istorija,
+		}
+	}
+	otkazivanje := PromenaTermina{Status: TERMIN_OTKAZAN, Razlog: "Bolest sudije", Datum: pocetak.AddDate(0, 0, -1)}
+
+	tests := []struct {
+		name    string
+		termini TerminiSudjenja
+		// redovi koje kalendar mora da sadrzi, posle odvijanja prelomljenih redova
+		redovi []string
+	}{
+		{
+			name:    "prazan kalendar",
+			termini: nil,
+			redovi:  []string{"BEGIN:VCALENDAR", "VERSION:2.0", "X-WR-CALNAME:Sudija Petar Petrovic\\, Nis", "X-WR-TIMEZONE:Europe/Belgrade", "END:VCALENDAR"},
+		},
+		{
+			name:    "zakazan termin",
+			termini: TerminiSudjenja{termin("")},
+			redovi: []string{
+				"BEGIN:VEVENT",
+				"SEQUENCE:0",
+				"DTSTART:20250602T080000Z",
+				"DTEND:20250602T093000Z",
+				"SUMMARY:Sudjenje: Krijumcarenje\\; granicni prelaz Gradina",
+				"LOCATION:Bulevar Nikole Tesle 42\\, Nis\\, prostorija Sudnica 1",
+				"DESCRIPTION:Status: ZAKAZAN",
+				"STATUS:CONFIRMED",
+				"END:VEVENT",
+			},
+		},
+		{
+			name:    "otkazan termin ostaje u kalendaru",
+			termini: TerminiSudjenja{termin(TERMIN_OTKAZAN, otkazivanje)},
+			redovi: []string{
+				"SEQUENCE:1",
+				"DTSTAMP:20250601T080000Z",
+				"LAST-MODIFIED:20250601T080000Z",
+				"SUMMARY:OTKAZANO - Sudjenje: Krijumcarenje\\; granicni prelaz Gradina",
+				"DESCRIPTION:Status: OTKAZAN\\n01.06.2025. 10:00 OTKAZAN: Bolest sudije",
+				"STATUS:CANCELLED",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var izlaz bytes.Buffer
+			if err := IzveziKalendar(&izlaz, "Sudija Petar Petrovic, Nis", tt.termini); err != nil {
+				t.Fatalf("IzveziKalendar() greska = %v", err)
+			}
+
+			kalendar := izlaz.String()
+			for _, red := range strings.Split(strings.TrimSuffix(kalendar, "\r\n"), "\r\n") {
+				if len(red) > maksDuzinaRedaKalendara {
+					t.Errorf("red duzi od %d bajtova: %q", maksDuzinaRedaKalendara, red)
+				}
+			}
+			logicki := odvijRedove(kalendar)
+			if logicki[0] != "BEGIN:VCALENDAR" || logicki[len(logicki)-1] != "END:VCALENDAR" {
+				t.Errorf("kalendar ne pocinje sa BEGIN:VCALENDAR i ne zavrsava se sa END:VCALENDAR: %q", logicki)
+			}
+			sadrzi := map[string]bool{}
+			for _, red := range logicki {
+				sadrzi[red] = true
+			}
+			for _, red := range tt.redovi {
+				if !sadrzi[red] {
+					t.Errorf("kalendar ne sadrzi red %q:\n%s", red, kalendar)
+				}
+			}
+			for _, termin := range tt.termini {
+				if !sadrzi["UID:"+termin.UidTermina()] {
+					t.Errorf("kalendar ne sadrzi UID termina %s", termin.ID.Hex())
+				}
+			}
+		})
+	}
+}
+
+type neispravanPisac struct{}
+
+func (neispravanPisac) Write([]byte) (int, error) {
+	return 0, errors.New("veza prekinuta")
+}
+
+func TestIzveziKalendarGreskaPisanja(t *testing.T) {
+	termini := TerminiSudjenja{{ID: primitive.NewObjectID(), Datum: primitive.NewDateTimeFromTime(time.Now())}}
+	if err := IzveziKalendar(neispravanPisac{}, "Kalendar", termini); err == nil {
+		t.Error("IzveziKalendar() nije vratio gresku pisanja")
+	}
+}
+
+func TestTokenKalendara(t *testing.T) {
+	token, hes, err := NoviTokenKalendara()
+	if err != nil {
+		t.Fatalf("NoviTokenKalendara() greska = %v", err)
+	}
+	if hes != HesTokenaKalendara(token) {
+		t.Errorf("hes tokena = %s, ocekivano %s", hes, HesTokenaKalendara(token))
+	}
+	drugi, _, _ := NoviTokenKalendara()
+	if drugi == token {
+		t.Error("NoviTokenKalendara() je dva puta vratio isti token")
+	}
+}
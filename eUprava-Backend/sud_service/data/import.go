@@ -0,0 +1,14 @@
+package data
+
+// Dodaj broji ishod uvoza zahteva i dodaje stavku u rezultat
+func (r *RezultatImporta) Dodaj(stavka StavkaImporta) {
+	switch stavka.Ishod {
+	case IMPORT_KREIRAN:
+		r.Kreirano++
+	case IMPORT_PRESKOCEN:
+		r.Preskoceno++
+	default:
+		r.Neuspesno++
+	}
+	r.Stavke = append(r.Stavke, stavka)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"io"
+	"time"
 )
 
 type Prelaz struct {
@@ -22,10 +23,19 @@ type Prelaz struct {
 }
 
 type KrivicnaPrijava struct {
-	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Datum  primitive.DateTime `bson:"datum,omitempty" json:"datum"`
-	Opis   string             `bson:"opis,omitempty" json:"opis"`
-	Prelaz Prelaz             `bson:"prelaz,omitempty" json:"prelaz"`
+	ID                  primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Datum               primitive.DateTime  `bson:"datum,omitempty" json:"datum"`
+	Opis                string              `bson:"opis,omitempty" json:"opis"`
+	Prelaz              Prelaz              `bson:"prelaz,omitempty" json:"prelaz"`
+	PravnaKvalifikacija PravnaKvalifikacija `bson:"pravnaKvalifikacija,omitempty" json:"pravnaKvalifikacija"`
+}
+
+// PravnaKvalifikacija je krivicno delo iz prijave, po njoj se biraju pravila rokova
+type PravnaKvalifikacija struct {
+	Zakon string `bson:"zakon,omitempty" json:"zakon"`
+	Clan  string `bson:"clan,omitempty" json:"clan"`
+	Stav  string `bson:"stav,omitempty" json:"stav,omitempty"`
+	Naziv string `bson:"naziv,omitempty" json:"naziv"`
 }
 
 type ZahtevZaSudskiPostupak struct {
@@ -34,38 +44,371 @@ type ZahtevZaSudskiPostupak struct {
 	Datum           primitive.DateTime `bson:"datum,omitempty" json:"datum"`
 	IdTuzioca       primitive.ObjectID `bson:"idTuzioca,omitempty" json:"idTuzioca"`
 	KrivicnaPrijava KrivicnaPrijava    `bson:"krivicnaPrijava,omitempty" json:"krivicnaPrijava"`
+	PredmetId       primitive.ObjectID `bson:"predmetId,omitempty" json:"predmetId,omitempty"`
 }
 
 type Zahtevi []*ZahtevZaSudskiPostupak
 
+type TipPredmeta string
+
+const (
+	PREDMET_SUDSKI_POSTUPAK   = "SUDSKI_POSTUPAK"
+	PREDMET_POTVRDA_SPORAZUMA = "POTVRDA_SPORAZUMA"
+)
+
+type StatusPotvrde string
+
+const (
+	NA_ODLUCIVANJU = "NA_ODLUCIVANJU"
+	POTVRDJEN      = "POTVRDJEN"
+	ODBIJEN        = "ODBIJEN"
+)
+
+// SporazumZaPotvrdu je sporazum o priznanju krivicnog dela koji je tuzilastvo dostavilo sudu na potvrdu,
+// ID je id sporazuma u tuzilastvu
+type SporazumZaPotvrdu struct {
+	ID              primitive.ObjectID `bson:"id" json:"id"`
+	IdZahteva       primitive.ObjectID `bson:"idZahteva,omitempty" json:"idZahteva"`
+	Uslovi          string             `bson:"uslovi,omitempty" json:"uslovi"`
+	Kazna           string             `bson:"kazna,omitempty" json:"kazna"`
+	Datum           primitive.DateTime `bson:"datum,omitempty" json:"datum"`
+	IdTuzioca       primitive.ObjectID `bson:"idTuzioca,omitempty" json:"idTuzioca"`
+	KrivicnaPrijava KrivicnaPrijava    `bson:"krivicnaPrijava,omitempty" json:"krivicnaPrijava"`
+}
+
+// OdlukaOSporazumu je odluka sudije o sporazumu, Dostavljena oznacava da je tuzilastvo potvrdilo prijem odluke
+type OdlukaOSporazumu struct {
+	Potvrdjen    bool               `bson:"potvrdjen" json:"potvrdjen"`
+	Obrazlozenje string             `bson:"obrazlozenje,omitempty" json:"obrazlozenje,omitempty"`
+	IdSudije     primitive.ObjectID `bson:"idSudije,omitempty" json:"idSudije"`
+	IdPredmeta   primitive.ObjectID `bson:"idPredmeta,omitempty" json:"idPredmeta"`
+	IdPresude    primitive.ObjectID `bson:"idPresude,omitempty" json:"idPresude,omitempty"`
+	Datum        primitive.DateTime `bson:"datum,omitempty" json:"datum"`
+	Dostavljena  bool               `bson:"dostavljena" json:"dostavljena"`
+}
+
+// Predmet bez tipa je sudski postupak po zahtevu tuzilastva
 type Predmet struct {
-	ID       primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
-	Opis     string                 `bson:"opis,omitempty" json:"opis"`
-	Datum    primitive.DateTime     `bson:"datum,omitempty" json:"datum"`
-	IdSudije primitive.ObjectID     `bson:"idSudije,omitempty" json:"idSudije"`
-	Zahtev   ZahtevZaSudskiPostupak `bson:"zahtev,omitempty" json:"zahtev"`
+	ID            primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	Opis          string                 `bson:"opis,omitempty" json:"opis"`
+	Datum         primitive.DateTime     `bson:"datum,omitempty" json:"datum"`
+	IdSudije      primitive.ObjectID     `bson:"idSudije,omitempty" json:"idSudije"`
+	Zahtev        ZahtevZaSudskiPostupak `bson:"zahtev,omitempty" json:"zahtev"`
+	Tip           TipPredmeta            `bson:"tip,omitempty" json:"tip,omitempty"`
+	Sporazum      *SporazumZaPotvrdu     `bson:"sporazum,omitempty" json:"sporazum,omitempty"`
+	StatusPotvrde StatusPotvrde          `bson:"statusPotvrde,omitempty" json:"statusPotvrde,omitempty"`
+	Odluka        *OdlukaOSporazumu      `bson:"odluka,omitempty" json:"odluka,omitempty"`
+	Izuzeca       []IzuzeceSudije        `bson:"izuzeca,omitempty" json:"izuzeca,omitempty"`
 }
 type Predmeti []*Predmet
 
+// TerminSudjenja traje od Datum do Kraj. Sudija i okrivljeni se pamte pri zakazivanju, po njima se
+// proverava da li se termin preklapa sa drugim terminima.
 type TerminSudjenja struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Adresa     string             `bson:"adresa,omitempty" json:"adresa"`
-	Datum      primitive.DateTime `bson:"datum,omitempty" json:"datum"`
-	Prostorija string             `bson:"prostorija,omitempty" json:"prostorija"`
-	Predmet    Predmet            `bson:"predmet,omitempty" json:"predmet"`
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Adresa         string             `bson:"adresa,omitempty" json:"adresa"`
+	Datum          primitive.DateTime `bson:"datum,omitempty" json:"datum"`
+	TrajanjeMinuta int                `bson:"trajanjeMinuta,omitempty" json:"trajanjeMinuta"`
+	Kraj           primitive.DateTime `bson:"kraj,omitempty" json:"kraj"`
+	Prostorija     string             `bson:"prostorija,omitempty" json:"prostorija"`
+	IdSudije       primitive.ObjectID `bson:"idSudije,omitempty" json:"idSudije,omitempty"`
+	Okrivljeni     string             `bson:"okrivljeni,omitempty" json:"okrivljeni,omitempty"`
+	Predmet        Predmet            `bson:"predmet,omitempty" json:"predmet"`
+	Status         StatusTermina      `bson:"status,omitempty" json:"status"`
+	Istorija       []PromenaTermina   `bson:"istorija,omitempty" json:"istorija,omitempty"`
 }
 type TerminiSudjenja []*TerminSudjenja
 
+// StatusTermina prati tok sudjenja, termin bez statusa je zakazan. Odlozen termin je pomeren na novi datum
+// i jos nije odrzan.
+type StatusTermina string
+
+const (
+	TERMIN_ZAKAZAN = "ZAKAZAN"
+	TERMIN_ODRZAN  = "ODRZAN"
+	TERMIN_ODLOZEN = "ODLOZEN"
+	TERMIN_OTKAZAN = "OTKAZAN"
+)
+
+// RasporedTermina je vreme i mesto odrzavanja termina
+type RasporedTermina struct {
+	Datum      primitive.DateTime `bson:"datum" json:"datum"`
+	Kraj       primitive.DateTime `bson:"kraj" json:"kraj"`
+	Adresa     string             `bson:"adresa" json:"adresa"`
+	Prostorija string             `bson:"prostorija" json:"prostorija"`
+}
+
+// PromenaTermina je zapis u istoriji termina. Prethodno je raspored pre promene, Novo je popunjeno
+// samo kada je termin odlozen.
+type PromenaTermina struct {
+	Status      StatusTermina      `bson:"status" json:"status"`
+	Razlog      string             `bson:"razlog,omitempty" json:"razlog,omitempty"`
+	Prethodno   RasporedTermina    `bson:"prethodno" json:"prethodno"`
+	Novo        *RasporedTermina   `bson:"novo,omitempty" json:"novo,omitempty"`
+	IdKorisnika primitive.ObjectID `bson:"idKorisnika" json:"idKorisnika"`
+	Datum       time.Time          `bson:"datum" json:"datum"`
+}
+
+// IzmenaTermina je zahtev za odlaganje ili otkazivanje termina, pri otkazivanju se navodi samo razlog.
+// Odlozen termin bez adrese, prostorije ili trajanja zadrzava postojece.
+type IzmenaTermina struct {
+	Datum          primitive.DateTime `json:"datum"`
+	TrajanjeMinuta int                `json:"trajanjeMinuta"`
+	Adresa         string             `json:"adresa"`
+	Prostorija     string             `json:"prostorija"`
+	Razlog         string             `json:"razlog"`
+}
+
+type TipKonflikta string
+
+const (
+	KONFLIKT_PROSTORIJA    = "PROSTORIJA"
+	KONFLIKT_SUDIJA        = "SUDIJA"
+	KONFLIKT_OKRIVLJENI    = "OKRIVLJENI"
+	KONFLIKT_NERADNO_VREME = "NERADNO_VREME"
+)
+
+type KonfliktTermina struct {
+	Tip       TipKonflikta       `json:"tip"`
+	IdTermina primitive.ObjectID `json:"idTermina,omitempty"`
+	Pocetak   time.Time          `json:"pocetak"`
+	Kraj      time.Time          `json:"kraj"`
+	Opis      string             `json:"opis"`
+}
+
+type SlobodanTermin struct {
+	Pocetak time.Time `json:"pocetak"`
+	Kraj    time.Time `json:"kraj"`
+}
+
+// ProveraTermina vraca razloge zbog kojih termin ne moze biti zakazan i prve slobodne termine iste duzine
+type ProveraTermina struct {
+	Konflikti       []KonfliktTermina `json:"konflikti"`
+	SlobodniTermini []SlobodanTermin  `json:"slobodniTermini"`
+}
+
+// NeradniDan je dan kada sud ne radi, a nije drzavni praznik, Datum je u obliku 2006-01-02
+type NeradniDan struct {
+	Datum string `bson:"_id" json:"datum"`
+	Naziv string `bson:"naziv" json:"naziv"`
+}
+
+type NeradniDani []*NeradniDan
+
+// TokenKalendara omogucava pretplatu na kalendar sudjenja bez prijave, ID je id korisnika. Cuva se samo hes
+// tokena, pa korisnik ima najvise jedan vazeci token, a izgubljen token moze samo da zameni novim.
+type TokenKalendara struct {
+	IdKorisnika primitive.ObjectID `bson:"_id" json:"idKorisnika"`
+	HesTokena   string             `bson:"hesTokena" json:"-"`
+	Ime         string             `bson:"ime" json:"ime"`
+	Kreirano    time.Time          `bson:"kreirano" json:"kreirano"`
+}
+
+// PretplataNaKalendar vraca token samo pri izdavanju. Kalendar prostorije se dobija sa istim tokenom
+// uz parametre adresa i prostorija.
+type PretplataNaKalendar struct {
+	Token              string    `json:"token"`
+	KalendarSudije     string    `json:"kalendarSudije"`
+	KalendarProstorije string    `json:"kalendarProstorije"`
+	Kreirano           time.Time `json:"kreirano"`
+}
+
 type Presuda struct {
 	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Opis           string             `bson:"opis,omitempty" json:"opis"`
 	Datum          primitive.DateTime `bson:"datum,omitempty" json:"datum"`
 	TerminSudjenja TerminSudjenja     `bson:"terminSudjenja,omitempty" json:"terminSudjenja"`
 	IdSudije       primitive.ObjectID `bson:"idSudije,omitempty" json:"idSudije"`
+	IdPredmeta     primitive.ObjectID `bson:"idPredmeta,omitempty" json:"idPredmeta,omitempty"`
 }
 
 type Presude []*Presuda
 
+const (
+	KORAK_ZAKAZIVANJE_SUDJENJA = "ZAKAZIVANJE_SUDJENJA"
+	KORAK_ODLUKA_O_SPORAZUMU   = "ODLUKA_O_SPORAZUMU"
+	KORAK_IZRADA_PRESUDE       = "IZRADA_PRESUDE"
+)
+
+// StanjePredmeta sadrzi predmet i podatke iz termina i presuda od kojih zavise rokovi predmeta
+type StanjePredmeta struct {
+	Predmet         *Predmet
+	PoslednjiTermin time.Time
+	ImaPresudu      bool
+}
+
+// RokPostupka je rok predmeta izracunat iz pravila. Kljuc je isti pri svakom racunanju,
+// pa se po njemu prepoznaje vec poslato obavestenje.
+type RokPostupka struct {
+	Kljuc         string             `json:"kljuc"`
+	PredmetId     primitive.ObjectID `json:"predmetId"`
+	OpisPredmeta  string             `json:"opisPredmeta"`
+	Korak         KorakPostupka      `json:"korak"`
+	Naziv         string             `json:"naziv"`
+	Pocetak       time.Time          `json:"pocetak"`
+	Istice        time.Time          `json:"istice"`
+	PreostaloDana int                `json:"preostaloDana"`
+	Nivo          NivoUpozorenja     `json:"nivo,omitempty"`
+	IdPravila     primitive.ObjectID `json:"idPravila"`
+}
+
+// ObavestenjeTuzilastvu javlja tuziocu promenu u sudskom postupku, po kljucu tuzilastvo prepoznaje ponovljeno slanje.
+// PredmetId je id predmeta u tuzilastvu. Neuspelo poslato obavestenje se cuva dok ne bude poslato ili ne istekne,
+// Pokusaja i PoslednjaGreska se ne salju tuzilastvu.
+type ObavestenjeTuzilastvu struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	Kljuc           string             `bson:"kljuc" json:"kljuc"`
+	IdTuzioca       primitive.ObjectID `bson:"idTuzioca" json:"idTuzioca"`
+	PredmetId       primitive.ObjectID `bson:"predmetId,omitempty" json:"predmetId,omitempty"`
+	Tekst           string             `bson:"tekst" json:"tekst"`
+	Istice          time.Time          `bson:"istice" json:"istice"`
+	Pokusaja        int                `bson:"pokusaja,omitempty" json:"-"`
+	PoslednjaGreska string             `bson:"poslednjaGreska,omitempty" json:"-"`
+}
+
+type ObavestenjaTuzilastvu []*ObavestenjeTuzilastvu
+
+// Korisnik sadrzi podatke o korisniku iz auth servisa koji su potrebni sudu
+type Korisnik struct {
+	ID            primitive.ObjectID `json:"id"`
+	Ime           string             `json:"ime"`
+	Prezime       string             `json:"prezime"`
+	KorisnickoIme string             `json:"korisnickoIme"`
+	Rola          string             `json:"rola"`
+}
+
+type Korisnici []*Korisnik
+
+// Sudija je sudija u rasporedu za dodelu predmeta, ID je id korisnika sudije. Norma je procenat punog
+// priliva predmeta koji sudija prima, npr. predsednik suda moze imati umanjenu normu.
+type Sudija struct {
+	ID       primitive.ObjectID `bson:"_id" json:"id"`
+	Ime      string             `bson:"ime,omitempty" json:"ime"`
+	Aktivan  bool               `bson:"aktivan" json:"aktivan"`
+	Norma    int                `bson:"norma" json:"norma"`
+	Odsustva []OdsustvoSudije   `bson:"odsustva,omitempty" json:"odsustva,omitempty"`
+}
+
+type Sudije []*Sudija
+
+type OdsustvoSudije struct {
+	Od     primitive.DateTime `bson:"od" json:"od"`
+	Do     primitive.DateTime `bson:"do" json:"do"`
+	Razlog string             `bson:"razlog,omitempty" json:"razlog"`
+}
+
+// IzuzeceSudije je izuzece sudije iz predmeta, izuzet sudija ne moze ponovo dobiti isti predmet
+type IzuzeceSudije struct {
+	IdSudije primitive.ObjectID `bson:"idSudije" json:"idSudije"`
+	Razlog   string             `bson:"razlog" json:"razlog"`
+	Datum    primitive.DateTime `bson:"datum" json:"datum"`
+}
+
+type NacinDodele string
+
+const (
+	DODELA_SLUCAJNA = "SLUCAJNA"
+	DODELA_RUCNA    = "RUCNA"
+)
+
+// KandidatDodele je sudija koji je ucestvovao u izvlacenju, sa opterecenjem i tezinom koju je tada imao
+type KandidatDodele struct {
+	IdSudije        primitive.ObjectID `bson:"idSudije" json:"idSudije"`
+	Ime             string             `bson:"ime,omitempty" json:"ime"`
+	AktivniPredmeti int                `bson:"aktivniPredmeti" json:"aktivniPredmeti"`
+	Norma           int                `bson:"norma" json:"norma"`
+	Tezina          float64            `bson:"tezina" json:"tezina"`
+}
+
+type IskljucenSudija struct {
+	IdSudije primitive.ObjectID `bson:"idSudije" json:"idSudije"`
+	Ime      string             `bson:"ime,omitempty" json:"ime"`
+	Razlog   string             `bson:"razlog" json:"razlog"`
+}
+
+// DodelaPredmeta belezi svaku dodelu predmeta sudiji. Kod slucajne dodele se cuvaju seme i kandidati
+// redosledom kojim su izvlaceni, pa se izvlacenje moze ponoviti i proveriti.
+type DodelaPredmeta struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	IdPredmeta      primitive.ObjectID `bson:"idPredmeta" json:"idPredmeta"`
+	Nacin           NacinDodele        `bson:"nacin" json:"nacin"`
+	IdSudije        primitive.ObjectID `bson:"idSudije" json:"idSudije"`
+	PrethodniSudija primitive.ObjectID `bson:"prethodniSudija,omitempty" json:"prethodniSudija,omitempty"`
+	Seme            int64              `bson:"seme,omitempty" json:"seme,omitempty"`
+	Kandidati       []KandidatDodele   `bson:"kandidati,omitempty" json:"kandidati,omitempty"`
+	Iskljuceni      []IskljucenSudija  `bson:"iskljuceni,omitempty" json:"iskljuceni,omitempty"`
+	IdDodelio       primitive.ObjectID `bson:"idDodelio,omitempty" json:"idDodelio,omitempty"`
+	Obrazlozenje    string             `bson:"obrazlozenje,omitempty" json:"obrazlozenje,omitempty"`
+	Datum           time.Time          `bson:"datum" json:"datum"`
+	Potvrdjena      *bool              `bson:"-" json:"potvrdjena,omitempty"`
+}
+
+type DodelePredmeta []*DodelaPredmeta
+
+// RucnaDodela je odluka predsednika suda kojom se predmet dodeljuje sudiji mimo slucajne dodele
+type RucnaDodela struct {
+	IdSudije     primitive.ObjectID `json:"idSudije"`
+	Obrazlozenje string             `json:"obrazlozenje"`
+}
+
+// PrijemZahteva je potvrda tuzilastvu da je po zahtevu za sudski postupak otvoren predmet
+type PrijemZahteva struct {
+	IdPredmetaSuda primitive.ObjectID `json:"idPredmetaSuda"`
+	IdSudije       primitive.ObjectID `json:"idSudije,omitempty"`
+	Datum          primitive.DateTime `json:"datum"`
+}
+
+type IshodImporta string
+
+const (
+	IMPORT_KREIRAN   = "KREIRAN"
+	IMPORT_PRESKOCEN = "PRESKOCEN"
+	IMPORT_NEUSPEO   = "NEUSPEO"
+)
+
+// ImportZahteva je stanje uvoza jednog zahteva tuzilastva, ID je id zahteva. Status je KREIRAN cim za zahtev
+// postoji predmet, a Primljen oznacava da je tuzilastvo potvrdilo prijem zahteva.
+type ImportZahteva struct {
+	ID               primitive.ObjectID `bson:"_id" json:"id"`
+	Status           IshodImporta       `bson:"status" json:"status"`
+	IdPredmeta       primitive.ObjectID `bson:"idPredmeta,omitempty" json:"idPredmeta,omitempty"`
+	IdSudije         primitive.ObjectID `bson:"idSudije,omitempty" json:"idSudije,omitempty"`
+	Greska           string             `bson:"greska,omitempty" json:"greska,omitempty"`
+	Primljen         bool               `bson:"primljen" json:"primljen"`
+	BrojPokusaja     int                `bson:"brojPokusaja" json:"brojPokusaja"`
+	PrviPokusaj      time.Time          `bson:"prviPokusaj" json:"prviPokusaj"`
+	PoslednjiPokusaj time.Time          `bson:"poslednjiPokusaj" json:"poslednjiPokusaj"`
+}
+
+type ImportiZahteva []*ImportZahteva
+
+// StavkaImporta je ishod uvoza jednog zahteva u okviru jednog poziva uvoza
+type StavkaImporta struct {
+	IdZahteva  primitive.ObjectID `json:"idZahteva"`
+	Ishod      IshodImporta       `json:"ishod"`
+	IdPredmeta primitive.ObjectID `json:"idPredmeta,omitempty"`
+	IdSudije   primitive.ObjectID `json:"idSudije,omitempty"`
+	Greska     string             `json:"greska,omitempty"`
+	Primljen   bool               `json:"primljen"`
+}
+
+type RezultatImporta struct {
+	Kreirano   int             `json:"kreirano"`
+	Preskoceno int             `json:"preskoceno"`
+	Neuspesno  int             `json:"neuspesno"`
+	Stavke     []StavkaImporta `json:"stavke"`
+}
+
+func (o *SporazumZaPotvrdu) FromJSON(r io.Reader) error {
+	d := json.NewDecoder(r)
+	return d.Decode(o)
+}
+
+func (o *OdlukaOSporazumu) FromJSON(r io.Reader) error {
+	d := json.NewDecoder(r)
+	return d.Decode(o)
+}
+
 func (o *Predmeti) ToJSON(w io.Writer) error {
 	e := json.NewEncoder(w)
 	return e.Encode(o)
@@ -165,3 +508,53 @@ func (o *Presuda) FromJSON(r io.Reader) error {
 	d := json.NewDecoder(r)
 	return d.Decode(o)
 }
+
+func (o *Sudije) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
+
+func (o *Sudija) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
+
+func (o *Sudija) FromJSON(r io.Reader) error {
+	d := json.NewDecoder(r)
+	return d.Decode(o)
+}
+
+func (o *DodelePredmeta) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
+
+func (o *DodelaPredmeta) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
+
+func (o *ImportiZahteva) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
+
+func (o *RezultatImporta) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
+
+func (o *ProveraTermina) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
+
+func (o *NeradniDani) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
+
+func (o *PretplataNaKalendar) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
@@ -0,0 +1,130 @@
+package data
+
+import (
+	"fmt"
+	"rokovi"
+	"sort"
+	"time"
+)
+
+// Pravila rokova, nivoi upozorenja i obavestenja su zajednicki sa tuzilastvom
+type (
+	KorakPostupka  = rokovi.KorakPostupka
+	PraviloRoka    = rokovi.PraviloRoka
+	PravilaRokova  = rokovi.PravilaRokova
+	NivoUpozorenja = rokovi.NivoUpozorenja
+	Obavestenje    = rokovi.Obavestenje
+	Obavestenja    = rokovi.Obavestenja
+)
+
+const (
+	ROK_UPOZORENJE          = rokovi.ROK_UPOZORENJE
+	ROK_HITNO               = rokovi.ROK_HITNO
+	ROK_ISTEKAO             = rokovi.ROK_ISTEKAO
+	OBAVESTENJE_INFORMACIJA = rokovi.OBAVESTENJE_INFORMACIJA
+)
+
+// Podrazumevani period za koji se prikazuju predstojeci rokovi
+const PeriodPredstojecihRokovaDana = 7
+
+// PodrazumevanaPravilaRokova se upisuju pri pokretanju za korake koji nemaju opste pravilo, posle toga se rokovi
+// podesavaju kroz pravila u bazi
+func PodrazumevanaPravilaRokova() PravilaRokova {
+	return PravilaRokova{
+		{Korak: KORAK_ZAKAZIVANJE_SUDJENJA, Naziv: "Zakazivanje glavnog pretresa", BrojDana: 60, UpozorenjeDana: 14, Aktivno: true},
+		{Korak: KORAK_ODLUKA_O_SPORAZUMU, Naziv: "Odluka o sporazumu o priznanju krivicnog dela", BrojDana: 30, UpozorenjeDana: 7, Aktivno: true},
+		{Korak: KORAK_IZRADA_PRESUDE, Naziv: "Izrada presude", BrojDana: 15, UpozorenjeDana: 5, Aktivno: true},
+	}
+}
+
+func PoznatKorakPostupka(korak KorakPostupka) bool {
+	switch korak {
+	case KORAK_ZAKAZIVANJE_SUDJENJA, KORAK_ODLUKA_O_SPORAZUMU, KORAK_IZRADA_PRESUDE:
+		return true
+	}
+	return false
+}
+
+// KrivicnaPrijava vraca prijavu iz zahteva za sudski postupak ili iz sporazuma koji se potvrdjuje
+func (p *Predmet) KrivicnaPrijava() KrivicnaPrijava {
+	if p.Sporazum != nil {
+		return p.Sporazum.KrivicnaPrijava
+	}
+	return p.Zahtev.KrivicnaPrijava
+}
+
+// tekuciKorak vraca korak postupka u kome se predmet nalazi i od kada tece rok za taj korak.
+// Predmet o kome je odluceno nema tekuci korak.
+func (s *StanjePredmeta) tekuciKorak() (KorakPostupka, time.Time, bool) {
+	predmet := s.Predmet
+	if predmet.Tip == PREDMET_POTVRDA_SPORAZUMA {
+		if predmet.StatusPotvrde != NA_ODLUCIVANJU {
+			return "", time.Time{}, false
+		}
+		return KORAK_ODLUKA_O_SPORAZUMU, predmet.Datum.Time(), predmet.Datum != 0
+	}
+
+	if s.ImaPresudu {
+		return "", time.Time{}, false
+	}
+	if !s.PoslednjiTermin.IsZero() {
+		return KORAK_IZRADA_PRESUDE, s.PoslednjiTermin, true
+	}
+	return KORAK_ZAKAZIVANJE_SUDJENJA, predmet.Datum.Time(), predmet.Datum != 0
+}
+
+// IzracunajRokPredmeta racuna rok tekuceg koraka postupka predmeta, ili vraca nil ako predmet nema rok
+func IzracunajRokPredmeta(stanje *StanjePredmeta, pravila PravilaRokova, sada time.Time) *RokPostupka {
+	korak, pocetak, aktuelan := stanje.tekuciKorak()
+	if !aktuelan {
+		return nil
+	}
+	predmet := stanje.Predmet
+	kvalifikacija := predmet.KrivicnaPrijava().PravnaKvalifikacija
+	pravilo := rokovi.IzaberiPraviloRoka(pravila, korak, kvalifikacija.Zakon, kvalifikacija.Clan)
+	if pravilo == nil {
+		return nil
+	}
+
+	istice := pocetak.AddDate(0, 0, pravilo.BrojDana)
+	return &RokPostupka{
+		Kljuc:         fmt.Sprintf("%s:%s:%s", predmet.ID.Hex(), korak, istice.UTC().Format("2006-01-02")),
+		PredmetId:     predmet.ID,
+		OpisPredmeta:  predmet.Opis,
+		Korak:         korak,
+		Naziv:         pravilo.Naziv,
+		Pocetak:       pocetak,
+		Istice:        istice,
+		PreostaloDana: rokovi.PreostaloDana(istice, sada),
+		Nivo:          rokovi.NivoRoka(istice, pravilo.UpozorenjeDana, sada),
+		IdPravila:     pravilo.ID,
+	}
+}
+
+// PredstojeciRokovi vraca istekle rokove i rokove koji isticu u narednih dana dana, od onog koji prvi istice
+func PredstojeciRokovi(stanja []*StanjePredmeta, pravila PravilaRokova, dana int, sada time.Time) []RokPostupka {
+	granica := sada.AddDate(0, 0, dana)
+	predstojeci := []RokPostupka{}
+	for _, stanje := range stanja {
+		rok := IzracunajRokPredmeta(stanje, pravila, sada)
+		if rok != nil && rok.Istice.Before(granica) {
+			predstojeci = append(predstojeci, *rok)
+		}
+	}
+	sort.Slice(predstojeci, func(i, j int) bool {
+		return predstojeci[i].Istice.Before(predstojeci[j].Istice)
+	})
+	return predstojeci
+}
+
+// TekstObavestenja opisuje rok za prikaz sudiji
+func (r *RokPostupka) TekstObavestenja() string {
+	datum := r.Istice.Format("02.01.2006.")
+	switch r.Nivo {
+	case ROK_ISTEKAO:
+		return fmt.Sprintf("Predmet \"%s\": rok \"%s\" je istekao %s", r.OpisPredmeta, r.Naziv, datum)
+	case ROK_HITNO:
+		return fmt.Sprintf("Predmet \"%s\": rok \"%s\" istice %s, preostalo dana: %d", r.OpisPredmeta, r.Naziv, datum, r.PreostaloDana)
+	}
+	return fmt.Sprintf("Predmet \"%s\": rok \"%s\" istice %s", r.OpisPredmeta, r.Naziv, datum)
+}
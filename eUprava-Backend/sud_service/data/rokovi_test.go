@@ -0,0 +1,133 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestIzracunajRokPredmeta(t *testing.T) {
+	sada := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	primljen := sada.AddDate(0, 0, -50)
+	poslednjiTermin := sada.AddDate(0, 0, -13)
+	kvalifikacija := PravnaKvalifikacija{Zakon: "KZ", Clan: "203"}
+
+	pravila := PravilaRokova{
+		{Korak: KORAK_ZAKAZIVANJE_SUDJENJA, Naziv: "Zakazivanje glavnog pretresa", BrojDana: 60, UpozorenjeDana: 14, Aktivno: true},
+		{Korak: KORAK_ZAKAZIVANJE_SUDJENJA, Naziv: "Zakazivanje za tesku kradju", Zakon: "KZ", Clan: "204", BrojDana: 30, UpozorenjeDana: 14, Aktivno: true},
+		{Korak: KORAK_ODLUKA_O_SPORAZUMU, Naziv: "Odluka o sporazumu", Zakon: "KZ", Clan: "203", BrojDana: 30, UpozorenjeDana: 7, Aktivno: true},
+		{Korak: KORAK_IZRADA_PRESUDE, Naziv: "Izrada presude", BrojDana: 15, UpozorenjeDana: 5, Aktivno: true},
+	}
+
+	tests := []struct {
+		name    string
+		stanje  StanjePredmeta
+		korak   KorakPostupka
+		istice  time.Time
+		nivo    NivoUpozorenja
+		nemaRok bool
+	}{
+		{
+			name: "nezakazano sudjenje",
+			stanje: StanjePredmeta{Predmet: &Predmet{
+				Datum:  primitive.NewDateTimeFromTime(primljen),
+				Zahtev: ZahtevZaSudskiPostupak{KrivicnaPrijava: KrivicnaPrijava{PravnaKvalifikacija: kvalifikacija}},
+			}},
+			korak:  KORAK_ZAKAZIVANJE_SUDJENJA,
+			istice: primljen.AddDate(0, 0, 60),
+			nivo:   ROK_UPOZORENJE,
+		},
+		{
+			name: "pravilo za krivicno delo iz zahteva",
+			stanje: StanjePredmeta{Predmet: &Predmet{
+				Datum:  primitive.NewDateTimeFromTime(primljen),
+				Zahtev: ZahtevZaSudskiPostupak{KrivicnaPrijava: KrivicnaPrijava{PravnaKvalifikacija: PravnaKvalifikacija{Zakon: "KZ", Clan: "204"}}},
+			}},
+			korak:  KORAK_ZAKAZIVANJE_SUDJENJA,
+			istice: primljen.AddDate(0, 0, 30),
+			nivo:   ROK_ISTEKAO,
+		},
+		{
+			name: "izrada presude tece od poslednjeg sudjenja",
+			stanje: StanjePredmeta{
+				Predmet:         &Predmet{Datum: primitive.NewDateTimeFromTime(primljen)},
+				PoslednjiTermin: poslednjiTermin,
+			},
+			korak:  KORAK_IZRADA_PRESUDE,
+			istice: poslednjiTermin.AddDate(0, 0, 15),
+			nivo:   ROK_HITNO,
+		},
+		{
+			name: "predmet sa presudom nema rok",
+			stanje: StanjePredmeta{
+				Predmet:         &Predmet{Datum: primitive.NewDateTimeFromTime(primljen)},
+				PoslednjiTermin: poslednjiTermin,
+				ImaPresudu:      true,
+			},
+			nemaRok: true,
+		},
+		{
+			name: "sporazum na odlucivanju koristi prijavu iz sporazuma",
+			stanje: StanjePredmeta{Predmet: &Predmet{
+				Tip:           PREDMET_POTVRDA_SPORAZUMA,
+				StatusPotvrde: NA_ODLUCIVANJU,
+				Datum:         primitive.NewDateTimeFromTime(sada.AddDate(0, 0, -20)),
+				Sporazum:      &SporazumZaPotvrdu{KrivicnaPrijava: KrivicnaPrijava{PravnaKvalifikacija: kvalifikacija}},
+			}},
+			korak:  KORAK_ODLUKA_O_SPORAZUMU,
+			istice: sada.AddDate(0, 0, 10),
+			nivo:   "",
+		},
+		{
+			name: "sporazum za drugo delo nema pravilo",
+			stanje: StanjePredmeta{Predmet: &Predmet{
+				Tip:           PREDMET_POTVRDA_SPORAZUMA,
+				StatusPotvrde: NA_ODLUCIVANJU,
+				Datum:         primitive.NewDateTimeFromTime(sada),
+				Sporazum:      &SporazumZaPotvrdu{},
+			}},
+			nemaRok: true,
+		},
+		{
+			name: "odluceno o sporazumu",
+			stanje: StanjePredmeta{Predmet: &Predmet{
+				Tip:           PREDMET_POTVRDA_SPORAZUMA,
+				StatusPotvrde: POTVRDJEN,
+				Datum:         primitive.NewDateTimeFromTime(sada),
+				Sporazum:      &SporazumZaPotvrdu{KrivicnaPrijava: KrivicnaPrijava{PravnaKvalifikacija: kvalifikacija}},
+			}},
+			nemaRok: true,
+		},
+		{
+			name:    "predmet bez datuma nema rok",
+			stanje:  StanjePredmeta{Predmet: &Predmet{}},
+			nemaRok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.stanje.Predmet.ID = primitive.NewObjectID()
+			rok := IzracunajRokPredmeta(&tt.stanje, pravila, sada)
+			if tt.nemaRok {
+				if rok != nil {
+					t.Fatalf("IzracunajRokPredmeta() = %+v, ocekivano bez roka", rok)
+				}
+				return
+			}
+			if rok == nil {
+				t.Fatal("IzracunajRokPredmeta() = nil, ocekivan rok")
+			}
+			if rok.Korak != tt.korak {
+				t.Errorf("Korak = %s, ocekivano %s", rok.Korak, tt.korak)
+			}
+			if !rok.Istice.Equal(tt.istice) {
+				t.Errorf("Istice = %v, ocekivano %v", rok.Istice, tt.istice)
+			}
+			if rok.Nivo != tt.nivo {
+				t.Errorf("Nivo = %q, ocekivano %q", rok.Nivo, tt.nivo)
+			}
+		})
+	}
+}
@@ -11,17 +11,34 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"regexp"
+	"rokovi"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const (
-	DATABASE           = "sud"
-	COLLECTIONPREDMETI = "predmeti"
-	COLLECTIONTERMINI  = "termini"
-	COLLECTIONPRESUDE  = "presude"
+	DATABASE                   = "sud"
+	COLLECTIONPREDMETI         = "predmeti"
+	COLLECTIONTERMINI          = "termini"
+	COLLECTIONPRESUDE          = "presude"
+	COLLECTIONPRAVILA          = "pravilaRokova"
+	COLLECTIONOBAVESTENJA      = "obavestenja"
+	COLLECTIONSUDIJE           = "sudije"
+	COLLECTIONDODELE           = "dodelePredmeta"
+	COLLECTIONIMPORTI          = "importZahteva"
+	COLLECTIONNERADNIDANI      = "neradniDani"
+	COLLECTIONKALENDARI        = "tokeniKalendara"
+	COLLECTIONNEPOSLATA        = "neposlataObavestenjaTuzilastvu"
+	COLLECTIONRESURSI          = "resursiTermina"
+	COLLECTIONDUPLIKATI        = "duplikatiPredmeta"
+	COLLECTIONDUPLIKATIPRESUDA = "duplikatiPresuda"
 )
 
+// SudRepo kroz ugradjeno rokovi.Skladiste cuva pravila rokova i obavestenja sudija
 type SudRepo struct {
+	*rokovi.Skladiste
 	cli    *mongo.Client
 	logger *log.Logger
 	client *http.Client
@@ -53,10 +70,11 @@ func New(ctx context.Context, logger *log.Logger) (*SudRepo, error) {
 
 	// Return repository with logger and DB client
 	return &SudRepo{
-		cli:    client,
-		logger: logger,
-		client: httpClient,
-		table:  table,
+		Skladiste: rokovi.NewSkladiste(table.Collection(COLLECTIONPRAVILA), table.Collection(COLLECTIONOBAVESTENJA), PodrazumevanaPravilaRokova(), logger),
+		cli:       client,
+		logger:    logger,
+		client:    httpClient,
+		table:     table,
 	}, nil
 }
 
@@ -89,7 +107,7 @@ func (sr *SudRepo) Ping() {
 }
 
 func (sr *SudRepo) DodajPredmet(ctx context.Context, predmet *Predmet) error {
-	rezultat, err := sr.table.Collection(COLLECTIONPREDMETI).InsertOne(context.TODO(), predmet)
+	rezultat, err := sr.table.Collection(COLLECTIONPREDMETI).InsertOne(ctx, predmet)
 
 	if err != nil {
 		log.Println("Greska prilikom dodavanja predmeta")
@@ -99,6 +117,17 @@ func (sr *SudRepo) DodajPredmet(ctx context.Context, predmet *Predmet) error {
 	return nil
 }
 
+// DodajPredmetSaDodelom upisuje predmet i njegovu dodelu sudiji u jednoj transakciji, pa predmet ne postoji
+// bez zabelezene dodele
+func (sr *SudRepo) DodajPredmetSaDodelom(ctx context.Context, predmet *Predmet, dodela *DodelaPredmeta) error {
+	return sr.uTransakciji(ctx, func(ctx mongo.SessionContext) error {
+		if err := sr.DodajPredmet(ctx, predmet); err != nil {
+			return err
+		}
+		return sr.sacuvajDodelu(ctx, dodela)
+	})
+}
+
 func (sr *SudRepo) DobaviPredmete(ctx context.Context) (Predmeti, error) {
 	filter := bson.D{{}}
 	return sr.filterPredmeti(ctx, filter)
@@ -140,10 +169,84 @@ func decodePredmeti(cursor *mongo.Cursor) (predmeti Predmeti, err error) {
 	return
 }
 
+// DodajPredmetPotvrdeSporazuma kreira predmet za potvrdu sporazuma samo ako za sporazum jos ne postoji,
+// pa ponovljeno slanje istog sporazuma iz tuzilastva vraca vec kreirani predmet
+func (sr *SudRepo) DodajPredmetPotvrdeSporazuma(ctx context.Context, sporazum *SporazumZaPotvrdu, sada time.Time) (*Predmet, error) {
+	filter := bson.D{{Key: "tip", Value: PREDMET_POTVRDA_SPORAZUMA}, {Key: "sporazum.id", Value: sporazum.ID}}
+	update := bson.D{{Key: "$setOnInsert", Value: bson.D{
+		{Key: "opis", Value: "Potvrda sporazuma o priznanju krivicnog dela"},
+		{Key: "datum", Value: primitive.NewDateTimeFromTime(sada)},
+		{Key: "sporazum", Value: sporazum},
+		{Key: "statusPotvrde", Value: NA_ODLUCIVANJU},
+	}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var predmet Predmet
+	err := sr.table.Collection(COLLECTIONPREDMETI).FindOneAndUpdate(ctx, filter, update, opts).Decode(&predmet)
+	if mongo.IsDuplicateKeyError(err) {
+		// isti sporazum je istovremeno upisan iz drugog zahteva, ponovljen upit vraca taj predmet
+		err = sr.table.Collection(COLLECTIONPREDMETI).FindOneAndUpdate(ctx, filter, update, opts).Decode(&predmet)
+	}
+	if err != nil {
+		log.Println("Greska prilikom dodavanja predmeta za potvrdu sporazuma")
+		return nil, err
+	}
+	return &predmet, nil
+}
+
+func (sr *SudRepo) DobaviPredmetPoSporazumu(ctx context.Context, sporazumId primitive.ObjectID) (*Predmet, error) {
+	filter := bson.D{{Key: "tip", Value: PREDMET_POTVRDA_SPORAZUMA}, {Key: "sporazum.id", Value: sporazumId}}
+	var predmet Predmet
+
+	err := sr.table.Collection(COLLECTIONPREDMETI).FindOne(ctx, filter).Decode(&predmet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &predmet, nil
+}
+
+// EvidentirajOdlukuOSporazumu upisuje odluku samo ako o sporazumu jos nije odluceno,
+// predmet koji je vec dodeljen sudiji moze da resi samo taj sudija
+func (sr *SudRepo) EvidentirajOdlukuOSporazumu(ctx context.Context, predmetId primitive.ObjectID, odluka OdlukaOSporazumu) (*Predmet, error) {
+	status := ODBIJEN
+	if odluka.Potvrdjen {
+		status = POTVRDJEN
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: predmetId},
+		{Key: "tip", Value: PREDMET_POTVRDA_SPORAZUMA},
+		{Key: "statusPotvrde", Value: NA_ODLUCIVANJU},
+		{Key: "idSudije", Value: bson.D{{Key: "$in", Value: bson.A{nil, odluka.IdSudije}}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "statusPotvrde", Value: status},
+		{Key: "odluka", Value: odluka},
+		{Key: "idSudije", Value: odluka.IdSudije},
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var predmet Predmet
+	err := sr.table.Collection(COLLECTIONPREDMETI).FindOneAndUpdate(ctx, filter, update, opts).Decode(&predmet)
+	if err != nil {
+		return nil, err
+	}
+	return &predmet, nil
+}
+
+func (sr *SudRepo) OznaciOdlukuDostavljenom(ctx context.Context, predmetId primitive.ObjectID) error {
+	filter := bson.D{{Key: "_id", Value: predmetId}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "odluka.dostavljena", Value: true}}}}
+
+	_, err := sr.table.Collection(COLLECTIONPREDMETI).UpdateOne(ctx, filter, update)
+	return err
+}
+
 //TERMINI
 
 func (sr *SudRepo) DodajTermin(ctx context.Context, termin *TerminSudjenja) error {
-	rezultat, err := sr.table.Collection(COLLECTIONTERMINI).InsertOne(context.TODO(), termin)
+	rezultat, err := sr.table.Collection(COLLECTIONTERMINI).InsertOne(ctx, termin)
 
 	if err != nil {
 		log.Println("Greska prilikom dodavanja termina")
@@ -181,6 +284,93 @@ func (sr *SudRepo) DobaviTerminPoID(ctx context.Context, id primitive.ObjectID)
 	return &termin, nil
 }
 
+// DobaviTermineUPeriodu vraca termine koji se preklapaju sa periodom od-do. Termin ne traje duze od
+// MaksTrajanjeTerminaMinuta, pa se traze termini koji pocinju najvise toliko pre pocetka perioda.
+func (sr *SudRepo) DobaviTermineUPeriodu(ctx context.Context, od time.Time, do time.Time) (TerminiSudjenja, error) {
+	filter := bson.D{{Key: "datum", Value: bson.D{
+		{Key: "$gt", Value: primitive.NewDateTimeFromTime(od.Add(-MaksTrajanjeTerminaMinuta * time.Minute))},
+		{Key: "$lt", Value: primitive.NewDateTimeFromTime(do)},
+	}}}
+	termini, err := sr.filterTermini(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	uPeriodu := TerminiSudjenja{}
+	for _, termin := range termini {
+		if termin.KrajTermina().After(od) {
+			uPeriodu = append(uPeriodu, termin)
+		}
+	}
+	return uPeriodu, nil
+}
+
+// PromeniTermin upisuje novi raspored i status termina i dodaje promenu u istoriju. Svaka promena produzava
+// istoriju, pa se termin menja samo ako istorija nije duza nego kada je termin procitan, inace vraca mongo.ErrNoDocuments.
+func (sr *SudRepo) PromeniTermin(ctx context.Context, termin *TerminSudjenja, brojPromena int, promena PromenaTermina) error {
+	filter := bson.D{
+		{Key: "_id", Value: termin.ID},
+		{Key: "istorija." + strconv.Itoa(brojPromena), Value: bson.D{{Key: "$exists", Value: false}}},
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "datum", Value: termin.Datum},
+			{Key: "kraj", Value: termin.Kraj},
+			{Key: "trajanjeMinuta", Value: termin.TrajanjeMinuta},
+			{Key: "adresa", Value: termin.Adresa},
+			{Key: "prostorija", Value: termin.Prostorija},
+			{Key: "status", Value: termin.Status},
+		}},
+		{Key: "$push", Value: bson.D{{Key: "istorija", Value: promena}}},
+	}
+	rezultat, err := sr.table.Collection(COLLECTIONTERMINI).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ZauzmiResurse izvrsava upise u transakciji koja prvo menja zapis svakog resursa termina. Istovremena transakcija
+// nad istim resursom zbog konflikta upisa ne uspeva i ponavlja se, pa pri proveri zauzetosti vidi vec upisan termin.
+func (sr *SudRepo) ZauzmiResurse(ctx context.Context, resursi []string, upisi func(ctx mongo.SessionContext) error) error {
+	kolekcija := sr.table.Collection(COLLECTIONRESURSI)
+	// zapisi resursa se kreiraju pre transakcije, kako istovremeno kreiranje istog zapisa ne bi prekinulo transakciju
+	for _, resurs := range resursi {
+		update := bson.D{{Key: "$setOnInsert", Value: bson.D{{Key: "verzija", Value: 0}}}}
+		_, err := kolekcija.UpdateOne(ctx, bson.D{{Key: "_id", Value: resurs}}, update, options.Update().SetUpsert(true))
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+
+	return sr.uTransakciji(ctx, func(ctx mongo.SessionContext) error {
+		for _, resurs := range resursi {
+			update := bson.D{{Key: "$inc", Value: bson.D{{Key: "verzija", Value: 1}}}}
+			if _, err := kolekcija.UpdateOne(ctx, bson.D{{Key: "_id", Value: resurs}}, update); err != nil {
+				return err
+			}
+		}
+		return upisi(ctx)
+	})
+}
+
+// uTransakciji izvrsava upise u jednoj transakciji, baza mora biti pokrenuta kao replika set
+func (sr *SudRepo) uTransakciji(ctx context.Context, upisi func(ctx mongo.SessionContext) error) error {
+	sesija, err := sr.cli.StartSession()
+	if err != nil {
+		return err
+	}
+	defer sesija.EndSession(ctx)
+
+	_, err = sesija.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		return nil, upisi(ctx)
+	})
+	return err
+}
+
 func decodeTermini(cursor *mongo.Cursor) (termini TerminiSudjenja, err error) {
 	for cursor.Next(context.TODO()) {
 		var termin TerminSudjenja
@@ -207,6 +397,86 @@ func (sr *SudRepo) DodajPresudu(ctx context.Context, presuda *Presuda) error {
 	return nil
 }
 
+// KreirajIndeksePresuda obezbedjuje da za jedno sudjenje postoji najvise jedna presuda. Od presuda koje su pre
+// indeksa donete za isto sudjenje zadrzava se prva, a ostale se premestaju u COLLECTIONDUPLIKATIPRESUDA.
+func (sr *SudRepo) KreirajIndeksePresuda(ctx context.Context) error {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "terminSudjenja._id", Value: bson.D{{Key: "$exists", Value: true}}}}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$terminSudjenja._id"},
+			{Key: "presude", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+			{Key: "broj", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		bson.D{{Key: "$match", Value: bson.D{{Key: "broj", Value: bson.D{{Key: "$gt", Value: 1}}}}}},
+	}
+	cursor, err := sr.table.Collection(COLLECTIONPRESUDE).Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	var grupe []struct {
+		Presude []primitive.ObjectID `bson:"presude"`
+	}
+	if err := cursor.All(ctx, &grupe); err != nil {
+		return err
+	}
+
+	for _, grupa := range grupe {
+		zadrzana, duplikati := grupa.Presude[0], grupa.Presude[1:]
+		err := sr.uTransakciji(ctx, func(ctx mongo.SessionContext) error {
+			uDuplikatima := bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: duplikati}}}}
+			cursor, err := sr.table.Collection(COLLECTIONPRESUDE).Find(ctx, uDuplikatima)
+			if err != nil {
+				return err
+			}
+			var presude []bson.M
+			if err := cursor.All(ctx, &presude); err != nil {
+				return err
+			}
+			dokumenti := make([]interface{}, 0, len(presude))
+			for _, presuda := range presude {
+				presuda["spojenU"] = zadrzana
+				dokumenti = append(dokumenti, presuda)
+			}
+			if len(dokumenti) > 0 {
+				if _, err := sr.table.Collection(COLLECTIONDUPLIKATIPRESUDA).InsertMany(ctx, dokumenti); err != nil {
+					return err
+				}
+			}
+			_, err = sr.table.Collection(COLLECTIONPRESUDE).DeleteMany(ctx, uDuplikatima)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		sr.logger.Printf("Presuda %s zadrzana za sudjenje, premesteno duplikata: %d\n", zadrzana.Hex(), len(duplikati))
+	}
+
+	_, err = sr.table.Collection(COLLECTIONPRESUDE).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "terminSudjenja._id", Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(
+			bson.D{{Key: "terminSudjenja._id", Value: bson.D{{Key: "$exists", Value: true}}}},
+		),
+	})
+	return err
+}
+
+// SacuvajPresuduPoSporazumu upisuje presudu samo ako presuda sa istim id jos ne postoji,
+// kako ponovljena odluka o istom sporazumu ne bi napravila dve presude
+func (sr *SudRepo) SacuvajPresuduPoSporazumu(ctx context.Context, presuda *Presuda) error {
+	dokument := *presuda
+	dokument.ID = primitive.NilObjectID
+	filter := bson.D{{Key: "_id", Value: presuda.ID}}
+	update := bson.D{{Key: "$setOnInsert", Value: dokument}}
+
+	_, err := sr.table.Collection(COLLECTIONPRESUDE).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		log.Println("Greska prilikom dodavanja presude po sporazumu")
+		return err
+	}
+	return nil
+}
+
 func (sr *SudRepo) DobaviPresude(ctx context.Context) (Presude, error) {
 	filter := bson.D{{}}
 	return sr.filterPresude(ctx, filter)
@@ -247,3 +517,622 @@ func decodePresude(cursor *mongo.Cursor) (presude Presude, err error) {
 	err = cursor.Err()
 	return
 }
+
+//ROKOVI
+
+// DobaviStanjaPredmeta vraca predmete za filter, uz datum poslednjeg termina i podatak da li je doneta presuda
+func (sr *SudRepo) DobaviStanjaPredmeta(ctx context.Context, filter interface{}) ([]*StanjePredmeta, error) {
+	predmeti, err := sr.filterPredmeti(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	stanja := make([]*StanjePredmeta, 0, len(predmeti))
+	if len(predmeti) == 0 {
+		return stanja, nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(predmeti))
+	poId := make(map[primitive.ObjectID]*StanjePredmeta, len(predmeti))
+	for _, predmet := range predmeti {
+		stanje := &StanjePredmeta{Predmet: predmet}
+		stanja = append(stanja, stanje)
+		ids = append(ids, predmet.ID)
+		poId[predmet.ID] = stanje
+	}
+
+	termini, err := sr.filterTermini(ctx, bson.D{{Key: "predmet._id", Value: bson.D{{Key: "$in", Value: ids}}}})
+	if err != nil {
+		return nil, err
+	}
+	for _, termin := range termini {
+		if termin.TrenutniStatus() == TERMIN_OTKAZAN {
+			continue
+		}
+		if stanje, ok := poId[termin.Predmet.ID]; ok && termin.Datum.Time().After(stanje.PoslednjiTermin) {
+			stanje.PoslednjiTermin = termin.Datum.Time()
+		}
+	}
+
+	presude, err := sr.filterPresude(ctx, bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "idPredmeta", Value: bson.D{{Key: "$in", Value: ids}}}},
+		bson.D{{Key: "terminSudjenja.predmet._id", Value: bson.D{{Key: "$in", Value: ids}}}},
+	}}})
+	if err != nil {
+		return nil, err
+	}
+	for _, presuda := range presude {
+		if stanje, ok := poId[presuda.IdPredmeta]; ok {
+			stanje.ImaPresudu = true
+		}
+		if stanje, ok := poId[presuda.TerminSudjenja.Predmet.ID]; ok {
+			stanje.ImaPresudu = true
+		}
+	}
+
+	return stanja, nil
+}
+
+func (sr *SudRepo) DobaviStanjePredmeta(ctx context.Context, id primitive.ObjectID) (*StanjePredmeta, error) {
+	stanja, err := sr.DobaviStanjaPredmeta(ctx, bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return nil, err
+	}
+	if len(stanja) == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+	return stanja[0], nil
+}
+
+// KreirajIndekseRokova uz indekse pravila i obavestenja sudija obezbedjuje da se neposlato obavestenje tuzilastvu
+// cuva jednom po kljucu i tuziocu, a baza ga sama brise kada istekne
+func (sr *SudRepo) KreirajIndekseRokova(ctx context.Context) error {
+	if err := sr.Skladiste.KreirajIndekseRokova(ctx); err != nil {
+		return err
+	}
+
+	neposlata := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "kljuc", Value: 1}, {Key: "idTuzioca", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "istice", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+	_, err := sr.table.Collection(COLLECTIONNEPOSLATA).Indexes().CreateMany(ctx, neposlata)
+	return err
+}
+
+// SacuvajNeposlatoObavestenje cuva obavestenje tuzilastvu koje nije poslato, kako bi ga pracenje rokova
+// poslalo ponovo. Ponovljen neuspeh za isti kljuc i tuzioca samo uvecava broj pokusaja.
+func (sr *SudRepo) SacuvajNeposlatoObavestenje(ctx context.Context, obavestenje *ObavestenjeTuzilastvu, greska error) error {
+	filter := bson.D{
+		{Key: "kljuc", Value: obavestenje.Kljuc},
+		{Key: "idTuzioca", Value: obavestenje.IdTuzioca},
+	}
+	update := bson.D{
+		{Key: "$setOnInsert", Value: bson.D{
+			{Key: "predmetId", Value: obavestenje.PredmetId},
+			{Key: "tekst", Value: obavestenje.Tekst},
+			{Key: "istice", Value: obavestenje.Istice},
+		}},
+		{Key: "$inc", Value: bson.D{{Key: "pokusaja", Value: 1}}},
+		{Key: "$set", Value: bson.D{{Key: "poslednjaGreska", Value: greska.Error()}}},
+	}
+	opts := options.Update().SetUpsert(true)
+
+	_, err := sr.table.Collection(COLLECTIONNEPOSLATA).UpdateOne(ctx, filter, update, opts)
+	if mongo.IsDuplicateKeyError(err) {
+		// istovremeni upis iste instance ili druge instance servisa, ponavlja se kao izmena
+		_, err = sr.table.Collection(COLLECTIONNEPOSLATA).UpdateOne(ctx, filter, update)
+	}
+	return err
+}
+
+// DobaviNeposlataObavestenja vraca neposlata obavestenja tuzilastvu koja jos nisu istekla
+func (sr *SudRepo) DobaviNeposlataObavestenja(ctx context.Context, sada time.Time) (ObavestenjaTuzilastvu, error) {
+	filter := bson.D{{Key: "istice", Value: bson.D{{Key: "$gt", Value: sada}}}}
+	cursor, err := sr.table.Collection(COLLECTIONNEPOSLATA).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var obavestenja ObavestenjaTuzilastvu
+	if err := cursor.All(ctx, &obavestenja); err != nil {
+		return nil, err
+	}
+	return obavestenja, nil
+}
+
+func (sr *SudRepo) UkloniNeposlatoObavestenje(ctx context.Context, id primitive.ObjectID) error {
+	_, err := sr.table.Collection(COLLECTIONNEPOSLATA).DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	return err
+}
+
+//DODELA PREDMETA
+
+func (sr *SudRepo) KreirajIndekseDodela(ctx context.Context) error {
+	_, err := sr.table.Collection(COLLECTIONDODELE).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "idPredmeta", Value: 1}, {Key: "datum", Value: 1}},
+	})
+	return err
+}
+
+// PrijaviSudiju dodaje sudiju u raspored sa punom normom ako u njemu jos nije, postojeci sudija se ne menja
+func (sr *SudRepo) PrijaviSudiju(ctx context.Context, id primitive.ObjectID, ime string) error {
+	filter := bson.D{{Key: "_id", Value: id}}
+	update := bson.D{{Key: "$setOnInsert", Value: bson.D{
+		{Key: "ime", Value: ime},
+		{Key: "aktivan", Value: true},
+		{Key: "norma", Value: PunaNorma},
+	}}}
+	_, err := sr.table.Collection(COLLECTIONSUDIJE).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// UskladiRasporedSudija dodaje u raspored korisnike sa rolom sudije koji u njemu jos nisu, a iz dodele
+// iskljucuje sudije iz rasporeda koji tu rolu vise nemaju i opoziva im tokene kalendara. Postojecim sudijama
+// se norma ne menja.
+func (sr *SudRepo) UskladiRasporedSudija(ctx context.Context, korisnici Korisnici) error {
+	ids := bson.A{}
+	for _, korisnik := range korisnici {
+		if err := sr.PrijaviSudiju(ctx, korisnik.ID, korisnik.ImeKorisnika()); err != nil {
+			return err
+		}
+		ids = append(ids, korisnik.ID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$nin", Value: ids}}},
+		{Key: "aktivan", Value: true},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "aktivan", Value: false}}}}
+	rezultat, err := sr.table.Collection(COLLECTIONSUDIJE).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.ModifiedCount > 0 {
+		sr.logger.Printf("Iz dodele predmeta iskljuceno %d sudija bez role sudije", rezultat.ModifiedCount)
+	}
+
+	// korisnik kome je rola sudije oduzeta ne sme vise da dobija kalendar sudjenja
+	opozvani, err := sr.table.Collection(COLLECTIONKALENDARI).DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$nin", Value: ids}}}})
+	if err != nil {
+		return err
+	}
+	if opozvani.DeletedCount > 0 {
+		sr.logger.Printf("Opozvano %d tokena kalendara korisnika bez role sudije", opozvani.DeletedCount)
+	}
+	return nil
+}
+
+func (sr *SudRepo) DobaviSudije(ctx context.Context) (Sudije, error) {
+	cursor, err := sr.table.Collection(COLLECTIONSUDIJE).Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sudije := Sudije{}
+	err = cursor.All(ctx, &sudije)
+	return sudije, err
+}
+
+func (sr *SudRepo) DobaviSudiju(ctx context.Context, id primitive.ObjectID) (*Sudija, error) {
+	var sudija Sudija
+	err := sr.table.Collection(COLLECTIONSUDIJE).FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&sudija)
+	if err != nil {
+		return nil, err
+	}
+	return &sudija, nil
+}
+
+// SacuvajSudiju dodaje sudiju u raspored ili menja ime, aktivnost i normu postojeceg, odsustva ostaju nepromenjena
+func (sr *SudRepo) SacuvajSudiju(ctx context.Context, sudija *Sudija) error {
+	filter := bson.D{{Key: "_id", Value: sudija.ID}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "ime", Value: sudija.Ime},
+		{Key: "aktivan", Value: sudija.Aktivan},
+		{Key: "norma", Value: sudija.Norma},
+	}}}
+	_, err := sr.table.Collection(COLLECTIONSUDIJE).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (sr *SudRepo) DodajOdsustvoSudije(ctx context.Context, id primitive.ObjectID, odsustvo OdsustvoSudije) error {
+	filter := bson.D{{Key: "_id", Value: id}}
+	update := bson.D{{Key: "$push", Value: bson.D{{Key: "odsustva", Value: odsustvo}}}}
+
+	rezultat, err := sr.table.Collection(COLLECTIONSUDIJE).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DobaviOpterecenjeSudija vraca broj predmeta o kojima jos nije odluceno za svakog sudiju iz rasporeda
+func (sr *SudRepo) DobaviOpterecenjeSudija(ctx context.Context, sudije Sudije) (map[primitive.ObjectID]int, error) {
+	opterecenje := make(map[primitive.ObjectID]int, len(sudije))
+	if len(sudije) == 0 {
+		return opterecenje, nil
+	}
+	ids := make([]primitive.ObjectID, 0, len(sudije))
+	for _, sudija := range sudije {
+		ids = append(ids, sudija.ID)
+	}
+
+	stanja, err := sr.DobaviStanjaPredmeta(ctx, bson.D{{Key: "idSudije", Value: bson.D{{Key: "$in", Value: ids}}}})
+	if err != nil {
+		return nil, err
+	}
+	for _, stanje := range stanja {
+		if stanje.Aktivan() {
+			opterecenje[stanje.Predmet.IdSudije]++
+		}
+	}
+	return opterecenje, nil
+}
+
+func (sr *SudRepo) sacuvajDodelu(ctx context.Context, dodela *DodelaPredmeta) error {
+	dodela.ID = primitive.NewObjectID()
+	_, err := sr.table.Collection(COLLECTIONDODELE).InsertOne(ctx, dodela)
+	return err
+}
+
+// DodeliPredmet menja sudiju predmeta samo ako predmet jos uvek ima sudiju od koga dodela polazi,
+// pa od dve istovremene dodele istog predmeta uspeva samo jedna. Dodela se belezi u istoj transakciji.
+func (sr *SudRepo) DodeliPredmet(ctx context.Context, dodela *DodelaPredmeta) error {
+	return sr.uTransakciji(ctx, func(ctx mongo.SessionContext) error {
+		filter := bson.D{{Key: "_id", Value: dodela.IdPredmeta}}
+		if dodela.PrethodniSudija.IsZero() {
+			filter = append(filter, bson.E{Key: "idSudije", Value: bson.D{{Key: "$exists", Value: false}}})
+		} else {
+			filter = append(filter, bson.E{Key: "idSudije", Value: dodela.PrethodniSudija})
+		}
+		update := bson.D{{Key: "$set", Value: bson.D{{Key: "idSudije", Value: dodela.IdSudije}}}}
+
+		rezultat, err := sr.table.Collection(COLLECTIONPREDMETI).UpdateOne(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+		if rezultat.MatchedCount == 0 {
+			return mongo.ErrNoDocuments
+		}
+		return sr.sacuvajDodelu(ctx, dodela)
+	})
+}
+
+// IzuzmiSudiju belezi izuzece sudije iz predmeta i predmet dodeljuje sudiji iz nove dodele,
+// samo ako je izuzeti sudija i dalje sudija predmeta, u istoj transakciji sa belezenjem dodele
+func (sr *SudRepo) IzuzmiSudiju(ctx context.Context, izuzece IzuzeceSudije, dodela *DodelaPredmeta) error {
+	return sr.uTransakciji(ctx, func(ctx mongo.SessionContext) error {
+		filter := bson.D{{Key: "_id", Value: dodela.IdPredmeta}, {Key: "idSudije", Value: izuzece.IdSudije}}
+		update := bson.D{
+			{Key: "$set", Value: bson.D{{Key: "idSudije", Value: dodela.IdSudije}}},
+			{Key: "$push", Value: bson.D{{Key: "izuzeca", Value: izuzece}}},
+		}
+
+		rezultat, err := sr.table.Collection(COLLECTIONPREDMETI).UpdateOne(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+		if rezultat.MatchedCount == 0 {
+			return mongo.ErrNoDocuments
+		}
+		return sr.sacuvajDodelu(ctx, dodela)
+	})
+}
+
+func (sr *SudRepo) DobaviDodelePredmeta(ctx context.Context, idPredmeta primitive.ObjectID) (DodelePredmeta, error) {
+	filter := bson.D{{Key: "idPredmeta", Value: idPredmeta}}
+	cursor, err := sr.table.Collection(COLLECTIONDODELE).Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "datum", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	dodele := DodelePredmeta{}
+	err = cursor.All(ctx, &dodele)
+	return dodele, err
+}
+
+//IMPORT ZAHTEVA
+
+// KreirajIndekseImporta obezbedjuje da za jedan zahtev tuzilastva postoji najvise jedan predmet,
+// i kada se isti zahtevi uvoze istovremeno. Predmeti koji su pre indeksa dupli uvezeni se prvo spajaju.
+func (sr *SudRepo) KreirajIndekseImporta(ctx context.Context) error {
+	if err := sr.spojiDuplePredmete(ctx, "zahtev._id"); err != nil {
+		return err
+	}
+	_, err := sr.table.Collection(COLLECTIONPREDMETI).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "zahtev._id", Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(
+			bson.D{{Key: "zahtev._id", Value: bson.D{{Key: "$exists", Value: true}}}},
+		),
+	})
+	return err
+}
+
+// KreirajIndekseSporazuma obezbedjuje da za jedan sporazum tuzilastva postoji najvise jedan predmet potvrde,
+// i kada vise instanci tuzilastva istovremeno posalje isti sporazum
+func (sr *SudRepo) KreirajIndekseSporazuma(ctx context.Context) error {
+	if err := sr.spojiDuplePredmete(ctx, "sporazum.id"); err != nil {
+		return err
+	}
+	_, err := sr.table.Collection(COLLECTIONPREDMETI).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "sporazum.id", Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(
+			bson.D{{Key: "sporazum.id", Value: bson.D{{Key: "$exists", Value: true}}}},
+		),
+	})
+	return err
+}
+
+// spojiDuplePredmete za svaku vrednost polja koju deli vise predmeta zadrzava jedan predmet, prevezuje
+// termine, presude, dodele, obavestenja i uvoze na njega, a duplikate premesta u COLLECTIONDUPLIKATI
+func (sr *SudRepo) spojiDuplePredmete(ctx context.Context, polje string) error {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.D{{Key: polje, Value: bson.D{{Key: "$exists", Value: true}}}}}},
+		// predmet sa odlukom o sporazumu ima prednost, inace se zadrzava najstariji
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "odluka", Value: -1}, {Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$" + polje},
+			{Key: "predmeti", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+			{Key: "broj", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		bson.D{{Key: "$match", Value: bson.D{{Key: "broj", Value: bson.D{{Key: "$gt", Value: 1}}}}}},
+	}
+	cursor, err := sr.table.Collection(COLLECTIONPREDMETI).Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	var grupe []struct {
+		Predmeti []primitive.ObjectID `bson:"predmeti"`
+	}
+	if err := cursor.All(ctx, &grupe); err != nil {
+		return err
+	}
+
+	for _, grupa := range grupe {
+		zadrzan, duplikati := grupa.Predmeti[0], grupa.Predmeti[1:]
+		err := sr.uTransakciji(ctx, func(ctx mongo.SessionContext) error {
+			return sr.preveziNaPredmet(ctx, zadrzan, duplikati)
+		})
+		if err != nil {
+			return err
+		}
+		sr.logger.Printf("Predmet %s zadrzan za %s, spojeno duplikata: %d\n", zadrzan.Hex(), polje, len(duplikati))
+	}
+	return nil
+}
+
+func (sr *SudRepo) preveziNaPredmet(ctx context.Context, zadrzan primitive.ObjectID, duplikati []primitive.ObjectID) error {
+	uDuplikatima := bson.D{{Key: "$in", Value: duplikati}}
+	prevezivanja := []struct {
+		kolekcija string
+		polje     string
+	}{
+		{COLLECTIONTERMINI, "predmet._id"},
+		{COLLECTIONPRESUDE, "idPredmeta"},
+		{COLLECTIONPRESUDE, "terminSudjenja.predmet._id"},
+		{COLLECTIONDODELE, "idPredmeta"},
+		{COLLECTIONOBAVESTENJA, "predmetId"},
+		{COLLECTIONNEPOSLATA, "predmetId"},
+		{COLLECTIONIMPORTI, "idPredmeta"},
+	}
+	for _, p := range prevezivanja {
+		_, err := sr.table.Collection(p.kolekcija).UpdateMany(ctx,
+			bson.D{{Key: p.polje, Value: uDuplikatima}},
+			bson.D{{Key: "$set", Value: bson.D{{Key: p.polje, Value: zadrzan}}}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	cursor, err := sr.table.Collection(COLLECTIONPREDMETI).Find(ctx, bson.D{{Key: "_id", Value: uDuplikatima}})
+	if err != nil {
+		return err
+	}
+	var predmeti []bson.M
+	if err := cursor.All(ctx, &predmeti); err != nil {
+		return err
+	}
+	dokumenti := make([]interface{}, 0, len(predmeti))
+	for _, predmet := range predmeti {
+		predmet["spojenU"] = zadrzan
+		dokumenti = append(dokumenti, predmet)
+	}
+	if len(dokumenti) > 0 {
+		if _, err := sr.table.Collection(COLLECTIONDUPLIKATI).InsertMany(ctx, dokumenti); err != nil {
+			return err
+		}
+	}
+	_, err = sr.table.Collection(COLLECTIONPREDMETI).DeleteMany(ctx, bson.D{{Key: "_id", Value: uDuplikatima}})
+	return err
+}
+
+func (sr *SudRepo) DobaviPredmetPoZahtevu(ctx context.Context, zahtevId primitive.ObjectID) (*Predmet, error) {
+	var predmet Predmet
+	err := sr.table.Collection(COLLECTIONPREDMETI).FindOne(ctx, bson.D{{Key: "zahtev._id", Value: zahtevId}}).Decode(&predmet)
+	if err != nil {
+		return nil, err
+	}
+	return &predmet, nil
+}
+
+// SacuvajImportZahteva belezi ishod pokusaja uvoza zahteva. Neuspeo pokusaj ne menja status zahteva
+// za koji je predmet vec kreiran.
+func (sr *SudRepo) SacuvajImportZahteva(ctx context.Context, stavka StavkaImporta, sada time.Time) error {
+	filter := bson.D{{Key: "_id", Value: stavka.IdZahteva}}
+	status := IMPORT_KREIRAN
+	if stavka.Ishod == IMPORT_NEUSPEO {
+		status = IMPORT_NEUSPEO
+		filter = append(filter, bson.E{Key: "status", Value: bson.D{{Key: "$ne", Value: IMPORT_KREIRAN}}})
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: status},
+			{Key: "idPredmeta", Value: stavka.IdPredmeta},
+			{Key: "idSudije", Value: stavka.IdSudije},
+			{Key: "greska", Value: stavka.Greska},
+			{Key: "primljen", Value: stavka.Primljen},
+			{Key: "poslednjiPokusaj", Value: sada},
+		}},
+		{Key: "$inc", Value: bson.D{{Key: "brojPokusaja", Value: 1}}},
+		{Key: "$setOnInsert", Value: bson.D{{Key: "prviPokusaj", Value: sada}}},
+	}
+
+	_, err := sr.table.Collection(COLLECTIONIMPORTI).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// DobaviImporteZahteva vraca stanja uvoza zahteva, od poslednjeg pokusaja
+func (sr *SudRepo) DobaviImporteZahteva(ctx context.Context) (ImportiZahteva, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "poslednjiPokusaj", Value: -1}})
+	cursor, err := sr.table.Collection(COLLECTIONIMPORTI).Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	importi := ImportiZahteva{}
+	err = cursor.All(ctx, &importi)
+	return importi, err
+}
+
+//NERADNI DANI
+
+func (sr *SudRepo) DobaviNeradneDane(ctx context.Context) (NeradniDani, error) {
+	cursor, err := sr.table.Collection(COLLECTIONNERADNIDANI).Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	dani := NeradniDani{}
+	err = cursor.All(ctx, &dani)
+	return dani, err
+}
+
+// SacuvajNeradniDan dodaje neradni dan ili menja naziv vec unetog dana
+func (sr *SudRepo) SacuvajNeradniDan(ctx context.Context, dan *NeradniDan) error {
+	filter := bson.D{{Key: "_id", Value: dan.Datum}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "naziv", Value: dan.Naziv}}}}
+	_, err := sr.table.Collection(COLLECTIONNERADNIDANI).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+//KALENDAR
+
+// KreirajIndekseKalendara obezbedjuje da se korisnik po tokenu kalendara pronalazi bez pretrage svih tokena
+// i da se termini sudije za kalendar biraju po indeksu
+func (sr *SudRepo) KreirajIndekseKalendara(ctx context.Context) error {
+	_, err := sr.table.Collection(COLLECTIONKALENDARI).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "hesTokena", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = sr.table.Collection(COLLECTIONTERMINI).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "idSudije", Value: 1}, {Key: "datum", Value: 1}}},
+		{Keys: bson.D{{Key: "predmet.idSudije", Value: 1}, {Key: "datum", Value: 1}}},
+	})
+	return err
+}
+
+// SacuvajTokenKalendara zamenjuje token korisnika novim, pa prethodni token prestaje da vazi
+func (sr *SudRepo) SacuvajTokenKalendara(ctx context.Context, token *TokenKalendara) error {
+	filter := bson.D{{Key: "_id", Value: token.IdKorisnika}}
+	_, err := sr.table.Collection(COLLECTIONKALENDARI).ReplaceOne(ctx, filter, token, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (sr *SudRepo) DobaviTokenKalendara(ctx context.Context, hesTokena string) (*TokenKalendara, error) {
+	var token TokenKalendara
+	err := sr.table.Collection(COLLECTIONKALENDARI).FindOne(ctx, bson.D{{Key: "hesTokena", Value: hesTokena}}).Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// OpozoviTokenKalendara brise token korisnika, a ako korisnik nema token vraca mongo.ErrNoDocuments
+func (sr *SudRepo) OpozoviTokenKalendara(ctx context.Context, idKorisnika primitive.ObjectID) error {
+	rezultat, err := sr.table.Collection(COLLECTIONKALENDARI).DeleteOne(ctx, bson.D{{Key: "_id", Value: idKorisnika}})
+	if err != nil {
+		return err
+	}
+	if rezultat.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DobaviTermineSudijeOd vraca termine sudije koji se zavrsavaju posle trenutka od, poredjane po pocetku. Termin
+// bez sudije pripada sudiji predmeta.
+func (sr *SudRepo) DobaviTermineSudijeOd(ctx context.Context, idSudije primitive.ObjectID, od time.Time) (TerminiSudjenja, error) {
+	uslov := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "idSudije", Value: idSudije}},
+		bson.D{
+			{Key: "idSudije", Value: bson.D{{Key: "$exists", Value: false}}},
+			{Key: "predmet.idSudije", Value: idSudije},
+		},
+	}}}
+	return sr.dobaviTermineOd(ctx, uslov, od)
+}
+
+// DobaviTermineProstorijeOd vraca termine u prostoriji koji se zavrsavaju posle trenutka od, poredjane po pocetku.
+// Adresa i prostorija se porede kao u KljucProstorije, bez obzira na velika slova i razmake.
+func (sr *SudRepo) DobaviTermineProstorijeOd(ctx context.Context, adresa, prostorija string, od time.Time) (TerminiSudjenja, error) {
+	uslov := bson.D{
+		{Key: "adresa", Value: primitive.Regex{Pattern: obrazacTeksta(adresa), Options: "i"}},
+		{Key: "prostorija", Value: primitive.Regex{Pattern: obrazacTeksta(prostorija), Options: "i"}},
+	}
+	return sr.dobaviTermineOd(ctx, uslov, od)
+}
+
+// obrazacTeksta pravi regularni izraz koji prihvata tekst sa bilo kojim razmacima oko i izmedju reci
+func obrazacTeksta(tekst string) string {
+	reci := strings.Fields(tekst)
+	for i, rec := range reci {
+		reci[i] = regexp.QuoteMeta(rec)
+	}
+	return `^\s*` + strings.Join(reci, `\s+`) + `\s*$`
+}
+
+func (sr *SudRepo) dobaviTermineOd(ctx context.Context, uslov bson.D, od time.Time) (TerminiSudjenja, error) {
+	filter := append(bson.D{{Key: "datum", Value: bson.D{
+		{Key: "$gt", Value: primitive.NewDateTimeFromTime(od.Add(-MaksTrajanjeTerminaMinuta * time.Minute))},
+	}}}, uslov...)
+	cursor, err := sr.table.Collection(COLLECTIONTERMINI).Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "datum", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	termini, err := decodeTermini(cursor)
+	if err != nil {
+		return nil, err
+	}
+	uKalendaru := TerminiSudjenja{}
+	for _, termin := range termini {
+		if termin.KrajTermina().After(od) {
+			uKalendaru = append(uKalendaru, termin)
+		}
+	}
+	return uKalendaru, nil
+}
@@ -0,0 +1,369 @@
+package data
+
+import (
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"sort"
+	"strings"
+	"time"
+	_ "time/tzdata"
+)
+
+const (
+	PodrazumevanoTrajanjeTerminaMinuta = 60
+	MinTrajanjeTerminaMinuta           = 15
+	// Termin ne moze trajati duze od radnog dana
+	MaksTrajanjeTerminaMinuta = 8 * 60
+	// Termini pocinju na pun sat ili na svakih KorakTermina posle punog sata
+	KorakTermina = 15 * time.Minute
+	// Radno vreme suda, u satima po vremenskoj zoni suda
+	PocetakRadnogVremena = 8
+	KrajRadnogVremena    = 16
+	// Slobodni termini se traze najvise HorizontSlobodnihTerminaDana unapred
+	HorizontSlobodnihTerminaDana = 60
+	BrojPredlozenihTermina       = 3
+	MaksBrojPredlozenihTermina   = 20
+)
+
+// ZonaSuda je vremenska zona u kojoj vazi radno vreme suda
+var ZonaSuda = ucitajZonuSuda()
+
+func ucitajZonuSuda() *time.Location {
+	zona, err := time.LoadLocation("Europe/Belgrade")
+	if err != nil {
+		return time.Local
+	}
+	return zona
+}
+
+// Pocetak vraca vreme pocetka termina
+func (t *TerminSudjenja) Pocetak() time.Time {
+	return t.Datum.Time()
+}
+
+// KrajTermina vraca vreme zavrsetka termina, za termine zakazane bez trajanja racuna se podrazumevano trajanje
+func (t *TerminSudjenja) KrajTermina() time.Time {
+	if t.Kraj > t.Datum {
+		return t.Kraj.Time()
+	}
+	trajanje := t.TrajanjeMinuta
+	if trajanje <= 0 {
+		trajanje = PodrazumevanoTrajanjeTerminaMinuta
+	}
+	return t.Pocetak().Add(time.Duration(trajanje) * time.Minute)
+}
+
+// SudijaTermina vraca sudiju termina, za termine zakazane bez sudije to je sudija predmeta
+func (t *TerminSudjenja) SudijaTermina() primitive.ObjectID {
+	if !t.IdSudije.IsZero() {
+		return t.IdSudije
+	}
+	return t.Predmet.IdSudije
+}
+
+// RolaPredsednikSuda moze da menja termine svih sudija
+const RolaPredsednikSuda = "PredsednikSuda"
+
+// MozeDaMenja vraca da li korisnik sme da odlozi, otkaze ili oznaci termin odrzanim. To sme sudija termina i
+// predsednik suda.
+func (t *TerminSudjenja) MozeDaMenja(idKorisnika primitive.ObjectID, rola string) bool {
+	return rola == RolaPredsednikSuda || t.SudijaTermina() == idKorisnika
+}
+
+// TrenutniStatus vraca status termina, termin zakazan pre uvodjenja statusa je zakazan
+func (t *TerminSudjenja) TrenutniStatus() StatusTermina {
+	if t.Status == "" {
+		return TERMIN_ZAKAZAN
+	}
+	return t.Status
+}
+
+// Predstoji oznacava termin koji jos nije ni odrzan ni otkazan, samo takav termin moze da se menja
+func (t *TerminSudjenja) Predstoji() bool {
+	status := t.TrenutniStatus()
+	return status == TERMIN_ZAKAZAN || status == TERMIN_ODLOZEN
+}
+
+// Raspored vraca vreme i mesto termina
+func (t *TerminSudjenja) Raspored() RasporedTermina {
+	return RasporedTermina{
+		Datum:      t.Datum,
+		Kraj:       primitive.NewDateTimeFromTime(t.KrajTermina()),
+		Adresa:     t.Adresa,
+		Prostorija: t.Prostorija,
+	}
+}
+
+// KljucPoslednjePromene je isti pri svakom slanju obavestenja o poslednjoj promeni termina,
+// pa ponovljeno slanje ne pravi novo obavestenje
+func (t *TerminSudjenja) KljucPoslednjePromene() string {
+	return fmt.Sprintf("termin:%s:%d", t.ID.Hex(), len(t.Istorija))
+}
+
+func formatirajVreme(trenutak time.Time) string {
+	return trenutak.In(ZonaSuda).Format("02.01.2006. 15:04")
+}
+
+// TekstObavestenja opisuje promenu termina ucesnicima postupka
+func (p *PromenaTermina) TekstObavestenja(opisPredmeta string) string {
+	prethodno := formatirajVreme(p.Prethodno.Datum.Time())
+	switch {
+	case p.Status == TERMIN_ODLOZEN && p.Novo != nil:
+		return fmt.Sprintf("Sudjenje u predmetu \"%s\" zakazano za %s je odlozeno za %s, %s, prostorija %s. Razlog: %s",
+			opisPredmeta, prethodno, formatirajVreme(p.Novo.Datum.Time()), p.Novo.Adresa, p.Novo.Prostorija, p.Razlog)
+	case p.Status == TERMIN_OTKAZAN:
+		return fmt.Sprintf("Sudjenje u predmetu \"%s\" zakazano za %s je otkazano. Razlog: %s", opisPredmeta, prethodno, p.Razlog)
+	}
+	return fmt.Sprintf("Sudjenje u predmetu \"%s\" zakazano za %s je odrzano", opisPredmeta, prethodno)
+}
+
+// OkrivljeniTermina vraca oznaku okrivljenog, za termine zakazane bez nje oznaka se uzima iz predmeta
+func (t *TerminSudjenja) OkrivljeniTermina() string {
+	if t.Okrivljeni != "" {
+		return t.Okrivljeni
+	}
+	return OkrivljeniPrijave(t.Predmet.KrivicnaPrijava())
+}
+
+// OkrivljeniPrijave oznacava okrivljenog iz prijave po JMBG-u, a ako ga nema po broju pasosa ili licne karte
+func OkrivljeniPrijave(prijava KrivicnaPrijava) string {
+	prelaz := prijava.Prelaz
+	switch {
+	case strings.TrimSpace(prelaz.JMBGPutnika) != "":
+		return "JMBG:" + strings.TrimSpace(prelaz.JMBGPutnika)
+	case strings.TrimSpace(prelaz.BrojPasosaPutnika) != "":
+		return "PASOS:" + strings.ToUpper(strings.TrimSpace(prelaz.BrojPasosaPutnika))
+	case strings.TrimSpace(prelaz.BrojLicneKartePutnika) != "":
+		return "LK:" + strings.ToUpper(strings.TrimSpace(prelaz.BrojLicneKartePutnika))
+	}
+	return ""
+}
+
+// KljucProstorije prepoznaje istu sudnicu bez obzira na velika i mala slova i razmake u unosu
+func (t *TerminSudjenja) KljucProstorije() string {
+	normalizuj := func(tekst string) string {
+		return strings.ToLower(strings.Join(strings.Fields(tekst), " "))
+	}
+	return normalizuj(t.Adresa) + "|" + normalizuj(t.Prostorija)
+}
+
+// ResursiTermina vraca kljuceve prostorije, sudije i okrivljenog termina, uredjene tako da ih sve transakcije
+// zakljucavaju istim redom
+func (t *TerminSudjenja) ResursiTermina() []string {
+	resursi := []string{"prostorija:" + t.KljucProstorije()}
+	if okrivljeni := t.OkrivljeniTermina(); okrivljeni != "" {
+		resursi = append(resursi, "okrivljeni:"+okrivljeni)
+	}
+	if sudija := t.SudijaTermina(); !sudija.IsZero() {
+		resursi = append(resursi, "sudija:"+sudija.Hex())
+	}
+	sort.Strings(resursi)
+	return resursi
+}
+
+func preklapaSe(pocetak1, kraj1, pocetak2, kraj2 time.Time) bool {
+	return pocetak1.Before(kraj2) && pocetak2.Before(kraj1)
+}
+
+// zajednickiResursi vraca konflikte ako termin koristi istu prostoriju, istog sudiju ili istog okrivljenog
+// kao novi termin, bez obzira na vreme termina
+func zajednickiResursi(novi *TerminSudjenja, termin *TerminSudjenja) []KonfliktTermina {
+	konflikti := []KonfliktTermina{}
+	konflikt := func(tip TipKonflikta, opis string) {
+		konflikti = append(konflikti, KonfliktTermina{
+			Tip:       tip,
+			IdTermina: termin.ID,
+			Pocetak:   termin.Pocetak(),
+			Kraj:      termin.KrajTermina(),
+			Opis:      opis,
+		})
+	}
+	// otkazan termin ne zauzima ni prostoriju, ni sudiju, ni okrivljenog
+	if termin.TrenutniStatus() == TERMIN_OTKAZAN || (!novi.ID.IsZero() && termin.ID == novi.ID) {
+		return konflikti
+	}
+	if termin.KljucProstorije() == novi.KljucProstorije() {
+		konflikt(KONFLIKT_PROSTORIJA, fmt.Sprintf("Prostorija %s je zauzeta", termin.Prostorija))
+	}
+	if sudija := novi.SudijaTermina(); !sudija.IsZero() && termin.SudijaTermina() == sudija {
+		konflikt(KONFLIKT_SUDIJA, "Sudija u isto vreme ima drugo sudjenje")
+	}
+	if okrivljeni := novi.OkrivljeniTermina(); okrivljeni != "" && termin.OkrivljeniTermina() == okrivljeni {
+		konflikt(KONFLIKT_OKRIVLJENI, "Okrivljeni u isto vreme ima drugo sudjenje")
+	}
+	return konflikti
+}
+
+// PronadjiKonflikte vraca postojece termine koji se vremenski preklapaju sa novim terminom
+// u istoj prostoriji, sa istim sudijom ili sa istim okrivljenim
+func PronadjiKonflikte(novi *TerminSudjenja, postojeci TerminiSudjenja) []KonfliktTermina {
+	konflikti := []KonfliktTermina{}
+	pocetak, kraj := novi.Pocetak(), novi.KrajTermina()
+	for _, termin := range postojeci {
+		if preklapaSe(pocetak, kraj, termin.Pocetak(), termin.KrajTermina()) {
+			konflikti = append(konflikti, zajednickiResursi(novi, termin)...)
+		}
+	}
+	return konflikti
+}
+
+// Kalendar odredjuje radne dane suda iz drzavnih praznika i neradnih dana koje je odredio sud
+type Kalendar struct {
+	neradni map[string]string
+	godine  map[int]bool
+}
+
+func NoviKalendar(neradniDani NeradniDani) *Kalendar {
+	kalendar := &Kalendar{neradni: map[string]string{}, godine: map[int]bool{}}
+	for _, dan := range neradniDani {
+		kalendar.neradni[dan.Datum] = dan.Naziv
+	}
+	return kalendar
+}
+
+// pravoslavniVaskrs racuna datum Vaskrsa po julijanskom kalendaru i pretvara ga u gregorijanski, vazi za 1900-2099
+func pravoslavniVaskrs(godina int) time.Time {
+	a := godina % 4
+	b := godina % 7
+	c := godina % 19
+	d := (19*c + 15) % 30
+	e := (2*a + 4*b - d + 34) % 7
+	mesec := (d + e + 114) / 31
+	dan := (d+e+114)%31 + 1
+	return time.Date(godina, time.Month(mesec), dan, 0, 0, 0, 0, ZonaSuda).AddDate(0, 0, 13)
+}
+
+// dodajPraznike upisuje drzavne praznike za godinu. Kada praznik koji se praznuje u vise dana ili
+// Dan primirja padne u nedelju, ne radi se prvog narednog radnog dana.
+func (k *Kalendar) dodajPraznike(godina int) {
+	if k.godine[godina] {
+		return
+	}
+	k.godine[godina] = true
+
+	dodaj := func(dan time.Time, naziv string) {
+		kljuc := dan.Format("2006-01-02")
+		if _, postoji := k.neradni[kljuc]; !postoji {
+			k.neradni[kljuc] = naziv
+		}
+	}
+	datum := func(mesec time.Month, dan int) time.Time {
+		return time.Date(godina, mesec, dan, 0, 0, 0, 0, ZonaSuda)
+	}
+
+	prenosivi := []struct {
+		dani  []time.Time
+		naziv string
+	}{
+		{[]time.Time{datum(time.January, 1), datum(time.January, 2)}, "Nova godina"},
+		{[]time.Time{datum(time.February, 15), datum(time.February, 16)}, "Sretenje"},
+		{[]time.Time{datum(time.May, 1), datum(time.May, 2)}, "Praznik rada"},
+		{[]time.Time{datum(time.November, 11)}, "Dan primirja"},
+	}
+	for _, praznik := range prenosivi {
+		for _, dan := range praznik.dani {
+			dodaj(dan, praznik.naziv)
+		}
+	}
+	dodaj(datum(time.January, 7), "Bozic")
+	vaskrs := pravoslavniVaskrs(godina)
+	for pomeraj := -2; pomeraj <= 1; pomeraj++ {
+		dodaj(vaskrs.AddDate(0, 0, pomeraj), "Vaskrs")
+	}
+
+	for _, praznik := range prenosivi {
+		for _, dan := range praznik.dani {
+			if dan.Weekday() != time.Sunday {
+				continue
+			}
+			naredni := dan.AddDate(0, 0, 1)
+			for !k.radniDanBezPrenosa(naredni) {
+				naredni = naredni.AddDate(0, 0, 1)
+			}
+			dodaj(naredni, praznik.naziv)
+		}
+	}
+}
+
+func (k *Kalendar) radniDanBezPrenosa(dan time.Time) bool {
+	if dan.Weekday() == time.Saturday || dan.Weekday() == time.Sunday {
+		return false
+	}
+	_, neradan := k.neradni[dan.Format("2006-01-02")]
+	return !neradan
+}
+
+// RadniDan proverava da li sud radi na dan u koji pada trenutak
+func (k *Kalendar) RadniDan(trenutak time.Time) bool {
+	dan := trenutak.In(ZonaSuda)
+	k.dodajPraznike(dan.Year())
+	return k.radniDanBezPrenosa(dan)
+}
+
+func pocetakRadnogDana(dan time.Time) time.Time {
+	dan = dan.In(ZonaSuda)
+	return time.Date(dan.Year(), dan.Month(), dan.Day(), PocetakRadnogVremena, 0, 0, 0, ZonaSuda)
+}
+
+func krajRadnogDana(dan time.Time) time.Time {
+	dan = dan.In(ZonaSuda)
+	return time.Date(dan.Year(), dan.Month(), dan.Day(), KrajRadnogVremena, 0, 0, 0, ZonaSuda)
+}
+
+// URadnomVremenu proverava da li termin pocinje i zavrsava se u radnom vremenu istog radnog dana
+func (k *Kalendar) URadnomVremenu(pocetak, kraj time.Time) bool {
+	if !k.RadniDan(pocetak) {
+		return false
+	}
+	return !pocetak.Before(pocetakRadnogDana(pocetak)) && !kraj.After(krajRadnogDana(pocetak))
+}
+
+func zaokruziNaKorak(trenutak time.Time) time.Time {
+	zaokruzeno := trenutak.Truncate(KorakTermina)
+	if zaokruzeno.Before(trenutak) {
+		zaokruzeno = zaokruzeno.Add(KorakTermina)
+	}
+	return zaokruzeno.In(ZonaSuda)
+}
+
+// SlobodniTermini trazi prve termine u radnom vremenu, pocev od trenutka od, u kojima ni prostorija, ni sudija,
+// ni okrivljeni novog termina nisu zauzeti. Predlozeni termini se medjusobno ne preklapaju.
+func (k *Kalendar) SlobodniTermini(novi *TerminSudjenja, postojeci TerminiSudjenja, od time.Time, broj int) []SlobodanTermin {
+	trajanje := novi.KrajTermina().Sub(novi.Pocetak())
+	zauzeti := TerminiSudjenja{}
+	for _, termin := range postojeci {
+		if len(zajednickiResursi(novi, termin)) > 0 {
+			zauzeti = append(zauzeti, termin)
+		}
+	}
+
+	slobodni := []SlobodanTermin{}
+	kandidat := zaokruziNaKorak(od)
+	granica := od.AddDate(0, 0, HorizontSlobodnihTerminaDana)
+	for len(slobodni) < broj && kandidat.Before(granica) {
+		if !k.RadniDan(kandidat) || kandidat.Add(trajanje).After(krajRadnogDana(kandidat)) {
+			kandidat = pocetakRadnogDana(kandidat.AddDate(0, 0, 1))
+			continue
+		}
+		if kandidat.Before(pocetakRadnogDana(kandidat)) {
+			kandidat = pocetakRadnogDana(kandidat)
+			continue
+		}
+
+		kraj := kandidat.Add(trajanje)
+		slobodan := true
+		for _, termin := range zauzeti {
+			if preklapaSe(kandidat, kraj, termin.Pocetak(), termin.KrajTermina()) {
+				slobodan = false
+				// sledeci kandidat pocinje kada se zauzeti termin zavrsi
+				kandidat = zaokruziNaKorak(termin.KrajTermina())
+				break
+			}
+		}
+		if slobodan {
+			slobodni = append(slobodni, SlobodanTermin{Pocetak: kandidat, Kraj: kraj})
+			kandidat = zaokruziNaKorak(kraj)
+		}
+	}
+	return slobodni
+}
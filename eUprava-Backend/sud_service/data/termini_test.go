@@ -0,0 +1,315 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func uZoniSuda(godina int, mesec time.Month, dan, sat, minut int) time.Time {
+	return time.Date(godina, mesec, dan, sat, minut, 0, 0, ZonaSuda)
+}
+
+func terminU(pocetak time.Time, trajanjeMinuta int, prostorija string, idSudije primitive.ObjectID, okrivljeni string) *TerminSudjenja {
+	return &TerminSudjenja{
+		ID:             primitive.NewObjectID(),
+		Adresa:         "Bulevar Nikole Tesle 42",
+		Datum:          primitive.NewDateTimeFromTime(pocetak),
+		TrajanjeMinuta: trajanjeMinuta,
+		Prostorija:     prostorija,
+		IdSudije:       idSudije,
+		Okrivljeni:     okrivljeni,
+	}
+}
+
+func TestPronadjiKonflikte(t *testing.T) {
+	sudija := primitive.NewObjectID()
+	drugiSudija := primitive.NewObjectID()
+	deset := uZoniSuda(2025, time.June, 2, 10, 0)
+	novi := terminU(deset, 60, "Sudnica 1", sudija, "JMBG:0101990710000")
+
+	tests := []struct {
+		name      string
+		postojeci func() *TerminSudjenja
+		want      []TipKonflikta
+	}{
+		{
+			name:      "druga prostorija, sudija i okrivljeni",
+			postojeci: func() *TerminSudjenja { return terminU(deset, 60, "Sudnica 2", drugiSudija, "JMBG:1") },
+			want:      nil,
+		},
+		{
+			name: "ista prostorija bez obzira na velika slova i razmake",
+			postojeci: func() *TerminSudjenja {
+				termin := terminU(deset.Add(30*time.Minute), 60, "  sudnica   1 ", drugiSudija, "")
+				termin.Adresa = "bulevar nikole tesle 42"
+				return termin
+			},
+			want: []TipKonflikta{KONFLIKT_PROSTORIJA},
+		},
+		{
+			name:      "termin koji se zavrsava kada novi pocinje",
+			postojeci: func() *TerminSudjenja { return terminU(deset.Add(-time.Hour), 60, "Sudnica 1", sudija, "") },
+			want:      nil,
+		},
+		{
+			name:      "termin koji pocinje kada se novi zavrsava",
+			postojeci: func() *TerminSudjenja { return terminU(deset.Add(time.Hour), 30, "Sudnica 1", sudija, "") },
+			want:      nil,
+		},
+		{
+			name:      "isti sudija u drugoj prostoriji",
+			postojeci: func() *TerminSudjenja { return terminU(deset.Add(-30*time.Minute), 60, "Sudnica 2", sudija, "") },
+			want:      []TipKonflikta{KONFLIKT_SUDIJA},
+		},
+		{
+			name: "sudija termina zakazanog bez sudije je sudija predmeta",
+			postojeci: func() *TerminSudjenja {
+				termin := terminU(deset, 60, "Sudnica 2", primitive.NilObjectID, "")
+				termin.Predmet.IdSudije = sudija
+				return termin
+			},
+			want: []TipKonflikta{KONFLIKT_SUDIJA},
+		},
+		{
+			name: "isti okrivljeni iz prijave predmeta",
+			postojeci: func() *TerminSudjenja {
+				termin := terminU(deset, 60, "Sudnica 2", drugiSudija, "")
+				termin.Predmet.Zahtev.KrivicnaPrijava.Prelaz.JMBGPutnika = " 0101990710000 "
+				return termin
+			},
+			want: []TipKonflikta{KONFLIKT_OKRIVLJENI},
+		},
+		{
+			name: "termin bez trajanja traje podrazumevano",
+			postojeci: func() *TerminSudjenja {
+				return terminU(deset.Add(-PodrazumevanoTrajanjeTerminaMinuta*time.Minute+time.Minute), 0, "Sudnica 1", drugiSudija, "")
+			},
+			want: []TipKonflikta{KONFLIKT_PROSTORIJA},
+		},
+		{
+			name:      "sve se preklapa",
+			postojeci: func() *TerminSudjenja { return terminU(deset, 15, "Sudnica 1", sudija, "JMBG:0101990710000") },
+			want:      []TipKonflikta{KONFLIKT_PROSTORIJA, KONFLIKT_SUDIJA, KONFLIKT_OKRIVLJENI},
+		},
+		{
+			name: "otkazan termin ne zauzima nista",
+			postojeci: func() *TerminSudjenja {
+				termin := terminU(deset, 60, "Sudnica 1", sudija, "JMBG:0101990710000")
+				termin.Status = TERMIN_OTKAZAN
+				return termin
+			},
+			want: nil,
+		},
+		{
+			name: "termin koji se pomera nije u konfliktu sam sa sobom",
+			postojeci: func() *TerminSudjenja {
+				termin := terminU(deset, 60, "Sudnica 1", sudija, "JMBG:0101990710000")
+				termin.ID = novi.ID
+				return termin
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postojeci := tt.postojeci()
+			konflikti := PronadjiKonflikte(novi, TerminiSudjenja{postojeci})
+			tipovi := []TipKonflikta{}
+			for _, konflikt := range konflikti {
+				tipovi = append(tipovi, konflikt.Tip)
+				if konflikt.IdTermina != postojeci.ID {
+					t.Errorf("IdTermina = %s, ocekivano %s", konflikt.IdTermina.Hex(), postojeci.ID.Hex())
+				}
+			}
+			if len(tipovi) != len(tt.want) || (len(tt.want) > 0 && !reflect.DeepEqual(tipovi, tt.want)) {
+				t.Errorf("PronadjiKonflikte() = %v, ocekivano %v", tipovi, tt.want)
+			}
+		})
+	}
+}
+
+func TestPravoslavniVaskrs(t *testing.T) {
+	tests := []struct {
+		godina int
+		want   time.Time
+	}{
+		{2000, uZoniSuda(2000, time.April, 30, 0, 0)},
+		{2021, uZoniSuda(2021, time.May, 2, 0, 0)},
+		{2022, uZoniSuda(2022, time.April, 24, 0, 0)},
+		{2023, uZoniSuda(2023, time.April, 16, 0, 0)},
+		{2024, uZoniSuda(2024, time.May, 5, 0, 0)},
+		{2025, uZoniSuda(2025, time.April, 20, 0, 0)},
+		{2026, uZoniSuda(2026, time.April, 12, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want.Format("2006"), func(t *testing.T) {
+			if got := pravoslavniVaskrs(tt.godina); !got.Equal(tt.want) {
+				t.Errorf("pravoslavniVaskrs(%d) = %s, ocekivano %s", tt.godina, got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestRadniDan(t *testing.T) {
+	kalendar := NoviKalendar(NeradniDani{{Datum: "2025-06-03", Naziv: "Dan suda"}})
+
+	tests := []struct {
+		name string
+		dan  time.Time
+		want bool
+	}{
+		{"obican radni dan", uZoniSuda(2025, time.June, 2, 10, 0), true},
+		{"subota", uZoniSuda(2025, time.June, 7, 10, 0), false},
+		{"nedelja", uZoniSuda(2025, time.June, 8, 10, 0), false},
+		{"neradni dan suda", uZoniSuda(2025, time.June, 3, 10, 0), false},
+		{"Nova godina", uZoniSuda(2025, time.January, 2, 10, 0), false},
+		{"Bozic", uZoniSuda(2025, time.January, 7, 10, 0), false},
+		{"Veliki petak", uZoniSuda(2025, time.April, 18, 10, 0), false},
+		{"Vaskrsnji ponedeljak", uZoniSuda(2025, time.April, 21, 10, 0), false},
+		{"dan posle Vaskrsa", uZoniSuda(2025, time.April, 22, 10, 0), true},
+		{"Sretenje u nedelju se prenosi na utorak", uZoniSuda(2026, time.February, 17, 10, 0), false},
+		{"posle prenetog Sretenja", uZoniSuda(2026, time.February, 18, 10, 0), true},
+		{"Dan primirja u nedelju se prenosi na ponedeljak", uZoniSuda(2018, time.November, 12, 10, 0), false},
+		{"Dan primirja u subotu se ne prenosi", uZoniSuda(2023, time.November, 13, 10, 0), true},
+		{"Praznik rada prenet preko Vaskrsnjeg ponedeljka", uZoniSuda(2021, time.May, 4, 10, 0), false},
+		{"posle prenetog Praznika rada", uZoniSuda(2021, time.May, 5, 10, 0), true},
+		{"kasno uvece po UTC je sledeci dan suda", time.Date(2025, time.January, 6, 23, 30, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := kalendar.RadniDan(tt.dan); got != tt.want {
+				t.Errorf("RadniDan(%s) = %v, ocekivano %v", tt.dan.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURadnomVremenu(t *testing.T) {
+	kalendar := NoviKalendar(nil)
+
+	tests := []struct {
+		name    string
+		pocetak time.Time
+		kraj    time.Time
+		want    bool
+	}{
+		{"pocetak radnog vremena", uZoniSuda(2025, time.June, 2, 8, 0), uZoniSuda(2025, time.June, 2, 9, 0), true},
+		{"kraj radnog vremena", uZoniSuda(2025, time.June, 2, 15, 0), uZoniSuda(2025, time.June, 2, 16, 0), true},
+		{"pre radnog vremena", uZoniSuda(2025, time.June, 2, 7, 45), uZoniSuda(2025, time.June, 2, 8, 45), false},
+		{"posle radnog vremena", uZoniSuda(2025, time.June, 2, 15, 30), uZoniSuda(2025, time.June, 2, 16, 15), false},
+		{"vikend", uZoniSuda(2025, time.June, 7, 10, 0), uZoniSuda(2025, time.June, 7, 11, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := kalendar.URadnomVremenu(tt.pocetak, tt.kraj); got != tt.want {
+				t.Errorf("URadnomVremenu() = %v, ocekivano %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlobodniTermini(t *testing.T) {
+	sudija := primitive.NewObjectID()
+	drugiSudija := primitive.NewObjectID()
+	ponedeljak := func(sat, minut int) time.Time { return uZoniSuda(2025, time.June, 2, sat, minut) }
+
+	tests := []struct {
+		name      string
+		trajanje  int
+		postojeci TerminiSudjenja
+		od        time.Time
+		broj      int
+		want      []time.Time
+	}{
+		{
+			name:     "pre radnog vremena pocinje od osam",
+			trajanje: 60,
+			od:       ponedeljak(7, 10),
+			broj:     3,
+			want:     []time.Time{ponedeljak(8, 0), ponedeljak(9, 0), ponedeljak(10, 0)},
+		},
+		{
+			name:     "pocetak se zaokruzuje na korak termina",
+			trajanje: 30,
+			od:       ponedeljak(9, 7),
+			broj:     2,
+			want:     []time.Time{ponedeljak(9, 15), ponedeljak(9, 45)},
+		},
+		{
+			name:     "zauzeta prostorija pomera predlog",
+			trajanje: 60,
+			postojeci: TerminiSudjenja{
+				terminU(ponedeljak(8, 0), 90, "Sudnica 1", drugiSudija, ""),
+			},
+			od:   ponedeljak(8, 0),
+			broj: 2,
+			want: []time.Time{ponedeljak(9, 30), ponedeljak(10, 30)},
+		},
+		{
+			name:     "zauzet sudija pomera predlog",
+			trajanje: 60,
+			postojeci: TerminiSudjenja{
+				terminU(ponedeljak(8, 30), 20, "Sudnica 7", sudija, ""),
+			},
+			od:   ponedeljak(8, 0),
+			broj: 1,
+			want: []time.Time{ponedeljak(9, 0)},
+		},
+		{
+			name:     "druga prostorija i drugi sudija ne smetaju",
+			trajanje: 60,
+			postojeci: TerminiSudjenja{
+				terminU(ponedeljak(8, 0), 120, "Sudnica 2", drugiSudija, ""),
+			},
+			od:   ponedeljak(8, 0),
+			broj: 1,
+			want: []time.Time{ponedeljak(8, 0)},
+		},
+		{
+			name:     "termin koji ne stane do kraja dana prelazi na sledeci radni dan",
+			trajanje: 60,
+			od:       uZoniSuda(2025, time.June, 6, 15, 20),
+			broj:     2,
+			want:     []time.Time{uZoniSuda(2025, time.June, 9, 8, 0), uZoniSuda(2025, time.June, 9, 9, 0)},
+		},
+		{
+			name:     "praznici se preskacu",
+			trajanje: 60,
+			od:       uZoniSuda(2025, time.April, 17, 15, 30),
+			broj:     1,
+			want:     []time.Time{uZoniSuda(2025, time.April, 22, 8, 0)},
+		},
+		{
+			name:     "termin duzi od radnog dana nema slobodnih termina",
+			trajanje: 9 * 60,
+			od:       ponedeljak(8, 0),
+			broj:     1,
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			novi := terminU(tt.od, tt.trajanje, "Sudnica 1", sudija, "")
+			slobodni := NoviKalendar(nil).SlobodniTermini(novi, tt.postojeci, tt.od, tt.broj)
+			if len(slobodni) != len(tt.want) {
+				t.Fatalf("SlobodniTermini() = %v, ocekivano %v", slobodni, tt.want)
+			}
+			for i, pocetak := range tt.want {
+				if !slobodni[i].Pocetak.Equal(pocetak) {
+					t.Errorf("termin %d: Pocetak = %v, ocekivano %v", i, slobodni[i].Pocetak, pocetak)
+				}
+				if kraj := pocetak.Add(time.Duration(tt.trajanje) * time.Minute); !slobodni[i].Kraj.Equal(kraj) {
+					t.Errorf("termin %d: Kraj = %v, ocekivano %v", i, slobodni[i].Kraj, kraj)
+				}
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ require (
 	go.opentelemetry.io/otel/sdk v1.17.0
 	go.opentelemetry.io/otel/trace v1.17.0
 	golang.org/x/crypto v0.18.0
+	rokovi v0.0.0
 
 )
 
@@ -34,3 +35,5 @@ require (
 	golang.org/x/text v0.14.0 // indirect
 
 )
+
+replace rokovi => ../rokovi
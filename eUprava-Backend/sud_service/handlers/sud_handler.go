@@ -2,12 +2,21 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"log"
 	"net/http"
+	"net/url"
+	"rokovi"
+	"strconv"
+	"strings"
 	"sud_service/client"
 	"sud_service/data"
 	"sud_service/helper"
@@ -21,10 +30,11 @@ type SudHandler struct {
 	sudRepo          *data.SudRepo
 	tracer           trace.Tracer
 	tuzilastvoClient client.TuzilastvoClient
+	authClient       client.AuthClient
 }
 
-func NewSudHandler(l *log.Logger, r *data.SudRepo, t trace.Tracer, tc client.TuzilastvoClient) *SudHandler {
-	return &SudHandler{l, r, t, tc}
+func NewSudHandler(l *log.Logger, r *data.SudRepo, t trace.Tracer, tc client.TuzilastvoClient, ac client.AuthClient) *SudHandler {
+	return &SudHandler{l, r, t, tc, ac}
 }
 
 func (h *SudHandler) DobaviPredmete(rw http.ResponseWriter, r *http.Request) {
@@ -82,6 +92,7 @@ func (h *SudHandler) DobaviPredmetPoId(rw http.ResponseWriter, r *http.Request)
 	}
 }
 
+// DodajPredmet zavodi predmet i slucajno ga dodeljuje sudiji iz rasporeda, a ne sudiji koji ga je zaveo
 func (h *SudHandler) DodajPredmet(writer http.ResponseWriter, req *http.Request) {
 	ctx, span := h.tracer.Start(req.Context(), "SudHandler.DodajPredmet")
 	defer span.End()
@@ -103,32 +114,54 @@ func (h *SudHandler) DodajPredmet(writer http.ResponseWriter, req *http.Request)
 		writer.Write([]byte("Id korisnika nije procitan"))
 		return
 	}
-	predmet.IdSudije = logovaniKorisnikId
 
-	err = h.sudRepo.DodajPredmet(ctx, predmet)
+	sudije, opterecenje, err := h.rasporedSudija(ctx, logovaniKorisnikId, claims["imeIPrezime"])
 	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja rasporeda sudija")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom dobavljanja rasporeda sudija"))
+		return
+	}
 
-		writer.WriteHeader(http.StatusBadRequest)
+	predmet.ID = primitive.NewObjectID()
+	predmet.IdSudije = primitive.NilObjectID
+	predmet.Izuzeca = nil
+	dodela, err := data.SlucajnaDodela(predmet, sudije, opterecenje, data.NovoSeme(), time.Now())
+	if err != nil {
+		span.SetStatus(codes.Error, "Nema dostupnih sudija za dodelu predmeta")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Nema dostupnih sudija za dodelu predmeta"))
+		return
+	}
+	dodela.IdDodelio = logovaniKorisnikId
+	predmet.IdSudije = dodela.IdSudije
+
+	err = h.sudRepo.DodajPredmetSaDodelom(ctx, predmet, dodela)
+	if err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
 		writer.Write([]byte("Greska prilikom dodavanja predmeta"))
 		span.SetStatus(codes.Error, "Greska prilikom dodavanja predmeta")
 		return
 	}
 
 	writer.WriteHeader(http.StatusOK)
+	predmet.ToJSON(writer)
 }
 
+// DodajPredmetePoZahtjevima uvozi aktivne zahteve tuzilastva i za svaki zahtev otvara najvise jedan predmet.
+// Ponovljen uvoz preskace zahteve za koje predmet vec postoji i ponovo salje potvrdu prijema koja nije uspela.
 func (h *SudHandler) DodajPredmetePoZahtjevima(writer http.ResponseWriter, req *http.Request) {
-	ctx, span := h.tracer.Start(req.Context(), "SudHandler.DodajPredmet")
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.DodajPredmetePoZahtjevima")
 	defer span.End()
 
 	bearer := req.Header.Get("Authorization")
-	log.Printf(bearer)
-
-	zahtjevi, err := h.tuzilastvoClient.DobaviAktivneZahtjeve(req.Context(), bearer)
 
+	zahtjevi, err := h.tuzilastvoClient.DobaviAktivneZahtjeve(ctx, bearer)
 	if err != nil {
-		log.Printf("Greska prilikom dodavanja zahtjeva: %v", err)
-		http.Error(writer, "Greska prilikom dodavanja zahtjeva", http.StatusServiceUnavailable)
+		log.Printf("Greska prilikom dobavljanja zahtjeva: %v", err)
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja zahtjeva")
+		http.Error(writer, "Greska prilikom dobavljanja zahtjeva", http.StatusServiceUnavailable)
+		return
 	}
 	claims := helper.ExtractClaims(req)
 	logovaniKorisnikId, err := primitive.ObjectIDFromHex(claims["id"])
@@ -139,25 +172,276 @@ func (h *SudHandler) DodajPredmetePoZahtjevima(writer http.ResponseWriter, req *
 		return
 	}
 
+	sudije, opterecenje, err := h.rasporedSudija(ctx, logovaniKorisnikId, claims["imeIPrezime"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja rasporeda sudija")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom dobavljanja rasporeda sudija"))
+		return
+	}
+
+	rezultat := data.RezultatImporta{Stavke: []data.StavkaImporta{}}
 	for _, zahtjev := range zahtjevi {
-		currentTime := time.Now()
-		currentDateTime := primitive.NewDateTimeFromTime(currentTime)
+		stavka := h.uveziZahtev(ctx, zahtjev, sudije, opterecenje, logovaniKorisnikId, bearer)
+		if !zahtjev.ID.IsZero() {
+			if err := h.sudRepo.SacuvajImportZahteva(ctx, stavka, time.Now()); err != nil {
+				h.logger.Println("Greska prilikom belezenja uvoza zahteva", zahtjev.ID.Hex(), err)
+			}
+		}
+		rezultat.Dodaj(stavka)
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	rezultat.ToJSON(writer)
+}
+
+// uveziZahtev otvara predmet za zahtev ako on jos ne postoji i potvrdjuje tuzilastvu prijem zahteva
+func (h *SudHandler) uveziZahtev(ctx context.Context, zahtjev *data.ZahtevZaSudskiPostupak, sudije data.Sudije, opterecenje map[primitive.ObjectID]int, idKorisnika primitive.ObjectID, bearer string) data.StavkaImporta {
+	stavka := data.StavkaImporta{IdZahteva: zahtjev.ID}
+	if zahtjev.ID.IsZero() {
+		stavka.Ishod = data.IMPORT_NEUSPEO
+		stavka.Greska = "Zahtev nema id"
+		return stavka
+	}
+
+	predmet, err := h.sudRepo.DobaviPredmetPoZahtevu(ctx, zahtjev.ID)
+	if err != nil && err != mongo.ErrNoDocuments {
+		stavka.Ishod = data.IMPORT_NEUSPEO
+		stavka.Greska = "Greska prilikom provere postojeceg predmeta"
+		return stavka
+	}
+
+	if predmet != nil {
+		stavka.Ishod = data.IMPORT_PRESKOCEN
+	} else {
+		sada := time.Now()
+		predmet = &data.Predmet{
+			ID:     primitive.NewObjectID(),
+			Opis:   zahtjev.Opis,
+			Datum:  primitive.NewDateTimeFromTime(sada),
+			Zahtev: *zahtjev,
+		}
+
+		dodela, err := data.SlucajnaDodela(predmet, sudije, opterecenje, data.NovoSeme(), sada)
+		if err != nil {
+			stavka.Ishod = data.IMPORT_NEUSPEO
+			stavka.Greska = "Nema dostupnih sudija za dodelu predmeta"
+			return stavka
+		}
+		dodela.IdDodelio = idKorisnika
+		predmet.IdSudije = dodela.IdSudije
+
+		err = h.sudRepo.DodajPredmetSaDodelom(ctx, predmet, dodela)
+		if mongo.IsDuplicateKeyError(err) {
+			// isti zahtev je u medjuvremenu uvezen drugim pozivom
+			predmet, err = h.sudRepo.DobaviPredmetPoZahtevu(ctx, zahtjev.ID)
+			stavka.Ishod = data.IMPORT_PRESKOCEN
+		} else if err == nil {
+			stavka.Ishod = data.IMPORT_KREIRAN
+			// sledeci predmet se izvlaci uz opterecenje koje ukljucuje upravo dodeljeni predmet
+			opterecenje[dodela.IdSudije]++
+		}
+		if err != nil {
+			stavka.Ishod = data.IMPORT_NEUSPEO
+			stavka.Greska = "Greska prilikom dodavanja predmeta"
+			return stavka
+		}
+	}
+	stavka.IdPredmeta = predmet.ID
+	stavka.IdSudije = predmet.IdSudije
+
+	// zahtev ostaje aktivan u tuzilastvu dok prijem ne bude potvrdjen, pa ce ga sledeci uvoz preskociti i ponovo potvrditi
+	prijem := data.PrijemZahteva{
+		IdPredmetaSuda: predmet.ID,
+		IdSudije:       predmet.IdSudije,
+		Datum:          predmet.Datum,
+	}
+	err = h.tuzilastvoClient.PotvrdiPrijemZahteva(ctx, zahtjev.ID.Hex(), prijem, bearer)
+	if err != nil {
+		log.Println("Greska prilikom potvrde prijema zahteva tuzilastvu:", err)
+		stavka.Greska = "Tuzilastvo nije potvrdilo prijem zahteva"
+	} else {
+		stavka.Primljen = true
+	}
+	return stavka
+}
+
+// DodajPredmetPotvrdeSporazuma prima sporazum o priznanju krivicnog dela koji tuzilastvo salje na potvrdu,
+// ponovljeno slanje istog sporazuma vraca vec kreirani predmet
+func (h *SudHandler) DodajPredmetPotvrdeSporazuma(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.DodajPredmetPotvrdeSporazuma")
+	defer span.End()
+
+	if !helper.PozivServisa(req, "tuzilastvo") {
+		span.SetStatus(codes.Error, "Sporazume na potvrdu dostavlja samo tuzilastvo")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Sporazume na potvrdu dostavlja samo tuzilastvo"))
+		return
+	}
+
+	sporazum := &data.SporazumZaPotvrdu{}
+	err := sporazum.FromJSON(req.Body)
+	if err != nil || sporazum.ID.IsZero() {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	predmet, err := h.sudRepo.DodajPredmetPotvrdeSporazuma(ctx, sporazum, time.Now())
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dodavanja predmeta")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom dodavanja predmeta"))
+		return
+	}
+
+	// predmet koji nije dodeljen ostaje dostupan svim sudijama, pa neuspela dodela ne ponistava prijem sporazuma
+	if predmet.IdSudije.IsZero() && predmet.StatusPotvrde == data.NA_ODLUCIVANJU {
+		dodela, err := h.slucajnoDodeli(ctx, predmet, primitive.NilObjectID)
+		if err != nil {
+			h.logger.Println("Greska prilikom dodele predmeta za potvrdu sporazuma", predmet.ID.Hex(), err)
+		} else {
+			predmet.IdSudije = dodela.IdSudije
+		}
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	predmet.ToJSON(writer)
+}
+
+// DobaviPredmetPoSporazumu omogucava tuzilastvu da proveri da li je sud odlucio o sporazumu
+func (h *SudHandler) DobaviPredmetPoSporazumu(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SudHandler.DobaviPredmetPoSporazumu")
+	defer span.End()
+
+	sporazumId, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id sporazuma nije procitan")
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Id sporazuma nije procitan"))
+		return
+	}
+
+	predmet, err := h.sudRepo.DobaviPredmetPoSporazumu(ctx, sporazumId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Predmet za sporazum ne postoji")
+		rw.WriteHeader(http.StatusNotFound)
+		rw.Write([]byte("Predmet za sporazum ne postoji"))
+		return
+	}
+
+	err = predmet.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+// OdluciOSporazumu potvrdjuje ili odbija sporazum, potvrdjen sporazum postaje presuda.
+// Ponovljen zahtev sa istom odlukom ponovo kreira presudu i salje odluku tuzilastvu ako to ranije nije uspelo.
+func (h *SudHandler) OdluciOSporazumu(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.OdluciOSporazumu")
+	defer span.End()
+
+	predmetId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id predmeta nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id predmeta nije procitan"))
+		return
+	}
+
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
 
-		predmet := &data.Predmet{
-			Opis:     zahtjev.Opis,
-			Datum:    currentDateTime,
-			Zahtev:   *zahtjev,
-			IdSudije: logovaniKorisnikId,
+	odluka := data.OdlukaOSporazumu{}
+	if err := odluka.FromJSON(req.Body); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if !odluka.Potvrdjen && odluka.Obrazlozenje == "" {
+		span.SetStatus(codes.Error, "Za odbijanje sporazuma je potrebno obrazlozenje")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Za odbijanje sporazuma je potrebno obrazlozenje"))
+		return
+	}
+
+	predmet, err := h.sudRepo.DobaviPredmetPoID(ctx, predmetId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Predmet sa prosledjenim id ne postoji")
+		writer.WriteHeader(http.StatusNotFound)
+		writer.Write([]byte("Predmet sa prosledjenim id ne postoji"))
+		return
+	}
+	if predmet.Tip != data.PREDMET_POTVRDA_SPORAZUMA || predmet.Sporazum == nil {
+		span.SetStatus(codes.Error, "Predmet nije potvrda sporazuma")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Predmet nije potvrda sporazuma"))
+		return
+	}
+
+	if predmet.StatusPotvrde == data.NA_ODLUCIVANJU {
+		odluka.IdSudije = logovaniKorisnikId
+		odluka.IdPredmeta = predmet.ID
+		odluka.Datum = primitive.NewDateTimeFromTime(time.Now())
+		odluka.Dostavljena = false
+		odluka.IdPresude = primitive.NilObjectID
+		if odluka.Potvrdjen {
+			odluka.IdPresude = primitive.NewObjectID()
+		}
+
+		predmet, err = h.sudRepo.EvidentirajOdlukuOSporazumu(ctx, predmetId, odluka)
+		if err != nil {
+			span.SetStatus(codes.Error, "Odluka o sporazumu je vec doneta")
+			writer.WriteHeader(http.StatusConflict)
+			writer.Write([]byte("Odluka o sporazumu je vec doneta"))
+			return
 		}
+	} else if predmet.Odluka == nil || predmet.Odluka.Potvrdjen != odluka.Potvrdjen || predmet.IdSudije != logovaniKorisnikId {
+		span.SetStatus(codes.Error, "Odluka o sporazumu je vec doneta")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Odluka o sporazumu je vec doneta"))
+		return
+	}
 
-		err = h.sudRepo.DodajPredmet(ctx, predmet)
+	if predmet.Odluka.Potvrdjen {
+		presuda := &data.Presuda{
+			ID:         predmet.Odluka.IdPresude,
+			Opis:       fmt.Sprintf("Potvrdjen sporazum o priznanju krivicnog dela. Uslovi: %s. Kazna: %s", predmet.Sporazum.Uslovi, predmet.Sporazum.Kazna),
+			Datum:      predmet.Odluka.Datum,
+			IdSudije:   predmet.Odluka.IdSudije,
+			IdPredmeta: predmet.ID,
+		}
+		err = h.sudRepo.SacuvajPresuduPoSporazumu(ctx, presuda)
 		if err != nil {
-			writer.WriteHeader(http.StatusBadRequest)
-			writer.Write([]byte("Greska prilikom dodavanja predmeta"))
-			span.SetStatus(codes.Error, "Greska prilikom dodavanja predmeta")
+			span.SetStatus(codes.Error, "Greska prilikom dodavanja presude")
+			writer.WriteHeader(http.StatusInternalServerError)
+			writer.Write([]byte("Odluka je evidentirana, ali presuda nije kreirana. Ponovite zahtev"))
 			return
 		}
 	}
+
+	// tuzilastvo periodicno proverava odluke o sporazumima, pa neuspelo slanje ne ponistava odluku
+	if !predmet.Odluka.Dostavljena {
+		err = h.tuzilastvoClient.PosaljiOdlukuOSporazumu(ctx, predmet.Sporazum.ID.Hex(), *predmet.Odluka, req.Header.Get("Authorization"))
+		if err != nil {
+			log.Println("Greska prilikom slanja odluke o sporazumu tuzilastvu:", err)
+		} else if err = h.sudRepo.OznaciOdlukuDostavljenom(ctx, predmet.ID); err == nil {
+			predmet.Odluka.Dostavljena = true
+		}
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	predmet.ToJSON(writer)
 }
 
 func (s *SudHandler) MiddlewareDeserialization(next http.Handler) http.Handler {
@@ -231,6 +515,8 @@ func (h *SudHandler) DobaviTerminPoId(rw http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// DodajTermin zakazuje sudjenje u radnom vremenu suda, ako u to vreme nisu zauzeti ni prostorija, ni sudija,
+// ni okrivljeni. Odbijen termin se vraca sa razlozima i prvim slobodnim terminima iste duzine.
 func (h *SudHandler) DodajTermin(writer http.ResponseWriter, req *http.Request) {
 	ctx, span := h.tracer.Start(req.Context(), "SudHandler.DodajTermin")
 	defer span.End()
@@ -252,6 +538,28 @@ func (h *SudHandler) DodajTermin(writer http.ResponseWriter, req *http.Request)
 		return
 	}
 
+	if termin.Datum == 0 || strings.TrimSpace(termin.Adresa) == "" || strings.TrimSpace(termin.Prostorija) == "" {
+		span.SetStatus(codes.Error, "Datum, adresa i prostorija su obavezni")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Datum, adresa i prostorija su obavezni"))
+		return
+	}
+	if !termin.Pocetak().After(time.Now()) {
+		span.SetStatus(codes.Error, "Termin moze biti zakazan samo za buduci datum")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Termin moze biti zakazan samo za buduci datum"))
+		return
+	}
+	if termin.TrajanjeMinuta == 0 {
+		termin.TrajanjeMinuta = data.PodrazumevanoTrajanjeTerminaMinuta
+	}
+	if termin.TrajanjeMinuta < data.MinTrajanjeTerminaMinuta || termin.TrajanjeMinuta > data.MaksTrajanjeTerminaMinuta {
+		span.SetStatus(codes.Error, "Trajanje termina mora biti izmedju 15 i 480 minuta")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Trajanje termina mora biti izmedju 15 i 480 minuta"))
+		return
+	}
+
 	predmet, err := h.sudRepo.DobaviPredmetPoID(ctx, predmetId)
 	if err != nil || predmet == nil {
 		writer.WriteHeader(http.StatusBadRequest)
@@ -259,119 +567,268 @@ func (h *SudHandler) DodajTermin(writer http.ResponseWriter, req *http.Request)
 		span.SetStatus(codes.Error, "Greska prilikom dodavanja termina")
 		return
 	}
+	termin.ID = primitive.NilObjectID
 	termin.Predmet = *predmet
+	termin.IdSudije = predmet.IdSudije
+	termin.Okrivljeni = data.OkrivljeniPrijave(predmet.KrivicnaPrijava())
+	termin.Kraj = primitive.NewDateTimeFromTime(termin.Pocetak().Add(time.Duration(termin.TrajanjeMinuta) * time.Minute))
+	termin.Status = data.TERMIN_ZAKAZAN
+	termin.Istorija = nil
 
-	err = h.sudRepo.DodajTermin(ctx, termin)
+	provera, err := h.zauzmiTermin(ctx, termin, func(ctx context.Context) error {
+		return h.sudRepo.DodajTermin(ctx, termin)
+	})
+	if errors.Is(err, errTerminZauzet) {
+		span.SetStatus(codes.Error, "Termin nije slobodan")
+		writer.WriteHeader(http.StatusConflict)
+		provera.ToJSON(writer)
+		return
+	}
 	if err != nil {
-		writer.WriteHeader(http.StatusBadRequest)
+		writer.WriteHeader(http.StatusInternalServerError)
 		writer.Write([]byte("Greska prilikom dodavanja termina"))
 		span.SetStatus(codes.Error, "Greska prilikom dodavanja termina")
 		return
 	}
 
 	writer.WriteHeader(http.StatusOK)
+	termin.ToJSON(writer)
 }
 
-func (s *SudHandler) TerminMiddlewareDeserialization(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(rw http.ResponseWriter, h *http.Request) {
-		termin := &data.TerminSudjenja{}
-		err := termin.FromJSON(h.Body)
+var errTerminZauzet = errors.New("termin nije slobodan")
+
+// zauzmiTermin proverava zauzetost termina i upisuje ga u transakciji koja zakljucava prostoriju, sudiju i
+// okrivljenog termina, pa dva istovremena zahteva ne mogu zauzeti isti resurs. Ako termin nije slobodan vraca
+// errTerminZauzet i proveru sa konfliktima.
+func (h *SudHandler) zauzmiTermin(ctx context.Context, termin *data.TerminSudjenja, upisi func(ctx context.Context) error) (*data.ProveraTermina, error) {
+	var provera *data.ProveraTermina
+	err := h.sudRepo.ZauzmiResurse(ctx, termin.ResursiTermina(), func(ctx mongo.SessionContext) error {
+		var err error
+		provera, err = h.proveriTermin(ctx, termin)
 		if err != nil {
-			http.Error(rw, "Unable to decode json", http.StatusBadRequest)
-			s.logger.Fatal(err)
-			return
+			return err
 		}
-		ctx := context.WithValue(h.Context(), KeyProduct{}, termin)
-		h = h.WithContext(ctx)
-		next.ServeHTTP(rw, h)
+		if len(provera.Konflikti) > 0 {
+			return errTerminZauzet
+		}
+		return upisi(ctx)
 	})
+	return provera, err
 }
 
-// PRESUDE
-func (h *SudHandler) DobaviPresude(rw http.ResponseWriter, r *http.Request) {
-	ctx, span := h.tracer.Start(r.Context(), "SudHandler.DobaviPresude")
-	defer span.End()
+func (h *SudHandler) kalendarSuda(ctx context.Context) (*data.Kalendar, error) {
+	neradniDani, err := h.sudRepo.DobaviNeradneDane(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return data.NoviKalendar(neradniDani), nil
+}
 
-	presude, err := h.sudRepo.DobaviPresude(ctx)
+// proveriTermin vraca konflikte termina sa radnim vremenom i drugim terminima, a ako ih ima i prve slobodne termine
+func (h *SudHandler) proveriTermin(ctx context.Context, termin *data.TerminSudjenja) (*data.ProveraTermina, error) {
+	kalendar, err := h.kalendarSuda(ctx)
 	if err != nil {
-		rw.WriteHeader(http.StatusBadRequest)
-		rw.Write([]byte("Greska"))
-		span.SetStatus(codes.Error, "Greska")
+		return nil, err
 	}
+	pocetak, kraj := termin.Pocetak(), termin.KrajTermina()
 
-	if presude == nil {
-		return
+	provera := &data.ProveraTermina{Konflikti: []data.KonfliktTermina{}, SlobodniTermini: []data.SlobodanTermin{}}
+	if !kalendar.URadnomVremenu(pocetak, kraj) {
+		provera.Konflikti = append(provera.Konflikti, data.KonfliktTermina{
+			Tip:     data.KONFLIKT_NERADNO_VREME,
+			Pocetak: pocetak,
+			Kraj:    kraj,
+			Opis:    fmt.Sprintf("Sud radi radnim danima od %d do %d casova", data.PocetakRadnogVremena, data.KrajRadnogVremena),
+		})
+	}
+	postojeci, err := h.sudRepo.DobaviTermineUPeriodu(ctx, pocetak, kraj)
+	if err != nil {
+		return nil, err
+	}
+	provera.Konflikti = append(provera.Konflikti, data.PronadjiKonflikte(termin, postojeci)...)
+	if len(provera.Konflikti) == 0 {
+		return provera, nil
 	}
 
-	err = presude.ToJSON(rw)
+	od := pocetak
+	if sada := time.Now(); od.Before(sada) {
+		od = sada
+	}
+	zauzeti, err := h.sudRepo.DobaviTermineUPeriodu(ctx, od, od.AddDate(0, 0, data.HorizontSlobodnihTerminaDana))
 	if err != nil {
-		rw.WriteHeader(http.StatusBadRequest)
-		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
-		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+		return nil, err
 	}
+	provera.SlobodniTermini = kalendar.SlobodniTermini(termin, zauzeti, od, data.BrojPredlozenihTermina)
+	return provera, nil
 }
 
-func (h *SudHandler) DobaviPresuduPoId(rw http.ResponseWriter, r *http.Request) {
-	ctx, span := h.tracer.Start(r.Context(), "SudHandler.DobaviPresuduPoId")
+// SlobodniTermini predlaze prve slobodne termine za predmet u prostoriji, pocev od parametra od ili od sada
+func (h *SudHandler) SlobodniTermini(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SudHandler.SlobodniTermini")
 	defer span.End()
 
-	vars := mux.Vars(r)
-	presudaId, err := primitive.ObjectIDFromHex(vars["id"])
-	if err != nil {
-		span.SetStatus(codes.Error, "Greska")
+	upit := r.URL.Query()
+	predmetId, err := primitive.ObjectIDFromHex(upit.Get("predmetId"))
+	if err != nil || upit.Get("adresa") == "" || upit.Get("prostorija") == "" {
+		span.SetStatus(codes.Error, "Predmet, adresa i prostorija su obavezni")
 		rw.WriteHeader(http.StatusBadRequest)
-		rw.Write([]byte("Greska"))
+		rw.Write([]byte("Predmet, adresa i prostorija su obavezni"))
 		return
 	}
 
-	presuda, err := h.sudRepo.DobaviPresuduPoID(ctx, presudaId)
+	trajanje := data.PodrazumevanoTrajanjeTerminaMinuta
+	if parametar := upit.Get("trajanje"); parametar != "" {
+		trajanje, err = strconv.Atoi(parametar)
+		if err != nil || trajanje < data.MinTrajanjeTerminaMinuta || trajanje > data.MaksTrajanjeTerminaMinuta {
+			span.SetStatus(codes.Error, "Trajanje termina mora biti izmedju 15 i 480 minuta")
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte("Trajanje termina mora biti izmedju 15 i 480 minuta"))
+			return
+		}
+	}
+	broj := data.BrojPredlozenihTermina
+	if parametar := upit.Get("broj"); parametar != "" {
+		broj, err = strconv.Atoi(parametar)
+		if err != nil || broj < 1 || broj > data.MaksBrojPredlozenihTermina {
+			span.SetStatus(codes.Error, "Broj termina mora biti izmedju 1 i 20")
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte("Broj termina mora biti izmedju 1 i 20"))
+			return
+		}
+	}
+	od := time.Now()
+	if parametar := upit.Get("od"); parametar != "" {
+		trazeno, err := time.Parse(time.RFC3339, parametar)
+		if err != nil {
+			span.SetStatus(codes.Error, "Pocetak pretrage mora biti u RFC3339 formatu")
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte("Pocetak pretrage mora biti u RFC3339 formatu"))
+			return
+		}
+		if trazeno.After(od) {
+			od = trazeno
+		}
+	}
+
+	predmet, err := h.sudRepo.DobaviPredmetPoID(ctx, predmetId)
 	if err != nil {
-		rw.WriteHeader(http.StatusBadRequest)
-		rw.Write([]byte("Greska dobavljanja presude po ID"))
-		span.SetStatus(codes.Error, "Greska dobavljanja presude po ID")
+		span.SetStatus(codes.Error, "Predmet sa prosledjenim id ne postoji")
+		rw.WriteHeader(http.StatusNotFound)
+		rw.Write([]byte("Predmet sa prosledjenim id ne postoji"))
+		return
+	}
+	termin := &data.TerminSudjenja{
+		Adresa:         upit.Get("adresa"),
+		Prostorija:     upit.Get("prostorija"),
+		Datum:          primitive.NewDateTimeFromTime(od),
+		TrajanjeMinuta: trajanje,
+		IdSudije:       predmet.IdSudije,
+		Okrivljeni:     data.OkrivljeniPrijave(predmet.KrivicnaPrijava()),
+		Predmet:        *predmet,
 	}
 
-	if presuda == nil {
+	kalendar, err := h.kalendarSuda(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja neradnih dana")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja neradnih dana"))
+		return
+	}
+	zauzeti, err := h.sudRepo.DobaviTermineUPeriodu(ctx, od, od.AddDate(0, 0, data.HorizontSlobodnihTerminaDana))
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja termina")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja termina"))
 		return
 	}
 
-	err = presuda.ToJSON(rw)
+	slobodni := kalendar.SlobodniTermini(termin, zauzeti, od, broj)
+	err = json.NewEncoder(rw).Encode(slobodni)
 	if err != nil {
-		rw.WriteHeader(http.StatusBadRequest)
+		rw.WriteHeader(http.StatusInternalServerError)
 		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
 		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
 	}
 }
 
-func (h *SudHandler) DodajPresudu(writer http.ResponseWriter, req *http.Request) {
-	ctx, span := h.tracer.Start(req.Context(), "SudHandler.DodajPresudu")
+func (h *SudHandler) DobaviNeradneDane(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SudHandler.DobaviNeradneDane")
 	defer span.End()
 
-	presuda, ok := req.Context().Value(KeyProduct{}).(*data.Presuda)
-	if !ok || presuda == nil {
+	dani, err := h.sudRepo.DobaviNeradneDane(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja neradnih dana")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja neradnih dana"))
+		return
+	}
+
+	err = dani.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+// SacuvajNeradniDan dodaje dan kada sud ne radi, drzavni praznici su vec uracunati i ne unose se
+func (h *SudHandler) SacuvajNeradniDan(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.SacuvajNeradniDan")
+	defer span.End()
+
+	var dan data.NeradniDan
+	if err := json.NewDecoder(req.Body).Decode(&dan); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
 		writer.WriteHeader(http.StatusBadRequest)
 		writer.Write([]byte("Pogresan format zahteva"))
-		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		return
+	}
+	if _, err := time.Parse("2006-01-02", dan.Datum); err != nil || dan.Naziv == "" {
+		span.SetStatus(codes.Error, "Datum u obliku 2006-01-02 i naziv su obavezni")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Datum u obliku 2006-01-02 i naziv su obavezni"))
 		return
 	}
 
-	vars := mux.Vars(req)
-	terminId, err := primitive.ObjectIDFromHex(vars["id"])
+	err := h.sudRepo.SacuvajNeradniDan(ctx, &dan)
 	if err != nil {
-		span.SetStatus(codes.Error, "Greska")
-		writer.WriteHeader(http.StatusBadRequest)
-		writer.Write([]byte("Greska"))
+		span.SetStatus(codes.Error, "Greska prilikom cuvanja neradnog dana")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom cuvanja neradnog dana"))
 		return
 	}
 
+	writer.WriteHeader(http.StatusOK)
+}
+
+// predstojeciTermin cita termin iz putanje koji jos nije ni odrzan ni otkazan i koji prijavljeni korisnik sme da
+// menja, a ako takav ne postoji vraca status i poruku odgovora
+func (h *SudHandler) predstojeciTermin(ctx context.Context, req *http.Request, idKorisnika primitive.ObjectID) (*data.TerminSudjenja, int, string) {
+	terminId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, http.StatusBadRequest, "Id termina nije procitan"
+	}
 	termin, err := h.sudRepo.DobaviTerminPoID(ctx, terminId)
-	if err != nil || termin == nil {
-		writer.WriteHeader(http.StatusBadRequest)
-		writer.Write([]byte("Greska prilikom dodavanja termina"))
-		span.SetStatus(codes.Error, "Greska prilikom dodavanja termina")
-		return
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, http.StatusNotFound, "Termin sa prosledjenim id ne postoji"
+		}
+		return nil, http.StatusInternalServerError, "Greska dobavljanja termina po ID"
 	}
-	presuda.TerminSudjenja = *termin
+	if !termin.MozeDaMenja(idKorisnika, helper.ExtractClaims(req)["rola"]) {
+		return nil, http.StatusForbidden, "Termin moze menjati samo sudija termina ili predsednik suda"
+	}
+	if !termin.Predstoji() {
+		return nil, http.StatusConflict, fmt.Sprintf("Termin je u statusu %s i ne moze se menjati", termin.TrenutniStatus())
+	}
+	return termin, http.StatusOK, ""
+}
+
+// OdloziTermin pomera termin koji jos nije odrzan na novi datum, uz obavezan razlog. Novi datum se proverava
+// kao pri zakazivanju, a prethodni datum ostaje u istoriji termina.
+func (h *SudHandler) OdloziTermin(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.OdloziTermin")
+	defer span.End()
 
 	claims := helper.ExtractClaims(req)
 	logovaniKorisnikId, err := primitive.ObjectIDFromHex(claims["id"])
@@ -381,12 +838,406 @@ func (h *SudHandler) DodajPresudu(writer http.ResponseWriter, req *http.Request)
 		writer.Write([]byte("Id korisnika nije procitan"))
 		return
 	}
-	presuda.IdSudije = logovaniKorisnikId
 
-	err = h.sudRepo.DodajPresudu(ctx, presuda)
-	if err != nil {
+	var izmena data.IzmenaTermina
+	if err := json.NewDecoder(req.Body).Decode(&izmena); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
 		writer.WriteHeader(http.StatusBadRequest)
-		writer.Write([]byte("Greska prilikom dodavanja presude"))
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	izmena.Razlog = strings.TrimSpace(izmena.Razlog)
+	if izmena.Datum == 0 || izmena.Razlog == "" {
+		span.SetStatus(codes.Error, "Novi datum i razlog odlaganja su obavezni")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Novi datum i razlog odlaganja su obavezni"))
+		return
+	}
+
+	termin, status, poruka := h.predstojeciTermin(ctx, req, logovaniKorisnikId)
+	if termin == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
+		return
+	}
+
+	odlozen := *termin
+	if strings.TrimSpace(izmena.Adresa) != "" {
+		odlozen.Adresa = izmena.Adresa
+	}
+	if strings.TrimSpace(izmena.Prostorija) != "" {
+		odlozen.Prostorija = izmena.Prostorija
+	}
+	if izmena.TrajanjeMinuta != 0 {
+		odlozen.TrajanjeMinuta = izmena.TrajanjeMinuta
+	} else if odlozen.TrajanjeMinuta == 0 {
+		odlozen.TrajanjeMinuta = data.PodrazumevanoTrajanjeTerminaMinuta
+	}
+	if odlozen.TrajanjeMinuta < data.MinTrajanjeTerminaMinuta || odlozen.TrajanjeMinuta > data.MaksTrajanjeTerminaMinuta {
+		span.SetStatus(codes.Error, "Trajanje termina mora biti izmedju 15 i 480 minuta")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Trajanje termina mora biti izmedju 15 i 480 minuta"))
+		return
+	}
+	odlozen.Datum = izmena.Datum
+	odlozen.Kraj = primitive.NewDateTimeFromTime(odlozen.Pocetak().Add(time.Duration(odlozen.TrajanjeMinuta) * time.Minute))
+	odlozen.Status = data.TERMIN_ODLOZEN
+	if !odlozen.Pocetak().After(time.Now()) {
+		span.SetStatus(codes.Error, "Termin moze biti odlozen samo na buduci datum")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Termin moze biti odlozen samo na buduci datum"))
+		return
+	}
+
+	novo := odlozen.Raspored()
+	promena := data.PromenaTermina{
+		Status:      data.TERMIN_ODLOZEN,
+		Razlog:      izmena.Razlog,
+		Prethodno:   termin.Raspored(),
+		Novo:        &novo,
+		IdKorisnika: logovaniKorisnikId,
+		Datum:       time.Now(),
+	}
+	h.sacuvajPromenuTermina(ctx, writer, termin, &odlozen, promena)
+}
+
+// OtkaziTermin otkazuje termin koji jos nije odrzan, uz obavezan razlog. Otkazan termin oslobadja prostoriju,
+// sudiju i okrivljenog za druge termine.
+func (h *SudHandler) OtkaziTermin(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.OtkaziTermin")
+	defer span.End()
+
+	claims := helper.ExtractClaims(req)
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(claims["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	var izmena data.IzmenaTermina
+	if err := json.NewDecoder(req.Body).Decode(&izmena); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	izmena.Razlog = strings.TrimSpace(izmena.Razlog)
+	if izmena.Razlog == "" {
+		span.SetStatus(codes.Error, "Razlog otkazivanja je obavezan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Razlog otkazivanja je obavezan"))
+		return
+	}
+
+	termin, status, poruka := h.predstojeciTermin(ctx, req, logovaniKorisnikId)
+	if termin == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
+		return
+	}
+
+	otkazan := *termin
+	otkazan.Status = data.TERMIN_OTKAZAN
+	promena := data.PromenaTermina{
+		Status:      data.TERMIN_OTKAZAN,
+		Razlog:      izmena.Razlog,
+		Prethodno:   termin.Raspored(),
+		IdKorisnika: logovaniKorisnikId,
+		Datum:       time.Now(),
+	}
+	h.sacuvajPromenuTermina(ctx, writer, termin, &otkazan, promena)
+}
+
+// OznaciTerminOdrzanim belezi da je sudjenje odrzano, tek tada sudija moze doneti presudu
+func (h *SudHandler) OznaciTerminOdrzanim(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.OznaciTerminOdrzanim")
+	defer span.End()
+
+	claims := helper.ExtractClaims(req)
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(claims["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	termin, status, poruka := h.predstojeciTermin(ctx, req, logovaniKorisnikId)
+	if termin == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
+		return
+	}
+	if termin.Pocetak().After(time.Now()) {
+		span.SetStatus(codes.Error, "Termin koji jos nije poceo ne moze biti oznacen kao odrzan")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Termin koji jos nije poceo ne moze biti oznacen kao odrzan"))
+		return
+	}
+
+	odrzan := *termin
+	odrzan.Status = data.TERMIN_ODRZAN
+	promena := data.PromenaTermina{
+		Status:      data.TERMIN_ODRZAN,
+		Prethodno:   termin.Raspored(),
+		IdKorisnika: logovaniKorisnikId,
+		Datum:       time.Now(),
+	}
+	h.sacuvajPromenuTermina(ctx, writer, termin, &odrzan, promena)
+}
+
+// sacuvajPromenuTermina upisuje izmenjen termin i promenu u istoriji, obavestava ucesnike o odlaganju ili
+// otkazivanju i vraca izmenjen termin. Odlozen termin se pri upisu ponovo proverava kao pri zakazivanju.
+func (h *SudHandler) sacuvajPromenuTermina(ctx context.Context, writer http.ResponseWriter, prethodni *data.TerminSudjenja, izmenjen *data.TerminSudjenja, promena data.PromenaTermina) {
+	span := trace.SpanFromContext(ctx)
+
+	upisi := func(ctx context.Context) error {
+		return h.sudRepo.PromeniTermin(ctx, izmenjen, len(prethodni.Istorija), promena)
+	}
+	var err error
+	if promena.Status == data.TERMIN_ODLOZEN {
+		var provera *data.ProveraTermina
+		provera, err = h.zauzmiTermin(ctx, izmenjen, upisi)
+		if errors.Is(err, errTerminZauzet) {
+			span.SetStatus(codes.Error, "Termin nije slobodan")
+			writer.WriteHeader(http.StatusConflict)
+			provera.ToJSON(writer)
+			return
+		}
+	} else {
+		err = upisi(ctx)
+	}
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			span.SetStatus(codes.Error, "Termin je u medjuvremenu izmenjen, pokusajte ponovo")
+			writer.WriteHeader(http.StatusConflict)
+			writer.Write([]byte("Termin je u medjuvremenu izmenjen, pokusajte ponovo"))
+			return
+		}
+		span.SetStatus(codes.Error, "Greska prilikom izmene termina")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom izmene termina"))
+		return
+	}
+	izmenjen.Istorija = append(append([]data.PromenaTermina{}, prethodni.Istorija...), promena)
+
+	if promena.Status != data.TERMIN_ODRZAN {
+		h.obavestiUcesnike(ctx, izmenjen, promena)
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	izmenjen.ToJSON(writer)
+}
+
+// obavestiUcesnike javlja promenu termina sudiji, ako termin nije menjao sam sudija, i tuziocu koji je pokrenuo postupak.
+// Promena je vec sacuvana, pa se obavestenje koje tuzilastvu nije poslato cuva i salje ponovo pri pracenju rokova.
+func (h *SudHandler) obavestiUcesnike(ctx context.Context, termin *data.TerminSudjenja, promena data.PromenaTermina) {
+	kljuc := termin.KljucPoslednjePromene()
+	tekst := promena.TekstObavestenja(termin.Predmet.Opis)
+
+	if sudija := termin.SudijaTermina(); !sudija.IsZero() && sudija != promena.IdKorisnika {
+		obavestenje := data.Obavestenje{
+			IdKorisnika: sudija,
+			KljucRoka:   kljuc,
+			Nivo:        data.OBAVESTENJE_INFORMACIJA,
+			PredmetId:   termin.Predmet.ID,
+			Tekst:       tekst,
+			Istice:      termin.Pocetak(),
+			Kreirano:    time.Now(),
+		}
+		if _, err := h.sudRepo.DodajObavestenje(ctx, &obavestenje); err != nil {
+			log.Println("Greska prilikom obavestavanja sudije o promeni termina:", err)
+		}
+	}
+
+	obavestenje := data.ObavestenjeTuzilastvu{
+		Kljuc:     kljuc,
+		IdTuzioca: termin.Predmet.Zahtev.IdTuzioca,
+		PredmetId: termin.Predmet.Zahtev.PredmetId,
+		Tekst:     tekst,
+		Istice:    termin.Pocetak(),
+	}
+	if termin.Predmet.Sporazum != nil {
+		obavestenje.IdTuzioca = termin.Predmet.Sporazum.IdTuzioca
+	}
+	if obavestenje.IdTuzioca.IsZero() {
+		return
+	}
+	if err := h.tuzilastvoClient.PosaljiObavestenjeTuzilastvu(ctx, obavestenje); err != nil {
+		log.Println("Greska prilikom obavestavanja tuzilastva o promeni termina:", err)
+		if err := h.sudRepo.SacuvajNeposlatoObavestenje(ctx, &obavestenje, err); err != nil {
+			log.Println("Greska prilikom cuvanja neposlatog obavestenja tuzilastvu:", err)
+		}
+	}
+}
+
+// posaljiNeposlataObavestenja ponovo salje obavestenja tuzilastvu koja nisu poslata pri promeni termina.
+// Tuzilastvo obavestenje sa istim kljucem upisuje samo jednom, pa ponovno slanje sa vise instanci nije problem.
+func (h *SudHandler) posaljiNeposlataObavestenja(ctx context.Context) {
+	obavestenja, err := h.sudRepo.DobaviNeposlataObavestenja(ctx, time.Now())
+	if err != nil {
+		h.logger.Println("Greska prilikom dobavljanja neposlatih obavestenja tuzilastvu:", err)
+		return
+	}
+
+	poslato := 0
+	for _, obavestenje := range obavestenja {
+		if err := h.tuzilastvoClient.PosaljiObavestenjeTuzilastvu(ctx, *obavestenje); err != nil {
+			if err := h.sudRepo.SacuvajNeposlatoObavestenje(ctx, obavestenje, err); err != nil {
+				h.logger.Println("Greska prilikom cuvanja neposlatog obavestenja tuzilastvu:", err)
+			}
+			continue
+		}
+		if err := h.sudRepo.UkloniNeposlatoObavestenje(ctx, obavestenje.ID); err != nil {
+			h.logger.Println("Greska prilikom uklanjanja poslatog obavestenja tuzilastvu:", err)
+			continue
+		}
+		poslato++
+	}
+	if poslato > 0 {
+		h.logger.Printf("Ponovo poslato %d obavestenja tuzilastvu", poslato)
+	}
+}
+
+func (s *SudHandler) TerminMiddlewareDeserialization(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, h *http.Request) {
+		termin := &data.TerminSudjenja{}
+		err := termin.FromJSON(h.Body)
+		if err != nil {
+			http.Error(rw, "Unable to decode json", http.StatusBadRequest)
+			s.logger.Fatal(err)
+			return
+		}
+		ctx := context.WithValue(h.Context(), KeyProduct{}, termin)
+		h = h.WithContext(ctx)
+		next.ServeHTTP(rw, h)
+	})
+}
+
+// PRESUDE
+func (h *SudHandler) DobaviPresude(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SudHandler.DobaviPresude")
+	defer span.End()
+
+	presude, err := h.sudRepo.DobaviPresude(ctx)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Greska"))
+		span.SetStatus(codes.Error, "Greska")
+	}
+
+	if presude == nil {
+		return
+	}
+
+	err = presude.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+func (h *SudHandler) DobaviPresuduPoId(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SudHandler.DobaviPresuduPoId")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	presudaId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska")
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Greska"))
+		return
+	}
+
+	presuda, err := h.sudRepo.DobaviPresuduPoID(ctx, presudaId)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Greska dobavljanja presude po ID"))
+		span.SetStatus(codes.Error, "Greska dobavljanja presude po ID")
+	}
+
+	if presuda == nil {
+		return
+	}
+
+	err = presuda.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+func (h *SudHandler) DodajPresudu(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.DodajPresudu")
+	defer span.End()
+
+	presuda, ok := req.Context().Value(KeyProduct{}).(*data.Presuda)
+	if !ok || presuda == nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		return
+	}
+
+	vars := mux.Vars(req)
+	terminId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Greska"))
+		return
+	}
+
+	termin, err := h.sudRepo.DobaviTerminPoID(ctx, terminId)
+	if err != nil || termin == nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Greska prilikom dodavanja termina"))
+		span.SetStatus(codes.Error, "Greska prilikom dodavanja termina")
+		return
+	}
+	if termin.TrenutniStatus() != data.TERMIN_ODRZAN {
+		span.SetStatus(codes.Error, "Presuda se moze doneti samo za odrzano sudjenje")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Presuda se moze doneti samo za odrzano sudjenje"))
+		return
+	}
+	presuda.TerminSudjenja = *termin
+
+	claims := helper.ExtractClaims(req)
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(claims["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+	// Presudu donosi sudija koji je sudio, ili predsednik suda
+	if !termin.MozeDaMenja(logovaniKorisnikId, claims["rola"]) {
+		span.SetStatus(codes.Error, "Presudu moze doneti samo sudija termina ili predsednik suda")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Presudu moze doneti samo sudija termina ili predsednik suda"))
+		return
+	}
+	presuda.IdSudije = logovaniKorisnikId
+
+	err = h.sudRepo.DodajPresudu(ctx, presuda)
+	if mongo.IsDuplicateKeyError(err) {
+		span.SetStatus(codes.Error, "Presuda za ovo sudjenje je vec doneta")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Presuda za ovo sudjenje je vec doneta"))
+		return
+	}
+	if err != nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Greska prilikom dodavanja presude"))
 		span.SetStatus(codes.Error, "Greska prilikom dodavanja presude")
 		return
 	}
@@ -408,3 +1259,871 @@ func (s *SudHandler) PresudaMiddlewareDeserialization(next http.Handler) http.Ha
 		next.ServeHTTP(rw, h)
 	})
 }
+
+// ROKOVI
+
+// PokreniPracenjeRokova periodicno racuna rokove predmeta i obavestava sudije o rokovima koji isticu ili su istekli.
+// Obavestenje za isti rok i nivo se salje samo jednom, pa provera moze da radi na vise instanci. Uz rokove se
+// ponovo salju obavestenja tuzilastvu koja nisu poslata pri promeni termina, a raspored sudija uskladjuje sa
+// rolama iz auth servisa.
+func (h *SudHandler) PokreniPracenjeRokova(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		h.uskladiRasporedSudija(ctx)
+		h.obradiRokove(ctx)
+		h.posaljiNeposlataObavestenja(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *SudHandler) obradiRokove(ctx context.Context) {
+	pravila, err := h.sudRepo.DobaviPravilaRokova(ctx)
+	if err != nil {
+		h.logger.Println("Greska prilikom dobavljanja pravila rokova:", err)
+		return
+	}
+	filter := bson.D{{Key: "idSudije", Value: bson.D{{Key: "$exists", Value: true}}}}
+	stanja, err := h.sudRepo.DobaviStanjaPredmeta(ctx, filter)
+	if err != nil {
+		h.logger.Println("Greska prilikom dobavljanja predmeta:", err)
+		return
+	}
+
+	// hitni i istekli rokovi se eskaliraju predsednicima suda, a ako auth servis nije dostupan
+	// obavestenje dobija samo sudija, dok predsednici obavestenje dobijaju u sledecoj proveri
+	var predsednici []primitive.ObjectID
+	korisnici, err := h.authClient.DobaviKorisnikePoRoli(ctx, data.RolaPredsednikSuda)
+	if err != nil {
+		h.logger.Println("Greska prilikom dobavljanja predsednika suda:", err)
+	}
+	for _, korisnik := range korisnici {
+		predsednici = append(predsednici, korisnik.ID)
+	}
+
+	sada := time.Now()
+	poslato := 0
+	for _, stanje := range stanja {
+		rok := data.IzracunajRokPredmeta(stanje, pravila, sada)
+		if rok == nil || rok.Nivo == "" {
+			continue
+		}
+		for _, idKorisnika := range rokovi.PrimaociObavestenja([]primitive.ObjectID{stanje.Predmet.IdSudije}, predsednici, rok.Nivo) {
+			obavestenje := data.Obavestenje{
+				IdKorisnika: idKorisnika,
+				KljucRoka:   rok.Kljuc,
+				Nivo:        rok.Nivo,
+				PredmetId:   rok.PredmetId,
+				Tekst:       rok.TekstObavestenja(),
+				Istice:      rok.Istice,
+				Kreirano:    sada,
+			}
+			novo, err := h.sudRepo.DodajObavestenje(ctx, &obavestenje)
+			if err != nil {
+				h.logger.Println("Greska prilikom slanja obavestenja o roku", rok.Kljuc, err)
+				continue
+			}
+			if novo {
+				poslato++
+			}
+		}
+	}
+	if poslato > 0 {
+		h.logger.Printf("Poslato %d obavestenja o rokovima", poslato)
+	}
+}
+
+// PredstojeciRokovi vraca rokove predmeta prijavljenog sudije koji su istekli ili isticu u narednih
+// dana dana, podrazumevano data.PeriodPredstojecihRokovaDana
+func (h *SudHandler) PredstojeciRokovi(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SudHandler.PredstojeciRokovi")
+	defer span.End()
+
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(r)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	dana := data.PeriodPredstojecihRokovaDana
+	if parametar := r.URL.Query().Get("dana"); parametar != "" {
+		dana, err = strconv.Atoi(parametar)
+		if err != nil || dana < 0 {
+			span.SetStatus(codes.Error, "Broj dana mora biti pozitivan ceo broj")
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte("Broj dana mora biti pozitivan ceo broj"))
+			return
+		}
+	}
+
+	stanja, err := h.sudRepo.DobaviStanjaPredmeta(ctx, bson.D{{Key: "idSudije", Value: logovaniKorisnikId}})
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja predmeta")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja predmeta"))
+		return
+	}
+	pravila, err := h.sudRepo.DobaviPravilaRokova(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja pravila rokova")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja pravila rokova"))
+		return
+	}
+
+	rokovi := data.PredstojeciRokovi(stanja, pravila, dana, time.Now())
+	err = json.NewEncoder(rw).Encode(rokovi)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+func (h *SudHandler) DobaviPravilaRokova(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SudHandler.DobaviPravilaRokova")
+	defer span.End()
+
+	pravila, err := h.sudRepo.DobaviPravilaRokova(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja pravila rokova")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja pravila rokova"))
+		return
+	}
+
+	err = pravila.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+// SacuvajPraviloRoka dodaje pravilo roka ili menja postojece, pravilo bez zakona vazi za sva krivicna dela
+func (h *SudHandler) SacuvajPraviloRoka(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.SacuvajPraviloRoka")
+	defer span.End()
+
+	var pravilo data.PraviloRoka
+	if err := json.NewDecoder(req.Body).Decode(&pravilo); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if greska := rokovi.ProveriPravilo(&pravilo, data.PoznatKorakPostupka); greska != "" {
+		span.SetStatus(codes.Error, greska)
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte(greska))
+		return
+	}
+
+	err := h.sudRepo.SacuvajPraviloRoka(ctx, &pravilo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			span.SetStatus(codes.Error, "Pravilo sa prosledjenim id ne postoji")
+			writer.WriteHeader(http.StatusNotFound)
+			writer.Write([]byte("Pravilo sa prosledjenim id ne postoji"))
+			return
+		}
+		if err == rokovi.ErrPraviloPostoji {
+			span.SetStatus(codes.Error, "Pravilo za isti korak i krivicno delo vec postoji")
+			writer.WriteHeader(http.StatusConflict)
+			writer.Write([]byte("Pravilo za isti korak i krivicno delo vec postoji"))
+			return
+		}
+		span.SetStatus(codes.Error, "Greska prilikom cuvanja pravila roka")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom cuvanja pravila roka"))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(&pravilo)
+}
+
+// DobaviObavestenja vraca obavestenja prijavljenog sudije, uz neprocitana=true samo neprocitana
+func (h *SudHandler) DobaviObavestenja(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SudHandler.DobaviObavestenja")
+	defer span.End()
+
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(r)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	obavestenja, err := h.sudRepo.DobaviObavestenja(ctx, logovaniKorisnikId, r.URL.Query().Get("neprocitana") == "true")
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja obavestenja")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja obavestenja"))
+		return
+	}
+
+	err = obavestenja.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+func (h *SudHandler) ProcitajObavestenje(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.ProcitajObavestenje")
+	defer span.End()
+
+	obavestenjeId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id obavestenja nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id obavestenja nije procitan"))
+		return
+	}
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	err = h.sudRepo.OznaciObavestenjeProcitanim(ctx, obavestenjeId, logovaniKorisnikId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Obavestenje ne postoji")
+		writer.WriteHeader(http.StatusNotFound)
+		writer.Write([]byte("Obavestenje ne postoji"))
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// DODELA PREDMETA
+
+// rasporedSudija vraca sudije iz rasporeda i broj aktivnih predmeta svakog od njih.
+// Sudija koji zavodi predmete ulazi u raspored pri prvom zavodjenju.
+func (h *SudHandler) rasporedSudija(ctx context.Context, idSudije primitive.ObjectID, ime string) (data.Sudije, map[primitive.ObjectID]int, error) {
+	if err := h.sudRepo.PrijaviSudiju(ctx, idSudije, ime); err != nil {
+		return nil, nil, err
+	}
+	sudije, err := h.sudRepo.DobaviSudije(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	opterecenje, err := h.sudRepo.DobaviOpterecenjeSudija(ctx, sudije)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sudije, opterecenje, nil
+}
+
+// uskladiRasporedSudija dopunjuje raspored sudijama iz auth servisa i iskljucuje iz dodele korisnike kojima je
+// rola sudije oduzeta. Ako auth servis nije dostupan raspored ostaje nepromenjen.
+func (h *SudHandler) uskladiRasporedSudija(ctx context.Context) {
+	korisnici := data.Korisnici{}
+	for _, rola := range []string{data.RolaSudija, data.RolaPredsednikSuda} {
+		saRolom, err := h.authClient.DobaviKorisnikePoRoli(ctx, rola)
+		if err != nil {
+			h.logger.Println("Greska prilikom dobavljanja sudija iz auth servisa:", err)
+			return
+		}
+		korisnici = append(korisnici, saRolom...)
+	}
+	if err := h.sudRepo.UskladiRasporedSudija(ctx, korisnici); err != nil {
+		h.logger.Println("Greska prilikom uskladjivanja rasporeda sudija:", err)
+	}
+}
+
+// slucajnoDodeli izvlaci sudiju za postojeci predmet i dodeljuje mu predmet ako ga u medjuvremenu niko nije preuzeo
+func (h *SudHandler) slucajnoDodeli(ctx context.Context, predmet *data.Predmet, idDodelio primitive.ObjectID) (*data.DodelaPredmeta, error) {
+	sudije, err := h.sudRepo.DobaviSudije(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opterecenje, err := h.sudRepo.DobaviOpterecenjeSudija(ctx, sudije)
+	if err != nil {
+		return nil, err
+	}
+	dodela, err := data.SlucajnaDodela(predmet, sudije, opterecenje, data.NovoSeme(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	dodela.IdDodelio = idDodelio
+	if err := h.sudRepo.DodeliPredmet(ctx, dodela); err != nil {
+		return nil, err
+	}
+	return dodela, nil
+}
+
+func (h *SudHandler) DobaviSudije(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SudHandler.DobaviSudije")
+	defer span.End()
+
+	sudije, err := h.sudRepo.DobaviSudije(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja sudija")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja sudija"))
+		return
+	}
+
+	err = sudije.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+// SacuvajSudiju dodaje sudiju u raspored ili mu menja normu i aktivnost
+func (h *SudHandler) SacuvajSudiju(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.SacuvajSudiju")
+	defer span.End()
+
+	sudija := &data.Sudija{}
+	if err := sudija.FromJSON(req.Body); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if sudija.ID.IsZero() || sudija.Ime == "" {
+		span.SetStatus(codes.Error, "Id i ime sudije su obavezni")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id i ime sudije su obavezni"))
+		return
+	}
+	if sudija.Norma < 0 || sudija.Norma > data.PunaNorma {
+		span.SetStatus(codes.Error, "Norma mora biti izmedju 0 i 100")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Norma mora biti izmedju 0 i 100"))
+		return
+	}
+
+	korisnik, err := h.authClient.DobaviKorisnika(ctx, sudija.ID.Hex())
+	if err != nil {
+		if client.JeNijePronadjen(err) {
+			span.SetStatus(codes.Error, "Korisnik ne postoji")
+			writer.WriteHeader(http.StatusNotFound)
+			writer.Write([]byte("Korisnik ne postoji"))
+			return
+		}
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja korisnika")
+		writer.WriteHeader(http.StatusServiceUnavailable)
+		writer.Write([]byte("Greska prilikom dobavljanja korisnika"))
+		return
+	}
+	if !data.JeRolaSudije(korisnik.Rola) {
+		span.SetStatus(codes.Error, "U raspored se moze dodati samo korisnik sa rolom sudije")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("U raspored se moze dodati samo korisnik sa rolom sudije"))
+		return
+	}
+
+	err = h.sudRepo.SacuvajSudiju(ctx, sudija)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom cuvanja sudije")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom cuvanja sudije"))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+}
+
+// DodajOdsustvoSudije belezi period u kome sudija ne ucestvuje u dodeli predmeta
+func (h *SudHandler) DodajOdsustvoSudije(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.DodajOdsustvoSudije")
+	defer span.End()
+
+	sudijaId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id sudije nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id sudije nije procitan"))
+		return
+	}
+
+	var odsustvo data.OdsustvoSudije
+	if err := json.NewDecoder(req.Body).Decode(&odsustvo); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if odsustvo.Od == 0 || odsustvo.Do <= odsustvo.Od {
+		span.SetStatus(codes.Error, "Kraj odsustva mora biti posle pocetka")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Kraj odsustva mora biti posle pocetka"))
+		return
+	}
+
+	err = h.sudRepo.DodajOdsustvoSudije(ctx, sudijaId, odsustvo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			span.SetStatus(codes.Error, "Sudija nije u rasporedu")
+			writer.WriteHeader(http.StatusNotFound)
+			writer.Write([]byte("Sudija nije u rasporedu"))
+			return
+		}
+		span.SetStatus(codes.Error, "Greska prilikom dodavanja odsustva")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom dodavanja odsustva"))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+}
+
+// DobaviDodelePredmeta vraca sve dodele predmeta, slucajne dodele su ponovo izvucene iz sacuvanog semena i kandidata
+func (h *SudHandler) DobaviDodelePredmeta(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SudHandler.DobaviDodelePredmeta")
+	defer span.End()
+
+	predmetId, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id predmeta nije procitan")
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Id predmeta nije procitan"))
+		return
+	}
+
+	dodele, err := h.sudRepo.DobaviDodelePredmeta(ctx, predmetId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja dodela predmeta")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja dodela predmeta"))
+		return
+	}
+	for _, dodela := range dodele {
+		dodela.Proveri()
+	}
+
+	err = dodele.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+// RucnoDodeliPredmet omogucava predsedniku suda da uz obrazlozenje dodeli predmet sudiji mimo slucajne dodele.
+// Sudija koji je izuzet iz predmeta, odsutan ili neaktivan ne moze dobiti predmet ni na ovaj nacin.
+func (h *SudHandler) RucnoDodeliPredmet(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.RucnoDodeliPredmet")
+	defer span.End()
+
+	predmetId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id predmeta nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id predmeta nije procitan"))
+		return
+	}
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	var rucna data.RucnaDodela
+	if err := json.NewDecoder(req.Body).Decode(&rucna); err != nil || rucna.IdSudije.IsZero() {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if rucna.Obrazlozenje == "" {
+		span.SetStatus(codes.Error, "Za rucnu dodelu je potrebno obrazlozenje")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Za rucnu dodelu je potrebno obrazlozenje"))
+		return
+	}
+
+	stanje, err := h.sudRepo.DobaviStanjePredmeta(ctx, predmetId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Predmet sa prosledjenim id ne postoji")
+		writer.WriteHeader(http.StatusNotFound)
+		writer.Write([]byte("Predmet sa prosledjenim id ne postoji"))
+		return
+	}
+	if !stanje.Aktivan() {
+		span.SetStatus(codes.Error, "O predmetu je vec odluceno")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("O predmetu je vec odluceno"))
+		return
+	}
+	predmet := stanje.Predmet
+	if predmet.IdSudije == rucna.IdSudije {
+		span.SetStatus(codes.Error, "Predmet je vec dodeljen tom sudiji")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Predmet je vec dodeljen tom sudiji"))
+		return
+	}
+
+	sada := time.Now()
+	sudija, err := h.sudRepo.DobaviSudiju(ctx, rucna.IdSudije)
+	if err != nil {
+		span.SetStatus(codes.Error, "Sudija nije u rasporedu")
+		writer.WriteHeader(http.StatusNotFound)
+		writer.Write([]byte("Sudija nije u rasporedu"))
+		return
+	}
+	if razlog := data.RazlogIskljucenja(sudija, predmet, sada); razlog != "" {
+		span.SetStatus(codes.Error, razlog)
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte(razlog))
+		return
+	}
+
+	dodela := &data.DodelaPredmeta{
+		IdPredmeta:      predmet.ID,
+		Nacin:           data.DODELA_RUCNA,
+		IdSudije:        sudija.ID,
+		PrethodniSudija: predmet.IdSudije,
+		IdDodelio:       logovaniKorisnikId,
+		Obrazlozenje:    rucna.Obrazlozenje,
+		Datum:           sada,
+	}
+	err = h.sudRepo.DodeliPredmet(ctx, dodela)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			span.SetStatus(codes.Error, "Predmet je u medjuvremenu dodeljen drugom sudiji")
+			writer.WriteHeader(http.StatusConflict)
+			writer.Write([]byte("Predmet je u medjuvremenu dodeljen drugom sudiji"))
+			return
+		}
+		span.SetStatus(codes.Error, "Greska prilikom dodele predmeta")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom dodele predmeta"))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	dodela.ToJSON(writer)
+}
+
+// IzuzmiSeIzPredmeta belezi izuzece sudije kome je predmet dodeljen i predmet slucajno dodeljuje drugom sudiji
+func (h *SudHandler) IzuzmiSeIzPredmeta(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.IzuzmiSeIzPredmeta")
+	defer span.End()
+
+	predmetId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id predmeta nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id predmeta nije procitan"))
+		return
+	}
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	var izuzece data.IzuzeceSudije
+	if err := json.NewDecoder(req.Body).Decode(&izuzece); err != nil || izuzece.Razlog == "" {
+		span.SetStatus(codes.Error, "Razlog izuzeca je obavezan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Razlog izuzeca je obavezan"))
+		return
+	}
+
+	stanje, err := h.sudRepo.DobaviStanjePredmeta(ctx, predmetId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Predmet sa prosledjenim id ne postoji")
+		writer.WriteHeader(http.StatusNotFound)
+		writer.Write([]byte("Predmet sa prosledjenim id ne postoji"))
+		return
+	}
+	predmet := stanje.Predmet
+	if predmet.IdSudije != logovaniKorisnikId {
+		span.SetStatus(codes.Error, "Predmet nije dodeljen prijavljenom sudiji")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Predmet nije dodeljen prijavljenom sudiji"))
+		return
+	}
+	if !stanje.Aktivan() {
+		span.SetStatus(codes.Error, "O predmetu je vec odluceno")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("O predmetu je vec odluceno"))
+		return
+	}
+
+	sada := time.Now()
+	izuzece.IdSudije = logovaniKorisnikId
+	izuzece.Datum = primitive.NewDateTimeFromTime(sada)
+	predmet.Izuzeca = append(predmet.Izuzeca, izuzece)
+
+	sudije, err := h.sudRepo.DobaviSudije(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja rasporeda sudija")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom dobavljanja rasporeda sudija"))
+		return
+	}
+	opterecenje, err := h.sudRepo.DobaviOpterecenjeSudija(ctx, sudije)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja rasporeda sudija")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom dobavljanja rasporeda sudija"))
+		return
+	}
+	dodela, err := data.SlucajnaDodela(predmet, sudije, opterecenje, data.NovoSeme(), sada)
+	if err != nil {
+		span.SetStatus(codes.Error, "Nema drugog dostupnog sudije, izuzece nije zabelezeno")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Nema drugog dostupnog sudije, izuzece nije zabelezeno"))
+		return
+	}
+	dodela.IdDodelio = logovaniKorisnikId
+	dodela.Obrazlozenje = izuzece.Razlog
+
+	err = h.sudRepo.IzuzmiSudiju(ctx, izuzece, dodela)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			span.SetStatus(codes.Error, "Predmet je u medjuvremenu dodeljen drugom sudiji")
+			writer.WriteHeader(http.StatusConflict)
+			writer.Write([]byte("Predmet je u medjuvremenu dodeljen drugom sudiji"))
+			return
+		}
+		span.SetStatus(codes.Error, "Greska prilikom izuzeca iz predmeta")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom izuzeca iz predmeta"))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	dodela.ToJSON(writer)
+}
+
+// DobaviImporteZahteva vraca stanje uvoza svakog zahteva tuzilastva
+func (h *SudHandler) DobaviImporteZahteva(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SudHandler.DobaviImporteZahteva")
+	defer span.End()
+
+	importi, err := h.sudRepo.DobaviImporteZahteva(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja uvoza zahteva")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja uvoza zahteva"))
+		return
+	}
+
+	err = importi.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+// KALENDAR
+
+// Putanja kalendara kroz api gateway, na koju se pretplacuju kalendarski klijenti
+const putanjaKalendara = "/api/sud/kalendar/"
+
+// IzdajTokenKalendara izdaje novi token za pretplatu na kalendar sudjenja, prethodni token korisnika prestaje da vazi
+func (h *SudHandler) IzdajTokenKalendara(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.IzdajTokenKalendara")
+	defer span.End()
+
+	claims := helper.ExtractClaims(req)
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(claims["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	token, hes, err := data.NoviTokenKalendara()
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom izdavanja tokena kalendara")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom izdavanja tokena kalendara"))
+		return
+	}
+	tokenKalendara := &data.TokenKalendara{
+		IdKorisnika: logovaniKorisnikId,
+		HesTokena:   hes,
+		Ime:         claims["imeIPrezime"],
+		Kreirano:    time.Now(),
+	}
+	err = h.sudRepo.SacuvajTokenKalendara(ctx, tokenKalendara)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom izdavanja tokena kalendara")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom izdavanja tokena kalendara"))
+		return
+	}
+
+	parametar := url.Values{"token": {token}}.Encode()
+	pretplata := &data.PretplataNaKalendar{
+		Token:              token,
+		KalendarSudije:     putanjaKalendara + "sudija.ics?" + parametar,
+		KalendarProstorije: putanjaKalendara + "prostorija.ics?" + parametar + "&adresa=&prostorija=",
+		Kreirano:           tokenKalendara.Kreirano,
+	}
+	writer.WriteHeader(http.StatusOK)
+	pretplata.ToJSON(writer)
+}
+
+// OpozoviTokenKalendara ponistava token korisnika, pretplate sa tim tokenom vise ne dobijaju kalendar
+func (h *SudHandler) OpozoviTokenKalendara(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "SudHandler.OpozoviTokenKalendara")
+	defer span.End()
+
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	err = h.sudRepo.OpozoviTokenKalendara(ctx, logovaniKorisnikId)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			span.SetStatus(codes.Error, "Korisnik nema token kalendara")
+			writer.WriteHeader(http.StatusNotFound)
+			writer.Write([]byte("Korisnik nema token kalendara"))
+			return
+		}
+		span.SetStatus(codes.Error, "Greska prilikom opoziva tokena kalendara")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom opoziva tokena kalendara"))
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// vlasnikTokenaKalendara vraca korisnika kome pripada token iz parametra token. Kalendarski klijenti ne salju JWT,
+// pa je token jedina provera pristupa kalendaru. Korisniku kome je rola sudije u medjuvremenu oduzeta token se opoziva.
+func (h *SudHandler) vlasnikTokenaKalendara(ctx context.Context, req *http.Request) (*data.TokenKalendara, int, string) {
+	token := req.URL.Query().Get("token")
+	if token == "" {
+		return nil, http.StatusUnauthorized, "Token kalendara nije prosledjen"
+	}
+	vlasnik, err := h.sudRepo.DobaviTokenKalendara(ctx, data.HesTokenaKalendara(token))
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, http.StatusUnauthorized, "Token kalendara nije vazeci"
+		}
+		return nil, http.StatusInternalServerError, "Greska prilikom provere tokena kalendara"
+	}
+
+	korisnik, err := h.authClient.DobaviKorisnika(ctx, vlasnik.IdKorisnika.Hex())
+	if err != nil && !client.JeNijePronadjen(err) {
+		return nil, http.StatusServiceUnavailable, "Greska prilikom dobavljanja korisnika"
+	}
+	if err != nil || !data.JeRolaSudije(korisnik.Rola) {
+		if err := h.sudRepo.OpozoviTokenKalendara(ctx, vlasnik.IdKorisnika); err != nil && err != mongo.ErrNoDocuments {
+			log.Println("Greska prilikom opozivanja tokena kalendara:", err)
+		}
+		return nil, http.StatusUnauthorized, "Token kalendara nije vazeci"
+	}
+	return vlasnik, http.StatusOK, ""
+}
+
+// posaljiKalendar salje termine koje vrati dobavi i koji ispunjavaju uslov, od termina zapocetih pre
+// IstorijaKalendaraDana do buducih
+func (h *SudHandler) posaljiKalendar(ctx context.Context, rw http.ResponseWriter, naziv string,
+	dobavi func(od time.Time) (data.TerminiSudjenja, error), uKalendaru func(*data.TerminSudjenja) bool) {
+	span := trace.SpanFromContext(ctx)
+
+	termini, err := dobavi(time.Now().AddDate(0, 0, -data.IstorijaKalendaraDana))
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja termina")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja termina"))
+		return
+	}
+	izabrani := data.TerminiSudjenja{}
+	for _, termin := range termini {
+		if uKalendaru(termin) {
+			izabrani = append(izabrani, termin)
+		}
+	}
+
+	rw.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	rw.Header().Set("Cache-Control", "no-cache")
+	err = data.IzveziKalendar(rw, naziv, izabrani)
+	if err != nil {
+		log.Println("Greska prilikom slanja kalendara:", err)
+		span.SetStatus(codes.Error, "Greska prilikom slanja kalendara")
+	}
+}
+
+// KalendarSudije vraca sudjenja vlasnika tokena u iCalendar formatu
+func (h *SudHandler) KalendarSudije(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SudHandler.KalendarSudije")
+	defer span.End()
+
+	vlasnik, status, poruka := h.vlasnikTokenaKalendara(ctx, r)
+	if vlasnik == nil {
+		span.SetStatus(codes.Error, poruka)
+		rw.WriteHeader(status)
+		rw.Write([]byte(poruka))
+		return
+	}
+
+	naziv := "Sudjenja"
+	if vlasnik.Ime != "" {
+		naziv += " - " + vlasnik.Ime
+	}
+	dobavi := func(od time.Time) (data.TerminiSudjenja, error) {
+		return h.sudRepo.DobaviTermineSudijeOd(ctx, vlasnik.IdKorisnika, od)
+	}
+	h.posaljiKalendar(ctx, rw, naziv, dobavi, func(termin *data.TerminSudjenja) bool {
+		return termin.SudijaTermina() == vlasnik.IdKorisnika
+	})
+}
+
+// KalendarProstorije vraca sudjenja u prostoriji iz parametara adresa i prostorija u iCalendar formatu
+func (h *SudHandler) KalendarProstorije(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SudHandler.KalendarProstorije")
+	defer span.End()
+
+	vlasnik, status, poruka := h.vlasnikTokenaKalendara(ctx, r)
+	if vlasnik == nil {
+		span.SetStatus(codes.Error, poruka)
+		rw.WriteHeader(status)
+		rw.Write([]byte(poruka))
+		return
+	}
+
+	upit := r.URL.Query()
+	prostorija := &data.TerminSudjenja{Adresa: upit.Get("adresa"), Prostorija: upit.Get("prostorija")}
+	if strings.TrimSpace(prostorija.Adresa) == "" || strings.TrimSpace(prostorija.Prostorija) == "" {
+		span.SetStatus(codes.Error, "Adresa i prostorija su obavezne")
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Adresa i prostorija su obavezne"))
+		return
+	}
+
+	naziv := fmt.Sprintf("Sudjenja - %s, prostorija %s", prostorija.Adresa, prostorija.Prostorija)
+	dobavi := func(od time.Time) (data.TerminiSudjenja, error) {
+		return h.sudRepo.DobaviTermineProstorijeOd(ctx, prostorija.Adresa, prostorija.Prostorija, od)
+	}
+	h.posaljiKalendar(ctx, rw, naziv, dobavi, func(termin *data.TerminSudjenja) bool {
+		return termin.KljucProstorije() == prostorija.KljucProstorije()
+	})
+}
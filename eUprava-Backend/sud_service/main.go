@@ -58,7 +58,7 @@ func main() {
 	defer store.DisconnectMongo(timeoutContext)
 	store.Ping()
 
-	tuzilastvoClient := &http.Client{
+	servisClient := &http.Client{
 		Transport: &http.Transport{
 			MaxIdleConns:        10,
 			MaxIdleConnsPerHost: 10,
@@ -88,9 +88,58 @@ func main() {
 	)
 
 	tuzilastvUri := fmt.Sprintf("http://%s:%s", os.Getenv("TUZILASTVO_SERVICE_HOST"), os.Getenv("TUZILASTVO_SERVICE_PORT"))
-	tuzilastvo := client.NewTuzilastvoClient(tuzilastvoClient, tuzilastvUri, tuzilastvoBreaker)
+	tuzilastvo := client.NewTuzilastvoClient(servisClient, tuzilastvUri, tuzilastvoBreaker)
 
-	sudHandler := handlers.NewSudHandler(logger, store, tracer, tuzilastvo)
+	authBreaker := gobreaker.NewCircuitBreaker(
+		gobreaker.Settings{
+			Name:        "auth",
+			MaxRequests: 1,
+			Timeout:     10 * time.Second,
+			Interval:    0,
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return counts.ConsecutiveFailures > 2
+			},
+			OnStateChange: func(name string, from, to gobreaker.State) {
+				logger.Printf("CB '%s' changed from '%s' to '%s'\n", name, from, to)
+			},
+			IsSuccessful: func(err error) bool {
+				if err == nil {
+					return true
+				}
+				errResp, ok := err.(domain.ErrResp)
+				return ok && errResp.StatusCode >= 400 && errResp.StatusCode < 500
+			},
+		},
+	)
+	authUri := fmt.Sprintf("http://%s:%s", os.Getenv("AUTH_SERVICE_HOST"), os.Getenv("AUTH_SERVICE_PORT"))
+	auth := client.NewAuthClient(servisClient, authUri, authBreaker)
+
+	sudHandler := handlers.NewSudHandler(logger, store, tracer, tuzilastvo, auth)
+
+	if err := store.KreirajIndekseRokova(timeoutContext); err != nil {
+		logger.Println("Greska prilikom kreiranja indeksa obavestenja:", err)
+	}
+	if err := store.UpisiPodrazumevanaPravilaRokova(timeoutContext); err != nil {
+		logger.Println("Greska prilikom upisa pravila rokova:", err)
+	}
+	if err := store.KreirajIndekseDodela(timeoutContext); err != nil {
+		logger.Println("Greska prilikom kreiranja indeksa dodela predmeta:", err)
+	}
+	if err := store.KreirajIndekseImporta(timeoutContext); err != nil {
+		logger.Fatal("Greska prilikom kreiranja indeksa uvoza zahteva:", err)
+	}
+	if err := store.KreirajIndekseSporazuma(timeoutContext); err != nil {
+		logger.Fatal("Greska prilikom kreiranja indeksa predmeta potvrde sporazuma:", err)
+	}
+	if err := store.KreirajIndeksePresuda(timeoutContext); err != nil {
+		logger.Fatal("Greska prilikom kreiranja indeksa presuda:", err)
+	}
+	if err := store.KreirajIndekseKalendara(timeoutContext); err != nil {
+		logger.Println("Greska prilikom kreiranja indeksa tokena kalendara:", err)
+	}
+	pozadinskeObradeCtx, zaustaviPozadinskeObrade := context.WithCancel(context.Background())
+	defer zaustaviPozadinskeObrade()
+	go sudHandler.PokreniPracenjeRokova(pozadinskeObradeCtx, time.Hour)
 
 	//Initialize the router and add a middleware for all the requests
 	router := mux.NewRouter()
@@ -115,6 +164,37 @@ func main() {
 	dodajPredmetePoZahtjevima := router.Methods(http.MethodPost).Subrouter()
 	dodajPredmetePoZahtjevima.HandleFunc("/predmeti/zahtjevi", sudHandler.DodajPredmetePoZahtjevima)
 
+	dobaviImporteZahteva := router.Methods(http.MethodGet).Subrouter()
+	dobaviImporteZahteva.HandleFunc("/importZahteva", sudHandler.DobaviImporteZahteva)
+
+	dodajPredmetPotvrdeSporazuma := router.Methods(http.MethodPost).Subrouter()
+	dodajPredmetPotvrdeSporazuma.HandleFunc("/predmeti/sporazumi", sudHandler.DodajPredmetPotvrdeSporazuma)
+
+	dobaviPredmetPoSporazumu := router.Methods(http.MethodGet).Subrouter()
+	dobaviPredmetPoSporazumu.HandleFunc("/predmeti/sporazumi/{id}", sudHandler.DobaviPredmetPoSporazumu)
+
+	odluciOSporazumu := router.Methods(http.MethodPut).Subrouter()
+	odluciOSporazumu.HandleFunc("/predmeti/{id}/odluka", sudHandler.OdluciOSporazumu)
+
+	dobaviDodelePredmeta := router.Methods(http.MethodGet).Subrouter()
+	dobaviDodelePredmeta.HandleFunc("/predmeti/{id}/dodele", sudHandler.DobaviDodelePredmeta)
+
+	rucnoDodeliPredmet := router.Methods(http.MethodPut).Subrouter()
+	rucnoDodeliPredmet.HandleFunc("/predmeti/{id}/dodela", sudHandler.RucnoDodeliPredmet)
+
+	izuzmiSeIzPredmeta := router.Methods(http.MethodPut).Subrouter()
+	izuzmiSeIzPredmeta.HandleFunc("/predmeti/{id}/izuzece", sudHandler.IzuzmiSeIzPredmeta)
+
+	//SUDIJE
+	dobaviSudije := router.Methods(http.MethodGet).Subrouter()
+	dobaviSudije.HandleFunc("/sudije", sudHandler.DobaviSudije)
+
+	sacuvajSudiju := router.Methods(http.MethodPut).Subrouter()
+	sacuvajSudiju.HandleFunc("/sudije", sudHandler.SacuvajSudiju)
+
+	dodajOdsustvoSudije := router.Methods(http.MethodPost).Subrouter()
+	dodajOdsustvoSudije.HandleFunc("/sudije/{id}/odsustva", sudHandler.DodajOdsustvoSudije)
+
 	//TERMINI
 	dobaviTermine := router.Methods(http.MethodGet).Subrouter()
 	dobaviTermine.HandleFunc("/termini", sudHandler.DobaviTermine)
@@ -126,6 +206,37 @@ func main() {
 	dobaviTerminPoId := router.Methods(http.MethodGet).Subrouter()
 	dobaviTerminPoId.HandleFunc("/termini/{id}", sudHandler.DobaviTerminPoId)
 
+	odloziTermin := router.Methods(http.MethodPut).Subrouter()
+	odloziTermin.HandleFunc("/termini/{id}/odlozi", sudHandler.OdloziTermin)
+
+	otkaziTermin := router.Methods(http.MethodPut).Subrouter()
+	otkaziTermin.HandleFunc("/termini/{id}/otkazi", sudHandler.OtkaziTermin)
+
+	oznaciTerminOdrzanim := router.Methods(http.MethodPut).Subrouter()
+	oznaciTerminOdrzanim.HandleFunc("/termini/{id}/odrzan", sudHandler.OznaciTerminOdrzanim)
+
+	slobodniTermini := router.Methods(http.MethodGet).Subrouter()
+	slobodniTermini.HandleFunc("/slobodniTermini", sudHandler.SlobodniTermini)
+
+	dobaviNeradneDane := router.Methods(http.MethodGet).Subrouter()
+	dobaviNeradneDane.HandleFunc("/neradniDani", sudHandler.DobaviNeradneDane)
+
+	sacuvajNeradniDan := router.Methods(http.MethodPut).Subrouter()
+	sacuvajNeradniDan.HandleFunc("/neradniDani", sudHandler.SacuvajNeradniDan)
+
+	//KALENDAR
+	izdajTokenKalendara := router.Methods(http.MethodPut).Subrouter()
+	izdajTokenKalendara.HandleFunc("/kalendar/token", sudHandler.IzdajTokenKalendara)
+
+	opozoviTokenKalendara := router.Methods(http.MethodDelete).Subrouter()
+	opozoviTokenKalendara.HandleFunc("/kalendar/token", sudHandler.OpozoviTokenKalendara)
+
+	kalendarSudije := router.Methods(http.MethodGet).Subrouter()
+	kalendarSudije.HandleFunc("/kalendar/sudija.ics", sudHandler.KalendarSudije)
+
+	kalendarProstorije := router.Methods(http.MethodGet).Subrouter()
+	kalendarProstorije.HandleFunc("/kalendar/prostorija.ics", sudHandler.KalendarProstorije)
+
 	//PRESUDE
 	dobaviPresude := router.Methods(http.MethodGet).Subrouter()
 	dobaviPresude.HandleFunc("/presude", sudHandler.DobaviPresude)
@@ -137,6 +248,22 @@ func main() {
 	dobaviPresuduPoId := router.Methods(http.MethodGet).Subrouter()
 	dobaviPresuduPoId.HandleFunc("/presude/{id}", sudHandler.DobaviPresuduPoId)
 
+	//ROKOVI
+	predstojeciRokovi := router.Methods(http.MethodGet).Subrouter()
+	predstojeciRokovi.HandleFunc("/rokovi/predstojeci", sudHandler.PredstojeciRokovi)
+
+	dobaviPravilaRokova := router.Methods(http.MethodGet).Subrouter()
+	dobaviPravilaRokova.HandleFunc("/rokovi/pravila", sudHandler.DobaviPravilaRokova)
+
+	sacuvajPraviloRoka := router.Methods(http.MethodPut).Subrouter()
+	sacuvajPraviloRoka.HandleFunc("/rokovi/pravila", sudHandler.SacuvajPraviloRoka)
+
+	dobaviObavestenja := router.Methods(http.MethodGet).Subrouter()
+	dobaviObavestenja.HandleFunc("/obavestenja", sudHandler.DobaviObavestenja)
+
+	procitajObavestenje := router.Methods(http.MethodPut).Subrouter()
+	procitajObavestenje.HandleFunc("/obavestenja/{id}/procitano", sudHandler.ProcitajObavestenje)
+
 	//Initialize the server
 	server := http.Server{
 		Addr:         ":" + port,
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"github.com/sony/gobreaker"
+	"net/http"
+	"net/url"
+	"tuzilastvo_service/data"
+	"tuzilastvo_service/helper"
+)
+
+type AuthClient struct {
+	client  *http.Client
+	address string
+	cb      *gobreaker.CircuitBreaker
+}
+
+func NewAuthClient(client *http.Client, address string, cb *gobreaker.CircuitBreaker) AuthClient {
+	return AuthClient{
+		client:  client,
+		address: address,
+		cb:      cb,
+	}
+}
+
+func (ac AuthClient) DobaviKorisnika(ctx context.Context, id string, bearerToken string) (*data.Korisnik, error) {
+	var korisnik data.Korisnik
+	err := posaljiZahtev(ctx, ac.client, ac.cb, http.MethodGet, ac.address+"/korisnik/"+url.PathEscape(id), nil, bearerToken, &korisnik)
+	if err != nil {
+		return nil, err
+	}
+	return &korisnik, nil
+}
+
+// DobaviKorisnikePoRoli vraca sve korisnike sa zadatom rolom, zahtev salje tuzilastvo u svoje ime
+func (ac AuthClient) DobaviKorisnikePoRoli(ctx context.Context, rola string) ([]data.Korisnik, error) {
+	bearerToken, err := helper.ServisniToken()
+	if err != nil {
+		return nil, err
+	}
+	var korisnici []data.Korisnik
+	err = posaljiZahtev(ctx, ac.client, ac.cb, http.MethodGet, ac.address+"/korisnici/rola/"+url.PathEscape(rola), nil, bearerToken, &korisnici)
+	if err != nil {
+		return nil, err
+	}
+	return korisnici, nil
+}
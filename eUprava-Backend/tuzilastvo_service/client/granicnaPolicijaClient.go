@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/sony/gobreaker"
+	"net/http"
+	"net/url"
+	"tuzilastvo_service/data"
+)
+
+type GranicnaPolicijaClient struct {
+	client  *http.Client
+	address string
+	cb      *gobreaker.CircuitBreaker
+}
+
+func NewGranicnaPolicijaClient(client *http.Client, address string, cb *gobreaker.CircuitBreaker) GranicnaPolicijaClient {
+	return GranicnaPolicijaClient{
+		client:  client,
+		address: address,
+		cb:      cb,
+	}
+}
+
+func (gc GranicnaPolicijaClient) DobaviKrivicnuPrijavu(ctx context.Context, id string, bearerToken string) (*data.KrivicnaPrijava, error) {
+	var prijava data.KrivicnaPrijava
+	err := posaljiZahtev(ctx, gc.client, gc.cb, http.MethodGet, gc.address+"/krivicna-prijava/"+url.PathEscape(id), nil, bearerToken, &prijava)
+	if err != nil {
+		return nil, err
+	}
+	if prijava.Izvor == "" {
+		prijava.Izvor = data.IZVOR_GRANICNA_POLICIJA
+	}
+	return &prijava, nil
+}
+
+func (gc GranicnaPolicijaClient) DobaviKrivicnePrijave(ctx context.Context, bearerToken string) ([]*data.KrivicnaPrijava, error) {
+	var prijave []*data.KrivicnaPrijava
+	err := posaljiZahtev(ctx, gc.client, gc.cb, http.MethodGet, gc.address+"/krivicna-prijava/all", nil, bearerToken, &prijave)
+	if err != nil {
+		return nil, err
+	}
+	for _, prijava := range prijave {
+		if prijava.Izvor == "" {
+			prijava.Izvor = data.IZVOR_GRANICNA_POLICIJA
+		}
+	}
+	return prijave, nil
+}
+
+func (gc GranicnaPolicijaClient) PretraziKrivicnePrijave(ctx context.Context, filter data.FilterKrivicnihPrijava, bearerToken string) (*data.StranicaKrivicnihPrijava, error) {
+	parametri := url.Values{}
+	if filter.Status != "" {
+		parametri.Set("status", string(filter.Status))
+	}
+	if filter.Od != "" {
+		parametri.Set("od", filter.Od)
+	}
+	if filter.Do != "" {
+		parametri.Set("do", filter.Do)
+	}
+	if filter.JMBG != "" {
+		parametri.Set("jmbg", filter.JMBG)
+	}
+	if filter.GranicniPrelazId != "" {
+		parametri.Set("granicniPrelazId", filter.GranicniPrelazId)
+	}
+	if filter.Stranica > 0 {
+		parametri.Set("stranica", fmt.Sprint(filter.Stranica))
+	}
+	if filter.Velicina > 0 {
+		parametri.Set("velicina", fmt.Sprint(filter.Velicina))
+	}
+
+	var stranica data.StranicaKrivicnihPrijava
+	err := posaljiZahtev(ctx, gc.client, gc.cb, http.MethodGet, gc.address+"/krivicna-prijava/pretraga?"+parametri.Encode(), nil, bearerToken, &stranica)
+	if err != nil {
+		return nil, err
+	}
+	return &stranica, nil
+}
+
+func (gc GranicnaPolicijaClient) PromeniStatusPrijave(ctx context.Context, id string, promena data.PromenaStatusa, bearerToken string) error {
+	return posaljiZahtev(ctx, gc.client, gc.cb, http.MethodPut, gc.address+"/krivicna-prijava/"+url.PathEscape(id)+"/status", promena, bearerToken, nil)
+}
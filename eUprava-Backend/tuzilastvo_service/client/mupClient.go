@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"github.com/sony/gobreaker"
+	"net/http"
+	"net/url"
+	"tuzilastvo_service/data"
+)
+
+type MupClient struct {
+	client  *http.Client
+	address string
+	cb      *gobreaker.CircuitBreaker
+}
+
+func NewMupClient(client *http.Client, address string, cb *gobreaker.CircuitBreaker) MupClient {
+	return MupClient{
+		client:  client,
+		address: address,
+		cb:      cb,
+	}
+}
+
+func (mc MupClient) DobaviKrivicnuPrijavu(ctx context.Context, id string, bearerToken string) (*data.KrivicnaPrijava, error) {
+	var prijava data.KrivicnaPrijava
+	err := posaljiZahtev(ctx, mc.client, mc.cb, http.MethodGet, mc.address+"/dobaviKrivicnuPrijavu/"+url.PathEscape(id), nil, bearerToken, &prijava)
+	if err != nil {
+		return nil, err
+	}
+	return &prijava, nil
+}
+
+func (mc MupClient) DobaviKrivicnePrijave(ctx context.Context, bearerToken string) ([]*data.KrivicnaPrijava, error) {
+	var prijave []*data.KrivicnaPrijava
+	err := posaljiZahtev(ctx, mc.client, mc.cb, http.MethodGet, mc.address+"/dobaviKrivicnePrijave", nil, bearerToken, &prijave)
+	if err != nil {
+		return nil, err
+	}
+	return prijave, nil
+}
+
+func (mc MupClient) PromeniStatusPrijave(ctx context.Context, id string, promena data.PromenaStatusa, bearerToken string) error {
+	return posaljiZahtev(ctx, mc.client, mc.cb, http.MethodPut, mc.address+"/promeniStatusKrivicnePrijave/"+url.PathEscape(id), promena, bearerToken, nil)
+}
+
+func (mc MupClient) DobaviJmbgKorisnika(ctx context.Context, id string, bearerToken string) (string, error) {
+	var odgovor struct {
+		JMBG string `json:"jmbg"`
+	}
+	err := posaljiZahtev(ctx, mc.client, mc.cb, http.MethodGet, mc.address+"/dobaviJmbgKorisnika/"+url.PathEscape(id), nil, bearerToken, &odgovor)
+	if err != nil {
+		return "", err
+	}
+	return odgovor.JMBG, nil
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"github.com/sony/gobreaker"
+	"net/http"
+	"net/url"
+	"tuzilastvo_service/data"
+	"tuzilastvo_service/helper"
+)
+
+type SudClient struct {
+	client  *http.Client
+	address string
+	cb      *gobreaker.CircuitBreaker
+}
+
+func NewSudClient(client *http.Client, address string, cb *gobreaker.CircuitBreaker) SudClient {
+	return SudClient{
+		client:  client,
+		address: address,
+		cb:      cb,
+	}
+}
+
+// PosaljiSporazumNaPotvrdu poziva pozadinska obrada, pa se salje sa servisnim tokenom tuzilastva,
+// sud za ponovljeno slanje istog sporazuma vraca vec kreirani predmet
+func (sc SudClient) PosaljiSporazumNaPotvrdu(ctx context.Context, sporazum data.SporazumZaPotvrdu) (*data.PredmetSuda, error) {
+	bearer, err := helper.ServisniToken()
+	if err != nil {
+		return nil, err
+	}
+	var predmet data.PredmetSuda
+	err = posaljiZahtev(ctx, sc.client, sc.cb, http.MethodPost, sc.address+"/predmeti/sporazumi", sporazum, bearer, &predmet)
+	if err != nil {
+		return nil, err
+	}
+	return &predmet, nil
+}
+
+func (sc SudClient) DobaviPredmetPoSporazumu(ctx context.Context, sporazumId string) (*data.PredmetSuda, error) {
+	bearer, err := helper.ServisniToken()
+	if err != nil {
+		return nil, err
+	}
+	var predmet data.PredmetSuda
+	err = posaljiZahtev(ctx, sc.client, sc.cb, http.MethodGet, sc.address+"/predmeti/sporazumi/"+url.PathEscape(sporazumId), nil, bearer, &predmet)
+	if err != nil {
+		return nil, err
+	}
+	return &predmet, nil
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"tuzilastvo_service/domain"
+
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func handleHttpReqErr(err error, reqUrl string, method string, timeout time.Duration) error {
+	// request failed because breaker wasn't in the closed state
+	// and we didn't even try to send it
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		return err
+	}
+	// the request was sent
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return domain.ErrUnknown{
+			InnerErr: err,
+		}
+	}
+	if urlErr.Timeout() {
+		return domain.ErrClientSideTimeout{
+			URL:        reqUrl,
+			Method:     method,
+			MaxTimeout: timeout,
+		}
+	}
+	return domain.ErrConnecting{
+		Err: urlErr,
+	}
+}
+
+// posaljiZahtev salje zahtev kroz circuit breaker i dekodira JSON odgovor u odgovor, ako je prosledjen
+func posaljiZahtev(ctx context.Context, httpClient *http.Client, cb *gobreaker.CircuitBreaker, method string, reqUrl string, telo interface{}, bearerToken string, odgovor interface{}) error {
+	var timeout time.Duration
+	deadline, reqHasDeadline := ctx.Deadline()
+	if reqHasDeadline {
+		timeout = time.Until(deadline)
+	}
+
+	_, err := cb.Execute(func() (interface{}, error) {
+		var body io.Reader
+		if telo != nil {
+			sadrzaj, err := json.Marshal(telo)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(sadrzaj)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, reqUrl, body)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", bearerToken)
+		if telo != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, domain.ErrResp{
+				URL:        resp.Request.URL.String(),
+				Method:     resp.Request.Method,
+				StatusCode: resp.StatusCode,
+			}
+		}
+
+		if odgovor != nil {
+			if err := json.NewDecoder(resp.Body).Decode(odgovor); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		if _, ok := err.(domain.ErrResp); ok {
+			return err
+		}
+		return handleHttpReqErr(err, reqUrl, method, timeout)
+	}
+	return nil
+}
+
+// JeNijePronadjen proverava da li je servis odgovorio sa 404
+func JeNijePronadjen(err error) bool {
+	errResp, ok := err.(domain.ErrResp)
+	return ok && errResp.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,236 @@
+package data
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"os"
+	"time"
+)
+
+// AlgoritamLanca oznacava unose potpisane kljucem lanca. Kljuc se cuva van baze, pa ni korisnik sa pristupom
+// bazi ne moze da izmeni unos i ponovo izracuna ispravan hes.
+const AlgoritamLanca = "HMAC-SHA256"
+
+var kljucLancaDokaza = []byte(os.Getenv("KLJUC_LANCA_DOKAZA"))
+
+func KljucLancaPostavljen() bool {
+	return len(kljucLancaDokaza) > 0
+}
+
+// Role kojima se dokaz moze predati na cuvanje
+var roleCuvaraDokaza = []string{"Tuzioc", "Istrazitelj", "Policajac"}
+
+func MozeDaCuvaDokaz(rola string) bool {
+	for _, dozvoljena := range roleCuvaraDokaza {
+		if rola == dozvoljena {
+			return true
+		}
+	}
+	return false
+}
+
+// VremeUnosaDokaza vraca trenutno vreme zaokruzeno na milisekunde, koliko Mongo cuva, kako bi hes
+// izracunat pri upisu bio isti kao hes izracunat iz procitanog unosa
+func VremeUnosaDokaza() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// IzracunajHes racuna HMAC-SHA256 unosa kljucem lanca iz svih polja osim samog hesa
+func (u *UnosLancaDokaza) IzracunajHes() string {
+	mac := hmac.New(sha256.New, kljucLancaDokaza)
+	mac.Write(u.sadrzajZaHes())
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// hesBezKljuca je hes unosa upisanih pre uvodjenja kljuca lanca i koristi se samo pri njihovom potpisivanju
+func (u *UnosLancaDokaza) hesBezKljuca() string {
+	hes := sha256.Sum256(u.sadrzajZaHes())
+	return hex.EncodeToString(hes[:])
+}
+
+// sadrzajZaHes serijalizuje polja unosa uvek istim redosledom, a vreme u UTC, pa hes ne zavisi od
+// vremenske zone servera
+func (u *UnosLancaDokaza) sadrzajZaHes() []byte {
+	type cuvar struct {
+		IdKorisnika string `json:"idKorisnika"`
+		Ime         string `json:"ime"`
+	}
+	type opis struct {
+		Naziv      string `json:"naziv"`
+		Opis       string `json:"opis"`
+		IdPrijave  string `json:"idPrijave"`
+		IdPredmeta string `json:"idPredmeta"`
+	}
+	sadrzaj := struct {
+		IdDokaza       string `json:"idDokaza"`
+		Redni          int    `json:"redni"`
+		Tip            string `json:"tip"`
+		Datum          string `json:"datum"`
+		IdZapisnicara  string `json:"idZapisnicara"`
+		ImeZapisnicara string `json:"imeZapisnicara"`
+		Predao         *cuvar `json:"predao"`
+		Primio         cuvar  `json:"primio"`
+		Lokacija       string `json:"lokacija"`
+		Napomena       string `json:"napomena"`
+		Dokaz          *opis  `json:"dokaz"`
+		PrethodniHes   string `json:"prethodniHes"`
+	}{
+		IdDokaza:       u.IdDokaza.Hex(),
+		Redni:          u.Redni,
+		Tip:            string(u.Tip),
+		Datum:          u.Datum.UTC().Format(time.RFC3339Nano),
+		IdZapisnicara:  u.IdZapisnicara.Hex(),
+		ImeZapisnicara: u.ImeZapisnicara,
+		Primio:         cuvar{IdKorisnika: u.Primio.IdKorisnika.Hex(), Ime: u.Primio.Ime},
+		Lokacija:       u.Lokacija,
+		Napomena:       u.Napomena,
+		PrethodniHes:   u.PrethodniHes,
+	}
+	if u.Predao != nil {
+		sadrzaj.Predao = &cuvar{IdKorisnika: u.Predao.IdKorisnika.Hex(), Ime: u.Predao.Ime}
+	}
+	if u.Dokaz != nil {
+		sadrzaj.Dokaz = &opis{
+			Naziv:      u.Dokaz.Naziv,
+			Opis:       u.Dokaz.Opis,
+			IdPrijave:  u.Dokaz.IdPrijave.Hex(),
+			IdPredmeta: u.Dokaz.IdPredmeta.Hex(),
+		}
+	}
+
+	serijalizovano, _ := json.Marshal(sadrzaj)
+	return serijalizovano
+}
+
+// NastaviLanac popunjava redni broj, hes prethodnog unosa i hes novog unosa koji se nadovezuje na poslednji
+func (u *UnosLancaDokaza) NastaviLanac(poslednji *UnosLancaDokaza) {
+	u.Redni = 0
+	u.PrethodniHes = ""
+	if poslednji != nil {
+		u.Redni = poslednji.Redni + 1
+		u.PrethodniHes = poslednji.Hes
+	}
+	u.Algoritam = AlgoritamLanca
+	u.Hes = u.IzracunajHes()
+}
+
+// PotpisiStariLanac proverava lanac upisan pre uvodjenja kljuca i, ako je neizmenjen, potpisuje sve
+// njegove unose kljucem lanca. Vraca false ako lanac vec nije bio ispravan, takav lanac se ne potpisuje.
+func PotpisiStariLanac(lanac []*UnosLancaDokaza) bool {
+	prethodniHes := ""
+	for _, unos := range lanac {
+		hes := unos.hesBezKljuca()
+		if unos.Algoritam == AlgoritamLanca {
+			hes = unos.IzracunajHes()
+		}
+		if unos.PrethodniHes != prethodniHes || hes != unos.Hes {
+			return false
+		}
+		prethodniHes = unos.Hes
+	}
+
+	prethodniHes = ""
+	for _, unos := range lanac {
+		unos.PrethodniHes = prethodniHes
+		unos.Algoritam = AlgoritamLanca
+		unos.Hes = unos.IzracunajHes()
+		prethodniHes = unos.Hes
+	}
+	return true
+}
+
+// ProveriLanacDokaza proverava redosled unosa, hes svakog unosa i njegovu vezu sa prethodnim, kontinuitet
+// cuvanja i da li podaci dokaza odgovaraju lancu. Vraca sve pronadjene nepravilnosti, a ne samo prvu.
+func ProveriLanacDokaza(dokaz *Dokaz, lanac []*UnosLancaDokaza) ProveraLanca {
+	provera := ProveraLanca{
+		IdDokaza:      dokaz.ID,
+		BrojUnosa:     len(lanac),
+		Nepravilnosti: []NepravilnostLanca{},
+		Provereno:     time.Now(),
+	}
+	nepravilnost := func(redni int, format string, args ...interface{}) {
+		provera.Nepravilnosti = append(provera.Nepravilnosti, NepravilnostLanca{Redni: redni, Opis: fmt.Sprintf(format, args...)})
+	}
+
+	if len(lanac) == 0 {
+		nepravilnost(0, "Lanac cuvanja ne sadrzi nijedan unos")
+		return provera
+	}
+
+	prethodniHes := ""
+	var prethodni *UnosLancaDokaza
+	for i, unos := range lanac {
+		if unos.Redni != i {
+			nepravilnost(unos.Redni, "Ocekivan unos sa rednim brojem %d, unos je uklonjen ili umetnut", i)
+		}
+		if unos.IdDokaza != dokaz.ID {
+			nepravilnost(unos.Redni, "Unos pripada drugom dokazu")
+		}
+		if unos.PrethodniHes != prethodniHes {
+			nepravilnost(unos.Redni, "Hes prethodnog unosa se ne poklapa, lanac je prekinut")
+		}
+		if unos.Algoritam != AlgoritamLanca {
+			nepravilnost(unos.Redni, "Unos nije potpisan kljucem lanca")
+		} else if unos.IzracunajHes() != unos.Hes {
+			nepravilnost(unos.Redni, "Sadrzaj unosa je izmenjen nakon upisa")
+		}
+
+		if prethodni == nil {
+			if unos.Tip != DOKAZ_EVIDENTIRAN || unos.Dokaz == nil {
+				nepravilnost(unos.Redni, "Prvi unos lanca nije evidentiranje dokaza")
+			} else if *unos.Dokaz != dokaz.PodaciDokaza() {
+				nepravilnost(unos.Redni, "Podaci dokaza se razlikuju od evidentiranih")
+			}
+		} else {
+			if unos.Tip != DOKAZ_PREDAT || unos.Predao == nil {
+				nepravilnost(unos.Redni, "Unos posle evidentiranja nije predaja dokaza")
+			} else if unos.Predao.IdKorisnika != prethodni.Primio.IdKorisnika {
+				nepravilnost(unos.Redni, "Dokaz je predao korisnik koji nije bio njegov cuvar")
+			}
+			if unos.Datum.Before(prethodni.Datum) {
+				nepravilnost(unos.Redni, "Predaja je zabelezena pre prethodnog unosa")
+			}
+		}
+
+		prethodniHes = unos.Hes
+		prethodni = unos
+	}
+
+	poslednji := lanac[len(lanac)-1]
+	if dokaz.BrojUnosa != len(lanac) || dokaz.PoslednjiHes != poslednji.Hes {
+		nepravilnost(poslednji.Redni, "Dokaz belezi %d unosa, a lanac ima %d, unosi su uklonjeni ili nisu zabelezeni", dokaz.BrojUnosa, len(lanac))
+	}
+	if dokaz.Cuvar.IdKorisnika != poslednji.Primio.IdKorisnika || dokaz.Lokacija != poslednji.Lokacija {
+		nepravilnost(poslednji.Redni, "Trenutni cuvar ili lokacija dokaza ne odgovaraju poslednjem unosu")
+	}
+
+	provera.Ispravan = len(provera.Nepravilnosti) == 0
+	return provera
+}
+
+// PodaciDokaza vraca podatke dokaza koji se cuvaju u prvom unosu lanca
+func (d *Dokaz) PodaciDokaza() OpisDokaza {
+	return OpisDokaza{Naziv: d.Naziv, Opis: d.Opis, IdPrijave: d.IdPrijave, IdPredmeta: d.IdPredmeta}
+}
+
+// PrviUnos evidentira dokaz kao prvu kariku lanca, korisnik koji ga evidentira postaje njegov cuvar
+func (d *Dokaz) PrviUnos(zapisnicar CuvarDokaza) *UnosLancaDokaza {
+	opis := d.PodaciDokaza()
+	unos := &UnosLancaDokaza{
+		ID:             primitive.NewObjectID(),
+		IdDokaza:       d.ID,
+		Tip:            DOKAZ_EVIDENTIRAN,
+		Datum:          d.Evidentiran,
+		IdZapisnicara:  zapisnicar.IdKorisnika,
+		ImeZapisnicara: zapisnicar.Ime,
+		Primio:         zapisnicar,
+		Lokacija:       d.Lokacija,
+		Dokaz:          &opis,
+	}
+	unos.NastaviLanac(nil)
+	return unos
+}
@@ -0,0 +1,252 @@
+package data
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func postaviKljucLanca(t *testing.T) {
+	t.Helper()
+	prethodni := kljucLancaDokaza
+	kljucLancaDokaza = []byte("kljuc-lanca-za-test")
+	t.Cleanup(func() { kljucLancaDokaza = prethodni })
+}
+
+// predaj nadovezuje predaju dokaza sledecem cuvaru i azurira dokaz kao sto to radi upis predaje
+func predaj(dokaz *Dokaz, lanac []*UnosLancaDokaza, primio CuvarDokaza, lokacija string) []*UnosLancaDokaza {
+	poslednji := lanac[len(lanac)-1]
+	predao := poslednji.Primio
+	unos := &UnosLancaDokaza{
+		ID:             primitive.NewObjectID(),
+		IdDokaza:       dokaz.ID,
+		Tip:            DOKAZ_PREDAT,
+		Datum:          poslednji.Datum.Add(time.Hour),
+		IdZapisnicara:  primio.IdKorisnika,
+		ImeZapisnicara: primio.Ime,
+		Predao:         &predao,
+		Primio:         primio,
+		Lokacija:       lokacija,
+	}
+	unos.NastaviLanac(poslednji)
+	dokaz.Cuvar = primio
+	dokaz.Lokacija = lokacija
+	dokaz.BrojUnosa++
+	dokaz.PoslednjiHes = unos.Hes
+	return append(lanac, unos)
+}
+
+func ispravanLanac() (*Dokaz, []*UnosLancaDokaza) {
+	istrazitelj := CuvarDokaza{IdKorisnika: primitive.NewObjectID(), Ime: "Istrazitelj"}
+	tuzioc := CuvarDokaza{IdKorisnika: primitive.NewObjectID(), Ime: "Tuzioc"}
+	dokaz := &Dokaz{
+		ID:          primitive.NewObjectID(),
+		Naziv:       "Noz",
+		Opis:        "Kuhinjski noz sa mesta dogadjaja",
+		IdPrijave:   primitive.NewObjectID(),
+		Evidentiran: time.Date(2025, time.June, 1, 10, 0, 0, 0, time.UTC),
+		Cuvar:       istrazitelj,
+		Lokacija:    "Magacin policije",
+	}
+	prvi := dokaz.PrviUnos(istrazitelj)
+	dokaz.BrojUnosa = 1
+	dokaz.PoslednjiHes = prvi.Hes
+
+	lanac := []*UnosLancaDokaza{prvi}
+	lanac = predaj(dokaz, lanac, tuzioc, "Arhiva tuzilastva")
+	lanac = predaj(dokaz, lanac, istrazitelj, "Laboratorija")
+	return dokaz, lanac
+}
+
+func TestProveriLanacDokaza(t *testing.T) {
+	postaviKljucLanca(t)
+
+	tests := []struct {
+		name   string
+		izmeni func(dokaz *Dokaz, lanac []*UnosLancaDokaza) []*UnosLancaDokaza
+		// delovi opisa nepravilnosti koje provera mora da pronadje
+		nepravilnosti []string
+	}{
+		{
+			name:   "ispravan lanac",
+			izmeni: func(dokaz *Dokaz, lanac []*UnosLancaDokaza) []*UnosLancaDokaza { return lanac },
+		},
+		{
+			name: "prazan lanac",
+			izmeni: func(dokaz *Dokaz, lanac []*UnosLancaDokaza) []*UnosLancaDokaza {
+				return nil
+			},
+			nepravilnosti: []string{"ne sadrzi nijedan unos"},
+		},
+		{
+			name: "izmenjena lokacija u unosu",
+			izmeni: func(dokaz *Dokaz, lanac []*UnosLancaDokaza) []*UnosLancaDokaza {
+				lanac[1].Lokacija = "Nepoznato"
+				return lanac
+			},
+			nepravilnosti: []string{"izmenjen nakon upisa"},
+		},
+		{
+			name: "uklonjen unos iz sredine",
+			izmeni: func(dokaz *Dokaz, lanac []*UnosLancaDokaza) []*UnosLancaDokaza {
+				return []*UnosLancaDokaza{lanac[0], lanac[2]}
+			},
+			nepravilnosti: []string{"uklonjen ili umetnut", "lanac je prekinut", "nije bio njegov cuvar", "Dokaz belezi 3 unosa"},
+		},
+		{
+			name: "uklonjen poslednji unos",
+			izmeni: func(dokaz *Dokaz, lanac []*UnosLancaDokaza) []*UnosLancaDokaza {
+				return lanac[:2]
+			},
+			nepravilnosti: []string{"Dokaz belezi 3 unosa", "Trenutni cuvar ili lokacija"},
+		},
+		{
+			name: "izmenjeni podaci dokaza",
+			izmeni: func(dokaz *Dokaz, lanac []*UnosLancaDokaza) []*UnosLancaDokaza {
+				dokaz.Naziv = "Pistolj"
+				return lanac
+			},
+			nepravilnosti: []string{"Podaci dokaza se razlikuju"},
+		},
+		{
+			name: "cuvar dokaza ne odgovara lancu",
+			izmeni: func(dokaz *Dokaz, lanac []*UnosLancaDokaza) []*UnosLancaDokaza {
+				dokaz.Lokacija = "Kod kuce"
+				return lanac
+			},
+			nepravilnosti: []string{"Trenutni cuvar ili lokacija"},
+		},
+		{
+			name: "unos bez potpisa kljucem",
+			izmeni: func(dokaz *Dokaz, lanac []*UnosLancaDokaza) []*UnosLancaDokaza {
+				lanac[2].Algoritam = ""
+				return lanac
+			},
+			nepravilnosti: []string{"nije potpisan kljucem"},
+		},
+		{
+			name: "unos drugog dokaza",
+			izmeni: func(dokaz *Dokaz, lanac []*UnosLancaDokaza) []*UnosLancaDokaza {
+				lanac[1].IdDokaza = primitive.NewObjectID()
+				lanac[1].Hes = lanac[1].IzracunajHes()
+				return lanac
+			},
+			nepravilnosti: []string{"pripada drugom dokazu", "lanac je prekinut"},
+		},
+		{
+			name: "predaja pre prethodnog unosa",
+			izmeni: func(dokaz *Dokaz, lanac []*UnosLancaDokaza) []*UnosLancaDokaza {
+				lanac[2].Datum = lanac[0].Datum.Add(-time.Hour)
+				lanac[2].Hes = lanac[2].IzracunajHes()
+				dokaz.PoslednjiHes = lanac[2].Hes
+				return lanac
+			},
+			nepravilnosti: []string{"pre prethodnog unosa"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dokaz, lanac := ispravanLanac()
+			lanac = tt.izmeni(dokaz, lanac)
+
+			provera := ProveriLanacDokaza(dokaz, lanac)
+			if provera.Ispravan != (len(tt.nepravilnosti) == 0) {
+				t.Errorf("Ispravan = %v, nepravilnosti %+v", provera.Ispravan, provera.Nepravilnosti)
+			}
+			if len(provera.Nepravilnosti) != len(tt.nepravilnosti) {
+				t.Fatalf("pronadjeno %d nepravilnosti %+v, ocekivano %d", len(provera.Nepravilnosti), provera.Nepravilnosti, len(tt.nepravilnosti))
+			}
+			for i, deo := range tt.nepravilnosti {
+				if !strings.Contains(provera.Nepravilnosti[i].Opis, deo) {
+					t.Errorf("nepravilnost %d = %q, ocekivano da sadrzi %q", i, provera.Nepravilnosti[i].Opis, deo)
+				}
+			}
+		})
+	}
+}
+
+// stariLanac pretvara lanac u lanac upisan pre uvodjenja kljuca, ciji su unosi hesirani bez kljuca
+func stariLanac(dokaz *Dokaz, lanac []*UnosLancaDokaza) {
+	prethodniHes := ""
+	for _, unos := range lanac {
+		unos.Algoritam = ""
+		unos.PrethodniHes = prethodniHes
+		unos.Hes = unos.hesBezKljuca()
+		prethodniHes = unos.Hes
+	}
+	dokaz.PoslednjiHes = prethodniHes
+}
+
+func TestPotpisiStariLanac(t *testing.T) {
+	postaviKljucLanca(t)
+
+	tests := []struct {
+		name   string
+		izmeni func(lanac []*UnosLancaDokaza)
+		want   bool
+	}{
+		{
+			name:   "neizmenjen stari lanac se potpisuje",
+			izmeni: func(lanac []*UnosLancaDokaza) {},
+			want:   true,
+		},
+		{
+			name: "delimicno potpisan lanac se potpisuje do kraja",
+			izmeni: func(lanac []*UnosLancaDokaza) {
+				lanac[0].Algoritam = AlgoritamLanca
+				lanac[0].Hes = lanac[0].IzracunajHes()
+				lanac[1].PrethodniHes = lanac[0].Hes
+				lanac[1].Hes = lanac[1].hesBezKljuca()
+				lanac[2].PrethodniHes = lanac[1].Hes
+				lanac[2].Hes = lanac[2].hesBezKljuca()
+			},
+			want: true,
+		},
+		{
+			name: "izmenjen stari unos se ne potpisuje",
+			izmeni: func(lanac []*UnosLancaDokaza) {
+				lanac[1].Napomena = "dopisano"
+			},
+			want: false,
+		},
+		{
+			name: "prekinut stari lanac se ne potpisuje",
+			izmeni: func(lanac []*UnosLancaDokaza) {
+				lanac[2].PrethodniHes = lanac[0].Hes
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dokaz, lanac := ispravanLanac()
+			stariLanac(dokaz, lanac)
+			tt.izmeni(lanac)
+			hesevi := make([]string, len(lanac))
+			for i, unos := range lanac {
+				hesevi[i] = unos.Hes
+			}
+
+			if got := PotpisiStariLanac(lanac); got != tt.want {
+				t.Fatalf("PotpisiStariLanac() = %v, ocekivano %v", got, tt.want)
+			}
+
+			if !tt.want {
+				for i, unos := range lanac {
+					if unos.Hes != hesevi[i] {
+						t.Errorf("unos %d je izmenjen iako lanac nije potpisan", i)
+					}
+				}
+				return
+			}
+			dokaz.PoslednjiHes = lanac[len(lanac)-1].Hes
+			if provera := ProveriLanacDokaza(dokaz, lanac); !provera.Ispravan {
+				t.Errorf("potpisan lanac nije ispravan: %+v", provera.Nepravilnosti)
+			}
+		})
+	}
+}
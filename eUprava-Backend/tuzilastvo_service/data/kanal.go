@@ -0,0 +1,100 @@
+package data
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strings"
+	"time"
+)
+
+// Role koje po politici pristupa mogu da koriste kanale za poruke
+var roleClanovaKanala = []string{"Istrazitelj", "Policajac"}
+
+// Clan vraca clanstvo korisnika u kanalu ili nil ako korisnik nije clan
+func (k *Kanal) Clan(idKorisnika primitive.ObjectID) *ClanKanala {
+	for i := range k.Clanovi {
+		if k.Clanovi[i].IdKorisnika == idKorisnika {
+			return &k.Clanovi[i]
+		}
+	}
+	return nil
+}
+
+// MozeDaCita dozvoljava citanje clanovima kanala, a otvorenog kanala i korisnicima sa rolom clanova kanala
+func (k *Kanal) MozeDaCita(korisnik *Korisnik) bool {
+	return k.Clan(korisnik.ID) != nil || (k.Otvoren && MozeDaBudeClanKanala(korisnik.Rola))
+}
+
+// MozeDaPise dozvoljava slanje poruka vlasniku i clanovima, ali ne i posmatracima. U otvoren kanal mogu
+// da pisu i korisnici koji nisu clanovi, kao pre uvodjenja clanstva.
+func (k *Kanal) MozeDaPise(korisnik *Korisnik) bool {
+	if clan := k.Clan(korisnik.ID); clan != nil {
+		return clan.Uloga != POSMATRAC_KANALA
+	}
+	return k.Otvoren && MozeDaBudeClanKanala(korisnik.Rola)
+}
+
+func (k *Kanal) JeVlasnik(idKorisnika primitive.ObjectID) bool {
+	return !k.IdVlasnika.IsZero() && k.IdVlasnika == idKorisnika
+}
+
+// DozvoljenaUlogaPoziva proverava ulogu koja se dodeljuje pozivom, vlasnik kanala se ne menja pozivom
+func DozvoljenaUlogaPoziva(uloga UlogaUKanalu) bool {
+	return uloga == CLAN_KANALA || uloga == POSMATRAC_KANALA
+}
+
+func MozeDaBudeClanKanala(rola string) bool {
+	for _, r := range roleClanovaKanala {
+		if r == rola {
+			return true
+		}
+	}
+	return false
+}
+
+// ImeKorisnika vraca ime i prezime za prikaz, a ako ih nema korisnicko ime
+func (k *Korisnik) ImeKorisnika() string {
+	ime := strings.TrimSpace(k.Ime + " " + k.Prezime)
+	if ime == "" {
+		return k.KorisnickoIme
+	}
+	return ime
+}
+
+const (
+	// Autor moze da izmeni poruku samo u ovom periodu nakon slanja
+	RokZaIzmenuPoruke = 15 * time.Minute
+	// Najveca velicina zahteva sa prilozima jedne poruke u bajtovima
+	MaxVelicinaPriloga   = 20 << 20
+	MaxRezultataPretrage = 50
+)
+
+// IzmenaIstekla proverava da li je prosao rok u kome autor moze da izmeni poruku
+func (p *Poruka) IzmenaIstekla(sada time.Time) bool {
+	return sada.Sub(p.Datum) > RokZaIzmenuPoruke
+}
+
+// MozeDaObrise dozvoljava brisanje autoru poruke i vlasniku kanala
+func (p *Poruka) MozeDaObrise(idKorisnika primitive.ObjectID, kanal *Kanal) bool {
+	return p.IdAutora == idKorisnika || kanal.JeVlasnik(idKorisnika)
+}
+
+// ZaPrikaz skriva sadrzaj, priloge i izmene obrisane poruke, a ostavlja podatke o brisanju
+func (p *Poruka) ZaPrikaz() *Poruka {
+	if !p.Obrisana {
+		return p
+	}
+	prikaz := *p
+	prikaz.Sadrzaj = ""
+	prikaz.Prilozi = nil
+	prikaz.Izmene = nil
+	return &prikaz
+}
+
+func (p *Poruka) Prilog(id primitive.ObjectID) *Prilog {
+	for i := range p.Prilozi {
+		if p.Prilozi[i].ID == id {
+			return &p.Prilozi[i]
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"io"
+	"prijave"
 	"time"
 )
 
@@ -22,51 +23,531 @@ type Prelaz struct {
 	Odobren               bool               `bson:"odobren,omitempty" json:"odobren"`
 }
 
+// Statusi krivicne prijave i dozvoljeni prelazi su zajednicki za sve servise
+type StatusPrijave = prijave.StatusPrijave
+
+const (
+	PODNETA                = prijave.PODNETA
+	PRIMLJENA_U_TUZILASTVU = prijave.PRIMLJENA_U_TUZILASTVU
+	ODBACENA               = prijave.ODBACENA
+	U_POSTUPKU             = prijave.U_POSTUPKU
+	ZAVRSENA               = prijave.ZAVRSENA
+)
+
+type IzvorPrijave string
+
+const (
+	IZVOR_MUP               = "MUP"
+	IZVOR_GRANICNA_POLICIJA = "GRANICNA_POLICIJA"
+)
+
+// ZaglavljeNedostupniIzvori navodi izvore prijava koji nisu odgovorili, odgovor sa ovim zaglavljem je nepotpun
+const ZaglavljeNedostupniIzvori = "Nedostupni-Izvori"
+
 type KrivicnaPrijava struct {
-	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Datum  primitive.DateTime `bson:"datum,omitempty" json:"datum"`
-	Opis   string             `bson:"opis,omitempty" json:"opis"`
-	Prelaz Prelaz             `bson:"prelaz,omitempty" json:"prelaz"`
+	ID                  primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Datum               primitive.DateTime  `bson:"datum,omitempty" json:"datum"`
+	Opis                string              `bson:"opis,omitempty" json:"opis"`
+	Izvor               IzvorPrijave        `bson:"izvor,omitempty" json:"izvor"`
+	Prelaz              Prelaz              `bson:"prelaz,omitempty" json:"prelaz"`
+	Status              StatusPrijave       `bson:"status,omitempty" json:"status"`
+	IdSluzbenika        primitive.ObjectID  `bson:"idSluzbenika,omitempty" json:"idSluzbenika"`
+	PravnaKvalifikacija PravnaKvalifikacija `bson:"pravnaKvalifikacija,omitempty" json:"pravnaKvalifikacija"`
+	Osumnjiceni         Osumnjiceni         `bson:"osumnjiceni,omitempty" json:"osumnjiceni"`
+	Svedoci             []Svedok            `bson:"svedoci,omitempty" json:"svedoci"`
+}
+
+// FilterKrivicnihPrijava odgovara parametrima pretrage krivicnih prijava u granicnoj policiji
+type FilterKrivicnihPrijava struct {
+	Status           StatusPrijave
+	Od               string
+	Do               string
+	JMBG             string
+	GranicniPrelazId string
+	Stranica         int64
+	Velicina         int64
+}
+
+type StranicaKrivicnihPrijava struct {
+	Prijave    []KrivicnaPrijava `json:"prijave"`
+	Ukupno     int64             `json:"ukupno"`
+	Stranica   int64             `json:"stranica"`
+	Velicina   int64             `json:"velicina"`
+	BrojStrana int64             `json:"brojStrana"`
+}
+
+type PravnaKvalifikacija struct {
+	Zakon string `bson:"zakon,omitempty" json:"zakon"`
+	Clan  string `bson:"clan,omitempty" json:"clan"`
+	Stav  string `bson:"stav,omitempty" json:"stav,omitempty"`
+	Naziv string `bson:"naziv,omitempty" json:"naziv"`
+}
+
+type Osumnjiceni struct {
+	Ime           string `bson:"ime,omitempty" json:"ime"`
+	Prezime       string `bson:"prezime,omitempty" json:"prezime"`
+	JMBG          string `bson:"jmbg,omitempty" json:"jmbg,omitempty"`
+	BrojPasosa    string `bson:"brojPasosa,omitempty" json:"brojPasosa,omitempty"`
+	Drzavljanstvo string `bson:"drzavljanstvo,omitempty" json:"drzavljanstvo"`
+	Adresa        string `bson:"adresa,omitempty" json:"adresa,omitempty"`
+}
+
+type Svedok struct {
+	Ime     string `bson:"ime,omitempty" json:"ime"`
+	Prezime string `bson:"prezime,omitempty" json:"prezime"`
+	JMBG    string `bson:"jmbg,omitempty" json:"jmbg,omitempty"`
+	Kontakt string `bson:"kontakt,omitempty" json:"kontakt,omitempty"`
+	Izjava  string `bson:"izjava,omitempty" json:"izjava,omitempty"`
+}
+
+type PromenaStatusa struct {
+	Status   StatusPrijave `json:"status"`
+	Napomena string        `json:"napomena,omitempty"`
+}
+
+// NeposlataPromenaStatusa je promena statusa krivicne prijave koju servis u kome je prijava podneta jos nije
+// prihvatio. Upisuje se pre slanja i brise tek posle uspeha, pa se slanje nastavlja i posle ponovnog pokretanja.
+type NeposlataPromenaStatusa struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	IdPrijave      primitive.ObjectID `bson:"idPrijave" json:"idPrijave"`
+	Izvor          IzvorPrijave       `bson:"izvor" json:"izvor"`
+	Status         StatusPrijave      `bson:"status" json:"status"`
+	Napomena       string             `bson:"napomena,omitempty" json:"napomena,omitempty"`
+	PokusajiSlanja int                `bson:"pokusajiSlanja" json:"pokusajiSlanja"`
+	SledeciPokusaj primitive.DateTime `bson:"sledeciPokusaj" json:"sledeciPokusaj"`
+	GreskaSlanja   string             `bson:"greskaSlanja,omitempty" json:"greskaSlanja,omitempty"`
+	Kreirano       primitive.DateTime `bson:"kreirano" json:"kreirano"`
 }
 
 type ZahtevZaSudskiPostupak struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Opis            string             `bson:"opis,omitempty" json:"opis"`
-	Datum           primitive.DateTime `bson:"datum,omitempty" json:"datum"`
-	IdTuzioca       primitive.ObjectID `bson:"idTuzioca,omitempty" json:"idTuzioca"`
-	KrivicnaPrijava KrivicnaPrijava    `bson:"krivicnaPrijava,omitempty" json:"krivicnaPrijava"`
+	ID              primitive.ObjectID            `bson:"_id,omitempty" json:"id"`
+	Opis            string                        `bson:"opis,omitempty" json:"opis"`
+	Datum           primitive.DateTime            `bson:"datum,omitempty" json:"datum"`
+	IdTuzioca       primitive.ObjectID            `bson:"idTuzioca,omitempty" json:"idTuzioca"`
+	KrivicnaPrijava KrivicnaPrijava               `bson:"krivicnaPrijava,omitempty" json:"krivicnaPrijava"`
+	PredmetId       primitive.ObjectID            `bson:"predmetId,omitempty" json:"predmetId,omitempty"`
+	Status          StatusZahtevaZaSudskiPostupak `bson:"status,omitempty" json:"status,omitempty"`
+	Prijem          *PrijemZahteva                `bson:"prijem,omitempty" json:"prijem,omitempty"`
 }
 
+// Zahtev bez statusa je aktivan, sud ga preuzima dok ne potvrdi prijem
+type StatusZahtevaZaSudskiPostupak string
+
+const (
+	ZAHTEV_AKTIVAN  = "AKTIVAN"
+	ZAHTEV_PRIMLJEN = "PRIMLJEN"
+)
+
+// PrijemZahteva je potvrda suda da je po zahtevu za sudski postupak otvoren predmet
+type PrijemZahteva struct {
+	IdPredmetaSuda primitive.ObjectID `bson:"idPredmetaSuda" json:"idPredmetaSuda"`
+	IdSudije       primitive.ObjectID `bson:"idSudije,omitempty" json:"idSudije,omitempty"`
+	Datum          primitive.DateTime `bson:"datum" json:"datum"`
+}
+
+type StatusZahtevaZaSporazum string
+
+const (
+	ZAHTEV_NA_CEKANJU = "NA_CEKANJU"
+	ZAHTEV_PRIHVACEN  = "PRIHVACEN"
+	ZAHTEV_ODBIJEN    = "ODBIJEN"
+	ZAHTEV_ISTEKAO    = "ISTEKAO"
+)
+
+type StranaUPregovorima string
+
+const (
+	STRANA_TUZILAC   = "TUZILAC"
+	STRANA_GRADJANIN = "GRADJANIN"
+)
+
+// ZahtevZaSklapanjeSporazuma je tok pregovora, Uslovi, Kazna i IstekPonude odgovaraju poslednjoj verziji ponude
 type ZahtevZaSklapanjeSporazuma struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Opis            string             `bson:"opis,omitempty" json:"opis"`
-	Uslovi          string             `bson:"uslovi,omitempty" json:"uslovi"`
-	Kazna           string             `bson:"kazna,omitempty" json:"kazna"`
-	Datum           primitive.DateTime `bson:"datum,omitempty" json:"datum"`
-	IdTuzioca       primitive.ObjectID `bson:"idTuzioca,omitempty" json:"idTuzioca"`
-	KrivicnaPrijava KrivicnaPrijava    `bson:"krivicnaPrijava,omitempty" json:"krivicnaPrijava"`
-	Prihvacen       bool               `bson:"prihvacen,omitempty" json:"prihvacen"`
+	ID              primitive.ObjectID      `bson:"_id,omitempty" json:"id"`
+	Opis            string                  `bson:"opis,omitempty" json:"opis"`
+	Uslovi          string                  `bson:"uslovi,omitempty" json:"uslovi"`
+	Kazna           string                  `bson:"kazna,omitempty" json:"kazna"`
+	Datum           primitive.DateTime      `bson:"datum,omitempty" json:"datum"`
+	IdTuzioca       primitive.ObjectID      `bson:"idTuzioca,omitempty" json:"idTuzioca"`
+	KrivicnaPrijava KrivicnaPrijava         `bson:"krivicnaPrijava,omitempty" json:"krivicnaPrijava"`
+	Prihvacen       bool                    `bson:"prihvacen,omitempty" json:"prihvacen"`
+	PredmetId       primitive.ObjectID      `bson:"predmetId,omitempty" json:"predmetId,omitempty"`
+	Status          StatusZahtevaZaSporazum `bson:"status,omitempty" json:"status"`
+	IstekPonude     primitive.DateTime      `bson:"istekPonude,omitempty" json:"istekPonude,omitempty"`
+	TrenutnaVerzija int                     `bson:"trenutnaVerzija,omitempty" json:"trenutnaVerzija"`
+	Verzije         []VerzijaPonude         `bson:"verzije,omitempty" json:"verzije"`
+	DatumOdluke     primitive.DateTime      `bson:"datumOdluke,omitempty" json:"datumOdluke,omitempty"`
+	RazlogOdbijanja string                  `bson:"razlogOdbijanja,omitempty" json:"razlogOdbijanja,omitempty"`
+}
+
+type VerzijaPonude struct {
+	Broj         int                `bson:"broj" json:"broj"`
+	Uslovi       string             `bson:"uslovi,omitempty" json:"uslovi"`
+	Kazna        string             `bson:"kazna,omitempty" json:"kazna"`
+	Predlagac    StranaUPregovorima `bson:"predlagac,omitempty" json:"predlagac"`
+	IdPredlagaca primitive.ObjectID `bson:"idPredlagaca,omitempty" json:"idPredlagaca,omitempty"`
+	Obrazlozenje string             `bson:"obrazlozenje,omitempty" json:"obrazlozenje,omitempty"`
+	Datum        primitive.DateTime `bson:"datum,omitempty" json:"datum"`
+	IstekPonude  primitive.DateTime `bson:"istekPonude,omitempty" json:"istekPonude,omitempty"`
+}
+
+// KontraPonuda je predlog nove verzije uslova, Verzija je broj verzije na koju se odgovara
+type KontraPonuda struct {
+	Verzija        int    `json:"verzija"`
+	Uslovi         string `json:"uslovi"`
+	Kazna          string `json:"kazna"`
+	Obrazlozenje   string `json:"obrazlozenje"`
+	RokVazenjaDana int    `json:"rokVazenjaDana"`
+}
+
+type OdgovorNaPonudu struct {
+	Verzija int    `json:"verzija"`
+	Razlog  string `json:"razlog"`
 }
 
+type StatusPotvrdeSporazuma string
+
+const (
+	SPORAZUM_CEKA_SLANJE = "CEKA_SLANJE"
+	// Sporazum koji jedna instanca servisa upravo salje sudu, druge instance ga preskacu do SledeciPokusaj
+	SPORAZUM_SALJE_SE  = "SALJE_SE"
+	SPORAZUM_KOD_SUDA  = "KOD_SUDA"
+	SPORAZUM_POTVRDJEN = "POTVRDJEN"
+	SPORAZUM_ODBIJEN   = "ODBIJEN"
+)
+
+// Sporazum postaje pravosnazan tek kada ga sud potvrdi, polja o slanju prate pokusaje dostavljanja sudu
 type Sporazum struct {
-	ID     primitive.ObjectID         `bson:"_id,omitempty" json:"id"`
-	Zahtev ZahtevZaSklapanjeSporazuma `bson:"zahtev,omitempty" json:"zahtev"`
-	Datum  primitive.DateTime         `bson:"datum,omitempty" json:"datum"`
+	ID               primitive.ObjectID         `bson:"_id,omitempty" json:"id"`
+	Zahtev           ZahtevZaSklapanjeSporazuma `bson:"zahtev,omitempty" json:"zahtev"`
+	Datum            primitive.DateTime         `bson:"datum,omitempty" json:"datum"`
+	PrihvacenaPonuda VerzijaPonude              `bson:"prihvacenaPonuda,omitempty" json:"prihvacenaPonuda"`
+	StatusPotvrde    StatusPotvrdeSporazuma     `bson:"statusPotvrde,omitempty" json:"statusPotvrde"`
+	IdPredmetaSuda   primitive.ObjectID         `bson:"idPredmetaSuda,omitempty" json:"idPredmetaSuda,omitempty"`
+	PokusajiSlanja   int                        `bson:"pokusajiSlanja,omitempty" json:"pokusajiSlanja"`
+	SledeciPokusaj   primitive.DateTime         `bson:"sledeciPokusaj,omitempty" json:"sledeciPokusaj,omitempty"`
+	GreskaSlanja     string                     `bson:"greskaSlanja,omitempty" json:"greskaSlanja,omitempty"`
+	OdlukaSuda       *OdlukaSuda                `bson:"odlukaSuda,omitempty" json:"odlukaSuda,omitempty"`
+}
+
+// SporazumZaPotvrdu je sporazum u obliku u kome se dostavlja sudu
+type SporazumZaPotvrdu struct {
+	ID              primitive.ObjectID `json:"id"`
+	IdZahteva       primitive.ObjectID `json:"idZahteva"`
+	Uslovi          string             `json:"uslovi"`
+	Kazna           string             `json:"kazna"`
+	Datum           primitive.DateTime `json:"datum"`
+	IdTuzioca       primitive.ObjectID `json:"idTuzioca"`
+	KrivicnaPrijava KrivicnaPrijava    `json:"krivicnaPrijava"`
+}
+
+type OdlukaSuda struct {
+	Potvrdjen    bool               `bson:"potvrdjen" json:"potvrdjen"`
+	Obrazlozenje string             `bson:"obrazlozenje,omitempty" json:"obrazlozenje,omitempty"`
+	IdSudije     primitive.ObjectID `bson:"idSudije,omitempty" json:"idSudije"`
+	IdPredmeta   primitive.ObjectID `bson:"idPredmeta,omitempty" json:"idPredmeta"`
+	IdPresude    primitive.ObjectID `bson:"idPresude,omitempty" json:"idPresude,omitempty"`
+	Datum        primitive.DateTime `bson:"datum,omitempty" json:"datum"`
+}
+
+// PredmetSuda sadrzi deo sudskog predmeta potreban za pracenje potvrde sporazuma, Odluka je nil dok sud ne odluci
+type PredmetSuda struct {
+	ID       primitive.ObjectID `json:"id"`
+	IdSudije primitive.ObjectID `json:"idSudije"`
+	Odluka   *OdlukaSuda        `json:"odluka"`
 }
 
+// Poruka cuva rolu posiljaoca u Posiljalac, a autora u IdAutora i ImeAutora
 type Poruka struct {
 	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	KanalId    primitive.ObjectID `bson:"kanalId" json:"kanalId"`
 	Posiljalac string             `bson:"posiljalac" json:"posiljalac"`
+	IdAutora   primitive.ObjectID `bson:"idAutora,omitempty" json:"idAutora,omitempty"`
+	ImeAutora  string             `bson:"imeAutora,omitempty" json:"imeAutora,omitempty"`
 	Sadrzaj    string             `bson:"sadrzaj" json:"sadrzaj"`
 	Datum      time.Time          `bson:"datum" json:"datum"`
+	Prilozi    []Prilog           `bson:"prilozi,omitempty" json:"prilozi,omitempty"`
+	Izmenjena  *time.Time         `bson:"izmenjena,omitempty" json:"izmenjena,omitempty"`
+	Izmene     []IzmenaPoruke     `bson:"izmene,omitempty" json:"izmene,omitempty"`
+	Obrisana   bool               `bson:"obrisana,omitempty" json:"obrisana"`
+	Brisanje   *BrisanjePoruke    `bson:"brisanje,omitempty" json:"brisanje,omitempty"`
+}
+
+// Prilog je fajl sacuvan u GridFS-u, Sha256 je hes sadrzaja izracunat pri otpremanju
+type Prilog struct {
+	ID          primitive.ObjectID `bson:"id" json:"id"`
+	Naziv       string             `bson:"naziv" json:"naziv"`
+	TipSadrzaja string             `bson:"tipSadrzaja,omitempty" json:"tipSadrzaja"`
+	Velicina    int64              `bson:"velicina" json:"velicina"`
+	Sha256      string             `bson:"sha256" json:"sha256"`
+}
+
+// IzmenaPoruke cuva sadrzaj poruke pre izmene
+type IzmenaPoruke struct {
+	Sadrzaj string    `bson:"sadrzaj" json:"sadrzaj"`
+	Datum   time.Time `bson:"datum" json:"datum"`
+}
+
+// BrisanjePoruke belezi ko je, kada i zasto obrisao poruku, sadrzaj obrisane poruke ostaje sacuvan
+type BrisanjePoruke struct {
+	IdKorisnika primitive.ObjectID `bson:"idKorisnika" json:"idKorisnika"`
+	Ime         string             `bson:"ime,omitempty" json:"ime"`
+	Datum       time.Time          `bson:"datum" json:"datum"`
+	Razlog      string             `bson:"razlog,omitempty" json:"razlog,omitempty"`
 }
 
+type IzmenaSadrzaja struct {
+	Sadrzaj string `json:"sadrzaj"`
+}
+
+type RazlogBrisanja struct {
+	Razlog string `json:"razlog"`
+}
+
+// RezultatPretrage je poruka pronadjena pretragom sa ocenom poklapanja sa upitom
+type RezultatPretrage struct {
+	Poruka `bson:",inline"`
+	Ocena  float64 `bson:"ocena" json:"ocena"`
+}
+
+type UlogaUKanalu string
+
+const (
+	VLASNIK_KANALA   = "VLASNIK"
+	CLAN_KANALA      = "CLAN"
+	POSMATRAC_KANALA = "POSMATRAC"
+)
+
+// ClanKanala je korisnik koji vidi kanal, posmatrac moze samo da cita poruke
+type ClanKanala struct {
+	IdKorisnika primitive.ObjectID `bson:"idKorisnika" json:"idKorisnika"`
+	Ime         string             `bson:"ime,omitempty" json:"ime"`
+	Rola        string             `bson:"rola,omitempty" json:"rola"`
+	Uloga       UlogaUKanalu       `bson:"uloga" json:"uloga"`
+	Dodat       time.Time          `bson:"dodat" json:"dodat"`
+	IdDodao     primitive.ObjectID `bson:"idDodao,omitempty" json:"idDodao,omitempty"`
+}
+
+// Kanal je dostupan samo svojim clanovima. Otvoren je kanal kreiran pre uvodjenja clanstva, njemu kao i ranije
+// pristupaju svi korisnici sa rolom clanova kanala dok ga vlasnik ne zatvori.
 type Kanal struct {
-	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Ime     string             `bson:"ime" json:"ime"`
-	Opis    string             `bson:"opis" json:"opis"`
-	Kreiran time.Time          `bson:"kreiran" json:"kreiran"`
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Ime        string             `bson:"ime" json:"ime"`
+	Opis       string             `bson:"opis" json:"opis"`
+	Kreiran    time.Time          `bson:"kreiran" json:"kreiran"`
+	PredmetId  primitive.ObjectID `bson:"predmetId,omitempty" json:"predmetId,omitempty"`
+	IdVlasnika primitive.ObjectID `bson:"idVlasnika,omitempty" json:"idVlasnika,omitempty"`
+	Clanovi    []ClanKanala       `bson:"clanovi,omitempty" json:"clanovi"`
+	Otvoren    bool               `bson:"otvoren,omitempty" json:"otvoren"`
+}
+
+// PozivUKanal dodaje korisnika u kanal ili mu menja ulogu
+type PozivUKanal struct {
+	IdKorisnika primitive.ObjectID `json:"idKorisnika"`
+	Uloga       UlogaUKanalu       `json:"uloga"`
+}
+
+const (
+	// RolaGlavniTuzilac je rola nadredjenog tuzioca kome se eskaliraju hitni i istekli rokovi
+	RolaGlavniTuzilac = "GlavniTuzilac"
+	RolaIstrazitelj   = "Istrazitelj"
+)
+
+// Korisnik sadrzi podatke o korisniku iz auth servisa koji su potrebni tuzilastvu
+type Korisnik struct {
+	ID            primitive.ObjectID `json:"id"`
+	Ime           string             `json:"ime"`
+	Prezime       string             `json:"prezime"`
+	KorisnickoIme string             `json:"korisnickoIme"`
+	Rola          string             `json:"rola"`
+}
+
+type StatusPredmeta string
+
+const (
+	PREDMET_OTVOREN  = "OTVOREN"
+	PREDMET_ISTRAGA  = "ISTRAGA"
+	PREDMET_KOD_SUDA = "KOD_SUDA"
+	PREDMET_ZATVOREN = "ZATVOREN"
+)
+
+// Predmet objedinjuje krivicnu prijavu, beleske istrage, zahteve, kanale za poruke i konacnu odluku tuzilastva
+type Predmet struct {
+	ID                          primitive.ObjectID          `bson:"_id,omitempty" json:"id"`
+	Broj                        string                      `bson:"broj,omitempty" json:"broj"`
+	Naziv                       string                      `bson:"naziv,omitempty" json:"naziv"`
+	Opis                        string                      `bson:"opis,omitempty" json:"opis"`
+	Datum                       primitive.DateTime          `bson:"datum,omitempty" json:"datum"`
+	Status                      StatusPredmeta              `bson:"status,omitempty" json:"status"`
+	IdTuzioca                   primitive.ObjectID          `bson:"idTuzioca,omitempty" json:"idTuzioca"`
+	IdIstrazitelja              []primitive.ObjectID        `bson:"idIstrazitelja,omitempty" json:"idIstrazitelja"`
+	KrivicnaPrijava             KrivicnaPrijava             `bson:"krivicnaPrijava,omitempty" json:"krivicnaPrijava"`
+	Beleske                     []Beleska                   `bson:"beleske,omitempty" json:"beleske"`
+	Rokovi                      []Rok                       `bson:"rokovi,omitempty" json:"rokovi"`
+	Odluka                      string                      `bson:"odluka,omitempty" json:"odluka,omitempty"`
+	IstorijaStatusa             []PromenaStatusaPredmeta    `bson:"istorijaStatusa,omitempty" json:"istorijaStatusa"`
+	ZahteviZaSudskiPostupak     ZahteviZaSudskiPostupak     `bson:"-" json:"zahteviZaSudskiPostupak,omitempty"`
+	ZahteviZaSklapanjeSporazuma ZahteviZaSklapanjeSporazuma `bson:"-" json:"zahteviZaSklapanjeSporazuma,omitempty"`
+	Kanali                      Kanali                      `bson:"-" json:"kanali,omitempty"`
+}
+
+type Beleska struct {
+	IdAutora primitive.ObjectID `bson:"idAutora,omitempty" json:"idAutora"`
+	Rola     string             `bson:"rola,omitempty" json:"rola"`
+	Sadrzaj  string             `bson:"sadrzaj,omitempty" json:"sadrzaj"`
+	Datum    primitive.DateTime `bson:"datum,omitempty" json:"datum"`
+}
+
+type Rok struct {
+	Naziv    string             `bson:"naziv,omitempty" json:"naziv"`
+	Datum    primitive.DateTime `bson:"datum,omitempty" json:"datum"`
+	Ispunjen bool               `bson:"ispunjen" json:"ispunjen"`
+}
+
+type PromenaStatusaPredmeta struct {
+	Status   StatusPredmeta     `bson:"status,omitempty" json:"status"`
+	Datum    primitive.DateTime `bson:"datum,omitempty" json:"datum"`
+	Napomena string             `bson:"napomena,omitempty" json:"napomena,omitempty"`
+	Odluka   string             `bson:"odluka,omitempty" json:"odluka,omitempty"`
+}
+
+// IzmenaPredmeta sadrzi polja koja tuzilac moze da menja nakon otvaranja predmeta
+type IzmenaPredmeta struct {
+	Naziv          string               `json:"naziv"`
+	Opis           string               `json:"opis"`
+	IdIstrazitelja []primitive.ObjectID `json:"idIstrazitelja"`
+	Rokovi         []Rok                `json:"rokovi"`
+}
+
+type RokPredmeta struct {
+	PredmetId    primitive.ObjectID `json:"predmetId"`
+	BrojPredmeta string             `json:"brojPredmeta"`
+	Rok          Rok                `json:"rok"`
+}
+
+// PregledPredmeta je kontrolna tabla predmeta jednog tuzioca ili istrazitelja
+type PregledPredmeta struct {
+	PoStatusu         map[StatusPredmeta]int `json:"poStatusu"`
+	Aktivni           Predmeti               `json:"aktivni"`
+	PredstojeciRokovi []RokPredmeta          `json:"predstojeciRokovi"`
+	IstekliRokovi     []RokPredmeta          `json:"istekliRokovi"`
+}
+
+// Dokaz je predmet lanca cuvanja vezan za krivicnu prijavu ili predmet. Cuvar, lokacija, broj unosa i hes
+// poslednjeg unosa prate stanje lanca radi brzog citanja, a izvor istine je sam lanac.
+type Dokaz struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Naziv        string             `bson:"naziv" json:"naziv"`
+	Opis         string             `bson:"opis,omitempty" json:"opis"`
+	IdPrijave    primitive.ObjectID `bson:"idPrijave,omitempty" json:"idPrijave,omitempty"`
+	IdPredmeta   primitive.ObjectID `bson:"idPredmeta,omitempty" json:"idPredmeta,omitempty"`
+	Evidentiran  time.Time          `bson:"evidentiran" json:"evidentiran"`
+	Cuvar        CuvarDokaza        `bson:"cuvar" json:"cuvar"`
+	Lokacija     string             `bson:"lokacija" json:"lokacija"`
+	BrojUnosa    int                `bson:"brojUnosa" json:"brojUnosa"`
+	PoslednjiHes string             `bson:"poslednjiHes" json:"poslednjiHes"`
+	Lanac        []*UnosLancaDokaza `bson:"-" json:"lanac,omitempty"`
+}
+
+type CuvarDokaza struct {
+	IdKorisnika primitive.ObjectID `bson:"idKorisnika" json:"idKorisnika"`
+	Ime         string             `bson:"ime,omitempty" json:"ime"`
+}
+
+type TipUnosaDokaza string
+
+const (
+	DOKAZ_EVIDENTIRAN = "EVIDENTIRAN"
+	DOKAZ_PREDAT      = "PREDAT"
+)
+
+// UnosLancaDokaza je jedna karika lanca cuvanja. Hes pokriva sva polja unosa i hes prethodnog unosa, pa izmena,
+// brisanje ili umetanje bilo kog unosa prekida lanac. Hes je potpisan kljucem koji se ne cuva u bazi.
+type UnosLancaDokaza struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	IdDokaza       primitive.ObjectID `bson:"idDokaza" json:"idDokaza"`
+	Redni          int                `bson:"redni" json:"redni"`
+	Tip            TipUnosaDokaza     `bson:"tip" json:"tip"`
+	Datum          time.Time          `bson:"datum" json:"datum"`
+	IdZapisnicara  primitive.ObjectID `bson:"idZapisnicara" json:"idZapisnicara"`
+	ImeZapisnicara string             `bson:"imeZapisnicara,omitempty" json:"imeZapisnicara"`
+	Predao         *CuvarDokaza       `bson:"predao,omitempty" json:"predao,omitempty"`
+	Primio         CuvarDokaza        `bson:"primio" json:"primio"`
+	Lokacija       string             `bson:"lokacija" json:"lokacija"`
+	Napomena       string             `bson:"napomena,omitempty" json:"napomena,omitempty"`
+	Dokaz          *OpisDokaza        `bson:"dokaz,omitempty" json:"dokaz,omitempty"`
+	PrethodniHes   string             `bson:"prethodniHes" json:"prethodniHes"`
+	Hes            string             `bson:"hes" json:"hes"`
+	Algoritam      string             `bson:"algoritam,omitempty" json:"algoritam,omitempty"`
+}
+
+// OpisDokaza cuva podatke dokaza u prvom unosu lanca, kako bi se otkrila njihova naknadna izmena
+type OpisDokaza struct {
+	Naziv      string             `bson:"naziv" json:"naziv"`
+	Opis       string             `bson:"opis,omitempty" json:"opis"`
+	IdPrijave  primitive.ObjectID `bson:"idPrijave,omitempty" json:"idPrijave,omitempty"`
+	IdPredmeta primitive.ObjectID `bson:"idPredmeta,omitempty" json:"idPredmeta,omitempty"`
+}
+
+type NoviDokaz struct {
+	Naziv      string             `json:"naziv"`
+	Opis       string             `json:"opis"`
+	IdPrijave  primitive.ObjectID `json:"idPrijave"`
+	IdPredmeta primitive.ObjectID `json:"idPredmeta"`
+	Lokacija   string             `json:"lokacija"`
+}
+
+type PredajaDokaza struct {
+	IdPrimaoca primitive.ObjectID `json:"idPrimaoca"`
+	Lokacija   string             `json:"lokacija"`
+	Napomena   string             `json:"napomena"`
+}
+
+// ProveraLanca je rezultat provere lanca cuvanja, Ispravan je true samo ako nije pronadjena nijedna nepravilnost
+type ProveraLanca struct {
+	IdDokaza      primitive.ObjectID  `json:"idDokaza"`
+	Ispravan      bool                `json:"ispravan"`
+	BrojUnosa     int                 `json:"brojUnosa"`
+	Nepravilnosti []NepravilnostLanca `json:"nepravilnosti"`
+	Provereno     time.Time           `json:"provereno"`
+}
+
+type NepravilnostLanca struct {
+	Redni int    `json:"redni"`
+	Opis  string `json:"opis"`
+}
+
+type Dokazi []*Dokaz
+
+const (
+	KORAK_ZAVRSETAK_ISTRAGE    = "ZAVRSETAK_ISTRAGE"
+	KORAK_PODIZANJE_OPTUZNICE  = "PODIZANJE_OPTUZNICE"
+	KORAK_ZASTARELOST_GONJENJA = "ZASTARELOST_GONJENJA"
+)
+
+// RokPostupka je rok predmeta izracunat iz pravila ili unet rucno. Kljuc je isti pri svakom racunanju,
+// pa se po njemu prepoznaje vec poslato obavestenje.
+type RokPostupka struct {
+	Kljuc         string             `json:"kljuc"`
+	PredmetId     primitive.ObjectID `json:"predmetId"`
+	BrojPredmeta  string             `json:"brojPredmeta"`
+	Korak         KorakPostupka      `json:"korak,omitempty"`
+	Naziv         string             `json:"naziv"`
+	Pocetak       time.Time          `json:"pocetak,omitempty"`
+	Istice        time.Time          `json:"istice"`
+	PreostaloDana int                `json:"preostaloDana"`
+	Nivo          NivoUpozorenja     `json:"nivo,omitempty"`
+	IdPravila     primitive.ObjectID `json:"idPravila,omitempty"`
+}
+
+// ObavestenjeSuda je obavestenje koje sud salje tuziocu, npr. o odlaganju ili otkazivanju sudjenja.
+// Sud ponavlja slanje sa istim kljucem, a tuzilac obavestenje dobija samo jednom.
+type ObavestenjeSuda struct {
+	Kljuc     string             `json:"kljuc"`
+	IdTuzioca primitive.ObjectID `json:"idTuzioca"`
+	PredmetId primitive.ObjectID `json:"predmetId,omitempty"`
+	Tekst     string             `json:"tekst"`
+	Istice    time.Time          `json:"istice"`
 }
 
 type ZahteviZaSudskiPostupak []*ZahtevZaSudskiPostupak
@@ -77,6 +558,8 @@ type Sporazumi []*Sporazum
 
 type Kanali []*Kanal
 
+type Predmeti []*Predmet
+
 type Poruke []*Poruka
 
 func (o *ZahtevZaSudskiPostupak) ToJSON(w io.Writer) error {
@@ -148,3 +631,33 @@ func (o *Poruke) FromJSON(r io.Reader) error {
 	d := json.NewDecoder(r)
 	return d.Decode(o)
 }
+
+func (o *Predmet) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
+
+func (o *Predmet) FromJSON(r io.Reader) error {
+	d := json.NewDecoder(r)
+	return d.Decode(o)
+}
+
+func (o *Predmeti) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
+
+func (o *Predmeti) FromJSON(r io.Reader) error {
+	d := json.NewDecoder(r)
+	return d.Decode(o)
+}
+
+func (o *Dokaz) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
+
+func (o *Dokazi) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(o)
+}
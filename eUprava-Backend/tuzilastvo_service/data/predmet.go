@@ -0,0 +1,81 @@
+package data
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"sort"
+	"time"
+)
+
+// Rokovi koji isticu u narednih PeriodPredstojecihRokovaDana dana prikazuju se na kontrolnoj tabli
+const PeriodPredstojecihRokovaDana = 7
+
+// Dozvoljeni prelazi izmedju statusa predmeta, zatvoren predmet se ne moze ponovo otvoriti
+var sledeciStatusiPredmeta = map[StatusPredmeta][]StatusPredmeta{
+	PREDMET_OTVOREN:  {PREDMET_ISTRAGA, PREDMET_KOD_SUDA, PREDMET_ZATVOREN},
+	PREDMET_ISTRAGA:  {PREDMET_KOD_SUDA, PREDMET_ZATVOREN},
+	PREDMET_KOD_SUDA: {PREDMET_ZATVOREN},
+}
+
+func DozvoljenaPromenaStatusaPredmeta(iz StatusPredmeta, u StatusPredmeta) bool {
+	for _, status := range sledeciStatusiPredmeta[iz] {
+		if status == u {
+			return true
+		}
+	}
+	return false
+}
+
+// ImaPristup proverava da li je korisnik tuzilac predmeta ili istrazitelj koji na njemu radi
+func (p *Predmet) ImaPristup(idKorisnika primitive.ObjectID) bool {
+	if p.IdTuzioca == idKorisnika {
+		return true
+	}
+	for _, id := range p.IdIstrazitelja {
+		if id == idKorisnika {
+			return true
+		}
+	}
+	return false
+}
+
+// NapraviPregledPredmeta broji predmete po statusu i izdvaja aktivne predmete i njihove neispunjene rokove
+func NapraviPregledPredmeta(predmeti Predmeti, sada time.Time) PregledPredmeta {
+	pregled := PregledPredmeta{
+		PoStatusu:         make(map[StatusPredmeta]int),
+		Aktivni:           Predmeti{},
+		PredstojeciRokovi: []RokPredmeta{},
+		IstekliRokovi:     []RokPredmeta{},
+	}
+	granica := sada.AddDate(0, 0, PeriodPredstojecihRokovaDana)
+
+	for _, predmet := range predmeti {
+		pregled.PoStatusu[predmet.Status]++
+		if predmet.Status == PREDMET_ZATVOREN {
+			continue
+		}
+		pregled.Aktivni = append(pregled.Aktivni, predmet)
+
+		for _, rok := range predmet.Rokovi {
+			if rok.Ispunjen {
+				continue
+			}
+			stavka := RokPredmeta{PredmetId: predmet.ID, BrojPredmeta: predmet.Broj, Rok: rok}
+			datum := rok.Datum.Time()
+			if datum.Before(sada) {
+				pregled.IstekliRokovi = append(pregled.IstekliRokovi, stavka)
+			} else if datum.Before(granica) {
+				pregled.PredstojeciRokovi = append(pregled.PredstojeciRokovi, stavka)
+			}
+		}
+	}
+
+	poDatumu := func(rokovi []RokPredmeta) func(i, j int) bool {
+		return func(i, j int) bool {
+			return rokovi[i].Rok.Datum < rokovi[j].Rok.Datum
+		}
+	}
+	sort.Slice(pregled.PredstojeciRokovi, poDatumu(pregled.PredstojeciRokovi))
+	sort.Slice(pregled.IstekliRokovi, poDatumu(pregled.IstekliRokovi))
+
+	return pregled
+}
@@ -0,0 +1,201 @@
+package data
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Pretplatnik koji ne prati tok ostaje bez pretplate i nastavlja od poslednje primljene poruke posle ponovnog povezivanja
+	VelicinaBaferaPretplate = 64
+	IntervalCitanjaPoruka   = time.Second
+	// Poruke upisane sa drugih replika mogu imati datum nesto stariji od vec procitanih, pa se citanje preklapa za ovaj period
+	PreklapanjeCitanjaPoruka = 5 * time.Second
+	PauzaPosleGreskeToka     = 5 * time.Second
+)
+
+// PozicijaPoruke odredjuje redosled poruka u kanalu, poruke sa istim datumom se porede po id
+type PozicijaPoruke struct {
+	Datum time.Time
+	ID    primitive.ObjectID
+}
+
+func (p *Poruka) Pozicija() PozicijaPoruke {
+	return PozicijaPoruke{Datum: p.Datum, ID: p.ID}
+}
+
+// Posle proverava da li je poruka na poziciji p novija od poruke na poziciji druga
+func (p PozicijaPoruke) Posle(druga PozicijaPoruke) bool {
+	if !p.Datum.Equal(druga.Datum) {
+		return p.Datum.After(druga.Datum)
+	}
+	return bytes.Compare(p.ID[:], druga.ID[:]) > 0
+}
+
+// PoslednjaPromena vraca trenutak slanja, poslednje izmene ili brisanja poruke, sta je poslednje
+func (p *Poruka) PoslednjaPromena() time.Time {
+	promena := p.Datum
+	if p.Izmenjena != nil && p.Izmenjena.After(promena) {
+		promena = *p.Izmenjena
+	}
+	if p.Brisanje != nil && p.Brisanje.Datum.After(promena) {
+		promena = p.Brisanje.Datum
+	}
+	return promena
+}
+
+// RazglasPoruka prosledjuje nove poruke pretplatnicima kanala na ovoj instanci servisa
+type RazglasPoruka struct {
+	mu           sync.Mutex
+	pretplatnici map[primitive.ObjectID]map[chan *Poruka]struct{}
+}
+
+func NewRazglasPoruka() *RazglasPoruka {
+	return &RazglasPoruka{pretplatnici: make(map[primitive.ObjectID]map[chan *Poruka]struct{})}
+}
+
+// Pretplati vraca kanal sa novim i promenjenim porukama i funkciju za odjavu, kanal se zatvara ako pretplatnik zaostane
+func (r *RazglasPoruka) Pretplati(kanalId primitive.ObjectID) (<-chan *Poruka, func()) {
+	tok := make(chan *Poruka, VelicinaBaferaPretplate)
+
+	r.mu.Lock()
+	if r.pretplatnici[kanalId] == nil {
+		r.pretplatnici[kanalId] = make(map[chan *Poruka]struct{})
+	}
+	r.pretplatnici[kanalId][tok] = struct{}{}
+	r.mu.Unlock()
+
+	return tok, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.ukloni(kanalId, tok)
+	}
+}
+
+func (r *RazglasPoruka) Objavi(poruka *Poruka) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for tok := range r.pretplatnici[poruka.KanalId] {
+		select {
+		case tok <- poruka:
+		default:
+			r.ukloni(poruka.KanalId, tok)
+		}
+	}
+}
+
+func (r *RazglasPoruka) ukloni(kanalId primitive.ObjectID, tok chan *Poruka) {
+	if _, ok := r.pretplatnici[kanalId][tok]; !ok {
+		return
+	}
+	delete(r.pretplatnici[kanalId], tok)
+	if len(r.pretplatnici[kanalId]) == 0 {
+		delete(r.pretplatnici, kanalId)
+	}
+	close(tok)
+}
+
+// IzvorPoruka isporucuje sve nove poruke, bez obzira na to koja instanca servisa ih je upisala
+type IzvorPoruka interface {
+	Prati(ctx context.Context, objavi func(*Poruka)) error
+}
+
+// ChangeStreamIzvor prati upise poruka kroz Mongo change stream, sto zahteva replica set.
+// Posle prekida tok se nastavlja od poslednjeg primljenog dogadjaja.
+type ChangeStreamIzvor struct {
+	repo         *TuzilastvoRepo
+	nastaviPosle bson.Raw
+}
+
+func NewChangeStreamIzvor(repo *TuzilastvoRepo) *ChangeStreamIzvor {
+	return &ChangeStreamIzvor{repo: repo}
+}
+
+func (i *ChangeStreamIzvor) Prati(ctx context.Context, objavi func(*Poruka)) error {
+	var err error
+	i.nastaviPosle, err = i.repo.PratiUpisePoruka(ctx, i.nastaviPosle, objavi)
+	return err
+}
+
+// CitanjeIzvor periodicno cita nove, izmenjene i obrisane poruke i radi i sa samostalnom Mongo instancom.
+// Posle prekida citanje se nastavlja od poslednje procitane promene.
+type CitanjeIzvor struct {
+	repo      *TuzilastvoRepo
+	interval  time.Duration
+	od        time.Time
+	procitane map[primitive.ObjectID]time.Time
+}
+
+func NewCitanjeIzvor(repo *TuzilastvoRepo, interval time.Duration) *CitanjeIzvor {
+	return &CitanjeIzvor{
+		repo:      repo,
+		interval:  interval,
+		od:        time.Now(),
+		procitane: make(map[primitive.ObjectID]time.Time),
+	}
+}
+
+func (i *CitanjeIzvor) Prati(ctx context.Context, objavi func(*Poruka)) error {
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		poruke, err := i.repo.DobaviPorukeOdDatuma(ctx, i.od.Add(-PreklapanjeCitanjaPoruka))
+		if err != nil {
+			return err
+		}
+		for _, poruka := range poruke {
+			promena := poruka.PoslednjaPromena()
+			if procitana, ok := i.procitane[poruka.ID]; ok && !promena.After(procitana) {
+				continue
+			}
+			i.procitane[poruka.ID] = promena
+			if promena.After(i.od) {
+				i.od = promena
+			}
+			objavi(poruka)
+		}
+		for id, datum := range i.procitane {
+			if datum.Before(i.od.Add(-2 * PreklapanjeCitanjaPoruka)) {
+				delete(i.procitane, id)
+			}
+		}
+	}
+}
+
+// PokreniPrenosPoruka puni razglas iz change stream-a, a ako Mongo ne podrzava change stream prelazi na periodicno citanje.
+// Izvor se ponovo pokrece posle greske dok se ctx ne otkaze.
+func PokreniPrenosPoruka(ctx context.Context, repo *TuzilastvoRepo, razglas *RazglasPoruka, logger *log.Logger) {
+	var izvor IzvorPoruka = NewChangeStreamIzvor(repo)
+	if !repo.PodrzavaChangeStream(ctx) {
+		logger.Println("Change stream nije podrzan, nove poruke se citaju periodicno")
+		izvor = NewCitanjeIzvor(repo, IntervalCitanjaPoruka)
+	}
+
+	for {
+		err := izvor.Prati(ctx, razglas.Objavi)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Println("Greska prilikom pracenja novih poruka:", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(PauzaPosleGreskeToka):
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package data
+
+import (
+	"fmt"
+	"rokovi"
+	"sort"
+	"time"
+)
+
+// Pravila rokova, nivoi upozorenja i obavestenja su zajednicki sa sudom
+type (
+	KorakPostupka  = rokovi.KorakPostupka
+	PraviloRoka    = rokovi.PraviloRoka
+	PravilaRokova  = rokovi.PravilaRokova
+	NivoUpozorenja = rokovi.NivoUpozorenja
+	Obavestenje    = rokovi.Obavestenje
+	Obavestenja    = rokovi.Obavestenja
+)
+
+const (
+	ROK_UPOZORENJE          = rokovi.ROK_UPOZORENJE
+	ROK_HITNO               = rokovi.ROK_HITNO
+	ROK_ISTEKAO             = rokovi.ROK_ISTEKAO
+	OBAVESTENJE_INFORMACIJA = rokovi.OBAVESTENJE_INFORMACIJA
+)
+
+// PodrazumevanaPravilaRokova se upisuju pri prvom pokretanju, posle toga se rokovi podesavaju kroz pravila u bazi
+func PodrazumevanaPravilaRokova() PravilaRokova {
+	return PravilaRokova{
+		{Korak: KORAK_ZAVRSETAK_ISTRAGE, Naziv: "Zavrsetak istrage", BrojDana: 180, UpozorenjeDana: 14, Aktivno: true},
+		{Korak: KORAK_PODIZANJE_OPTUZNICE, Naziv: "Podizanje optuznice", BrojDana: 365, UpozorenjeDana: 30, Aktivno: true},
+		{Korak: KORAK_ZASTARELOST_GONJENJA, Naziv: "Zastarelost krivicnog gonjenja", BrojDana: 3 * 365, UpozorenjeDana: 60, Aktivno: true},
+	}
+}
+
+func PoznatKorakPostupka(korak KorakPostupka) bool {
+	switch korak {
+	case KORAK_ZAVRSETAK_ISTRAGE, KORAK_PODIZANJE_OPTUZNICE, KORAK_ZASTARELOST_GONJENJA:
+		return true
+	}
+	return false
+}
+
+// pocetakKoraka vraca od kada tece rok za korak postupka i da li je rok jos uvek aktuelan za predmet
+func (p *Predmet) pocetakKoraka(korak KorakPostupka) (time.Time, bool) {
+	prijavljeno := p.KrivicnaPrijava.Datum
+	if prijavljeno == 0 {
+		prijavljeno = p.Datum
+	}
+
+	switch korak {
+	case KORAK_ZAVRSETAK_ISTRAGE:
+		if p.Status != PREDMET_ISTRAGA {
+			return time.Time{}, false
+		}
+		pocetak := p.Datum
+		for _, promena := range p.IstorijaStatusa {
+			if promena.Status == PREDMET_ISTRAGA {
+				pocetak = promena.Datum
+			}
+		}
+		return pocetak.Time(), true
+	case KORAK_PODIZANJE_OPTUZNICE, KORAK_ZASTARELOST_GONJENJA:
+		// podnosenjem zahteva sudu optuznica je podignuta, a zastarelost gonjenja prekinuta
+		return prijavljeno.Time(), p.Status == PREDMET_OTVOREN || p.Status == PREDMET_ISTRAGA
+	}
+	return time.Time{}, false
+}
+
+// IzracunajRokovePredmeta racuna rokove predmeta iz pravila za korake postupka koji jos nisu zavrseni
+// i dodaje neispunjene rucno unete rokove. Zatvoren predmet nema rokova.
+func IzracunajRokovePredmeta(predmet *Predmet, pravila PravilaRokova, sada time.Time) []RokPostupka {
+	rezultat := []RokPostupka{}
+	if predmet.Status == PREDMET_ZATVOREN {
+		return rezultat
+	}
+
+	kvalifikacija := predmet.KrivicnaPrijava.PravnaKvalifikacija
+	for _, korak := range []KorakPostupka{KORAK_ZAVRSETAK_ISTRAGE, KORAK_PODIZANJE_OPTUZNICE, KORAK_ZASTARELOST_GONJENJA} {
+		pocetak, aktuelan := predmet.pocetakKoraka(korak)
+		if !aktuelan {
+			continue
+		}
+		pravilo := rokovi.IzaberiPraviloRoka(pravila, korak, kvalifikacija.Zakon, kvalifikacija.Clan)
+		if pravilo == nil {
+			continue
+		}
+		istice := pocetak.AddDate(0, 0, pravilo.BrojDana)
+		rezultat = append(rezultat, RokPostupka{
+			Kljuc:         fmt.Sprintf("%s:%s:%s", predmet.ID.Hex(), korak, istice.UTC().Format("2006-01-02")),
+			PredmetId:     predmet.ID,
+			BrojPredmeta:  predmet.Broj,
+			Korak:         korak,
+			Naziv:         pravilo.Naziv,
+			Pocetak:       pocetak,
+			Istice:        istice,
+			PreostaloDana: rokovi.PreostaloDana(istice, sada),
+			Nivo:          rokovi.NivoRoka(istice, pravilo.UpozorenjeDana, sada),
+			IdPravila:     pravilo.ID,
+		})
+	}
+
+	for _, rok := range predmet.Rokovi {
+		if rok.Ispunjen {
+			continue
+		}
+		istice := rok.Datum.Time()
+		rezultat = append(rezultat, RokPostupka{
+			Kljuc:         fmt.Sprintf("%s:rucni:%s:%d", predmet.ID.Hex(), rok.Naziv, rok.Datum),
+			PredmetId:     predmet.ID,
+			BrojPredmeta:  predmet.Broj,
+			Naziv:         rok.Naziv,
+			Istice:        istice,
+			PreostaloDana: rokovi.PreostaloDana(istice, sada),
+			Nivo:          rokovi.NivoRoka(istice, PeriodPredstojecihRokovaDana, sada),
+		})
+	}
+
+	return rezultat
+}
+
+// PredstojeciRokovi vraca istekle rokove i rokove koji isticu u narednih dana dana, od onog koji prvi istice
+func PredstojeciRokovi(predmeti Predmeti, pravila PravilaRokova, dana int, sada time.Time) []RokPostupka {
+	granica := sada.AddDate(0, 0, dana)
+	predstojeci := []RokPostupka{}
+	for _, predmet := range predmeti {
+		for _, rok := range IzracunajRokovePredmeta(predmet, pravila, sada) {
+			if rok.Istice.Before(granica) {
+				predstojeci = append(predstojeci, rok)
+			}
+		}
+	}
+	sort.Slice(predstojeci, func(i, j int) bool {
+		return predstojeci[i].Istice.Before(predstojeci[j].Istice)
+	})
+	return predstojeci
+}
+
+// TekstObavestenja opisuje rok za prikaz korisniku
+func (r *RokPostupka) TekstObavestenja() string {
+	datum := r.Istice.Format("02.01.2006.")
+	switch r.Nivo {
+	case ROK_ISTEKAO:
+		return fmt.Sprintf("Predmet %s: rok \"%s\" je istekao %s", r.BrojPredmeta, r.Naziv, datum)
+	case ROK_HITNO:
+		return fmt.Sprintf("Predmet %s: rok \"%s\" istice %s, preostalo dana: %d", r.BrojPredmeta, r.Naziv, datum, r.PreostaloDana)
+	}
+	return fmt.Sprintf("Predmet %s: rok \"%s\" istice %s", r.BrojPredmeta, r.Naziv, datum)
+}
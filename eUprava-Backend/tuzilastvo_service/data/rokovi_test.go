@@ -0,0 +1,139 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestIzracunajRokovePredmeta(t *testing.T) {
+	sada := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	prijavljeno := sada.AddDate(0, 0, -100)
+	istragaOd := sada.AddDate(0, 0, -170)
+
+	pravila := PravilaRokova{
+		{Korak: KORAK_ZAVRSETAK_ISTRAGE, Naziv: "Zavrsetak istrage", BrojDana: 180, UpozorenjeDana: 14, Aktivno: true},
+		{Korak: KORAK_PODIZANJE_OPTUZNICE, Naziv: "Podizanje optuznice", BrojDana: 365, UpozorenjeDana: 30, Aktivno: true},
+		{Korak: KORAK_PODIZANJE_OPTUZNICE, Naziv: "Optuznica za ubistvo", Zakon: "KZ", Clan: "113", BrojDana: 100, UpozorenjeDana: 30, Aktivno: true},
+	}
+
+	type ocekivaniRok struct {
+		naziv  string
+		istice time.Time
+		nivo   NivoUpozorenja
+	}
+
+	tests := []struct {
+		name    string
+		predmet Predmet
+		want    []ocekivaniRok
+	}{
+		{
+			name:    "zatvoren predmet nema rokova",
+			predmet: Predmet{Status: PREDMET_ZATVOREN, Datum: primitive.NewDateTimeFromTime(prijavljeno)},
+			want:    nil,
+		},
+		{
+			name: "otvoren predmet bez prijave racuna od datuma predmeta",
+			predmet: Predmet{
+				Status: PREDMET_OTVOREN,
+				Datum:  primitive.NewDateTimeFromTime(prijavljeno),
+			},
+			want: []ocekivaniRok{
+				{"Podizanje optuznice", prijavljeno.AddDate(0, 0, 365), ""},
+			},
+		},
+		{
+			name: "pravilo za krivicno delo ima prednost",
+			predmet: Predmet{
+				Status:          PREDMET_OTVOREN,
+				Datum:           primitive.NewDateTimeFromTime(sada),
+				KrivicnaPrijava: KrivicnaPrijava{Datum: primitive.NewDateTimeFromTime(prijavljeno), PravnaKvalifikacija: PravnaKvalifikacija{Zakon: "KZ", Clan: "113"}},
+			},
+			want: []ocekivaniRok{
+				{"Optuznica za ubistvo", prijavljeno.AddDate(0, 0, 100), ROK_ISTEKAO},
+			},
+		},
+		{
+			name: "istraga tece od poslednjeg ulaska u istragu",
+			predmet: Predmet{
+				Status:          PREDMET_ISTRAGA,
+				Datum:           primitive.NewDateTimeFromTime(prijavljeno),
+				KrivicnaPrijava: KrivicnaPrijava{Datum: primitive.NewDateTimeFromTime(prijavljeno)},
+				IstorijaStatusa: []PromenaStatusaPredmeta{
+					{Status: PREDMET_ISTRAGA, Datum: primitive.NewDateTimeFromTime(prijavljeno)},
+					{Status: PREDMET_OTVOREN, Datum: primitive.NewDateTimeFromTime(prijavljeno.AddDate(0, 0, 1))},
+					{Status: PREDMET_ISTRAGA, Datum: primitive.NewDateTimeFromTime(istragaOd)},
+				},
+			},
+			want: []ocekivaniRok{
+				{"Zavrsetak istrage", istragaOd.AddDate(0, 0, 180), ROK_UPOZORENJE},
+				{"Podizanje optuznice", prijavljeno.AddDate(0, 0, 365), ""},
+			},
+		},
+		{
+			name: "predmet kod suda ima samo rucne rokove",
+			predmet: Predmet{
+				Status:          PREDMET_KOD_SUDA,
+				KrivicnaPrijava: KrivicnaPrijava{Datum: primitive.NewDateTimeFromTime(prijavljeno)},
+				Rokovi: []Rok{
+					{Naziv: "Dostaviti spise", Datum: primitive.NewDateTimeFromTime(sada.AddDate(0, 0, 2))},
+					{Naziv: "Ispunjen rok", Datum: primitive.NewDateTimeFromTime(sada.AddDate(0, 0, 1)), Ispunjen: true},
+					{Naziv: "Saslusanje", Datum: primitive.NewDateTimeFromTime(sada.AddDate(0, 0, 5))},
+				},
+			},
+			want: []ocekivaniRok{
+				{"Dostaviti spise", sada.AddDate(0, 0, 2), ROK_HITNO},
+				{"Saslusanje", sada.AddDate(0, 0, 5), ROK_UPOZORENJE},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.predmet.ID = primitive.NewObjectID()
+			rokovi := IzracunajRokovePredmeta(&tt.predmet, pravila, sada)
+			if len(rokovi) != len(tt.want) {
+				t.Fatalf("IzracunajRokovePredmeta() = %d rokova %+v, ocekivano %d", len(rokovi), rokovi, len(tt.want))
+			}
+			for i, ocekivan := range tt.want {
+				rok := rokovi[i]
+				if rok.Naziv != ocekivan.naziv {
+					t.Errorf("rok %d: Naziv = %q, ocekivano %q", i, rok.Naziv, ocekivan.naziv)
+				}
+				if !rok.Istice.Equal(ocekivan.istice) {
+					t.Errorf("rok %d: Istice = %v, ocekivano %v", i, rok.Istice, ocekivan.istice)
+				}
+				if rok.Nivo != ocekivan.nivo {
+					t.Errorf("rok %d: Nivo = %q, ocekivano %q", i, rok.Nivo, ocekivan.nivo)
+				}
+				if rok.PredmetId != tt.predmet.ID {
+					t.Errorf("rok %d: PredmetId = %s, ocekivano %s", i, rok.PredmetId.Hex(), tt.predmet.ID.Hex())
+				}
+			}
+		})
+	}
+}
+
+func TestPredstojeciRokovi(t *testing.T) {
+	sada := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	rucni := func(dana int) Rok {
+		return Rok{Naziv: "Rok", Datum: primitive.NewDateTimeFromTime(sada.AddDate(0, 0, dana))}
+	}
+	predmeti := Predmeti{
+		{ID: primitive.NewObjectID(), Status: PREDMET_KOD_SUDA, Rokovi: []Rok{rucni(5), rucni(20)}},
+		{ID: primitive.NewObjectID(), Status: PREDMET_KOD_SUDA, Rokovi: []Rok{rucni(-3), rucni(1)}},
+	}
+
+	rokovi := PredstojeciRokovi(predmeti, nil, PeriodPredstojecihRokovaDana, sada)
+	want := []time.Time{sada.AddDate(0, 0, -3), sada.AddDate(0, 0, 1), sada.AddDate(0, 0, 5)}
+	if len(rokovi) != len(want) {
+		t.Fatalf("PredstojeciRokovi() = %d rokova, ocekivano %d", len(rokovi), len(want))
+	}
+	for i, istice := range want {
+		if !rokovi[i].Istice.Equal(istice) {
+			t.Errorf("rok %d: Istice = %v, ocekivano %v", i, rokovi[i].Istice, istice)
+		}
+	}
+}
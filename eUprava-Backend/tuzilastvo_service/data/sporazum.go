@@ -0,0 +1,149 @@
+package data
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// Ponuda vazi PodrazumevaniRokPonudeDana dana ako predlagac ne navede drugaciji rok
+const (
+	PodrazumevaniRokPonudeDana = 15
+	MaxRokPonudeDana           = 60
+)
+
+// TrenutniStatus vraca status zahteva, ponuda na cekanju kojoj je prosao rok smatra se isteklom.
+// Zahtevi kreirani pre uvodjenja statusa nemaju status pa se on izvodi iz polja Prihvacen.
+func (z *ZahtevZaSklapanjeSporazuma) TrenutniStatus(sada time.Time) StatusZahtevaZaSporazum {
+	status := z.Status
+	if status == "" {
+		status = ZAHTEV_NA_CEKANJU
+		if z.Prihvacen {
+			status = ZAHTEV_PRIHVACEN
+		}
+	}
+	if status == ZAHTEV_NA_CEKANJU && z.IstekPonude != 0 && z.IstekPonude.Time().Before(sada) {
+		return ZAHTEV_ISTEKAO
+	}
+	return status
+}
+
+// SveVerzije vraca istoriju ponuda, za zahteve bez istorije pocetni uslovi se prikazuju kao prva verzija tuzioca
+func (z *ZahtevZaSklapanjeSporazuma) SveVerzije() []VerzijaPonude {
+	if len(z.Verzije) > 0 {
+		return z.Verzije
+	}
+	return []VerzijaPonude{{
+		Broj:         1,
+		Uslovi:       z.Uslovi,
+		Kazna:        z.Kazna,
+		Predlagac:    STRANA_TUZILAC,
+		IdPredlagaca: z.IdTuzioca,
+		Datum:        z.Datum,
+		IstekPonude:  z.IstekPonude,
+	}}
+}
+
+func (z *ZahtevZaSklapanjeSporazuma) TrenutnaPonuda() VerzijaPonude {
+	verzije := z.SveVerzije()
+	return verzije[len(verzije)-1]
+}
+
+// MozeDaOdgovori proverava da li je na potezu strana koja nije predlozila poslednju verziju
+func (z *ZahtevZaSklapanjeSporazuma) MozeDaOdgovori(strana StranaUPregovorima) bool {
+	return z.TrenutnaPonuda().Predlagac != strana
+}
+
+// NovaVerzija pravi sledecu verziju ponude sa rokom vazenja racunajuci od trenutka predlaganja
+func (z *ZahtevZaSklapanjeSporazuma) NovaVerzija(ponuda KontraPonuda, strana StranaUPregovorima, idPredlagaca primitive.ObjectID, sada time.Time) VerzijaPonude {
+	return VerzijaPonude{
+		Broj:         z.TrenutnaPonuda().Broj + 1,
+		Uslovi:       ponuda.Uslovi,
+		Kazna:        ponuda.Kazna,
+		Predlagac:    strana,
+		IdPredlagaca: idPredlagaca,
+		Obrazlozenje: ponuda.Obrazlozenje,
+		Datum:        primitive.NewDateTimeFromTime(sada),
+		IstekPonude:  IstekPonude(ponuda.RokVazenjaDana, sada),
+	}
+}
+
+func IstekPonude(rokVazenjaDana int, sada time.Time) primitive.DateTime {
+	if rokVazenjaDana <= 0 {
+		rokVazenjaDana = PodrazumevaniRokPonudeDana
+	}
+	if rokVazenjaDana > MaxRokPonudeDana {
+		rokVazenjaDana = MaxRokPonudeDana
+	}
+	return primitive.NewDateTimeFromTime(sada.AddDate(0, 0, rokVazenjaDana))
+}
+
+// IstekPocetnePonude vraca rok vazenja pocetne ponude koji je predlagac naveo, ogranicen na MaxRokPonudeDana dana
+// kao i rok kontraponude. Bez navedenog roka ponuda vazi PodrazumevaniRokPonudeDana dana, a rok u proslosti
+// nije dozvoljen.
+func IstekPocetnePonude(istek primitive.DateTime, sada time.Time) (primitive.DateTime, bool) {
+	if istek == 0 {
+		return IstekPonude(PodrazumevaniRokPonudeDana, sada), true
+	}
+	if istek.Time().Before(sada) {
+		return 0, false
+	}
+	if najkasnije := IstekPonude(MaxRokPonudeDana, sada); istek > najkasnije {
+		return najkasnije, true
+	}
+	return istek, true
+}
+
+// Slanje sporazuma sudu se ponavlja sa udvostrucenim cekanjem posle svakog neuspeha, najduze MaxCekanjeSlanja
+const (
+	PocetnoCekanjeSlanja = 30 * time.Second
+	MaxCekanjeSlanja     = time.Hour
+	// Instanca koja je preuzela slanje i prekinuta je pre upisa ishoda zadrzava sporazum najduze ovoliko
+	TrajanjePreuzimanjaSlanja = time.Minute
+)
+
+// NoviSporazum pravi sporazum po prihvacenoj ponudi zahteva, sporazum ceka slanje sudu na potvrdu
+func NoviSporazum(prihvaceniZahtev *ZahtevZaSklapanjeSporazuma, sada time.Time) *Sporazum {
+	return &Sporazum{
+		ID:               primitive.NewObjectID(),
+		Datum:            primitive.NewDateTimeFromTime(sada),
+		Zahtev:           *prihvaceniZahtev,
+		PrihvacenaPonuda: prihvaceniZahtev.TrenutnaPonuda(),
+		StatusPotvrde:    SPORAZUM_CEKA_SLANJE,
+	}
+}
+
+// TrenutniStatusPotvrde vraca status potvrde, sporazumi kreirani pre uvodjenja potvrde jos nisu poslati sudu
+func (s *Sporazum) TrenutniStatusPotvrde() StatusPotvrdeSporazuma {
+	if s.StatusPotvrde == "" {
+		return SPORAZUM_CEKA_SLANJE
+	}
+	return s.StatusPotvrde
+}
+
+// ZaPotvrdu priprema sporazum za slanje sudu, za stare sporazume bez sacuvane ponude koristi se poslednja ponuda zahteva
+func (s *Sporazum) ZaPotvrdu() SporazumZaPotvrdu {
+	ponuda := s.PrihvacenaPonuda
+	if ponuda.Broj == 0 {
+		ponuda = s.Zahtev.TrenutnaPonuda()
+	}
+	return SporazumZaPotvrdu{
+		ID:              s.ID,
+		IdZahteva:       s.Zahtev.ID,
+		Uslovi:          ponuda.Uslovi,
+		Kazna:           ponuda.Kazna,
+		Datum:           s.Datum,
+		IdTuzioca:       s.Zahtev.IdTuzioca,
+		KrivicnaPrijava: s.Zahtev.KrivicnaPrijava,
+	}
+}
+
+func SledeciPokusajSlanja(pokusaji int, sada time.Time) time.Time {
+	cekanje := PocetnoCekanjeSlanja
+	for i := 1; i < pokusaji && cekanje < MaxCekanjeSlanja; i++ {
+		cekanje *= 2
+	}
+	if cekanje > MaxCekanjeSlanja {
+		cekanje = MaxCekanjeSlanja
+	}
+	return sada.Add(cekanje)
+}
@@ -0,0 +1,164 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var sadaSporazuma = time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+func datumPosle(dana int) primitive.DateTime {
+	return primitive.NewDateTimeFromTime(sadaSporazuma.AddDate(0, 0, dana))
+}
+
+func TestIstekPonude(t *testing.T) {
+	tests := []struct {
+		name string
+		rok  int
+		want primitive.DateTime
+	}{
+		{"bez roka vazi podrazumevani rok", 0, datumPosle(PodrazumevaniRokPonudeDana)},
+		{"negativan rok vazi podrazumevani rok", -5, datumPosle(PodrazumevaniRokPonudeDana)},
+		{"navedeni rok", 7, datumPosle(7)},
+		{"najduzi rok", MaxRokPonudeDana, datumPosle(MaxRokPonudeDana)},
+		{"predug rok se ogranicava", MaxRokPonudeDana + 30, datumPosle(MaxRokPonudeDana)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IstekPonude(tt.rok, sadaSporazuma); got != tt.want {
+				t.Errorf("IstekPonude(%d) = %v, ocekivano %v", tt.rok, got.Time(), tt.want.Time())
+			}
+		})
+	}
+}
+
+func TestIstekPocetnePonude(t *testing.T) {
+	tests := []struct {
+		name  string
+		istek primitive.DateTime
+		want  primitive.DateTime
+		ok    bool
+	}{
+		{"bez roka vazi podrazumevani rok", 0, datumPosle(PodrazumevaniRokPonudeDana), true},
+		{"rok u proslosti nije dozvoljen", datumPosle(-1), 0, false},
+		{"navedeni rok se zadrzava", datumPosle(20), datumPosle(20), true},
+		{"rok tacno na granici", datumPosle(MaxRokPonudeDana), datumPosle(MaxRokPonudeDana), true},
+		{"predug rok se ogranicava", datumPosle(365), datumPosle(MaxRokPonudeDana), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := IstekPocetnePonude(tt.istek, sadaSporazuma)
+			if ok != tt.ok {
+				t.Fatalf("IstekPocetnePonude() ok = %v, ocekivano %v", ok, tt.ok)
+			}
+			if got != tt.want {
+				t.Errorf("IstekPocetnePonude() = %v, ocekivano %v", got.Time(), tt.want.Time())
+			}
+		})
+	}
+}
+
+func TestTrenutniStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		zahtev ZahtevZaSklapanjeSporazuma
+		want   StatusZahtevaZaSporazum
+	}{
+		{"stari zahtev na cekanju", ZahtevZaSklapanjeSporazuma{}, ZAHTEV_NA_CEKANJU},
+		{"stari prihvacen zahtev", ZahtevZaSklapanjeSporazuma{Prihvacen: true}, ZAHTEV_PRIHVACEN},
+		{"ponuda u roku", ZahtevZaSklapanjeSporazuma{Status: ZAHTEV_NA_CEKANJU, IstekPonude: datumPosle(1)}, ZAHTEV_NA_CEKANJU},
+		{"istekla ponuda", ZahtevZaSklapanjeSporazuma{Status: ZAHTEV_NA_CEKANJU, IstekPonude: datumPosle(-1)}, ZAHTEV_ISTEKAO},
+		{"stari zahtev sa isteklom ponudom", ZahtevZaSklapanjeSporazuma{IstekPonude: datumPosle(-1)}, ZAHTEV_ISTEKAO},
+		{"prihvacen zahtev ne istice", ZahtevZaSklapanjeSporazuma{Status: ZAHTEV_PRIHVACEN, IstekPonude: datumPosle(-1)}, ZAHTEV_PRIHVACEN},
+		{"odbijen zahtev ne istice", ZahtevZaSklapanjeSporazuma{Status: ZAHTEV_ODBIJEN, IstekPonude: datumPosle(-1)}, ZAHTEV_ODBIJEN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.zahtev.TrenutniStatus(sadaSporazuma); got != tt.want {
+				t.Errorf("TrenutniStatus() = %s, ocekivano %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNovaVerzija(t *testing.T) {
+	idTuzioca := primitive.NewObjectID()
+	idGradjanina := primitive.NewObjectID()
+	ponuda := KontraPonuda{Uslovi: "Priznanje krivice", Kazna: "6 meseci uslovno", Obrazlozenje: "Blaza kazna", RokVazenjaDana: 10}
+
+	tests := []struct {
+		name     string
+		zahtev   ZahtevZaSklapanjeSporazuma
+		strana   StranaUPregovorima
+		broj     int
+		naPotezu StranaUPregovorima
+	}{
+		{
+			name:     "prva kontraponuda na zahtev bez istorije",
+			zahtev:   ZahtevZaSklapanjeSporazuma{Uslovi: "Priznanje", Kazna: "1 godina", IdTuzioca: idTuzioca},
+			strana:   STRANA_GRADJANIN,
+			broj:     2,
+			naPotezu: STRANA_GRADJANIN,
+		},
+		{
+			name: "kontraponuda na postojecu istoriju",
+			zahtev: ZahtevZaSklapanjeSporazuma{Verzije: []VerzijaPonude{
+				{Broj: 1, Predlagac: STRANA_TUZILAC, IdPredlagaca: idTuzioca},
+				{Broj: 2, Predlagac: STRANA_GRADJANIN, IdPredlagaca: idGradjanina},
+			}},
+			strana:   STRANA_TUZILAC,
+			broj:     3,
+			naPotezu: STRANA_TUZILAC,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.zahtev.MozeDaOdgovori(tt.naPotezu) {
+				t.Errorf("MozeDaOdgovori(%s) = false, ocekivano true", tt.naPotezu)
+			}
+			if tt.zahtev.MozeDaOdgovori(tt.zahtev.TrenutnaPonuda().Predlagac) {
+				t.Errorf("MozeDaOdgovori(%s) = true za predlagaca poslednje verzije", tt.zahtev.TrenutnaPonuda().Predlagac)
+			}
+
+			verzija := tt.zahtev.NovaVerzija(ponuda, tt.strana, idGradjanina, sadaSporazuma)
+			if verzija.Broj != tt.broj {
+				t.Errorf("Broj = %d, ocekivano %d", verzija.Broj, tt.broj)
+			}
+			if verzija.Predlagac != tt.strana || verzija.IdPredlagaca != idGradjanina {
+				t.Errorf("predlagac = %s %s, ocekivano %s %s", verzija.Predlagac, verzija.IdPredlagaca.Hex(), tt.strana, idGradjanina.Hex())
+			}
+			if verzija.Uslovi != ponuda.Uslovi || verzija.Kazna != ponuda.Kazna || verzija.Obrazlozenje != ponuda.Obrazlozenje {
+				t.Errorf("uslovi verzije = %+v, ocekivani iz ponude %+v", verzija, ponuda)
+			}
+			if verzija.Datum != primitive.NewDateTimeFromTime(sadaSporazuma) {
+				t.Errorf("Datum = %v, ocekivano %v", verzija.Datum.Time(), sadaSporazuma)
+			}
+			if verzija.IstekPonude != datumPosle(ponuda.RokVazenjaDana) {
+				t.Errorf("IstekPonude = %v, ocekivano %v", verzija.IstekPonude.Time(), datumPosle(ponuda.RokVazenjaDana).Time())
+			}
+		})
+	}
+}
+
+func TestSveVerzijeBezIstorije(t *testing.T) {
+	idTuzioca := primitive.NewObjectID()
+	zahtev := ZahtevZaSklapanjeSporazuma{Uslovi: "Priznanje", Kazna: "1 godina", IdTuzioca: idTuzioca, IstekPonude: datumPosle(5)}
+
+	verzije := zahtev.SveVerzije()
+	if len(verzije) != 1 {
+		t.Fatalf("SveVerzije() = %d verzija, ocekivana 1", len(verzije))
+	}
+	prva := verzije[0]
+	if prva.Broj != 1 || prva.Predlagac != STRANA_TUZILAC || prva.IdPredlagaca != idTuzioca {
+		t.Errorf("prva verzija = %+v, ocekivana verzija 1 tuzioca", prva)
+	}
+	if prva.Uslovi != zahtev.Uslovi || prva.Kazna != zahtev.Kazna || prva.IstekPonude != zahtev.IstekPonude {
+		t.Errorf("prva verzija = %+v, ocekivani pocetni uslovi zahteva", prva)
+	}
+}
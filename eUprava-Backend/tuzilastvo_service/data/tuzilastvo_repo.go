@@ -2,15 +2,20 @@ package data
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"rokovi"
 	"time"
 )
 
@@ -21,9 +26,19 @@ const (
 	COLLECTIONSPORAZUM                   = "sporazum"
 	COLLECTIONPORUKA                     = "poruka"
 	COLLECTIONKANAL                      = "kanal"
+	COLLECTIONPREDMET                    = "predmet"
+	COLLECTIONBROJAC                     = "brojac"
+	COLLECTIONDOKAZ                      = "dokaz"
+	COLLECTIONLANACDOKAZA                = "lanacDokaza"
+	COLLECTIONPRAVILOROKA                = "praviloRoka"
+	COLLECTIONOBAVESTENJE                = "obavestenje"
+	COLLECTIONPROMENASTATUSAPRIJAVE      = "neposlataPromenaStatusa"
+	COLLECTIONDUPLIKATPREDMETA           = "duplikatPredmeta"
+	BUCKETPRILOZI                        = "prilozi"
 )
 
 type TuzilastvoRepo struct {
+	*rokovi.Skladiste
 	cli    *mongo.Client
 	logger *log.Logger
 	client *http.Client
@@ -53,10 +68,11 @@ func New(ctx context.Context, logger *log.Logger) (*TuzilastvoRepo, error) {
 	tabela := client.Database(DATABASE)
 	// Return repository with logger and DB client
 	return &TuzilastvoRepo{
-		cli:    client,
-		logger: logger,
-		client: httpClient,
-		tabela: tabela,
+		Skladiste: rokovi.NewSkladiste(tabela.Collection(COLLECTIONPRAVILOROKA), tabela.Collection(COLLECTIONOBAVESTENJE), PodrazumevanaPravilaRokova(), logger),
+		cli:       client,
+		logger:    logger,
+		client:    httpClient,
+		tabela:    tabela,
 	}, nil
 }
 
@@ -115,7 +131,41 @@ func (rr *TuzilastvoRepo) DobaviZahteveZaSudskiPostupak(ctx context.Context) (Za
 	return rr.filterZahteviZaSudskiPostupak(ctx, filter)
 }
 
+// DobaviAktivneZahteveZaSudskiPostupak vraca zahteve ciji prijem sud jos nije potvrdio
+func (rr *TuzilastvoRepo) DobaviAktivneZahteveZaSudskiPostupak(ctx context.Context) (ZahteviZaSudskiPostupak, error) {
+	filter := bson.D{{Key: "status", Value: bson.D{{Key: "$ne", Value: ZAHTEV_PRIMLJEN}}}}
+	return rr.filterZahteviZaSudskiPostupak(ctx, filter)
+}
+
+// OznaciZahtevPrimljenim belezi prijem zahteva u sudu. Ponovljena potvrda za isti predmet suda je uspesna,
+// a vraca false ako je prijem vec potvrdjen za drugi predmet suda.
+func (rr *TuzilastvoRepo) OznaciZahtevPrimljenim(ctx context.Context, id primitive.ObjectID, prijem PrijemZahteva) (bool, error) {
+	kolekcija := rr.tabela.Collection(COLLECTIONZAHTEVZASUDSKIPOSTUPAK)
+	filter := bson.D{{Key: "_id", Value: id}, {Key: "status", Value: bson.D{{Key: "$ne", Value: ZAHTEV_PRIMLJEN}}}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: ZAHTEV_PRIMLJEN},
+		{Key: "prijem", Value: prijem},
+	}}}
+
+	rezultat, err := kolekcija.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	if rezultat.MatchedCount > 0 {
+		return true, nil
+	}
+
+	var zahtev ZahtevZaSudskiPostupak
+	if err := kolekcija.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&zahtev); err != nil {
+		return false, err
+	}
+	return zahtev.Prijem != nil && zahtev.Prijem.IdPredmetaSuda == prijem.IdPredmetaSuda, nil
+}
+
 func (rr *TuzilastvoRepo) DobaviZahteveZaSklapanjeSporazuma(ctx context.Context) (ZahteviZaSklapanjeSporazuma, error) {
+	if err := rr.OznaciIstekleZahteveZaSklapanjeSporazuma(ctx); err != nil {
+		return nil, err
+	}
 	filter := bson.D{{}}
 	cursor, err := rr.tabela.Collection(COLLECTIONZAHTEVZASKLAPANJESPORAZUMA).Find(ctx, filter)
 	if err != nil {
@@ -161,17 +211,6 @@ func decodeZahteviZaSudskiPostupak(cursor *mongo.Cursor) (zahtevi ZahteviZaSudsk
 	return
 }
 
-func (rr *TuzilastvoRepo) DodajSporazum(ctx context.Context, sporazum *Sporazum) error {
-
-	_, err := rr.tabela.Collection(COLLECTIONSPORAZUM).InsertOne(ctx, sporazum)
-
-	if err != nil {
-		log.Println("Greska prilikom dodavanja sporazuma")
-		return err
-	}
-	return nil
-}
-
 func (rr *TuzilastvoRepo) DobaviSporazume(ctx context.Context) (Sporazumi, error) {
 	filter := bson.D{{}}
 	cursor, err := rr.tabela.Collection(COLLECTIONSPORAZUM).Find(ctx, filter)
@@ -206,8 +245,15 @@ func (rr *TuzilastvoRepo) DobaviZahtevZaSudskiPostupakPoPrijavi(ctx context.Cont
 	return &zahtev, nil
 }
 
+// DobaviZahtevZaSklapanjeSporazumaPoPrijavi vraca zahtev koji nije odbijen niti istekao
 func (rr *TuzilastvoRepo) DobaviZahtevZaSklapanjeSporazumaPoPrijavi(ctx context.Context, id primitive.ObjectID) (*ZahtevZaSklapanjeSporazuma, error) {
-	filter := bson.D{{"krivicnaPrijava._id", id}}
+	if err := rr.OznaciIstekleZahteveZaSklapanjeSporazuma(ctx); err != nil {
+		return nil, err
+	}
+	filter := bson.D{
+		{Key: "krivicnaPrijava._id", Value: id},
+		{Key: "status", Value: bson.D{{Key: "$nin", Value: bson.A{ZAHTEV_ODBIJEN, ZAHTEV_ISTEKAO}}}},
+	}
 	var zahtev ZahtevZaSklapanjeSporazuma
 
 	err := rr.tabela.Collection(COLLECTIONZAHTEVZASKLAPANJESPORAZUMA).FindOne(ctx, filter).Decode(&zahtev)
@@ -219,6 +265,9 @@ func (rr *TuzilastvoRepo) DobaviZahtevZaSklapanjeSporazumaPoPrijavi(ctx context.
 }
 
 func (rr *TuzilastvoRepo) DobaviZahteveZaSklapanjeSporazumaPoGradjaninu(ctx context.Context, jmbg string) (ZahteviZaSklapanjeSporazuma, error) {
+	if err := rr.OznaciIstekleZahteveZaSklapanjeSporazuma(ctx); err != nil {
+		return nil, err
+	}
 	filter := bson.D{{"krivicnaPrijava.prelaz.JMBGPutnika", jmbg}}
 	var zahtevi ZahteviZaSklapanjeSporazuma
 
@@ -243,19 +292,56 @@ func (rr *TuzilastvoRepo) DobaviZahteveZaSklapanjeSporazumaPoGradjaninu(ctx cont
 	return zahtevi, nil
 }
 
-func (rr *TuzilastvoRepo) PrihvatiZahtevZaSklapanjeSporazuma(ctx context.Context, id primitive.ObjectID) (*ZahtevZaSklapanjeSporazuma, error) {
-	filter := bson.D{{"_id", id}}
-	update := bson.D{{"$set", bson.D{{"prihvacen", true}}}}
+// otvorenaPonuda odgovara zahtevu na cekanju cija je poslednja verzija jos uvek verzija, kako bi
+// istovremeni odgovori na istu ponudu uspeli najvise jednom
+func otvorenaPonuda(id primitive.ObjectID, verzija int, sada time.Time) bson.D {
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "status", Value: bson.D{{Key: "$nin", Value: bson.A{ZAHTEV_PRIHVACEN, ZAHTEV_ODBIJEN, ZAHTEV_ISTEKAO}}}},
+		{Key: "prihvacen", Value: bson.D{{Key: "$ne", Value: true}}},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "istekPonude", Value: bson.D{{Key: "$exists", Value: false}}}},
+			bson.D{{Key: "istekPonude", Value: bson.D{{Key: "$gte", Value: primitive.NewDateTimeFromTime(sada)}}}},
+		}},
+	}
+	if verzija == 0 {
+		return append(filter, bson.E{Key: "trenutnaVerzija", Value: bson.D{{Key: "$exists", Value: false}}})
+	}
+	return append(filter, bson.E{Key: "trenutnaVerzija", Value: verzija})
+}
+
+// PrihvatiZahtevZaSklapanjeSporazuma prihvata otvorenu ponudu i kreira sporazum u istoj transakciji, pa prihvacen
+// zahtev nikad ne ostaje bez sporazuma. Ako ponuda vise nije otvorena vraca mongo.ErrNoDocuments.
+func (rr *TuzilastvoRepo) PrihvatiZahtevZaSklapanjeSporazuma(ctx context.Context, id primitive.ObjectID, verzija int, sada time.Time) (*Sporazum, error) {
+	filter := otvorenaPonuda(id, verzija, sada)
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "prihvacen", Value: true},
+		{Key: "status", Value: ZAHTEV_PRIHVACEN},
+		{Key: "datumOdluke", Value: primitive.NewDateTimeFromTime(sada)},
+	}}}
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 
-	var prihvaceniZahtev ZahtevZaSklapanjeSporazuma
-	err := rr.tabela.Collection(COLLECTIONZAHTEVZASKLAPANJESPORAZUMA).FindOneAndUpdate(ctx, filter, update, opts).Decode(&prihvaceniZahtev)
+	var sporazum *Sporazum
+	err := rr.uTransakciji(ctx, func(ctx mongo.SessionContext) error {
+		var prihvaceniZahtev ZahtevZaSklapanjeSporazuma
+		err := rr.tabela.Collection(COLLECTIONZAHTEVZASKLAPANJESPORAZUMA).FindOneAndUpdate(ctx, filter, update, opts).Decode(&prihvaceniZahtev)
+		if err != nil {
+			return err
+		}
+
+		sporazum = NoviSporazum(&prihvaceniZahtev, sada)
+		_, err = rr.tabela.Collection(COLLECTIONSPORAZUM).InsertOne(ctx, sporazum)
+		if err != nil {
+			log.Println("Greska prilikom dodavanja sporazuma")
+		}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &prihvaceniZahtev, nil
+	return sporazum, nil
 }
 
 func (rr *TuzilastvoRepo) DobaviSporazumPoZahtevu(ctx context.Context, id primitive.ObjectID) (*Sporazum, error) {
@@ -270,17 +356,203 @@ func (rr *TuzilastvoRepo) DobaviSporazumPoZahtevu(ctx context.Context, id primit
 	return &sporzum, nil
 }
 
-func (rr *TuzilastvoRepo) OdbijZahtevZaSklapanjeSporazuma(ctx context.Context, id primitive.ObjectID) error {
-	filter := bson.D{{"_id", id}}
+func (rr *TuzilastvoRepo) DobaviSporazum(ctx context.Context, id primitive.ObjectID) (*Sporazum, error) {
+	filter := bson.D{{Key: "_id", Value: id}}
+	var sporazum Sporazum
+
+	err := rr.tabela.Collection(COLLECTIONSPORAZUM).FindOne(ctx, filter).Decode(&sporazum)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sporazum, nil
+}
+
+func (rr *TuzilastvoRepo) filterSporazumi(ctx context.Context, filter interface{}) (Sporazumi, error) {
+	cursor, err := rr.tabela.Collection(COLLECTIONSPORAZUM).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sporazumi Sporazumi
+	err = cursor.All(ctx, &sporazumi)
+	return sporazumi, err
+}
+
+// neposlatSporazum odgovara sporazumima koji jos nisu dostavljeni sudu, ukljucujuci one kreirane pre uvodjenja potvrde
+func neposlatSporazum(id primitive.ObjectID) bson.D {
+	return bson.D{
+		{Key: "_id", Value: id},
+		{Key: "statusPotvrde", Value: bson.D{{Key: "$in", Value: bson.A{SPORAZUM_CEKA_SLANJE, SPORAZUM_SALJE_SE, nil}}}},
+	}
+}
+
+// spremanZaSlanje odgovara neposlatim sporazumima kojima je doslo vreme za sledeci pokusaj, sto ukljucuje
+// i sporazume cije je preuzimanje isteklo jer instanca koja ih je slala nije upisala ishod
+func spremanZaSlanje(sada time.Time) bson.D {
+	return bson.D{
+		{Key: "statusPotvrde", Value: bson.D{{Key: "$in", Value: bson.A{SPORAZUM_CEKA_SLANJE, SPORAZUM_SALJE_SE, nil}}}},
+		{Key: "sledeciPokusaj", Value: bson.D{{Key: "$not", Value: bson.D{{Key: "$gt", Value: primitive.NewDateTimeFromTime(sada)}}}}},
+	}
+}
+
+// DobaviSporazumeZaSlanje vraca sporazume koji cekaju slanje sudu i kojima je doslo vreme za sledeci pokusaj
+func (rr *TuzilastvoRepo) DobaviSporazumeZaSlanje(ctx context.Context, sada time.Time) (Sporazumi, error) {
+	return rr.filterSporazumi(ctx, spremanZaSlanje(sada))
+}
+
+// PreuzmiSlanjeSporazuma atomski oznacava da ova instanca salje sporazum, pa ga goroutine posle kreiranja i
+// periodicna obrada na svim instancama ne salju istovremeno. Vraca false ako je slanje vec preuzeto ili zavrseno.
+func (rr *TuzilastvoRepo) PreuzmiSlanjeSporazuma(ctx context.Context, id primitive.ObjectID, sada time.Time) (bool, error) {
+	filter := append(bson.D{{Key: "_id", Value: id}}, spremanZaSlanje(sada)...)
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "statusPotvrde", Value: SPORAZUM_SALJE_SE},
+		{Key: "sledeciPokusaj", Value: primitive.NewDateTimeFromTime(sada.Add(TrajanjePreuzimanjaSlanja))},
+	}}}
+
+	rezultat, err := rr.tabela.Collection(COLLECTIONSPORAZUM).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return rezultat.ModifiedCount == 1, nil
+}
+
+func (rr *TuzilastvoRepo) DobaviSporazumeKodSuda(ctx context.Context) (Sporazumi, error) {
+	filter := bson.D{{Key: "statusPotvrde", Value: SPORAZUM_KOD_SUDA}}
+	return rr.filterSporazumi(ctx, filter)
+}
+
+func (rr *TuzilastvoRepo) OznaciSporazumKodSuda(ctx context.Context, id primitive.ObjectID, idPredmetaSuda primitive.ObjectID) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "statusPotvrde", Value: SPORAZUM_KOD_SUDA},
+			{Key: "idPredmetaSuda", Value: idPredmetaSuda},
+		}},
+		{Key: "$unset", Value: bson.D{
+			{Key: "sledeciPokusaj", Value: ""},
+			{Key: "greskaSlanja", Value: ""},
+		}},
+	}
+
+	_, err := rr.tabela.Collection(COLLECTIONSPORAZUM).UpdateOne(ctx, neposlatSporazum(id), update)
+	return err
+}
+
+func (rr *TuzilastvoRepo) ZakaziPonovnoSlanjeSporazuma(ctx context.Context, id primitive.ObjectID, pokusaji int, sledeciPokusaj time.Time, greska string) error {
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "statusPotvrde", Value: SPORAZUM_CEKA_SLANJE},
+		{Key: "pokusajiSlanja", Value: pokusaji},
+		{Key: "sledeciPokusaj", Value: primitive.NewDateTimeFromTime(sledeciPokusaj)},
+		{Key: "greskaSlanja", Value: greska},
+	}}}
+
+	_, err := rr.tabela.Collection(COLLECTIONSPORAZUM).UpdateOne(ctx, neposlatSporazum(id), update)
+	return err
+}
+
+// EvidentirajOdlukuSuda upisuje odluku suda samo jednom, vraca false ako je odluka vec bila evidentirana
+func (rr *TuzilastvoRepo) EvidentirajOdlukuSuda(ctx context.Context, id primitive.ObjectID, odluka OdlukaSuda) (bool, error) {
+	status := SPORAZUM_ODBIJEN
+	if odluka.Potvrdjen {
+		status = SPORAZUM_POTVRDJEN
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "statusPotvrde", Value: bson.D{{Key: "$in", Value: bson.A{SPORAZUM_CEKA_SLANJE, SPORAZUM_SALJE_SE, SPORAZUM_KOD_SUDA, nil}}}},
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "statusPotvrde", Value: status},
+			{Key: "idPredmetaSuda", Value: odluka.IdPredmeta},
+			{Key: "odlukaSuda", Value: odluka},
+		}},
+		{Key: "$unset", Value: bson.D{
+			{Key: "sledeciPokusaj", Value: ""},
+			{Key: "greskaSlanja", Value: ""},
+		}},
+	}
+
+	rezultat, err := rr.tabela.Collection(COLLECTIONSPORAZUM).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return rezultat.MatchedCount > 0, nil
+}
+
+// DodajZahtevZaSudskiPostupakAkoNePostoji ne vraca gresku ako zahtev sa istim id vec postoji
+func (rr *TuzilastvoRepo) DodajZahtevZaSudskiPostupakAkoNePostoji(ctx context.Context, zahtev *ZahtevZaSudskiPostupak) error {
+	_, err := rr.tabela.Collection(COLLECTIONZAHTEVZASUDSKIPOSTUPAK).InsertOne(ctx, zahtev)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		log.Println("Greska prilikom dodavanja zahteva za sudski postupak")
+		return err
+	}
+	return nil
+}
+
+// OdbijZahtevZaSklapanjeSporazuma zavrsava pregovore odbijanjem, zahtev ostaje sacuvan sa razlogom odbijanja
+func (rr *TuzilastvoRepo) OdbijZahtevZaSklapanjeSporazuma(ctx context.Context, id primitive.ObjectID, verzija int, razlog string, sada time.Time) error {
+	filter := otvorenaPonuda(id, verzija, sada)
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: ZAHTEV_ODBIJEN},
+		{Key: "razlogOdbijanja", Value: razlog},
+		{Key: "datumOdluke", Value: primitive.NewDateTimeFromTime(sada)},
+	}}}
 
-	_, err := rr.tabela.Collection(COLLECTIONZAHTEVZASKLAPANJESPORAZUMA).DeleteOne(ctx, filter)
+	rezultat, err := rr.tabela.Collection(COLLECTIONZAHTEVZASKLAPANJESPORAZUMA).UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// DodajKontraPonudu dodaje novu verziju ponude, zahtevima bez istorije se prvo upisuje pocetna verzija
+func (rr *TuzilastvoRepo) DodajKontraPonudu(ctx context.Context, zahtev *ZahtevZaSklapanjeSporazuma, nova VerzijaPonude, sada time.Time) error {
+	nove := bson.A{}
+	if len(zahtev.Verzije) == 0 {
+		nove = append(nove, zahtev.TrenutnaPonuda())
+	}
+	nove = append(nove, nova)
+
+	filter := otvorenaPonuda(zahtev.ID, zahtev.TrenutnaVerzija, sada)
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "uslovi", Value: nova.Uslovi},
+			{Key: "kazna", Value: nova.Kazna},
+			{Key: "istekPonude", Value: nova.IstekPonude},
+			{Key: "trenutnaVerzija", Value: nova.Broj},
+			{Key: "status", Value: ZAHTEV_NA_CEKANJU},
+		}},
+		{Key: "$push", Value: bson.D{{Key: "verzije", Value: bson.D{{Key: "$each", Value: nove}}}}},
+	}
 
+	rezultat, err := rr.tabela.Collection(COLLECTIONZAHTEVZASKLAPANJESPORAZUMA).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
 	return nil
 }
 
+// OznaciIstekleZahteveZaSklapanjeSporazuma prebacuje ponude na cekanju kojima je prosao rok u status ISTEKAO
+func (rr *TuzilastvoRepo) OznaciIstekleZahteveZaSklapanjeSporazuma(ctx context.Context) error {
+	filter := bson.D{
+		{Key: "status", Value: ZAHTEV_NA_CEKANJU},
+		{Key: "istekPonude", Value: bson.D{{Key: "$lt", Value: primitive.NewDateTimeFromTime(time.Now())}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "status", Value: ZAHTEV_ISTEKAO}}}}
+
+	_, err := rr.tabela.Collection(COLLECTIONZAHTEVZASKLAPANJESPORAZUMA).UpdateMany(ctx, filter, update)
+	return err
+}
+
 func (rr *TuzilastvoRepo) DobaviZahtevZaSklapanjeSporazuma(ctx context.Context, id primitive.ObjectID) (*ZahtevZaSklapanjeSporazuma, error) {
 	filter := bson.D{{"_id", id}}
 	var zahtev ZahtevZaSklapanjeSporazuma
@@ -304,38 +576,151 @@ func (rr *TuzilastvoRepo) KreirajKanal(ctx context.Context, kanal *Kanal) error
 	return nil
 }
 
-func (rr *TuzilastvoRepo) DobaviKanale(ctx context.Context) (Kanali, error) {
-	filter := bson.D{{}}
+func (rr *TuzilastvoRepo) DobaviKanal(ctx context.Context, id primitive.ObjectID) (*Kanal, error) {
+	filter := bson.D{{"_id", id}}
+	var kanal Kanal
+
+	err := rr.tabela.Collection(COLLECTIONKANAL).FindOne(ctx, filter).Decode(&kanal)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kanal, nil
+}
+
+// DobaviKanaleClana vraca kanale u kojima je korisnik vlasnik, clan ili posmatrac i otvorene kanale
+// ako korisnik ima rolu clanova kanala
+func (rr *TuzilastvoRepo) DobaviKanaleClana(ctx context.Context, korisnik *Korisnik) (Kanali, error) {
+	filter := bson.D{{Key: "clanovi.idKorisnika", Value: korisnik.ID}}
+	if MozeDaBudeClanKanala(korisnik.Rola) {
+		filter = bson.D{{Key: "$or", Value: bson.A{filter, bson.D{{Key: "otvoren", Value: true}}}}}
+	}
 	cursor, err := rr.tabela.Collection(COLLECTIONKANAL).Find(ctx, filter)
 	if err != nil {
-		log.Println("Ne postoje kanali za dati filter")
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
 	var kanali Kanali
-	for cursor.Next(context.TODO()) {
-		var kanal Kanal
-		err = cursor.Decode(&kanal)
-		if err != nil {
-			return nil, nil
-		}
-		kanali = append(kanali, &kanal)
+	err = cursor.All(ctx, &kanali)
+	return kanali, err
+}
+
+// PostaviClanaKanala dodaje korisnika u kanal ili menja ulogu postojecem clanu, uloga vlasnika se ne menja
+func (rr *TuzilastvoRepo) PostaviClanaKanala(ctx context.Context, kanalId primitive.ObjectID, clan ClanKanala) error {
+	kolekcija := rr.tabela.Collection(COLLECTIONKANAL)
+
+	filter := bson.D{
+		{Key: "_id", Value: kanalId},
+		{Key: "clanovi.idKorisnika", Value: bson.D{{Key: "$ne", Value: clan.IdKorisnika}}},
 	}
-	err = cursor.Err()
-	return kanali, nil
+	update := bson.D{{Key: "$push", Value: bson.D{{Key: "clanovi", Value: clan}}}}
+	rezultat, err := kolekcija.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount > 0 {
+		return nil
+	}
+
+	filter = bson.D{
+		{Key: "_id", Value: kanalId},
+		{Key: "clanovi", Value: bson.D{{Key: "$elemMatch", Value: bson.D{
+			{Key: "idKorisnika", Value: clan.IdKorisnika},
+			{Key: "uloga", Value: bson.D{{Key: "$ne", Value: VLASNIK_KANALA}}},
+		}}}},
+	}
+	update = bson.D{{Key: "$set", Value: bson.D{{Key: "clanovi.$.uloga", Value: clan.Uloga}}}}
+	rezultat, err = kolekcija.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
 }
 
-func (rr *TuzilastvoRepo) DobaviKanal(ctx context.Context, id primitive.ObjectID) (*Kanal, error) {
-	filter := bson.D{{"_id", id}}
-	var kanal Kanal
+// UkloniClanaKanala uklanja clana iz kanala, vlasnik kanala se ne moze ukloniti
+func (rr *TuzilastvoRepo) UkloniClanaKanala(ctx context.Context, kanalId primitive.ObjectID, idKorisnika primitive.ObjectID) error {
+	filter := bson.D{{Key: "_id", Value: kanalId}}
+	update := bson.D{{Key: "$pull", Value: bson.D{{Key: "clanovi", Value: bson.D{
+		{Key: "idKorisnika", Value: idKorisnika},
+		{Key: "uloga", Value: bson.D{{Key: "$ne", Value: VLASNIK_KANALA}}},
+	}}}}}
 
-	err := rr.tabela.Collection(COLLECTIONKANAL).FindOne(ctx, filter).Decode(&kanal)
+	rezultat, err := rr.tabela.Collection(COLLECTIONKANAL).UpdateOne(ctx, filter, update)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if rezultat.ModifiedCount == 0 {
+		return mongo.ErrNoDocuments
 	}
+	return nil
+}
 
-	return &kanal, nil
+// ZatvoriKanal ukida pristup otvorenom kanalu korisnicima koji nisu njegovi clanovi
+func (rr *TuzilastvoRepo) ZatvoriKanal(ctx context.Context, kanalId primitive.ObjectID) error {
+	filter := bson.D{{Key: "_id", Value: kanalId}}
+	update := bson.D{{Key: "$unset", Value: bson.D{{Key: "otvoren", Value: ""}}}}
+	_, err := rr.tabela.Collection(COLLECTIONKANAL).UpdateOne(ctx, filter, update)
+	return err
+}
+
+// MigrirajKanale prevodi kanale kreirane pre uvodjenja clanstva na clanstvo. Takvim kanalima su pristupali svi
+// korisnici sa rolom clanova kanala, pa ostaju otvoreni za njih. Istrazitelji predmeta kanala postaju clanovi,
+// a prvi od njih vlasnik koji moze da zatvori kanal. Kanal bez predmeta ostaje otvoren bez vlasnika.
+func (rr *TuzilastvoRepo) MigrirajKanale(ctx context.Context) error {
+	filter := bson.D{
+		{Key: "idVlasnika", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: "clanovi", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: "otvoren", Value: bson.D{{Key: "$exists", Value: false}}},
+	}
+	cursor, err := rr.tabela.Collection(COLLECTIONKANAL).Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	var kanali Kanali
+	if err := cursor.All(ctx, &kanali); err != nil {
+		return err
+	}
+
+	migrirano := 0
+	for _, kanal := range kanali {
+		postavi := bson.D{{Key: "otvoren", Value: true}}
+
+		if !kanal.PredmetId.IsZero() {
+			predmet, err := rr.DobaviPredmet(ctx, kanal.PredmetId)
+			if err != nil && err != mongo.ErrNoDocuments {
+				return err
+			}
+			if predmet != nil && len(predmet.IdIstrazitelja) > 0 {
+				clanovi := make([]ClanKanala, 0, len(predmet.IdIstrazitelja))
+				for i, id := range predmet.IdIstrazitelja {
+					uloga := UlogaUKanalu(CLAN_KANALA)
+					if i == 0 {
+						uloga = VLASNIK_KANALA
+					}
+					clanovi = append(clanovi, ClanKanala{IdKorisnika: id, Rola: "Istrazitelj", Uloga: uloga, Dodat: kanal.Kreiran})
+				}
+				postavi = append(postavi,
+					bson.E{Key: "idVlasnika", Value: predmet.IdIstrazitelja[0]},
+					bson.E{Key: "clanovi", Value: clanovi},
+				)
+			}
+		}
+
+		update := bson.D{{Key: "$set", Value: postavi}}
+		_, err = rr.tabela.Collection(COLLECTIONKANAL).UpdateOne(ctx, bson.D{{Key: "_id", Value: kanal.ID}}, update)
+		if err != nil {
+			return err
+		}
+		migrirano++
+	}
+	if migrirano > 0 {
+		rr.logger.Printf("Migrirano %d kanala na clanstvo", migrirano)
+	}
+	return nil
 }
 
 func (rr *TuzilastvoRepo) KreirajPoruku(ctx context.Context, poruka *Poruka) error {
@@ -370,3 +755,815 @@ func (rr *TuzilastvoRepo) DobaviPorukePoKanalu(ctx context.Context, id primitive
 	err = cursor.Err()
 	return poruke, nil
 }
+
+func (rr *TuzilastvoRepo) DobaviPoruku(ctx context.Context, id primitive.ObjectID) (*Poruka, error) {
+	filter := bson.D{{Key: "_id", Value: id}}
+	var poruka Poruka
+
+	err := rr.tabela.Collection(COLLECTIONPORUKA).FindOne(ctx, filter).Decode(&poruka)
+	if err != nil {
+		return nil, err
+	}
+
+	return &poruka, nil
+}
+
+func (rr *TuzilastvoRepo) filterPoruke(ctx context.Context, filter interface{}) (Poruke, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "datum", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := rr.tabela.Collection(COLLECTIONPORUKA).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var poruke Poruke
+	err = cursor.All(ctx, &poruke)
+	return poruke, err
+}
+
+// DobaviPorukeNakon vraca poruke kanala koje su po redosledu posle prosledjene pozicije
+func (rr *TuzilastvoRepo) DobaviPorukeNakon(ctx context.Context, kanalId primitive.ObjectID, pozicija PozicijaPoruke) (Poruke, error) {
+	filter := bson.D{
+		{Key: "kanalId", Value: kanalId},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "datum", Value: bson.D{{Key: "$gt", Value: pozicija.Datum}}}},
+			bson.D{{Key: "datum", Value: pozicija.Datum}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: pozicija.ID}}}},
+		}},
+	}
+	return rr.filterPoruke(ctx, filter)
+}
+
+// DobaviPorukeOdDatuma vraca poruke svih kanala upisane, izmenjene ili obrisane od prosledjenog trenutka
+func (rr *TuzilastvoRepo) DobaviPorukeOdDatuma(ctx context.Context, od time.Time) (Poruke, error) {
+	odDatuma := bson.D{{Key: "$gte", Value: od}}
+	filter := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "datum", Value: odDatuma}},
+		bson.D{{Key: "izmenjena", Value: odDatuma}},
+		bson.D{{Key: "brisanje.datum", Value: odDatuma}},
+	}}}
+	return rr.filterPoruke(ctx, filter)
+}
+
+// PodrzavaChangeStream proverava da li je Mongo pokrenut kao replica set, sto je uslov za change stream
+func (rr *TuzilastvoRepo) PodrzavaChangeStream(ctx context.Context) bool {
+	var odgovor bson.M
+	err := rr.tabela.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&odgovor)
+	if err != nil {
+		return false
+	}
+	_, ok := odgovor["setName"]
+	return ok
+}
+
+// PratiUpisePoruka objavljuje svaku novu, izmenjenu i obrisanu poruku dok se tok ne prekine i vraca token za nastavak toka
+func (rr *TuzilastvoRepo) PratiUpisePoruka(ctx context.Context, nastaviPosle bson.Raw, objavi func(*Poruka)) (bson.Raw, error) {
+	pipeline := mongo.Pipeline{bson.D{{Key: "$match", Value: bson.D{{Key: "operationType", Value: bson.D{
+		{Key: "$in", Value: bson.A{"insert", "update", "replace"}},
+	}}}}}}
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if nastaviPosle != nil {
+		opts.SetResumeAfter(nastaviPosle)
+	}
+
+	tok, err := rr.tabela.Collection(COLLECTIONPORUKA).Watch(ctx, pipeline, opts)
+	if err != nil {
+		return nastaviPosle, err
+	}
+	defer tok.Close(context.Background())
+
+	for tok.Next(ctx) {
+		var dogadjaj struct {
+			Poruka Poruka `bson:"fullDocument"`
+		}
+		if err := tok.Decode(&dogadjaj); err != nil {
+			return nastaviPosle, err
+		}
+		nastaviPosle = tok.ResumeToken()
+		if dogadjaj.Poruka.ID.IsZero() {
+			// dokument je uklonjen pre nego sto je izmena procitana
+			continue
+		}
+		objavi(&dogadjaj.Poruka)
+	}
+	return nastaviPosle, tok.Err()
+}
+
+// KreirajIndeksePoruka kreira tekstualni indeks za pretragu poruka. Jezik "none" iskljucuje engleske stop reci i
+// korenovanje, koji bi pokvarili pretragu teksta na srpskom.
+func (rr *TuzilastvoRepo) KreirajIndeksePoruka(ctx context.Context) error {
+	indeks := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "sadrzaj", Value: "text"},
+			{Key: "prilozi.naziv", Value: "text"},
+		},
+		Options: options.Index().SetName("pretraga_poruka").SetDefaultLanguage("none"),
+	}
+	_, err := rr.tabela.Collection(COLLECTIONPORUKA).Indexes().CreateOne(ctx, indeks)
+	return err
+}
+
+// IzmeniPoruku menja sadrzaj poruke i cuva prethodni sadrzaj u istoriji izmena. Izmena uspeva samo ako je poruku
+// poslao autor posle trenutka najranije, nije obrisana i nije u medjuvremenu izmenjena.
+func (rr *TuzilastvoRepo) IzmeniPoruku(ctx context.Context, poruka *Poruka, idAutora primitive.ObjectID, najranije time.Time, sadrzaj string) error {
+	sada := time.Now()
+	filter := bson.D{
+		{Key: "_id", Value: poruka.ID},
+		{Key: "idAutora", Value: idAutora},
+		{Key: "sadrzaj", Value: poruka.Sadrzaj},
+		{Key: "datum", Value: bson.D{{Key: "$gte", Value: najranije}}},
+		{Key: "obrisana", Value: bson.D{{Key: "$ne", Value: true}}},
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "sadrzaj", Value: sadrzaj},
+			{Key: "izmenjena", Value: sada},
+		}},
+		{Key: "$push", Value: bson.D{{Key: "izmene", Value: IzmenaPoruke{Sadrzaj: poruka.Sadrzaj, Datum: sada}}}},
+	}
+
+	rezultat, err := rr.tabela.Collection(COLLECTIONPORUKA).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ObrisiPoruku oznacava poruku kao obrisanu i belezi ko ju je obrisao, sadrzaj i prilozi ostaju sacuvani
+func (rr *TuzilastvoRepo) ObrisiPoruku(ctx context.Context, id primitive.ObjectID, brisanje BrisanjePoruke) error {
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "obrisana", Value: bson.D{{Key: "$ne", Value: true}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "obrisana", Value: true},
+		{Key: "brisanje", Value: brisanje},
+	}}}
+
+	rezultat, err := rr.tabela.Collection(COLLECTIONPORUKA).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// PretraziPoruke pretrazuje sadrzaj i nazive priloga neobrisanih poruka u prosledjenim kanalima, najbolja poklapanja prva
+func (rr *TuzilastvoRepo) PretraziPoruke(ctx context.Context, upit string, kanali []primitive.ObjectID) ([]*RezultatPretrage, error) {
+	filter := bson.D{
+		{Key: "$text", Value: bson.D{{Key: "$search", Value: upit}}},
+		{Key: "kanalId", Value: bson.D{{Key: "$in", Value: kanali}}},
+		{Key: "obrisana", Value: bson.D{{Key: "$ne", Value: true}}},
+	}
+	ocena := bson.D{{Key: "ocena", Value: bson.D{{Key: "$meta", Value: "textScore"}}}}
+	opts := options.Find().
+		SetProjection(ocena).
+		SetSort(bson.D{{Key: "ocena", Value: bson.D{{Key: "$meta", Value: "textScore"}}}, {Key: "datum", Value: -1}}).
+		SetLimit(MaxRezultataPretrage)
+
+	cursor, err := rr.tabela.Collection(COLLECTIONPORUKA).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	rezultati := []*RezultatPretrage{}
+	err = cursor.All(ctx, &rezultati)
+	return rezultati, err
+}
+
+func (rr *TuzilastvoRepo) bucketPriloga() (*gridfs.Bucket, error) {
+	return gridfs.NewBucket(rr.tabela, options.GridFSBucket().SetName(BUCKETPRILOZI))
+}
+
+// SacuvajPrilog upisuje fajl u GridFS i racuna njegov SHA-256 hes tokom upisa
+func (rr *TuzilastvoRepo) SacuvajPrilog(ctx context.Context, naziv string, tipSadrzaja string, izvor io.Reader) (*Prilog, error) {
+	bucket, err := rr.bucketPriloga()
+	if err != nil {
+		return nil, err
+	}
+	if rok, ok := ctx.Deadline(); ok {
+		bucket.SetWriteDeadline(rok)
+	}
+
+	hes := sha256.New()
+	opts := options.GridFSUpload().SetMetadata(bson.D{{Key: "tipSadrzaja", Value: tipSadrzaja}})
+	id, err := bucket.UploadFromStream(naziv, io.TeeReader(izvor, hes), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var fajl struct {
+		Velicina int64 `bson:"length"`
+	}
+	err = bucket.GetFilesCollection().FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&fajl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Prilog{
+		ID:          id,
+		Naziv:       naziv,
+		TipSadrzaja: tipSadrzaja,
+		Velicina:    fajl.Velicina,
+		Sha256:      hex.EncodeToString(hes.Sum(nil)),
+	}, nil
+}
+
+func (rr *TuzilastvoRepo) OtvoriPrilog(ctx context.Context, id primitive.ObjectID) (*gridfs.DownloadStream, error) {
+	bucket, err := rr.bucketPriloga()
+	if err != nil {
+		return nil, err
+	}
+	if rok, ok := ctx.Deadline(); ok {
+		bucket.SetReadDeadline(rok)
+	}
+	return bucket.OpenDownloadStream(id)
+}
+
+func (rr *TuzilastvoRepo) ObrisiPrilog(id primitive.ObjectID) error {
+	bucket, err := rr.bucketPriloga()
+	if err != nil {
+		return err
+	}
+	return bucket.Delete(id)
+}
+
+// sledeciBrojPredmeta dodeljuje redni broj predmeta u okviru godine, npr. KT-12/2024
+func (rr *TuzilastvoRepo) sledeciBrojPredmeta(ctx context.Context, godina int) (string, error) {
+	filter := bson.D{{Key: "_id", Value: fmt.Sprintf("predmet-%d", godina)}}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "vrednost", Value: 1}}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var brojac struct {
+		Vrednost int `bson:"vrednost"`
+	}
+	err := rr.tabela.Collection(COLLECTIONBROJAC).FindOneAndUpdate(ctx, filter, update, opts).Decode(&brojac)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("KT-%d/%d", brojac.Vrednost, godina), nil
+}
+
+func (rr *TuzilastvoRepo) DodajPredmet(ctx context.Context, predmet *Predmet) error {
+	broj, err := rr.sledeciBrojPredmeta(ctx, predmet.Datum.Time().Year())
+	if err != nil {
+		log.Println("Greska prilikom dodele broja predmeta")
+		return err
+	}
+	predmet.Broj = broj
+
+	_, err = rr.tabela.Collection(COLLECTIONPREDMET).InsertOne(ctx, predmet)
+	if err != nil {
+		log.Println("Greska prilikom dodavanja predmeta")
+		return err
+	}
+	return nil
+}
+
+// KreirajIndeksePredmeta obezbedjuje da za jednu krivicnu prijavu postoji samo jedan predmet. Predmeti kreirani
+// za istu prijavu pre uvodjenja indeksa se prvo spajaju u jedan.
+func (rr *TuzilastvoRepo) KreirajIndeksePredmeta(ctx context.Context) error {
+	if err := rr.spojiDuplePredmete(ctx); err != nil {
+		return err
+	}
+	_, err := rr.tabela.Collection(COLLECTIONPREDMET).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "krivicnaPrijava._id", Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(
+			bson.D{{Key: "krivicnaPrijava._id", Value: bson.D{{Key: "$exists", Value: true}}}},
+		),
+	})
+	return err
+}
+
+// spojiDuplePredmete zadrzava jedan predmet po krivicnoj prijavi, a zahteve, kanale i obavestenja ostalih
+// prevezuje na njega. Dokaz ne moze da se preveze jer lanac cuvanja potvrdjuje predmet dokaza, pa se zadrzava
+// predmet sa dokazima, a ako dokaze ima vise predmeta iste prijave oni se moraju spojiti rucno.
+func (rr *TuzilastvoRepo) spojiDuplePredmete(ctx context.Context) error {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "krivicnaPrijava._id", Value: bson.D{{Key: "$exists", Value: true}}}}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$krivicnaPrijava._id"},
+			{Key: "predmeti", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+		}}},
+		bson.D{{Key: "$match", Value: bson.D{{Key: "predmeti.1", Value: bson.D{{Key: "$exists", Value: true}}}}}},
+	}
+	cursor, err := rr.tabela.Collection(COLLECTIONPREDMET).Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	var grupe []struct {
+		IdPrijave primitive.ObjectID   `bson:"_id"`
+		Predmeti  []primitive.ObjectID `bson:"predmeti"`
+	}
+	if err := cursor.All(ctx, &grupe); err != nil {
+		return err
+	}
+
+	for _, grupa := range grupe {
+		saDokazima, err := rr.tabela.Collection(COLLECTIONDOKAZ).Distinct(ctx, "idPredmeta",
+			bson.D{{Key: "idPredmeta", Value: bson.D{{Key: "$in", Value: grupa.Predmeti}}}})
+		if err != nil {
+			return err
+		}
+		if len(saDokazima) > 1 {
+			return fmt.Errorf("za krivicnu prijavu %s postoji %d predmeta sa dokazima, predmete je potrebno spojiti rucno",
+				grupa.IdPrijave.Hex(), len(saDokazima))
+		}
+
+		zadrzan := grupa.Predmeti[0]
+		if len(saDokazima) == 1 {
+			zadrzan = saDokazima[0].(primitive.ObjectID)
+		}
+		duplikati := []primitive.ObjectID{}
+		for _, id := range grupa.Predmeti {
+			if id != zadrzan {
+				duplikati = append(duplikati, id)
+			}
+		}
+
+		err = rr.uTransakciji(ctx, func(ctx mongo.SessionContext) error {
+			return rr.preveziNaPredmet(ctx, zadrzan, duplikati)
+		})
+		if err != nil {
+			return err
+		}
+		rr.logger.Printf("Predmet %s zadrzan za krivicnu prijavu %s, spojeno duplikata: %d\n", zadrzan.Hex(), grupa.IdPrijave.Hex(), len(duplikati))
+	}
+	return nil
+}
+
+func (rr *TuzilastvoRepo) preveziNaPredmet(ctx context.Context, zadrzan primitive.ObjectID, duplikati []primitive.ObjectID) error {
+	uDuplikatima := bson.D{{Key: "$in", Value: duplikati}}
+	for _, kolekcija := range []string{COLLECTIONZAHTEVZASUDSKIPOSTUPAK, COLLECTIONZAHTEVZASKLAPANJESPORAZUMA, COLLECTIONKANAL, COLLECTIONOBAVESTENJE} {
+		_, err := rr.tabela.Collection(kolekcija).UpdateMany(ctx,
+			bson.D{{Key: "predmetId", Value: uDuplikatima}},
+			bson.D{{Key: "$set", Value: bson.D{{Key: "predmetId", Value: zadrzan}}}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	cursor, err := rr.tabela.Collection(COLLECTIONPREDMET).Find(ctx, bson.D{{Key: "_id", Value: uDuplikatima}})
+	if err != nil {
+		return err
+	}
+	var predmeti []bson.M
+	if err := cursor.All(ctx, &predmeti); err != nil {
+		return err
+	}
+	dokumenti := make([]interface{}, 0, len(predmeti))
+	for _, predmet := range predmeti {
+		predmet["spojenU"] = zadrzan
+		dokumenti = append(dokumenti, predmet)
+	}
+	if len(dokumenti) > 0 {
+		if _, err := rr.tabela.Collection(COLLECTIONDUPLIKATPREDMETA).InsertMany(ctx, dokumenti); err != nil {
+			return err
+		}
+	}
+	_, err = rr.tabela.Collection(COLLECTIONPREDMET).DeleteMany(ctx, bson.D{{Key: "_id", Value: uDuplikatima}})
+	return err
+}
+
+func (rr *TuzilastvoRepo) DobaviPredmet(ctx context.Context, id primitive.ObjectID) (*Predmet, error) {
+	filter := bson.D{{Key: "_id", Value: id}}
+	var predmet Predmet
+
+	err := rr.tabela.Collection(COLLECTIONPREDMET).FindOne(ctx, filter).Decode(&predmet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &predmet, nil
+}
+
+func (rr *TuzilastvoRepo) DobaviPredmetPoPrijavi(ctx context.Context, prijavaId primitive.ObjectID) (*Predmet, error) {
+	filter := bson.D{{Key: "krivicnaPrijava._id", Value: prijavaId}}
+	var predmet Predmet
+
+	err := rr.tabela.Collection(COLLECTIONPREDMET).FindOne(ctx, filter).Decode(&predmet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &predmet, nil
+}
+
+// DobaviPredmetePoKorisniku vraca predmete kojima je korisnik dodeljen kao tuzilac ili istrazitelj
+func (rr *TuzilastvoRepo) DobaviPredmetePoKorisniku(ctx context.Context, id primitive.ObjectID) (Predmeti, error) {
+	filter := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "idTuzioca", Value: id}},
+		bson.D{{Key: "idIstrazitelja", Value: id}},
+	}}}
+	opts := options.Find().SetSort(bson.D{{Key: "datum", Value: -1}})
+
+	cursor, err := rr.tabela.Collection(COLLECTIONPREDMET).Find(ctx, filter, opts)
+	if err != nil {
+		log.Println("Ne postoje predmeti za datog korisnika")
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	predmeti := Predmeti{}
+	if err := cursor.All(ctx, &predmeti); err != nil {
+		return nil, err
+	}
+	return predmeti, nil
+}
+
+func (rr *TuzilastvoRepo) AzurirajPredmet(ctx context.Context, id primitive.ObjectID, izmena IzmenaPredmeta) (*Predmet, error) {
+	filter := bson.D{{Key: "_id", Value: id}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "naziv", Value: izmena.Naziv},
+		{Key: "opis", Value: izmena.Opis},
+		{Key: "idIstrazitelja", Value: izmena.IdIstrazitelja},
+		{Key: "rokovi", Value: izmena.Rokovi},
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var predmet Predmet
+	err := rr.tabela.Collection(COLLECTIONPREDMET).FindOneAndUpdate(ctx, filter, update, opts).Decode(&predmet)
+	if err != nil {
+		return nil, err
+	}
+	return &predmet, nil
+}
+
+func (rr *TuzilastvoRepo) DodajBeleskuPredmetu(ctx context.Context, id primitive.ObjectID, beleska Beleska) error {
+	filter := bson.D{{Key: "_id", Value: id}}
+	update := bson.D{{Key: "$push", Value: bson.D{{Key: "beleske", Value: beleska}}}}
+
+	rezultat, err := rr.tabela.Collection(COLLECTIONPREDMET).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// PromeniStatusPredmeta menja status samo ako predmet jos uvek ima ocekivani status iz
+func (rr *TuzilastvoRepo) PromeniStatusPredmeta(ctx context.Context, id primitive.ObjectID, iz StatusPredmeta, promena PromenaStatusaPredmeta) error {
+	filter := bson.D{{Key: "_id", Value: id}, {Key: "status", Value: iz}}
+	set := bson.D{{Key: "status", Value: promena.Status}}
+	if promena.Odluka != "" {
+		set = append(set, bson.E{Key: "odluka", Value: promena.Odluka})
+	}
+	update := bson.D{
+		{Key: "$set", Value: set},
+		{Key: "$push", Value: bson.D{{Key: "istorijaStatusa", Value: promena}}},
+	}
+
+	rezultat, err := rr.tabela.Collection(COLLECTIONPREDMET).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if rezultat.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// PoveziZahteveSaPredmetom oznacava postojece zahteve za prijavu predmetom koji je za nju otvoren
+func (rr *TuzilastvoRepo) PoveziZahteveSaPredmetom(ctx context.Context, prijavaId primitive.ObjectID, predmetId primitive.ObjectID) error {
+	filter := bson.D{{Key: "krivicnaPrijava._id", Value: prijavaId}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "predmetId", Value: predmetId}}}}
+
+	if _, err := rr.tabela.Collection(COLLECTIONZAHTEVZASUDSKIPOSTUPAK).UpdateMany(ctx, filter, update); err != nil {
+		return err
+	}
+	if _, err := rr.tabela.Collection(COLLECTIONZAHTEVZASKLAPANJESPORAZUMA).UpdateMany(ctx, filter, update); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (rr *TuzilastvoRepo) DobaviZahteveZaSudskiPostupakPoPredmetu(ctx context.Context, predmetId primitive.ObjectID) (ZahteviZaSudskiPostupak, error) {
+	filter := bson.D{{Key: "predmetId", Value: predmetId}}
+	return rr.filterZahteviZaSudskiPostupak(ctx, filter)
+}
+
+func (rr *TuzilastvoRepo) DobaviZahteveZaSklapanjeSporazumaPoPredmetu(ctx context.Context, predmetId primitive.ObjectID) (ZahteviZaSklapanjeSporazuma, error) {
+	filter := bson.D{{Key: "predmetId", Value: predmetId}}
+	cursor, err := rr.tabela.Collection(COLLECTIONZAHTEVZASKLAPANJESPORAZUMA).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var zahtevi ZahteviZaSklapanjeSporazuma
+	if err := cursor.All(ctx, &zahtevi); err != nil {
+		return nil, err
+	}
+	return zahtevi, nil
+}
+
+func (rr *TuzilastvoRepo) DobaviKanalePoPredmetu(ctx context.Context, predmetId primitive.ObjectID) (Kanali, error) {
+	filter := bson.D{{Key: "predmetId", Value: predmetId}}
+	cursor, err := rr.tabela.Collection(COLLECTIONKANAL).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var kanali Kanali
+	if err := cursor.All(ctx, &kanali); err != nil {
+		return nil, err
+	}
+	return kanali, nil
+}
+
+// KreirajIndekseDokaza obezbedjuje da u lancu cuvanja jednog dokaza ne postoje dva unosa sa istim rednim brojem,
+// pa od dve istovremene predaje istog dokaza uspeva samo jedna
+func (rr *TuzilastvoRepo) KreirajIndekseDokaza(ctx context.Context) error {
+	indeksi := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "idDokaza", Value: 1}, {Key: "redni", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	_, err := rr.tabela.Collection(COLLECTIONLANACDOKAZA).Indexes().CreateMany(ctx, indeksi)
+	return err
+}
+
+// EvidentirajDokaz upisuje prvi unos lanca i dokaz u istoj transakciji, pa dokaz nikad ne postoji bez lanca
+func (rr *TuzilastvoRepo) EvidentirajDokaz(ctx context.Context, dokaz *Dokaz, prviUnos *UnosLancaDokaza) error {
+	return rr.uTransakciji(ctx, func(ctx mongo.SessionContext) error {
+		_, err := rr.tabela.Collection(COLLECTIONLANACDOKAZA).InsertOne(ctx, prviUnos)
+		if err != nil {
+			log.Println("Greska prilikom upisa prvog unosa lanca dokaza")
+			return err
+		}
+
+		_, err = rr.tabela.Collection(COLLECTIONDOKAZ).InsertOne(ctx, dokaz)
+		if err != nil {
+			log.Println("Greska prilikom evidentiranja dokaza")
+			return err
+		}
+		return nil
+	})
+}
+
+// uTransakciji izvrsava upise u jednoj transakciji, baza mora biti pokrenuta kao replika set
+func (rr *TuzilastvoRepo) uTransakciji(ctx context.Context, upisi func(ctx mongo.SessionContext) error) error {
+	sesija, err := rr.cli.StartSession()
+	if err != nil {
+		return err
+	}
+	defer sesija.EndSession(ctx)
+
+	_, err = sesija.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		return nil, upisi(ctx)
+	})
+	return err
+}
+
+func (rr *TuzilastvoRepo) DobaviDokaz(ctx context.Context, id primitive.ObjectID) (*Dokaz, error) {
+	filter := bson.D{{Key: "_id", Value: id}}
+	var dokaz Dokaz
+
+	err := rr.tabela.Collection(COLLECTIONDOKAZ).FindOne(ctx, filter).Decode(&dokaz)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dokaz, nil
+}
+
+// DobaviDokazePredmeta vraca dokaze vezane za predmet ili za krivicnu prijavu predmeta
+func (rr *TuzilastvoRepo) DobaviDokazePredmeta(ctx context.Context, predmet *Predmet) (Dokazi, error) {
+	uslovi := bson.A{bson.D{{Key: "idPredmeta", Value: predmet.ID}}}
+	if !predmet.KrivicnaPrijava.ID.IsZero() {
+		uslovi = append(uslovi, bson.D{{Key: "idPrijave", Value: predmet.KrivicnaPrijava.ID}})
+	}
+	filter := bson.D{{Key: "$or", Value: uslovi}}
+	opts := options.Find().SetSort(bson.D{{Key: "evidentiran", Value: 1}})
+
+	cursor, err := rr.tabela.Collection(COLLECTIONDOKAZ).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	dokazi := Dokazi{}
+	err = cursor.All(ctx, &dokazi)
+	return dokazi, err
+}
+
+// DobaviLanacDokaza vraca sve unose lanca cuvanja po rednom broju
+func (rr *TuzilastvoRepo) DobaviLanacDokaza(ctx context.Context, idDokaza primitive.ObjectID) ([]*UnosLancaDokaza, error) {
+	filter := bson.D{{Key: "idDokaza", Value: idDokaza}}
+	opts := options.Find().SetSort(bson.D{{Key: "redni", Value: 1}})
+
+	cursor, err := rr.tabela.Collection(COLLECTIONLANACDOKAZA).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	lanac := []*UnosLancaDokaza{}
+	err = cursor.All(ctx, &lanac)
+	return lanac, err
+}
+
+func (rr *TuzilastvoRepo) DobaviPrviUnosLanca(ctx context.Context, idDokaza primitive.ObjectID) (*UnosLancaDokaza, error) {
+	filter := bson.D{{Key: "idDokaza", Value: idDokaza}}
+	opts := options.FindOne().SetSort(bson.D{{Key: "redni", Value: 1}})
+	var unos UnosLancaDokaza
+
+	err := rr.tabela.Collection(COLLECTIONLANACDOKAZA).FindOne(ctx, filter, opts).Decode(&unos)
+	if err != nil {
+		return nil, err
+	}
+
+	return &unos, nil
+}
+
+func (rr *TuzilastvoRepo) DobaviPoslednjiUnosLanca(ctx context.Context, idDokaza primitive.ObjectID) (*UnosLancaDokaza, error) {
+	filter := bson.D{{Key: "idDokaza", Value: idDokaza}}
+	opts := options.FindOne().SetSort(bson.D{{Key: "redni", Value: -1}})
+	var unos UnosLancaDokaza
+
+	err := rr.tabela.Collection(COLLECTIONLANACDOKAZA).FindOne(ctx, filter, opts).Decode(&unos)
+	if err != nil {
+		return nil, err
+	}
+
+	return &unos, nil
+}
+
+// DodajUnosLanca nadovezuje unos na lanac i azurira stanje dokaza u istoj transakciji. Ako je drugi unos sa istim
+// rednim brojem vec upisan vraca gresku duplikata kljuca. Stanje dokaza se ne vraca na stariji unos ako ga je noviji vec azurirao.
+func (rr *TuzilastvoRepo) DodajUnosLanca(ctx context.Context, unos *UnosLancaDokaza) error {
+	return rr.uTransakciji(ctx, func(ctx mongo.SessionContext) error {
+		_, err := rr.tabela.Collection(COLLECTIONLANACDOKAZA).InsertOne(ctx, unos)
+		if err != nil {
+			return err
+		}
+
+		filter := bson.D{
+			{Key: "_id", Value: unos.IdDokaza},
+			{Key: "brojUnosa", Value: bson.D{{Key: "$lte", Value: unos.Redni}}},
+		}
+		update := bson.D{{Key: "$set", Value: bson.D{
+			{Key: "brojUnosa", Value: unos.Redni + 1},
+			{Key: "poslednjiHes", Value: unos.Hes},
+			{Key: "cuvar", Value: unos.Primio},
+			{Key: "lokacija", Value: unos.Lokacija},
+		}}}
+		_, err = rr.tabela.Collection(COLLECTIONDOKAZ).UpdateOne(ctx, filter, update)
+		return err
+	})
+}
+
+// PotpisiLanceDokaza potpisuje kljucem lanca unose upisane pre uvodjenja kljuca. Potpisuju se samo lanci koji su
+// i dalje ispravni, a izmenjeni lanci ostaju nepotpisani pa ih provera lanca prijavljuje.
+func (rr *TuzilastvoRepo) PotpisiLanceDokaza(ctx context.Context) error {
+	filter := bson.D{{Key: "algoritam", Value: bson.D{{Key: "$exists", Value: false}}}}
+	idDokaza, err := rr.tabela.Collection(COLLECTIONLANACDOKAZA).Distinct(ctx, "idDokaza", filter)
+	if err != nil {
+		return err
+	}
+
+	for _, vrednost := range idDokaza {
+		id, ok := vrednost.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		lanac, err := rr.DobaviLanacDokaza(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !PotpisiStariLanac(lanac) {
+			rr.logger.Printf("Lanac dokaza %s nije ispravan i nije potpisan", id.Hex())
+			continue
+		}
+
+		err = rr.uTransakciji(ctx, func(ctx mongo.SessionContext) error {
+			for _, unos := range lanac {
+				update := bson.D{{Key: "$set", Value: bson.D{
+					{Key: "prethodniHes", Value: unos.PrethodniHes},
+					{Key: "hes", Value: unos.Hes},
+					{Key: "algoritam", Value: unos.Algoritam},
+				}}}
+				if _, err := rr.tabela.Collection(COLLECTIONLANACDOKAZA).UpdateOne(ctx, bson.D{{Key: "_id", Value: unos.ID}}, update); err != nil {
+					return err
+				}
+			}
+			poslednji := lanac[len(lanac)-1]
+			_, err := rr.tabela.Collection(COLLECTIONDOKAZ).UpdateOne(ctx,
+				bson.D{{Key: "_id", Value: id}, {Key: "brojUnosa", Value: len(lanac)}},
+				bson.D{{Key: "$set", Value: bson.D{{Key: "poslednjiHes", Value: poslednji.Hes}}}})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DobaviAktivnePredmete vraca sve predmete koji nisu zatvoreni
+func (rr *TuzilastvoRepo) DobaviAktivnePredmete(ctx context.Context) (Predmeti, error) {
+	filter := bson.D{{Key: "status", Value: bson.D{{Key: "$ne", Value: PREDMET_ZATVOREN}}}}
+	cursor, err := rr.tabela.Collection(COLLECTIONPREDMET).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	predmeti := Predmeti{}
+	err = cursor.All(ctx, &predmeti)
+	return predmeti, err
+}
+
+// KreirajIndeksePromenaStatusa obezbedjuje da se ista promena statusa prijave cuva samo jednom
+func (rr *TuzilastvoRepo) KreirajIndeksePromenaStatusa(ctx context.Context) error {
+	indeksi := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "idPrijave", Value: 1}, {Key: "status", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "sledeciPokusaj", Value: 1}},
+		},
+	}
+	_, err := rr.tabela.Collection(COLLECTIONPROMENASTATUSAPRIJAVE).Indexes().CreateMany(ctx, indeksi)
+	return err
+}
+
+// ZapamtiPromenuStatusa upisuje promenu statusa prijave pre slanja, a za vec zapamcenu promenu vraca postojecu
+func (rr *TuzilastvoRepo) ZapamtiPromenuStatusa(ctx context.Context, promena *NeposlataPromenaStatusa) error {
+	filter := bson.D{{Key: "idPrijave", Value: promena.IdPrijave}, {Key: "status", Value: promena.Status}}
+	update := bson.D{{Key: "$setOnInsert", Value: bson.D{
+		{Key: "izvor", Value: promena.Izvor},
+		{Key: "napomena", Value: promena.Napomena},
+		{Key: "pokusajiSlanja", Value: 0},
+		{Key: "sledeciPokusaj", Value: promena.SledeciPokusaj},
+		{Key: "kreirano", Value: promena.Kreirano},
+	}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	err := rr.tabela.Collection(COLLECTIONPROMENASTATUSAPRIJAVE).FindOneAndUpdate(ctx, filter, update, opts).Decode(promena)
+	if mongo.IsDuplicateKeyError(err) {
+		return rr.tabela.Collection(COLLECTIONPROMENASTATUSAPRIJAVE).FindOne(ctx, filter).Decode(promena)
+	}
+	return err
+}
+
+// DobaviPromeneStatusaZaSlanje vraca neposlate promene statusa kojima je doslo vreme za sledeci pokusaj
+func (rr *TuzilastvoRepo) DobaviPromeneStatusaZaSlanje(ctx context.Context, sada time.Time) ([]*NeposlataPromenaStatusa, error) {
+	filter := bson.D{{Key: "sledeciPokusaj", Value: bson.D{{Key: "$lte", Value: primitive.NewDateTimeFromTime(sada)}}}}
+	opts := options.Find().SetSort(bson.D{{Key: "sledeciPokusaj", Value: 1}}).SetLimit(100)
+	cursor, err := rr.tabela.Collection(COLLECTIONPROMENASTATUSAPRIJAVE).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	promene := []*NeposlataPromenaStatusa{}
+	err = cursor.All(ctx, &promene)
+	return promene, err
+}
+
+// PreuzmiSlanjePromeneStatusa atomski pomera sledeci pokusaj za TrajanjePreuzimanjaSlanja, pa promenu u isto vreme
+// salje samo jedna instanca servisa. Vraca false ako je slanje vec preuzeto ili zavrseno.
+func (rr *TuzilastvoRepo) PreuzmiSlanjePromeneStatusa(ctx context.Context, id primitive.ObjectID, sada time.Time) (bool, error) {
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "sledeciPokusaj", Value: bson.D{{Key: "$lte", Value: primitive.NewDateTimeFromTime(sada)}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "sledeciPokusaj", Value: primitive.NewDateTimeFromTime(sada.Add(TrajanjePreuzimanjaSlanja))},
+	}}}
+
+	rezultat, err := rr.tabela.Collection(COLLECTIONPROMENASTATUSAPRIJAVE).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return rezultat.ModifiedCount == 1, nil
+}
+
+func (rr *TuzilastvoRepo) ZakaziPonovnoSlanjePromeneStatusa(ctx context.Context, id primitive.ObjectID, pokusaji int, sledeciPokusaj time.Time, greska string) error {
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "pokusajiSlanja", Value: pokusaji},
+		{Key: "sledeciPokusaj", Value: primitive.NewDateTimeFromTime(sledeciPokusaj)},
+		{Key: "greskaSlanja", Value: greska},
+	}}}
+
+	_, err := rr.tabela.Collection(COLLECTIONPROMENASTATUSAPRIJAVE).UpdateOne(ctx, bson.D{{Key: "_id", Value: id}}, update)
+	return err
+}
+
+func (rr *TuzilastvoRepo) ObrisiPromenuStatusa(ctx context.Context, id primitive.ObjectID) error {
+	_, err := rr.tabela.Collection(COLLECTIONPROMENASTATUSAPRIJAVE).DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	return err
+}
@@ -6,6 +6,9 @@ require (
 	github.com/casbin/casbin v1.9.1
 	github.com/cristalhq/jwt/v4 v4.0.2
 	github.com/gorilla/mux v1.8.0
+	github.com/sony/gobreaker v0.5.0
+	prijave v0.0.0
+	rokovi v0.0.0
 	go.mongodb.org/mongo-driver v1.13.0
 	go.opentelemetry.io/otel v1.11.2
 	go.opentelemetry.io/otel/exporters/jaeger v1.11.2
@@ -28,5 +31,9 @@ require (
 	golang.org/x/sync v0.0.0-20220722155255-886fb9371eb4 // indirect
 	golang.org/x/sys v0.16.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
+)
 
+replace (
+	prijave => ../prijave
+	rokovi => ../rokovi
 )
@@ -3,17 +3,27 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+	"io"
 	"io/ioutil"
 	"log"
+	"mime"
 	"net/http"
 	"os"
+	"prijave"
+	"rokovi"
+	"strconv"
+	"strings"
 	"time"
+	"tuzilastvo_service/client"
 	"tuzilastvo_service/data"
+	"tuzilastvo_service/domain"
 	"tuzilastvo_service/helper"
 )
 
@@ -27,13 +37,18 @@ var (
 type KeyProduct struct{}
 
 type TuzilastvoHandler struct {
-	logger         *log.Logger
-	tuzilastvoRepo *data.TuzilastvoRepo
-	tracer         trace.Tracer
+	logger                 *log.Logger
+	tuzilastvoRepo         *data.TuzilastvoRepo
+	tracer                 trace.Tracer
+	granicnaPolicijaClient client.GranicnaPolicijaClient
+	mupClient              client.MupClient
+	sudClient              client.SudClient
+	authClient             client.AuthClient
+	razglasPoruka          *data.RazglasPoruka
 }
 
-func NewTuzilastvoHandler(l *log.Logger, r *data.TuzilastvoRepo, t trace.Tracer) *TuzilastvoHandler {
-	return &TuzilastvoHandler{l, r, t}
+func NewTuzilastvoHandler(l *log.Logger, r *data.TuzilastvoRepo, t trace.Tracer, gc client.GranicnaPolicijaClient, mc client.MupClient, sc client.SudClient, ac client.AuthClient, rp *data.RazglasPoruka) *TuzilastvoHandler {
+	return &TuzilastvoHandler{l, r, t, gc, mc, sc, ac, rp}
 }
 
 func (h *TuzilastvoHandler) KreirajZahtevZaSudskiPostupak(writer http.ResponseWriter, req *http.Request) {
@@ -65,10 +80,10 @@ func (h *TuzilastvoHandler) KreirajZahtevZaSudskiPostupak(writer http.ResponseWr
 		return
 	}
 
-	prijava, err := h.DobaviKrivicnuPrijavuByID(prijavaId.Hex())
+	prijava, err := h.DobaviKrivicnuPrijavuByID(ctx, prijavaId.Hex(), req.Header.Get("Authorization"))
 	if err != nil {
 		span.SetStatus(codes.Error, "Greska prilikom dobavljanja krivicne prijave po id")
-		writer.WriteHeader(http.StatusNotFound)
+		writer.WriteHeader(statusGreskePrijave(err))
 		writer.Write([]byte("Greska prilikom dobavljanja krivicne prijave po id"))
 		return
 	}
@@ -96,6 +111,19 @@ func (h *TuzilastvoHandler) KreirajZahtevZaSudskiPostupak(writer http.ResponseWr
 
 	zahtev.IdTuzioca = logovaniKorisnikId
 	zahtev.KrivicnaPrijava = *prijava
+	zahtev.Status = data.ZAHTEV_AKTIVAN
+	zahtev.Prijem = nil
+
+	predmet, err := h.predmetPrijave(ctx, prijavaId)
+	if err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom dobavljanja predmeta prijave"))
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja predmeta prijave")
+		return
+	}
+	if predmet != nil {
+		zahtev.PredmetId = predmet.ID
+	}
 
 	err = h.tuzilastvoRepo.DodajZahtevZaSudskiPostupak(ctx, &zahtev)
 	if err != nil {
@@ -105,6 +133,19 @@ func (h *TuzilastvoHandler) KreirajZahtevZaSudskiPostupak(writer http.ResponseWr
 		return
 	}
 
+	if predmet != nil && data.DozvoljenaPromenaStatusaPredmeta(predmet.Status, data.PREDMET_KOD_SUDA) {
+		err = h.tuzilastvoRepo.PromeniStatusPredmeta(ctx, predmet.ID, predmet.Status, data.PromenaStatusaPredmeta{
+			Status:   data.PREDMET_KOD_SUDA,
+			Datum:    zahtev.Datum,
+			Napomena: "Kreiran zahtev za sudski postupak",
+		})
+		if err != nil {
+			log.Println("Greska prilikom promene statusa predmeta:", err)
+		}
+	}
+
+	h.pokreniPostupakPoPrijavi(ctx, prijava, "Kreiran zahtev za sudski postupak")
+
 	message := "Zahtev za sudski postupak je uspešno kreiran"
 	// Encode and send JSON response
 	writer.Header().Set("Content-Type", "application/json")
@@ -121,7 +162,13 @@ func (h *TuzilastvoHandler) DobaviZahteveZaSudskiPostupak(rw http.ResponseWriter
 	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviZahteveZaSudskiPostupak")
 	defer span.End()
 
-	zahtevi, err := h.tuzilastvoRepo.DobaviZahteveZaSudskiPostupak(ctx)
+	var zahtevi data.ZahteviZaSudskiPostupak
+	var err error
+	if r.URL.Query().Get("aktivni") == "true" {
+		zahtevi, err = h.tuzilastvoRepo.DobaviAktivneZahteveZaSudskiPostupak(ctx)
+	} else {
+		zahtevi, err = h.tuzilastvoRepo.DobaviZahteveZaSudskiPostupak(ctx)
+	}
 	if err != nil {
 		rw.WriteHeader(http.StatusBadRequest)
 		rw.Write([]byte("Greska"))
@@ -140,6 +187,56 @@ func (h *TuzilastvoHandler) DobaviZahteveZaSudskiPostupak(rw http.ResponseWriter
 	}
 }
 
+// PrijemZahtevaZaSudskiPostupak belezi da je sud po zahtevu otvorio predmet, primljen zahtev vise nije aktivan.
+// Sud ponavlja potvrdu dok ne uspe, pa ponovljena potvrda za isti predmet nije greska.
+func (h *TuzilastvoHandler) PrijemZahtevaZaSudskiPostupak(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.PrijemZahtevaZaSudskiPostupak")
+	defer span.End()
+
+	zahtevId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id zahteva nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id zahteva nije procitan"))
+		return
+	}
+
+	var prijem data.PrijemZahteva
+	if err := json.NewDecoder(req.Body).Decode(&prijem); err != nil || prijem.IdPredmetaSuda.IsZero() {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if prijem.Datum == 0 {
+		prijem.Datum = primitive.NewDateTimeFromTime(time.Now())
+	}
+
+	evidentiran, err := h.tuzilastvoRepo.OznaciZahtevPrimljenim(ctx, zahtevId, prijem)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			span.SetStatus(codes.Error, "Zahtev sa prosledjenim id ne postoji")
+			writer.WriteHeader(http.StatusNotFound)
+			writer.Write([]byte("Zahtev sa prosledjenim id ne postoji"))
+			return
+		}
+		span.SetStatus(codes.Error, "Greska prilikom evidentiranja prijema zahteva")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom evidentiranja prijema zahteva"))
+		return
+	}
+	if !evidentiran {
+		span.SetStatus(codes.Error, "Prijem zahteva je vec potvrdjen za drugi predmet suda")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Prijem zahteva je vec potvrdjen za drugi predmet suda"))
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(map[string]string{"message": "Prijem zahteva za sudski postupak je evidentiran"})
+}
+
 func (h *TuzilastvoHandler) KreirajZahtevZaSklapanjeSporazuma(writer http.ResponseWriter, req *http.Request) {
 	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.KreirajZahtevZaSklapanjeSporazuma")
 	defer span.End()
@@ -169,10 +266,10 @@ func (h *TuzilastvoHandler) KreirajZahtevZaSklapanjeSporazuma(writer http.Respon
 		return
 	}
 
-	prijava, err := h.DobaviKrivicnuPrijavuByID(prijavaId.Hex())
+	prijava, err := h.DobaviKrivicnuPrijavuByID(ctx, prijavaId.Hex(), req.Header.Get("Authorization"))
 	if err != nil {
 		span.SetStatus(codes.Error, "Greska prilikom dobavljanja krivicne prijave po id")
-		writer.WriteHeader(http.StatusNotFound)
+		writer.WriteHeader(statusGreskePrijave(err))
 		writer.Write([]byte("Greska prilikom dobavljanja krivicne prijave po id"))
 		return
 	}
@@ -198,9 +295,42 @@ func (h *TuzilastvoHandler) KreirajZahtevZaSklapanjeSporazuma(writer http.Respon
 		return
 	}
 
+	sada := time.Now()
+	istek, ok := data.IstekPocetnePonude(zahtev.IstekPonude, sada)
+	if !ok {
+		span.SetStatus(codes.Error, "Rok vazenja ponude je u proslosti")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Rok vazenja ponude je u proslosti"))
+		return
+	}
+	zahtev.IstekPonude = istek
+
 	zahtev.IdTuzioca = logovaniKorisnikId
 	zahtev.KrivicnaPrijava = *prijava
 	zahtev.Prihvacen = false
+	zahtev.Status = data.ZAHTEV_NA_CEKANJU
+	zahtev.RazlogOdbijanja = ""
+	zahtev.DatumOdluke = 0
+	zahtev.TrenutnaVerzija = 1
+	zahtev.Verzije = []data.VerzijaPonude{{
+		Broj:         1,
+		Uslovi:       zahtev.Uslovi,
+		Kazna:        zahtev.Kazna,
+		Predlagac:    data.STRANA_TUZILAC,
+		IdPredlagaca: logovaniKorisnikId,
+		Datum:        zahtev.Datum,
+		IstekPonude:  zahtev.IstekPonude,
+	}}
+	predmet, err := h.predmetPrijave(ctx, prijavaId)
+	if err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom dobavljanja predmeta prijave"))
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja predmeta prijave")
+		return
+	}
+	if predmet != nil {
+		zahtev.PredmetId = predmet.ID
+	}
 
 	err = h.tuzilastvoRepo.DodajZahtevZaSklapanjeSporazuma(ctx, &zahtev)
 	if err != nil {
@@ -210,6 +340,8 @@ func (h *TuzilastvoHandler) KreirajZahtevZaSklapanjeSporazuma(writer http.Respon
 		return
 	}
 
+	h.pokreniPostupakPoPrijavi(ctx, prijava, "Kreiran zahtev za sklapanje sporazuma")
+
 	message := "Zahtev za sklapanje sporazuma je uspešno kreiran"
 	// Encode and send JSON response
 	writer.Header().Set("Content-Type", "application/json")
@@ -222,6 +354,117 @@ func (h *TuzilastvoHandler) KreirajZahtevZaSklapanjeSporazuma(writer http.Respon
 
 }
 
+// pokreniPostupakPoPrijavi prevodi krivicnu prijavu u status U_POSTUPKU, prolazeci kroz PRIMLJENA_U_TUZILASTVU
+// ako prijava jos nije primljena. Promena se pamti pre slanja, pa je periodicna obrada ponavlja ako slanje ne uspe.
+func (h *TuzilastvoHandler) pokreniPostupakPoPrijavi(ctx context.Context, prijava *data.KrivicnaPrijava, napomena string) {
+	sada := primitive.NewDateTimeFromTime(time.Now())
+	promena := data.NeposlataPromenaStatusa{
+		IdPrijave:      prijava.ID,
+		Izvor:          prijava.Izvor,
+		Status:         data.U_POSTUPKU,
+		Napomena:       napomena,
+		SledeciPokusaj: sada,
+		Kreirano:       sada,
+	}
+	if err := h.tuzilastvoRepo.ZapamtiPromenuStatusa(ctx, &promena); err != nil {
+		h.logger.Println("Greska prilikom cuvanja promene statusa krivicne prijave", prijava.ID.Hex(), err)
+		return
+	}
+	h.posaljiPromenuStatusa(ctx, &promena, prijava)
+}
+
+// PokreniSlanjePromenaStatusa periodicno ponavlja slanje promena statusa krivicnih prijava koje nisu uspele
+func (h *TuzilastvoHandler) PokreniSlanjePromenaStatusa(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		promene, err := h.tuzilastvoRepo.DobaviPromeneStatusaZaSlanje(ctx, time.Now())
+		if err != nil {
+			h.logger.Println("Greska prilikom dobavljanja promena statusa prijava za slanje:", err)
+		}
+		for _, promena := range promene {
+			h.posaljiPromenuStatusa(ctx, promena, nil)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// posaljiPromenuStatusa primenjuje zapamcenu promenu statusa i brise je posle uspeha, a posle neuspeha zakazuje
+// sledeci pokusaj. Ako prijava nije prosledjena, njen trenutni status se dobavlja od servisa u kome je podneta.
+func (h *TuzilastvoHandler) posaljiPromenuStatusa(ctx context.Context, promena *data.NeposlataPromenaStatusa, prijava *data.KrivicnaPrijava) {
+	preuzeta, err := h.tuzilastvoRepo.PreuzmiSlanjePromeneStatusa(ctx, promena.ID, time.Now())
+	if err != nil {
+		h.logger.Println("Greska prilikom preuzimanja slanja promene statusa prijave", promena.IdPrijave.Hex(), err)
+		return
+	}
+	if !preuzeta {
+		return
+	}
+
+	zahtevCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err = h.primeniPromenuStatusa(zahtevCtx, promena, prijava)
+	if err == nil {
+		if err := h.tuzilastvoRepo.ObrisiPromenuStatusa(ctx, promena.ID); err != nil {
+			h.logger.Println("Greska prilikom brisanja poslate promene statusa prijave", promena.IdPrijave.Hex(), err)
+		}
+		return
+	}
+
+	pokusaji := promena.PokusajiSlanja + 1
+	h.logger.Printf("Promena statusa prijave %s nije poslata (pokusaj %d): %v", promena.IdPrijave.Hex(), pokusaji, err)
+	err = h.tuzilastvoRepo.ZakaziPonovnoSlanjePromeneStatusa(ctx, promena.ID, pokusaji, data.SledeciPokusajSlanja(pokusaji, time.Now()), err.Error())
+	if err != nil {
+		h.logger.Println("Greska prilikom zakazivanja ponovnog slanja promene statusa prijave", promena.IdPrijave.Hex(), err)
+	}
+}
+
+// primeniPromenuStatusa prevodi prijavu kroz sve statuse do ciljnog, napomena se upisuje uz poslednji korak.
+// Prijava koja je vec u ciljnom statusu ili u njega vise ne moze da stigne se ne menja.
+func (h *TuzilastvoHandler) primeniPromenuStatusa(ctx context.Context, promena *data.NeposlataPromenaStatusa, prijava *data.KrivicnaPrijava) error {
+	bearer, err := helper.ServisniToken()
+	if err != nil {
+		return err
+	}
+	if prijava == nil {
+		prijava, err = h.DobaviKrivicnuPrijavuByID(ctx, promena.IdPrijave.Hex(), bearer)
+		if err != nil {
+			return err
+		}
+	}
+	trenutni := prijava.Status
+	if trenutni == "" {
+		trenutni = data.PODNETA
+	}
+
+	koraci := prijave.KoraciDoStatusa(trenutni, promena.Status)
+	for i, status := range koraci {
+		korak := data.PromenaStatusa{Status: status}
+		if i == len(koraci)-1 {
+			korak.Napomena = promena.Napomena
+		}
+		if err := h.promeniStatusPrijave(ctx, promena.Izvor, promena.IdPrijave, korak, bearer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// promeniStatusPrijave salje promenu statusa servisu u kome je prijava podneta
+func (h *TuzilastvoHandler) promeniStatusPrijave(ctx context.Context, izvor data.IzvorPrijave, id primitive.ObjectID, promena data.PromenaStatusa, bearer string) error {
+	if izvor == data.IZVOR_MUP {
+		return h.mupClient.PromeniStatusPrijave(ctx, id.Hex(), promena, bearer)
+	}
+	return h.granicnaPolicijaClient.PromeniStatusPrijave(ctx, id.Hex(), promena, bearer)
+}
+
 func (h *TuzilastvoHandler) DobaviZahteveZaSklapanjeSporazuma(rw http.ResponseWriter, r *http.Request) {
 	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviZahteveZaSklapanjeSporazuma")
 	defer span.End()
@@ -245,102 +488,251 @@ func (h *TuzilastvoHandler) DobaviZahteveZaSklapanjeSporazuma(rw http.ResponseWr
 	}
 }
 
-func (h *TuzilastvoHandler) KreirajSporazum(prihvaceniZahtev data.ZahtevZaSklapanjeSporazuma) bool {
+// PokreniPotvrduSporazuma vodi sporazume kroz potvrdu u sudu: periodicno ponavlja neuspela slanja
+// i proverava odluke suda koje nisu stigle do tuzilastva, pa se tok zavrsava i posle ispada nekog od servisa
+func (h *TuzilastvoHandler) PokreniPotvrduSporazuma(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		h.obradiPotvrdeSporazuma(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
 
-	novSporazum := data.Sporazum{}
-	novSporazum.ID = primitive.NewObjectID()
-	novSporazum.Datum = primitive.NewDateTimeFromTime(time.Now())
-	novSporazum.Zahtev = prihvaceniZahtev
-	err := h.tuzilastvoRepo.DodajSporazum(context.Background(), &novSporazum)
+func (h *TuzilastvoHandler) obradiPotvrdeSporazuma(ctx context.Context) {
+	zaSlanje, err := h.tuzilastvoRepo.DobaviSporazumeZaSlanje(ctx, time.Now())
 	if err != nil {
-		return false
+		h.logger.Println("Greska prilikom dobavljanja sporazuma za slanje sudu:", err)
+	}
+	for _, sporazum := range zaSlanje {
+		h.posaljiSporazumSudu(ctx, sporazum)
 	}
 
-	return true
+	kodSuda, err := h.tuzilastvoRepo.DobaviSporazumeKodSuda(ctx)
+	if err != nil {
+		h.logger.Println("Greska prilikom dobavljanja sporazuma kod suda:", err)
+	}
+	for _, sporazum := range kodSuda {
+		zahtevCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		predmet, err := h.sudClient.DobaviPredmetPoSporazumu(zahtevCtx, sporazum.ID.Hex())
+		cancel()
+		if err != nil {
+			h.logger.Println("Greska prilikom provere odluke suda o sporazumu", sporazum.ID.Hex(), err)
+			continue
+		}
+		if predmet.Odluka == nil {
+			continue
+		}
+		if err := h.evidentirajOdlukuSuda(ctx, sporazum, *predmet.Odluka); err != nil {
+			h.logger.Println("Greska prilikom evidentiranja odluke suda o sporazumu", sporazum.ID.Hex(), err)
+		}
+	}
 }
 
-func (h *TuzilastvoHandler) DobaviSporazume(rw http.ResponseWriter, r *http.Request) {
-	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviSporazume")
-	defer span.End()
-
-	sporazumi, err := h.tuzilastvoRepo.DobaviSporazume(ctx)
+// posaljiSporazumSudu dostavlja sporazum sudu, a posle neuspeha zakazuje sledeci pokusaj.
+// Sporazum salje samo instanca koja je preuzela slanje.
+func (h *TuzilastvoHandler) posaljiSporazumSudu(ctx context.Context, sporazum *data.Sporazum) {
+	preuzet, err := h.tuzilastvoRepo.PreuzmiSlanjeSporazuma(ctx, sporazum.ID, time.Now())
 	if err != nil {
-		rw.WriteHeader(http.StatusBadRequest)
-		rw.Write([]byte("Greska"))
-		span.SetStatus(codes.Error, "Greska")
+		h.logger.Println("Greska prilikom preuzimanja slanja sporazuma", sporazum.ID.Hex(), err)
+		return
+	}
+	if !preuzet {
+		return
 	}
 
-	if sporazumi == nil {
+	zahtevCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	predmet, err := h.sudClient.PosaljiSporazumNaPotvrdu(zahtevCtx, sporazum.ZaPotvrdu())
+	if err != nil {
+		pokusaji := sporazum.PokusajiSlanja + 1
+		sledeciPokusaj := data.SledeciPokusajSlanja(pokusaji, time.Now())
+		h.logger.Printf("Slanje sporazuma %s sudu nije uspelo (pokusaj %d), sledeci pokusaj u %s: %v", sporazum.ID.Hex(), pokusaji, sledeciPokusaj.Format(time.RFC3339), err)
+
+		err = h.tuzilastvoRepo.ZakaziPonovnoSlanjeSporazuma(ctx, sporazum.ID, pokusaji, sledeciPokusaj, err.Error())
+		if err != nil {
+			h.logger.Println("Greska prilikom zakazivanja ponovnog slanja sporazuma:", err)
+		}
 		return
 	}
 
-	err = sporazumi.ToJSON(rw)
+	err = h.tuzilastvoRepo.OznaciSporazumKodSuda(ctx, sporazum.ID, predmet.ID)
 	if err != nil {
-		rw.WriteHeader(http.StatusBadRequest)
-		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
-		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+		h.logger.Println("Greska prilikom oznacavanja sporazuma poslatim sudu:", err)
+		return
+	}
+	if predmet.Odluka != nil {
+		if err := h.evidentirajOdlukuSuda(ctx, sporazum, *predmet.Odluka); err != nil {
+			h.logger.Println("Greska prilikom evidentiranja odluke suda o sporazumu", sporazum.ID.Hex(), err)
+		}
+	}
+}
+
+// evidentirajOdlukuSuda zavrsava tok potvrde sporazuma. Potvrdjen sporazum zatvara predmet, a za odbijen se
+// po prijavi pokrece redovan sudski postupak. Posledice odluke se izvrsavaju pre upisa odluke i mogu se ponoviti,
+// pa se tok zavrsava i ako upis odluke ne uspe iz prvog pokusaja.
+func (h *TuzilastvoHandler) evidentirajOdlukuSuda(ctx context.Context, sporazum *data.Sporazum, odluka data.OdlukaSuda) error {
+	if odluka.Potvrdjen {
+		predmet, err := h.tuzilastvoRepo.DobaviPredmet(ctx, sporazum.Zahtev.PredmetId)
+		if err == nil && data.DozvoljenaPromenaStatusaPredmeta(predmet.Status, data.PREDMET_ZATVOREN) {
+			err = h.tuzilastvoRepo.PromeniStatusPredmeta(ctx, predmet.ID, predmet.Status, data.PromenaStatusaPredmeta{
+				Status:   data.PREDMET_ZATVOREN,
+				Datum:    primitive.NewDateTimeFromTime(time.Now()),
+				Napomena: "Sud je potvrdio sporazum o priznanju krivicnog dela",
+				Odluka:   fmt.Sprintf("Sporazum potvrdjen presudom %s", odluka.IdPresude.Hex()),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		// zahtev dobija id sporazuma kako ponovljena obrada iste odluke ne bi napravila dva zahteva
+		zahtev := data.ZahtevZaSudskiPostupak{
+			ID:              sporazum.ID,
+			Opis:            "Sud je odbio sporazum o priznanju krivicnog dela: " + odluka.Obrazlozenje,
+			Datum:           primitive.NewDateTimeFromTime(time.Now()),
+			IdTuzioca:       sporazum.Zahtev.IdTuzioca,
+			KrivicnaPrijava: sporazum.Zahtev.KrivicnaPrijava,
+			PredmetId:       sporazum.Zahtev.PredmetId,
+			Status:          data.ZAHTEV_AKTIVAN,
+		}
+		if err := h.tuzilastvoRepo.DodajZahtevZaSudskiPostupakAkoNePostoji(ctx, &zahtev); err != nil {
+			return err
+		}
 	}
+
+	_, err := h.tuzilastvoRepo.EvidentirajOdlukuSuda(ctx, sporazum.ID, odluka)
+	return err
 }
 
-func (h *TuzilastvoHandler) DobaviKrivicnePrijaveOdGranicnePolicjie(rw http.ResponseWriter, r *http.Request) {
-	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviKrivicnePrijaveOdGranicnePolicjie")
+// OdlukaSudaOSporazumu prima odluku sudije o sporazumu, ponovljeno slanje iste odluke se prihvata
+func (h *TuzilastvoHandler) OdlukaSudaOSporazumu(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.OdlukaSudaOSporazumu")
 	defer span.End()
 
-	dobaviPrijaveEndpoint := fmt.Sprintf("http://%s:%s/krivicna-prijava/all", gpServiceHost, gpServicePort)
-	log.Println(dobaviPrijaveEndpoint)
+	sporazumId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id sporazuma nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id sporazuma nije procitan"))
+		return
+	}
 
-	// Create an HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", dobaviPrijaveEndpoint, nil)
+	var odluka data.OdlukaSuda
+	if err := json.NewDecoder(req.Body).Decode(&odluka); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	sporazum, err := h.tuzilastvoRepo.DobaviSporazum(ctx, sporazumId)
 	if err != nil {
-		rw.WriteHeader(http.StatusBadRequest)
-		rw.Write([]byte("Greska prilikom kreiranja zahteva"))
-		span.SetStatus(codes.Error, "Greska prilikom kreiranja zahteva")
+		span.SetStatus(codes.Error, "Sporazum sa prosledjenim id ne postoji")
+		writer.WriteHeader(http.StatusNotFound)
+		writer.Write([]byte("Sporazum sa prosledjenim id ne postoji"))
 		return
 	}
 
-	// Make the HTTP request
-	resp, err := http.DefaultClient.Do(req)
+	// odluka se prihvata samo ako je sud evidentirao istu odluku na predmetu sporazuma, a salje je sudija
+	// kome je predmet dodeljen, pa se evidentira odluka iz suda, a ne telo zahteva
+	predmetSuda, err := h.sudClient.DobaviPredmetPoSporazumu(ctx, sporazumId.Hex())
 	if err != nil {
-		rw.WriteHeader(http.StatusBadRequest)
-		rw.Write([]byte("Greska prilikom slanja zahteva"))
-		span.SetStatus(codes.Error, "Greska prilikom slanja zahteva")
+		if client.JeNijePronadjen(err) {
+			span.SetStatus(codes.Error, "Sud nema predmet za sporazum")
+			writer.WriteHeader(http.StatusNotFound)
+			writer.Write([]byte("Sud nema predmet za sporazum"))
+			return
+		}
+		span.SetStatus(codes.Error, "Sud trenutno nije dostupan")
+		writer.WriteHeader(http.StatusServiceUnavailable)
+		writer.Write([]byte("Sud trenutno nije dostupan"))
 		return
 	}
-	defer resp.Body.Close()
+	if predmetSuda.Odluka == nil || predmetSuda.Odluka.Potvrdjen != odluka.Potvrdjen {
+		span.SetStatus(codes.Error, "Odluka se ne slaze sa odlukom evidentiranom u sudu")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Odluka se ne slaze sa odlukom evidentiranom u sudu"))
+		return
+	}
+	idSudije, err := primitive.ObjectIDFromHex(helper.ExtractClaims(req)["id"])
+	if err != nil || idSudije != predmetSuda.IdSudije || predmetSuda.Odluka.IdSudije != predmetSuda.IdSudije {
+		span.SetStatus(codes.Error, "Odluku moze da dostavi samo sudija kome je predmet dodeljen")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Odluku moze da dostavi samo sudija kome je predmet dodeljen"))
+		return
+	}
+	odluka = *predmetSuda.Odluka
 
-	// Check if resp is nil before further processing
-	if resp == nil {
-		rw.WriteHeader(http.StatusInternalServerError)
-		rw.Write([]byte("Nil response from server"))
-		span.SetStatus(codes.Error, "Nil response from server")
+	if sporazum.OdlukaSuda != nil {
+		if sporazum.OdlukaSuda.Potvrdjen != odluka.Potvrdjen {
+			span.SetStatus(codes.Error, "Za sporazum je vec evidentirana drugacija odluka suda")
+			writer.WriteHeader(http.StatusConflict)
+			writer.Write([]byte("Za sporazum je vec evidentirana drugacija odluka suda"))
+			return
+		}
+	} else if err := h.evidentirajOdlukuSuda(ctx, sporazum, odluka); err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom evidentiranja odluke suda")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom evidentiranja odluke suda"))
 		return
 	}
 
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(map[string]string{"message": "Odluka suda o sporazumu je evidentirana"})
+}
+
+func (h *TuzilastvoHandler) DobaviSporazume(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviSporazume")
+	defer span.End()
+
+	sporazumi, err := h.tuzilastvoRepo.DobaviSporazume(ctx)
+	if err != nil {
 		rw.WriteHeader(http.StatusBadRequest)
-		rw.Write([]byte("Greska prilikom izvrsavanja zahteva u granicna policija servisu"))
-		span.SetStatus(codes.Error, "Greska prilikom izvrsavanja zahteva u granicna policija servisu")
+		rw.Write([]byte("Greska"))
+		span.SetStatus(codes.Error, "Greska")
+	}
+
+	if sporazumi == nil {
 		return
 	}
 
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
+	err = sporazumi.ToJSON(rw)
 	if err != nil {
-		rw.WriteHeader(http.StatusNotFound)
-		rw.Write([]byte("Greska prilikom citanja odgovora"))
-		span.SetStatus(codes.Error, "Greska prilikom citanja odgovora")
-		return
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
 	}
+}
 
-	// Unmarshal the response body into a Korisnik object
-	var prijave []*data.KrivicnaPrijava
-	if errUnmarshal := json.Unmarshal(body, &prijave); errUnmarshal != nil {
-		rw.WriteHeader(http.StatusInternalServerError)
-		rw.Write([]byte("Unmarshal greska tela odgovora"))
-		span.SetStatus(codes.Error, "Unmarshal greska tela odgovora")
+// DobaviSveKrivicnePrijave vraca prijave iz granicne policije i MUP-a za koje jos nije kreiran zahtev
+func (h *TuzilastvoHandler) DobaviSveKrivicnePrijave(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviSveKrivicnePrijave")
+	defer span.End()
+
+	prijave, nedostupni, err := h.DobaviKrivicnePrijave(ctx, r.Header.Get("Authorization"))
+	if err != nil {
+		rw.WriteHeader(http.StatusBadGateway)
+		rw.Write([]byte("Greska prilikom dobavljanja krivicnih prijava"))
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja krivicnih prijava")
 		return
 	}
+	// Spisak bez prijava izvora koji nije odgovorio je nepotpun, pa se izvori navode u zaglavlju odgovora
+	if len(nedostupni) > 0 {
+		izvori := make([]string, len(nedostupni))
+		for i, izvor := range nedostupni {
+			izvori[i] = string(izvor)
+		}
+		rw.Header().Set(data.ZaglavljeNedostupniIzvori, strings.Join(izvori, ","))
+	}
 
 	prijaveMap := make(map[string]*data.KrivicnaPrijava)
 	for _, prijava := range prijave {
@@ -350,6 +742,10 @@ func (h *TuzilastvoHandler) DobaviKrivicnePrijaveOdGranicnePolicjie(rw http.Resp
 	var svePrijave []*data.KrivicnaPrijava
 
 	for prijavaIdString, prijava := range prijaveMap {
+		if prijava.Status == data.ODBACENA {
+			continue
+		}
+
 		prijavaId, errObjId := primitive.ObjectIDFromHex(prijavaIdString)
 		if errObjId != nil {
 			log.Println(codes.Error, "Greska prilikom konverzije ID-ja")
@@ -380,76 +776,80 @@ func (h *TuzilastvoHandler) DobaviKrivicnePrijaveOdGranicnePolicjie(rw http.Resp
 	rw.Write(jsonResponse)
 }
 
-func (h *TuzilastvoHandler) DobaviKrivicnePrijave() ([]*data.KrivicnaPrijava, error) {
-
-	dobaviPrijaveEndpoint := fmt.Sprintf("http://%s:%s/krivicna-prijava/all", gpServiceHost, gpServicePort)
-	log.Println(dobaviPrijaveEndpoint)
-
-	// Create an HTTP request
-	req, err := http.NewRequestWithContext(context.Background(), "GET", dobaviPrijaveEndpoint, nil)
-	if err != nil {
-		log.Println(codes.Error, "Greska prilikom kreiranja zahteva")
-		return nil, err
-	}
-
-	// Make the HTTP request
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Println(codes.Error, "Greska prilikom slanja zahteva")
-		return nil, err
+// DobaviKrivicnePrijave objedinjuje krivicne prijave svih izvora i vraca izvore koji nisu odgovorili. Greska se
+// vraca samo ako nijedan izvor nije odgovorio.
+func (h *TuzilastvoHandler) DobaviKrivicnePrijave(ctx context.Context, bearer string) ([]*data.KrivicnaPrijava, []data.IzvorPrijave, error) {
+	nedostupni := []data.IzvorPrijave{}
+	prijaveGranicnePolicije, errGp := h.granicnaPolicijaClient.DobaviKrivicnePrijave(ctx, bearer)
+	if errGp != nil {
+		log.Println("Greska prilikom dobavljanja krivicnih prijava granicne policije:", errGp)
+		nedostupni = append(nedostupni, data.IZVOR_GRANICNA_POLICIJA)
 	}
-	defer resp.Body.Close()
 
-	// Check if resp is nil before further processing
-	if resp == nil {
-		log.Println(codes.Error, "Nil response from server")
-		return nil, err
+	prijaveMup, errMup := h.mupClient.DobaviKrivicnePrijave(ctx, bearer)
+	if errMup != nil {
+		log.Println("Greska prilikom dobavljanja krivicnih prijava MUP-a:", errMup)
+		nedostupni = append(nedostupni, data.IZVOR_MUP)
 	}
 
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK {
-		log.Println(codes.Error, "Greska prilikom izvrsavanja zahteva u granicna policija servisu")
-		return nil, err
+	if errGp != nil && errMup != nil {
+		return nil, nedostupni, errGp
 	}
+	return append(prijaveGranicnePolicije, prijaveMup...), nedostupni, nil
+}
 
-	// Read the response body
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		log.Println(codes.Error, "Greska prilikom citanja odgovora")
-		return nil, err
+// DobaviKrivicnuPrijavuByID trazi prijavu u granicnoj policiji, a zatim u MUP-u
+func (h *TuzilastvoHandler) DobaviKrivicnuPrijavuByID(ctx context.Context, id string, bearer string) (*data.KrivicnaPrijava, error) {
+	prijava, err := h.granicnaPolicijaClient.DobaviKrivicnuPrijavu(ctx, id, bearer)
+	if err == nil {
+		return prijava, nil
 	}
-
-	// Unmarshal the response body into a Korisnik object
-	var prijave []*data.KrivicnaPrijava
-	if err := json.Unmarshal(body, &prijave); err != nil {
-		log.Println(codes.Error, "Unmarshal greska tela odgovora")
+	// U MUP-u se trazi samo prijava koju granicna policija nema, ostale greske se prosledjuju
+	if !client.JeNijePronadjen(err) {
+		log.Println("Greska prilikom dobavljanja krivicne prijave iz granicne policije:", err)
 		return nil, err
 	}
 
-	return prijave, nil
+	return h.mupClient.DobaviKrivicnuPrijavu(ctx, id, bearer)
 }
 
-func (h *TuzilastvoHandler) DobaviKrivicnuPrijavuByID(id string) (*data.KrivicnaPrijava, error) {
-
-	prijave, err := h.DobaviKrivicnePrijave()
-	if err != nil {
-		log.Println(codes.Error, "Greska prilikom dobavljanja krivicnih prijava")
-		return nil, err
+// statusGreskePrijave vraca 404 za prijavu koja ne postoji, a 503 kada prijava nije mogla da se dobavi
+func statusGreskePrijave(err error) int {
+	if client.JeNijePronadjen(err) {
+		return http.StatusNotFound
 	}
+	return http.StatusServiceUnavailable
+}
 
-	// Create a map to store prijave with their IDs as keys
-	prijaveMap := make(map[string]*data.KrivicnaPrijava)
-	for _, prijava := range prijave {
-		prijaveMap[prijava.ID.Hex()] = prijava
+// PretraziKrivicnePrijave prosledjuje filtere i stranicenje pretrazi prijava u granicnoj policiji
+func (h *TuzilastvoHandler) PretraziKrivicnePrijave(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.PretraziKrivicnePrijave")
+	defer span.End()
+
+	query := r.URL.Query()
+	filter := data.FilterKrivicnihPrijava{
+		Status:           data.StatusPrijave(query.Get("status")),
+		Od:               query.Get("od"),
+		Do:               query.Get("do"),
+		JMBG:             query.Get("jmbg"),
+		GranicniPrelazId: query.Get("granicniPrelazId"),
 	}
+	filter.Stranica, _ = strconv.ParseInt(query.Get("stranica"), 10, 64)
+	filter.Velicina, _ = strconv.ParseInt(query.Get("velicina"), 10, 64)
 
-	// Retrieve the prijava from the map by its ID
-	prijava, ok := prijaveMap[id]
-	if !ok {
-		return nil, fmt.Errorf("Prijava sa id %s nije pronadjena", id)
+	stranica, err := h.granicnaPolicijaClient.PretraziKrivicnePrijave(ctx, filter, r.Header.Get("Authorization"))
+	if err != nil {
+		status := http.StatusBadGateway
+		if errResp, ok := err.(domain.ErrResp); ok && errResp.StatusCode == http.StatusBadRequest {
+			status = http.StatusBadRequest
+		}
+		span.SetStatus(codes.Error, "Greska prilikom pretrage krivicnih prijava")
+		http.Error(rw, "Greska prilikom pretrage krivicnih prijava", status)
+		return
 	}
 
-	return prijava, nil
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(stranica)
 }
 
 func (h *TuzilastvoHandler) DobaviZahteveZaSklapanjeSporazumaByGradjanin(rw http.ResponseWriter, r *http.Request) {
@@ -544,20 +944,96 @@ func (h *TuzilastvoHandler) DobaviZahteveZaSklapanjeSporazumaByGradjanin(rw http
 	}
 }
 
-func (h *TuzilastvoHandler) PrihvatiZahtevZaSklapanjeSporazuma(writer http.ResponseWriter, req *http.Request) {
-	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.PrihvatiZahtevZaSklapanjeSporazuma")
-	defer span.End()
-
-	vars := mux.Vars(req)
-	zahtevId, err := primitive.ObjectIDFromHex(vars["id"])
+// stranaUPregovorima odredjuje stranu prijavljenog korisnika, tuzilac mora biti tuzilac zahteva,
+// a gradjanin osumnjiceni iz krivicne prijave
+func (h *TuzilastvoHandler) stranaUPregovorima(ctx context.Context, req *http.Request, zahtev *data.ZahtevZaSklapanjeSporazuma) (data.StranaUPregovorima, primitive.ObjectID, int, string) {
+	claims := helper.ExtractClaims(req)
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(claims["id"])
 	if err != nil {
-		span.SetStatus(codes.Error, "Id zahteva nije procitan")
+		return "", primitive.NilObjectID, http.StatusBadRequest, "Id korisnika nije procitan"
+	}
+
+	switch claims["rola"] {
+	case "Tuzioc":
+		if zahtev.IdTuzioca != logovaniKorisnikId {
+			return "", primitive.NilObjectID, http.StatusForbidden, "Korisnik nije tuzilac zahteva"
+		}
+		return data.STRANA_TUZILAC, logovaniKorisnikId, http.StatusOK, ""
+	case "Gradjanin":
+		jmbg, err := h.mupClient.DobaviJmbgKorisnika(ctx, logovaniKorisnikId.Hex(), req.Header.Get("Authorization"))
+		if err != nil {
+			return "", primitive.NilObjectID, http.StatusBadGateway, "Greska prilikom dobavljanja JMBG-a korisnika"
+		}
+		prijava := zahtev.KrivicnaPrijava
+		if jmbg == "" || (jmbg != prijava.Prelaz.JMBGPutnika && jmbg != prijava.Osumnjiceni.JMBG) {
+			return "", primitive.NilObjectID, http.StatusForbidden, "Zahtev se ne odnosi na korisnika"
+		}
+		return data.STRANA_GRADJANIN, logovaniKorisnikId, http.StatusOK, ""
+	}
+	return "", primitive.NilObjectID, http.StatusForbidden, "Korisnik ne ucestvuje u pregovorima"
+}
+
+// zahtevNaPotezu dobavlja zahtev iz putanje i proverava da li je prijavljeni korisnik na potezu da odgovori na ponudu
+func (h *TuzilastvoHandler) zahtevNaPotezu(ctx context.Context, req *http.Request, verzija int) (*data.ZahtevZaSklapanjeSporazuma, data.StranaUPregovorima, primitive.ObjectID, int, string) {
+	zahtevId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, "", primitive.NilObjectID, http.StatusBadRequest, "Id zahteva nije procitan"
+	}
+
+	zahtev, err := h.tuzilastvoRepo.DobaviZahtevZaSklapanjeSporazuma(ctx, zahtevId)
+	if err != nil {
+		return nil, "", primitive.NilObjectID, http.StatusNotFound, "Zahtev za sklapanje sporazuma ne postoji"
+	}
+
+	strana, korisnikId, status, poruka := h.stranaUPregovorima(ctx, req, zahtev)
+	if strana == "" {
+		return nil, "", primitive.NilObjectID, status, poruka
+	}
+
+	if trenutniStatus := zahtev.TrenutniStatus(time.Now()); trenutniStatus != data.ZAHTEV_NA_CEKANJU {
+		return nil, "", primitive.NilObjectID, http.StatusConflict, fmt.Sprintf("Pregovori su zavrseni, zahtev je u statusu %s", trenutniStatus)
+	}
+	if verzija != 0 && verzija != zahtev.TrenutnaPonuda().Broj {
+		return nil, "", primitive.NilObjectID, http.StatusConflict, "Ponuda na koju se odgovara nije poslednja verzija"
+	}
+	if !zahtev.MozeDaOdgovori(strana) {
+		return nil, "", primitive.NilObjectID, http.StatusConflict, "Poslednju ponudu je predlozila ista strana, ceka se odgovor druge strane"
+	}
+
+	return zahtev, strana, korisnikId, http.StatusOK, ""
+}
+
+// procitajOdgovorNaPonudu cita opciono telo zahteva, prazno telo znaci odgovor na poslednju verziju
+func procitajOdgovorNaPonudu(req *http.Request) (data.OdgovorNaPonudu, error) {
+	var odgovor data.OdgovorNaPonudu
+	err := json.NewDecoder(req.Body).Decode(&odgovor)
+	if err == io.EOF {
+		return odgovor, nil
+	}
+	return odgovor, err
+}
+
+func (h *TuzilastvoHandler) PrihvatiZahtevZaSklapanjeSporazuma(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.PrihvatiZahtevZaSklapanjeSporazuma")
+	defer span.End()
+
+	odgovor, err := procitajOdgovorNaPonudu(req)
+	if err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
 		writer.WriteHeader(http.StatusBadRequest)
-		writer.Write([]byte("Id zahteva nije procitan"))
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	zahtev, _, _, status, poruka := h.zahtevNaPotezu(ctx, req, odgovor.Verzija)
+	if zahtev == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
 		return
 	}
 
-	sporazum, _ := h.tuzilastvoRepo.DobaviSporazumPoZahtevu(context.Background(), zahtevId)
+	sporazum, _ := h.tuzilastvoRepo.DobaviSporazumPoZahtevu(ctx, zahtev.ID)
 	if sporazum != nil {
 		span.SetStatus(codes.Error, "Sporazum za prosledjeni zahtev vec postoji. Nije moguce prihvatiti zahtev")
 		writer.WriteHeader(http.StatusForbidden)
@@ -565,12 +1041,18 @@ func (h *TuzilastvoHandler) PrihvatiZahtevZaSklapanjeSporazuma(writer http.Respo
 		return
 	}
 
-	prihvaceniZahtev, err := h.tuzilastvoRepo.PrihvatiZahtevZaSklapanjeSporazuma(ctx, zahtevId)
+	sporazum, err = h.tuzilastvoRepo.PrihvatiZahtevZaSklapanjeSporazuma(ctx, zahtev.ID, zahtev.TrenutnaVerzija, time.Now())
 	if err != nil {
-		message := "Greska prilikom prihvatanja zahteva za sklapanje sporazuma"
+		message := "Greska prilikom kreiranja sporazuma"
+		status := http.StatusInternalServerError
+		if err == mongo.ErrNoDocuments {
+			message = "Greska prilikom prihvatanja zahteva za sklapanje sporazuma"
+			status = http.StatusConflict
+		}
+		span.SetStatus(codes.Error, message)
 		// Encode and send JSON response
 		writer.Header().Set("Content-Type", "application/json")
-		writer.WriteHeader(http.StatusBadRequest)
+		writer.WriteHeader(status)
 		err = json.NewEncoder(writer).Encode(map[string]string{"message": message})
 		if err != nil {
 			// handle error
@@ -579,22 +1061,8 @@ func (h *TuzilastvoHandler) PrihvatiZahtevZaSklapanjeSporazuma(writer http.Respo
 		return
 	}
 
-	if prihvaceniZahtev != nil {
-		resultat := h.KreirajSporazum(*prihvaceniZahtev)
-
-		if resultat == false {
-			message := "Greska prilikom kreiranja sporazuma"
-			// Encode and send JSON response
-			writer.Header().Set("Content-Type", "application/json")
-			writer.WriteHeader(http.StatusInternalServerError)
-			err = json.NewEncoder(writer).Encode(map[string]string{"message": message})
-			if err != nil {
-				// handle error
-				return
-			}
-			return
-		}
-	}
+	// sporazum se odmah salje sudu, a ako slanje ne uspe ili se zahtev prekine ponovice ga PokreniPotvrduSporazuma
+	h.posaljiSporazumSudu(ctx, sporazum)
 
 	message := "Zahtev za sklapanje sporazuma je uspešno prihvaćen"
 	// Encode and send JSON response
@@ -608,30 +1076,24 @@ func (h *TuzilastvoHandler) PrihvatiZahtevZaSklapanjeSporazuma(writer http.Respo
 
 }
 
+// OdbijZahtevZaSklapanjeSporazuma zavrsava pregovore odbijanjem i pokrece sudski postupak po prijavi
 func (h *TuzilastvoHandler) OdbijZahtevZaSklapanjeSporazuma(writer http.ResponseWriter, req *http.Request) {
 	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.OdbijZahtevZaSklapanjeSporazuma")
 	defer span.End()
 
-	vars := mux.Vars(req)
-	zahtevId, err := primitive.ObjectIDFromHex(vars["id"])
+	odgovor, err := procitajOdgovorNaPonudu(req)
 	if err != nil {
-		span.SetStatus(codes.Error, "Id zahteva nije procitan")
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
 		writer.WriteHeader(http.StatusBadRequest)
-		writer.Write([]byte("Id zahteva nije procitan"))
+		writer.Write([]byte("Pogresan format zahteva"))
 		return
 	}
 
-	zahtev, err := h.tuzilastvoRepo.DobaviZahtevZaSklapanjeSporazuma(ctx, zahtevId)
-	if err != nil {
-		message := "Greska prilikom dobavljanja zahteva za sklapanje sporazuma"
-		// Encode and send JSON response
-		writer.Header().Set("Content-Type", "application/json")
-		writer.WriteHeader(http.StatusBadRequest)
-		err = json.NewEncoder(writer).Encode(map[string]string{"message": message})
-		if err != nil {
-			// handle error
-			return
-		}
+	zahtev, _, _, status, poruka := h.zahtevNaPotezu(ctx, req, odgovor.Verzija)
+	if zahtev == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
 		return
 	}
 
@@ -639,14 +1101,17 @@ func (h *TuzilastvoHandler) OdbijZahtevZaSklapanjeSporazuma(writer http.Response
 	novZahtevZaSudskiPostupak.ID = primitive.NewObjectID()
 	novZahtevZaSudskiPostupak.Datum = primitive.NewDateTimeFromTime(time.Now())
 	novZahtevZaSudskiPostupak.Opis = "Odbijen zahtev za sklapanje sporazuma"
+	novZahtevZaSudskiPostupak.IdTuzioca = zahtev.IdTuzioca
 	novZahtevZaSudskiPostupak.KrivicnaPrijava = zahtev.KrivicnaPrijava
+	novZahtevZaSudskiPostupak.PredmetId = zahtev.PredmetId
+	novZahtevZaSudskiPostupak.Status = data.ZAHTEV_AKTIVAN
 
-	err = h.tuzilastvoRepo.OdbijZahtevZaSklapanjeSporazuma(ctx, zahtevId)
+	err = h.tuzilastvoRepo.OdbijZahtevZaSklapanjeSporazuma(ctx, zahtev.ID, zahtev.TrenutnaVerzija, odgovor.Razlog, time.Now())
 	if err != nil {
 		message := "Greska prilikom odbijanja zahteva za sklapanje sporazuma"
 		// Encode and send JSON response
 		writer.Header().Set("Content-Type", "application/json")
-		writer.WriteHeader(http.StatusBadRequest)
+		writer.WriteHeader(http.StatusConflict)
 		err = json.NewEncoder(writer).Encode(map[string]string{"message": message})
 		if err != nil {
 			// handle error
@@ -681,6 +1146,87 @@ func (h *TuzilastvoHandler) OdbijZahtevZaSklapanjeSporazuma(writer http.Response
 
 }
 
+// KontraPonudaZahtevaZaSklapanjeSporazuma dodaje novu verziju uslova i kazne kao odgovor na poslednju ponudu druge strane
+func (h *TuzilastvoHandler) KontraPonudaZahtevaZaSklapanjeSporazuma(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.KontraPonudaZahtevaZaSklapanjeSporazuma")
+	defer span.End()
+
+	var ponuda data.KontraPonuda
+	if err := json.NewDecoder(req.Body).Decode(&ponuda); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if ponuda.Uslovi == "" || ponuda.Kazna == "" {
+		span.SetStatus(codes.Error, "Uslovi i kazna su obavezni")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Uslovi i kazna su obavezni"))
+		return
+	}
+
+	zahtev, strana, korisnikId, status, poruka := h.zahtevNaPotezu(ctx, req, ponuda.Verzija)
+	if zahtev == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
+		return
+	}
+
+	sada := time.Now()
+	verzija := zahtev.NovaVerzija(ponuda, strana, korisnikId, sada)
+	err := h.tuzilastvoRepo.DodajKontraPonudu(ctx, zahtev, verzija, sada)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dodavanja kontraponude")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Greska prilikom dodavanja kontraponude"))
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusCreated)
+	json.NewEncoder(writer).Encode(verzija)
+}
+
+// DobaviZahtevZaSklapanjeSporazuma vraca zahtev sa svim verzijama ponude stranama u pregovorima
+func (h *TuzilastvoHandler) DobaviZahtevZaSklapanjeSporazuma(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviZahtevZaSklapanjeSporazuma")
+	defer span.End()
+
+	zahtevId, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id zahteva nije procitan")
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Id zahteva nije procitan"))
+		return
+	}
+
+	zahtev, err := h.tuzilastvoRepo.DobaviZahtevZaSklapanjeSporazuma(ctx, zahtevId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Zahtev za sklapanje sporazuma ne postoji")
+		rw.WriteHeader(http.StatusNotFound)
+		rw.Write([]byte("Zahtev za sklapanje sporazuma ne postoji"))
+		return
+	}
+
+	if strana, _, status, poruka := h.stranaUPregovorima(ctx, r, zahtev); strana == "" {
+		span.SetStatus(codes.Error, poruka)
+		rw.WriteHeader(status)
+		rw.Write([]byte(poruka))
+		return
+	}
+
+	zahtev.Status = zahtev.TrenutniStatus(time.Now())
+	zahtev.Verzije = zahtev.SveVerzije()
+
+	err = zahtev.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
 func (h *TuzilastvoHandler) KreirajKanal(writer http.ResponseWriter, req *http.Request) {
 	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.KreirajKanal")
 	defer span.End()
@@ -700,10 +1246,35 @@ func (h *TuzilastvoHandler) KreirajKanal(writer http.ResponseWriter, req *http.R
 	//	log.Fatalf("Unable to load location: %v", err)
 	//}
 
+	if !kanal.PredmetId.IsZero() {
+		if _, err := h.tuzilastvoRepo.DobaviPredmet(ctx, kanal.PredmetId); err != nil {
+			span.SetStatus(codes.Error, "Predmet sa prosledjenim id ne postoji")
+			writer.WriteHeader(http.StatusBadRequest)
+			writer.Write([]byte("Predmet sa prosledjenim id ne postoji"))
+			return
+		}
+	}
+
+	korisnik, err := korisnikIzTokena(req)
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
 	kanal.ID = primitive.NewObjectID()
 	kanal.Kreiran = time.Now()
-
-	err := h.tuzilastvoRepo.KreirajKanal(ctx, &kanal)
+	kanal.IdVlasnika = korisnik.ID
+	kanal.Clanovi = []data.ClanKanala{{
+		IdKorisnika: korisnik.ID,
+		Ime:         korisnik.ImeKorisnika(),
+		Rola:        korisnik.Rola,
+		Uloga:       data.VLASNIK_KANALA,
+		Dodat:       kanal.Kreiran,
+	}}
+
+	err = h.tuzilastvoRepo.KreirajKanal(ctx, &kanal)
 	if err != nil {
 		span.SetStatus(codes.Error, "Greska prilikom kreiranja kanala za poruke")
 		writer.WriteHeader(http.StatusBadRequest)
@@ -727,7 +1298,15 @@ func (h *TuzilastvoHandler) DobaviKanale(rw http.ResponseWriter, r *http.Request
 	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviKanale")
 	defer span.End()
 
-	kanali, err := h.tuzilastvoRepo.DobaviKanale(ctx)
+	korisnik, err := korisnikIzTokena(r)
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	kanali, err := h.tuzilastvoRepo.DobaviKanaleClana(ctx, korisnik)
 	if err != nil {
 		rw.WriteHeader(http.StatusBadRequest)
 		rw.Write([]byte("Greska"))
@@ -747,49 +1326,39 @@ func (h *TuzilastvoHandler) DobaviKanale(rw http.ResponseWriter, r *http.Request
 }
 
 func (h *TuzilastvoHandler) KreirajPoruku(writer http.ResponseWriter, req *http.Request) {
-	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.KreirajKanal")
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.KreirajPoruku")
 	defer span.End()
 
-	vars := mux.Vars(req)
-	kanalId, err := primitive.ObjectIDFromHex(vars["id"])
-	if err != nil {
-		span.SetStatus(codes.Error, "Id kanala nije procitan")
-		writer.WriteHeader(http.StatusBadRequest)
-		writer.Write([]byte("Id kanala nije procitan"))
+	kanal, korisnik, status, poruka := h.kanalKorisnika(ctx, req)
+	if kanal == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
 		return
 	}
-
-	kanal, _ := h.tuzilastvoRepo.DobaviKanal(ctx, kanalId)
-	if kanal == nil {
-		span.SetStatus(codes.Error, "Kanal sa prosledjenim id ne postoji")
-		writer.WriteHeader(http.StatusBadRequest)
-		writer.Write([]byte("Kanal sa prosledjenim id ne postoji"))
+	if !kanal.MozeDaPise(korisnik) {
+		span.SetStatus(codes.Error, "Posmatrac ne moze da salje poruke u kanal")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Posmatrac ne moze da salje poruke u kanal"))
 		return
 	}
 
-	var poruka data.Poruka
-	if err := json.NewDecoder(req.Body).Decode(&poruka); err != nil {
+	var novaPoruka data.Poruka
+	if err := json.NewDecoder(req.Body).Decode(&novaPoruka); err != nil {
 		span.SetStatus(codes.Error, "Pogresan format zahteva")
 		writer.WriteHeader(http.StatusBadRequest)
 		writer.Write([]byte("Pogresan format zahteva"))
 		return
 	}
 
-	poruka.ID = primitive.NewObjectID()
-	poruka.Datum = time.Now()
-	poruka.KanalId = kanalId
-
-	rola, err := helper.ExtractUserType(req)
-	if err != nil {
-		span.SetStatus(codes.Error, "Greska prilikom uzimanja role iz tokena")
-		writer.WriteHeader(http.StatusBadRequest)
-		writer.Write([]byte("Greska prilikom uzimanja role iz tokena"))
-		return
-	}
-
-	poruka.Posiljalac = rola
+	novaPoruka.ID = primitive.NewObjectID()
+	novaPoruka.Datum = time.Now()
+	novaPoruka.KanalId = kanal.ID
+	novaPoruka.Posiljalac = korisnik.Rola
+	novaPoruka.IdAutora = korisnik.ID
+	novaPoruka.ImeAutora = korisnik.ImeKorisnika()
 
-	err = h.tuzilastvoRepo.KreirajPoruku(ctx, &poruka)
+	err := h.tuzilastvoRepo.KreirajPoruku(ctx, &novaPoruka)
 	if err != nil {
 		span.SetStatus(codes.Error, "Greska prilikom kreiranja poruke")
 		writer.WriteHeader(http.StatusBadRequest)
@@ -800,7 +1369,7 @@ func (h *TuzilastvoHandler) KreirajPoruku(writer http.ResponseWriter, req *http.
 	// Encode and send JSON response
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(http.StatusOK)
-	err = json.NewEncoder(writer).Encode(&poruka)
+	err = json.NewEncoder(writer).Encode(&novaPoruka)
 	if err != nil {
 		// handle error
 		return
@@ -812,16 +1381,15 @@ func (h *TuzilastvoHandler) DobaviPorukePoKanalu(rw http.ResponseWriter, r *http
 	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviPorukePoKanalu")
 	defer span.End()
 
-	vars := mux.Vars(r)
-	kanalId, err := primitive.ObjectIDFromHex(vars["id"])
-	if err != nil {
-		span.SetStatus(codes.Error, "Id kanala nije procitan")
-		rw.WriteHeader(http.StatusBadRequest)
-		rw.Write([]byte("Id kanala nije procitan"))
+	kanal, _, status, poruka := h.kanalKorisnika(ctx, r)
+	if kanal == nil {
+		span.SetStatus(codes.Error, poruka)
+		rw.WriteHeader(status)
+		rw.Write([]byte(poruka))
 		return
 	}
 
-	poruke, err := h.tuzilastvoRepo.DobaviPorukePoKanalu(ctx, kanalId)
+	poruke, err := h.tuzilastvoRepo.DobaviPorukePoKanalu(ctx, kanal.ID)
 	if err != nil {
 		rw.WriteHeader(http.StatusBadRequest)
 		rw.Write([]byte("Greska"))
@@ -831,6 +1399,9 @@ func (h *TuzilastvoHandler) DobaviPorukePoKanalu(rw http.ResponseWriter, r *http
 	if poruke == nil {
 		return
 	}
+	for i := range poruke {
+		poruke[i] = poruke[i].ZaPrikaz()
+	}
 
 	err = poruke.ToJSON(rw)
 	if err != nil {
@@ -839,3 +1410,1774 @@ func (h *TuzilastvoHandler) DobaviPorukePoKanalu(rw http.ResponseWriter, r *http
 		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
 	}
 }
+
+// korisnikIzTokena cita prijavljenog korisnika iz tokena, ime za prikaz postoji samo u novijim tokenima
+func korisnikIzTokena(req *http.Request) (*data.Korisnik, error) {
+	claims := helper.ExtractClaims(req)
+	id, err := primitive.ObjectIDFromHex(claims["id"])
+	if err != nil {
+		return nil, err
+	}
+	return &data.Korisnik{
+		ID:            id,
+		Ime:           claims["imeIPrezime"],
+		KorisnickoIme: claims["korisnickoIme"],
+		Rola:          claims["rola"],
+	}, nil
+}
+
+// kanalKorisnika dobavlja kanal iz putanje i proverava da li je prijavljeni korisnik njegov clan
+func (h *TuzilastvoHandler) kanalKorisnika(ctx context.Context, req *http.Request) (*data.Kanal, *data.Korisnik, int, string) {
+	kanalId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, "Id kanala nije procitan"
+	}
+
+	korisnik, err := korisnikIzTokena(req)
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, "Id korisnika nije procitan"
+	}
+
+	kanal, err := h.tuzilastvoRepo.DobaviKanal(ctx, kanalId)
+	if err != nil {
+		return nil, nil, http.StatusNotFound, "Kanal sa prosledjenim id ne postoji"
+	}
+	if !kanal.MozeDaCita(korisnik) {
+		return nil, nil, http.StatusForbidden, "Korisnik nije clan kanala"
+	}
+
+	return kanal, korisnik, http.StatusOK, ""
+}
+
+// PozoviUKanal dodaje korisnika u kanal kao clana ili posmatraca, odnosno menja ulogu postojecem clanu
+func (h *TuzilastvoHandler) PozoviUKanal(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.PozoviUKanal")
+	defer span.End()
+
+	kanal, korisnik, status, poruka := h.kanalKorisnika(ctx, req)
+	if kanal == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
+		return
+	}
+	if !kanal.JeVlasnik(korisnik.ID) {
+		span.SetStatus(codes.Error, "Clanove kanala moze da poziva samo vlasnik kanala")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Clanove kanala moze da poziva samo vlasnik kanala"))
+		return
+	}
+
+	var poziv data.PozivUKanal
+	if err := json.NewDecoder(req.Body).Decode(&poziv); err != nil || poziv.IdKorisnika.IsZero() {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if poziv.Uloga == "" {
+		poziv.Uloga = data.CLAN_KANALA
+	}
+	if !data.DozvoljenaUlogaPoziva(poziv.Uloga) {
+		span.SetStatus(codes.Error, "Nepoznata uloga u kanalu")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Nepoznata uloga u kanalu"))
+		return
+	}
+	if poziv.IdKorisnika == kanal.IdVlasnika {
+		span.SetStatus(codes.Error, "Vlasniku kanala se ne moze promeniti uloga")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Vlasniku kanala se ne moze promeniti uloga"))
+		return
+	}
+
+	pozvani, err := h.authClient.DobaviKorisnika(ctx, poziv.IdKorisnika.Hex(), req.Header.Get("Authorization"))
+	if err != nil {
+		if client.JeNijePronadjen(err) {
+			span.SetStatus(codes.Error, "Korisnik ne postoji")
+			writer.WriteHeader(http.StatusNotFound)
+			writer.Write([]byte("Korisnik ne postoji"))
+			return
+		}
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja korisnika")
+		writer.WriteHeader(http.StatusServiceUnavailable)
+		writer.Write([]byte("Greska prilikom dobavljanja korisnika"))
+		return
+	}
+	if !data.MozeDaBudeClanKanala(pozvani.Rola) {
+		span.SetStatus(codes.Error, "Korisnik sa ovom rolom ne moze biti clan kanala")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Korisnik sa ovom rolom ne moze biti clan kanala"))
+		return
+	}
+
+	clan := data.ClanKanala{
+		IdKorisnika: pozvani.ID,
+		Ime:         pozvani.ImeKorisnika(),
+		Rola:        pozvani.Rola,
+		Uloga:       poziv.Uloga,
+		Dodat:       time.Now(),
+		IdDodao:     korisnik.ID,
+	}
+	err = h.tuzilastvoRepo.PostaviClanaKanala(ctx, kanal.ID, clan)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dodavanja clana kanala")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Greska prilikom dodavanja clana kanala"))
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// UkloniIzKanala uklanja clana iz kanala, vlasnik moze da ukloni bilo kog clana, a clan samo sebe
+func (h *TuzilastvoHandler) UkloniIzKanala(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.UkloniIzKanala")
+	defer span.End()
+
+	kanal, korisnik, status, poruka := h.kanalKorisnika(ctx, req)
+	if kanal == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
+		return
+	}
+
+	var poziv data.PozivUKanal
+	if err := json.NewDecoder(req.Body).Decode(&poziv); err != nil || poziv.IdKorisnika.IsZero() {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if poziv.IdKorisnika != korisnik.ID && !kanal.JeVlasnik(korisnik.ID) {
+		span.SetStatus(codes.Error, "Clanove kanala moze da uklanja samo vlasnik kanala")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Clanove kanala moze da uklanja samo vlasnik kanala"))
+		return
+	}
+	if poziv.IdKorisnika == kanal.IdVlasnika {
+		span.SetStatus(codes.Error, "Vlasnik se ne moze ukloniti iz kanala")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Vlasnik se ne moze ukloniti iz kanala"))
+		return
+	}
+
+	err := h.tuzilastvoRepo.UkloniClanaKanala(ctx, kanal.ID, poziv.IdKorisnika)
+	if err != nil {
+		span.SetStatus(codes.Error, "Korisnik nije clan kanala")
+		writer.WriteHeader(http.StatusNotFound)
+		writer.Write([]byte("Korisnik nije clan kanala"))
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// ZatvoriKanal ogranicava otvoren kanal na njegove clanove, kanal moze da zatvori samo vlasnik
+func (h *TuzilastvoHandler) ZatvoriKanal(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.ZatvoriKanal")
+	defer span.End()
+
+	kanal, korisnik, status, poruka := h.kanalKorisnika(ctx, req)
+	if kanal == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
+		return
+	}
+	if !kanal.JeVlasnik(korisnik.ID) {
+		span.SetStatus(codes.Error, "Kanal moze da zatvori samo vlasnik kanala")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Kanal moze da zatvori samo vlasnik kanala"))
+		return
+	}
+
+	if err := h.tuzilastvoRepo.ZatvoriKanal(ctx, kanal.ID); err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom zatvaranja kanala")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom zatvaranja kanala"))
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+var errNijeClanKanala = errors.New("korisnik vise nije clan kanala")
+
+// PratiPorukeKanala salje nove poruke kanala kao Server-Sent Events. Klijent koji se ponovo poveze nastavlja
+// od poruke iz zaglavlja Last-Event-ID ili parametra poslednjiId i dobija sve poruke posle nje.
+func (h *TuzilastvoHandler) PratiPorukeKanala(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.PratiPorukeKanala")
+	defer span.End()
+
+	kanal, korisnik, status, poruka := h.kanalKorisnika(ctx, r)
+	if kanal == nil {
+		span.SetStatus(codes.Error, poruka)
+		rw.WriteHeader(status)
+		rw.Write([]byte(poruka))
+		return
+	}
+	kanalId := kanal.ID
+
+	pozicija := data.PozicijaPoruke{Datum: time.Now()}
+	poslednjiId := r.Header.Get("Last-Event-ID")
+	if poslednjiId == "" {
+		poslednjiId = r.URL.Query().Get("poslednjiId")
+	}
+	if poslednjiId != "" {
+		id, err := primitive.ObjectIDFromHex(poslednjiId)
+		if err != nil {
+			span.SetStatus(codes.Error, "Id poslednje poruke nije procitan")
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte("Id poslednje poruke nije procitan"))
+			return
+		}
+		poslednja, err := h.tuzilastvoRepo.DobaviPoruku(ctx, id)
+		if err != nil || poslednja.KanalId != kanalId {
+			span.SetStatus(codes.Error, "Poslednja poruka ne postoji u kanalu")
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte("Poslednja poruka ne postoji u kanalu"))
+			return
+		}
+		pozicija = poslednja.Pozicija()
+	}
+
+	// pretplata pre citanja propustenih poruka, kako se ne bi izgubila poruka upisana u medjuvremenu
+	nove, odjavi := h.razglasPoruka.Pretplati(kanalId)
+	defer odjavi()
+
+	propustene, err := h.tuzilastvoRepo.DobaviPorukeNakon(ctx, kanalId, pozicija)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja poruka")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja poruka"))
+		return
+	}
+
+	// tok traje duze od WriteTimeout servera
+	kontroler := http.NewResponseController(rw)
+	if err := kontroler.SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Println("Rok za slanje toka poruka nije uklonjen:", err)
+	}
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Connection", "keep-alive")
+	rw.Header().Set("X-Accel-Buffering", "no")
+	rw.WriteHeader(http.StatusOK)
+
+	// clan moze biti uklonjen iz kanala na bilo kojoj instanci servisa dok je tok otvoren, pa se clanstvo
+	// proverava pre svake poruke i pri odrzavanju veze, a tok se prekida cim korisnik vise nije clan
+	jeClan := func() error {
+		trenutni, err := h.tuzilastvoRepo.DobaviKanal(ctx, kanalId)
+		if err != nil {
+			return err
+		}
+		if !trenutni.MozeDaCita(korisnik) {
+			return errNijeClanKanala
+		}
+		return nil
+	}
+
+	// poruka posle poslednje poslate je nova za klijenta, a ranija izmenjena ili obrisana poruka se salje kao
+	// izmena bez id dogadjaja, da ne bi pomerila poziciju od koje klijent nastavlja posle ponovnog povezivanja
+	posalji := func(poruka *data.Poruka) error {
+		nova := poruka.Pozicija().Posle(pozicija)
+		if !nova && poruka.Izmenjena == nil && !poruka.Obrisana {
+			return nil
+		}
+		if err := jeClan(); err != nil {
+			return err
+		}
+		sadrzaj, err := json.Marshal(poruka.ZaPrikaz())
+		if err != nil {
+			return err
+		}
+		if !nova {
+			if _, err := fmt.Fprintf(rw, "event: izmena\ndata: %s\n\n", sadrzaj); err != nil {
+				return err
+			}
+			return kontroler.Flush()
+		}
+		if _, err := fmt.Fprintf(rw, "id: %s\nevent: poruka\ndata: %s\n\n", poruka.ID.Hex(), sadrzaj); err != nil {
+			return err
+		}
+		pozicija = poruka.Pozicija()
+		return kontroler.Flush()
+	}
+
+	for _, poruka := range propustene {
+		if err := posalji(poruka); err != nil {
+			return
+		}
+	}
+	if err := kontroler.Flush(); err != nil {
+		return
+	}
+
+	// komentar odrzava vezu kroz proksije koji zatvaraju neaktivne konekcije
+	odrzavanje := time.NewTicker(15 * time.Second)
+	defer odrzavanje.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case poruka, ok := <-nove:
+			if !ok {
+				// pretplatnik je zaostao, klijent se ponovo povezuje i nastavlja od poslednje poslate poruke
+				return
+			}
+			if err := posalji(poruka); err != nil {
+				return
+			}
+		case <-odrzavanje.C:
+			if err := jeClan(); err != nil {
+				return
+			}
+			if _, err := fmt.Fprint(rw, ": ping\n\n"); err != nil {
+				return
+			}
+			if err := kontroler.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// porukaKorisnika dobavlja poruku iz putanje i njen kanal i proverava da li je prijavljeni korisnik clan kanala
+func (h *TuzilastvoHandler) porukaKorisnika(ctx context.Context, req *http.Request) (*data.Poruka, *data.Kanal, *data.Korisnik, int, string) {
+	porukaId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, nil, nil, http.StatusBadRequest, "Id poruke nije procitan"
+	}
+
+	korisnik, err := korisnikIzTokena(req)
+	if err != nil {
+		return nil, nil, nil, http.StatusBadRequest, "Id korisnika nije procitan"
+	}
+
+	poruka, err := h.tuzilastvoRepo.DobaviPoruku(ctx, porukaId)
+	if err != nil {
+		return nil, nil, nil, http.StatusNotFound, "Poruka sa prosledjenim id ne postoji"
+	}
+	kanal, err := h.tuzilastvoRepo.DobaviKanal(ctx, poruka.KanalId)
+	if err != nil {
+		return nil, nil, nil, http.StatusNotFound, "Kanal poruke ne postoji"
+	}
+	if !kanal.MozeDaCita(korisnik) {
+		return nil, nil, nil, http.StatusForbidden, "Korisnik nije clan kanala"
+	}
+
+	return poruka, kanal, korisnik, http.StatusOK, ""
+}
+
+// PosaljiPrilog salje poruku sa prilozima u kanal. Zahtev je multipart/form-data sa jednim ili vise fajlova
+// u polju prilog i opcionim tekstom poruke u polju sadrzaj.
+func (h *TuzilastvoHandler) PosaljiPrilog(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.PosaljiPrilog")
+	defer span.End()
+
+	kanal, korisnik, status, poruka := h.kanalKorisnika(ctx, req)
+	if kanal == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
+		return
+	}
+	if !kanal.MozeDaPise(korisnik) {
+		span.SetStatus(codes.Error, "Posmatrac ne moze da salje poruke u kanal")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Posmatrac ne moze da salje poruke u kanal"))
+		return
+	}
+
+	// otpremanje vecih fajlova traje duze od ReadTimeout servera
+	kontroler := http.NewResponseController(writer)
+	if err := kontroler.SetReadDeadline(time.Now().Add(2 * time.Minute)); err != nil {
+		h.logger.Println("Rok za otpremanje priloga nije produzen:", err)
+	}
+	req.Body = http.MaxBytesReader(writer, req.Body, data.MaxVelicinaPriloga)
+
+	delovi, err := req.MultipartReader()
+	if err != nil {
+		span.SetStatus(codes.Error, "Zahtev mora biti multipart/form-data")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Zahtev mora biti multipart/form-data"))
+		return
+	}
+
+	novaPoruka := data.Poruka{ID: primitive.NewObjectID()}
+	sacuvano := false
+	defer func() {
+		if sacuvano {
+			return
+		}
+		for _, prilog := range novaPoruka.Prilozi {
+			if err := h.tuzilastvoRepo.ObrisiPrilog(prilog.ID); err != nil {
+				h.logger.Println("Greska prilikom brisanja priloga neposlate poruke:", err)
+			}
+		}
+	}()
+
+	for {
+		deo, err := delovi.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err == nil {
+			switch deo.FormName() {
+			case "sadrzaj":
+				var sadrzaj []byte
+				sadrzaj, err = io.ReadAll(deo)
+				novaPoruka.Sadrzaj = string(sadrzaj)
+			case "prilog":
+				var prilog *data.Prilog
+				if deo.FileName() == "" {
+					err = errors.New("prilog bez naziva")
+					break
+				}
+				tipSadrzaja := deo.Header.Get("Content-Type")
+				if tipSadrzaja == "" {
+					tipSadrzaja = "application/octet-stream"
+				}
+				prilog, err = h.tuzilastvoRepo.SacuvajPrilog(ctx, deo.FileName(), tipSadrzaja, deo)
+				if err == nil {
+					novaPoruka.Prilozi = append(novaPoruka.Prilozi, *prilog)
+				}
+			}
+		}
+		if err != nil {
+			var prevelik *http.MaxBytesError
+			if errors.As(err, &prevelik) {
+				span.SetStatus(codes.Error, "Prilozi su preveliki")
+				writer.WriteHeader(http.StatusRequestEntityTooLarge)
+				writer.Write([]byte(fmt.Sprintf("Prilozi jedne poruke mogu imati najvise %d MB", data.MaxVelicinaPriloga>>20)))
+				return
+			}
+			span.SetStatus(codes.Error, "Greska prilikom otpremanja priloga")
+			writer.WriteHeader(http.StatusBadRequest)
+			writer.Write([]byte("Greska prilikom otpremanja priloga"))
+			return
+		}
+	}
+
+	if len(novaPoruka.Prilozi) == 0 {
+		span.SetStatus(codes.Error, "Poruka mora imati bar jedan prilog")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Poruka mora imati bar jedan prilog"))
+		return
+	}
+
+	novaPoruka.Datum = time.Now()
+	novaPoruka.KanalId = kanal.ID
+	novaPoruka.Posiljalac = korisnik.Rola
+	novaPoruka.IdAutora = korisnik.ID
+	novaPoruka.ImeAutora = korisnik.ImeKorisnika()
+
+	err = h.tuzilastvoRepo.KreirajPoruku(ctx, &novaPoruka)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom kreiranja poruke")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Greska prilikom kreiranja poruke"))
+		return
+	}
+	sacuvano = true
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(&novaPoruka)
+}
+
+// PreuzmiPrilog salje sadrzaj priloga poruke, a u zaglavlju X-Sha256 hes izracunat pri otpremanju
+func (h *TuzilastvoHandler) PreuzmiPrilog(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.PreuzmiPrilog")
+	defer span.End()
+
+	poruka, _, _, status, greska := h.porukaKorisnika(ctx, r)
+	if poruka == nil {
+		span.SetStatus(codes.Error, greska)
+		rw.WriteHeader(status)
+		rw.Write([]byte(greska))
+		return
+	}
+	if poruka.Obrisana {
+		span.SetStatus(codes.Error, "Poruka je obrisana")
+		rw.WriteHeader(http.StatusGone)
+		rw.Write([]byte("Poruka je obrisana"))
+		return
+	}
+
+	prilogId, err := primitive.ObjectIDFromHex(mux.Vars(r)["prilogId"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id priloga nije procitan")
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Id priloga nije procitan"))
+		return
+	}
+	prilog := poruka.Prilog(prilogId)
+	if prilog == nil {
+		span.SetStatus(codes.Error, "Prilog ne postoji u poruci")
+		rw.WriteHeader(http.StatusNotFound)
+		rw.Write([]byte("Prilog ne postoji u poruci"))
+		return
+	}
+
+	tok, err := h.tuzilastvoRepo.OtvoriPrilog(ctx, prilog.ID)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom citanja priloga")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom citanja priloga"))
+		return
+	}
+	defer tok.Close()
+
+	kontroler := http.NewResponseController(rw)
+	if err := kontroler.SetWriteDeadline(time.Now().Add(2 * time.Minute)); err != nil {
+		h.logger.Println("Rok za preuzimanje priloga nije produzen:", err)
+	}
+
+	rw.Header().Set("Content-Type", prilog.TipSadrzaja)
+	rw.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": prilog.Naziv}))
+	rw.Header().Set("Content-Length", strconv.FormatInt(prilog.Velicina, 10))
+	rw.Header().Set("X-Sha256", prilog.Sha256)
+	// tip sadrzaja zadaje posiljalac, pa pregledac ne sme da ga pogadja i prikaze prilog kao HTML ili skriptu
+	rw.Header().Set("X-Content-Type-Options", "nosniff")
+	rw.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(rw, tok); err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom slanja priloga")
+		h.logger.Println("Greska prilikom slanja priloga:", err)
+	}
+}
+
+// IzmeniPoruku menja sadrzaj poruke, autor moze da je izmeni u roku od data.RokZaIzmenuPoruke od slanja
+func (h *TuzilastvoHandler) IzmeniPoruku(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.IzmeniPoruku")
+	defer span.End()
+
+	poruka, kanal, korisnik, status, greska := h.porukaKorisnika(ctx, req)
+	if poruka == nil {
+		span.SetStatus(codes.Error, greska)
+		writer.WriteHeader(status)
+		writer.Write([]byte(greska))
+		return
+	}
+
+	var izmena data.IzmenaSadrzaja
+	if err := json.NewDecoder(req.Body).Decode(&izmena); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if izmena.Sadrzaj == "" && len(poruka.Prilozi) == 0 {
+		span.SetStatus(codes.Error, "Poruka bez priloga mora imati sadrzaj")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Poruka bez priloga mora imati sadrzaj"))
+		return
+	}
+
+	if poruka.IdAutora != korisnik.ID || !kanal.MozeDaPise(korisnik) {
+		span.SetStatus(codes.Error, "Poruku moze da izmeni samo njen autor")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Poruku moze da izmeni samo njen autor"))
+		return
+	}
+	if poruka.Obrisana {
+		span.SetStatus(codes.Error, "Obrisana poruka se ne moze menjati")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Obrisana poruka se ne moze menjati"))
+		return
+	}
+	sada := time.Now()
+	if poruka.IzmenaIstekla(sada) {
+		span.SetStatus(codes.Error, "Rok za izmenu poruke je istekao")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Rok za izmenu poruke je istekao"))
+		return
+	}
+
+	err := h.tuzilastvoRepo.IzmeniPoruku(ctx, poruka, korisnik.ID, sada.Add(-data.RokZaIzmenuPoruke), izmena.Sadrzaj)
+	if err != nil {
+		span.SetStatus(codes.Error, "Poruka je u medjuvremenu izmenjena ili obrisana")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Poruka je u medjuvremenu izmenjena ili obrisana"))
+		return
+	}
+
+	izmenjena, err := h.tuzilastvoRepo.DobaviPoruku(ctx, poruka.ID)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja poruke")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom dobavljanja poruke"))
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(izmenjena)
+}
+
+// ObrisiPoruku oznacava poruku kao obrisanu. Poruku moze da obrise autor ili vlasnik kanala, a sadrzaj se cuva
+// zajedno sa podacima o tome ko ju je, kada i zasto obrisao.
+func (h *TuzilastvoHandler) ObrisiPoruku(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.ObrisiPoruku")
+	defer span.End()
+
+	poruka, kanal, korisnik, status, greska := h.porukaKorisnika(ctx, req)
+	if poruka == nil {
+		span.SetStatus(codes.Error, greska)
+		writer.WriteHeader(status)
+		writer.Write([]byte(greska))
+		return
+	}
+
+	var razlog data.RazlogBrisanja
+	if err := json.NewDecoder(req.Body).Decode(&razlog); err != nil && err != io.EOF {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	if !poruka.MozeDaObrise(korisnik.ID, kanal) {
+		span.SetStatus(codes.Error, "Poruku moze da obrise samo autor ili vlasnik kanala")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Poruku moze da obrise samo autor ili vlasnik kanala"))
+		return
+	}
+	if poruka.Obrisana {
+		span.SetStatus(codes.Error, "Poruka je vec obrisana")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Poruka je vec obrisana"))
+		return
+	}
+
+	brisanje := data.BrisanjePoruke{
+		IdKorisnika: korisnik.ID,
+		Ime:         korisnik.ImeKorisnika(),
+		Datum:       time.Now(),
+		Razlog:      razlog.Razlog,
+	}
+	err := h.tuzilastvoRepo.ObrisiPoruku(ctx, poruka.ID, brisanje)
+	if err != nil {
+		span.SetStatus(codes.Error, "Poruka je vec obrisana")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Poruka je vec obrisana"))
+		return
+	}
+	h.logger.Printf("Poruku %s u kanalu %s obrisao je korisnik %s", poruka.ID.Hex(), kanal.ID.Hex(), korisnik.ID.Hex())
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// PretraziPoruke pretrazuje poruke po tekstu i nazivima priloga, samo u kanalima ciji je prijavljeni korisnik clan.
+// Parametar kanalId ogranicava pretragu na jedan kanal.
+func (h *TuzilastvoHandler) PretraziPoruke(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.PretraziPoruke")
+	defer span.End()
+
+	korisnik, err := korisnikIzTokena(r)
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	upit := strings.TrimSpace(r.URL.Query().Get("upit"))
+	if upit == "" {
+		span.SetStatus(codes.Error, "Upit za pretragu je obavezan")
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Upit za pretragu je obavezan"))
+		return
+	}
+
+	kanali, err := h.tuzilastvoRepo.DobaviKanaleClana(ctx, korisnik)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja kanala")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja kanala"))
+		return
+	}
+
+	dostupni := make([]primitive.ObjectID, 0, len(kanali))
+	for _, kanal := range kanali {
+		dostupni = append(dostupni, kanal.ID)
+	}
+
+	if kanalId := r.URL.Query().Get("kanalId"); kanalId != "" {
+		id, err := primitive.ObjectIDFromHex(kanalId)
+		if err != nil {
+			span.SetStatus(codes.Error, "Id kanala nije procitan")
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte("Id kanala nije procitan"))
+			return
+		}
+		clan := false
+		for _, dostupan := range dostupni {
+			clan = clan || dostupan == id
+		}
+		if !clan {
+			span.SetStatus(codes.Error, "Korisnik nije clan kanala")
+			rw.WriteHeader(http.StatusForbidden)
+			rw.Write([]byte("Korisnik nije clan kanala"))
+			return
+		}
+		dostupni = []primitive.ObjectID{id}
+	}
+
+	rezultati, err := h.tuzilastvoRepo.PretraziPoruke(ctx, upit, dostupni)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom pretrage poruka")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom pretrage poruka"))
+		return
+	}
+
+	err = json.NewEncoder(rw).Encode(rezultati)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+// KreirajPredmet otvara predmet za krivicnu prijavu, tuzilac predmeta je prijavljeni korisnik
+func (h *TuzilastvoHandler) KreirajPredmet(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.KreirajPredmet")
+	defer span.End()
+
+	prijavaId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id krivicne prijave nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id krivicne prijave nije procitan"))
+		return
+	}
+
+	predmetPostoji, _ := h.tuzilastvoRepo.DobaviPredmetPoPrijavi(ctx, prijavaId)
+	if predmetPostoji != nil {
+		span.SetStatus(codes.Error, "Predmet za prosledjenu prijavu vec postoji")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Predmet za prosledjenu prijavu vec postoji"))
+		return
+	}
+
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	var izmena data.IzmenaPredmeta
+	if err := json.NewDecoder(req.Body).Decode(&izmena); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	if status, poruka := h.proveriIstrazitelje(ctx, izmena.IdIstrazitelja); status != 0 {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
+		return
+	}
+
+	prijava, err := h.DobaviKrivicnuPrijavuByID(ctx, prijavaId.Hex(), req.Header.Get("Authorization"))
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja krivicne prijave po id")
+		writer.WriteHeader(statusGreskePrijave(err))
+		writer.Write([]byte("Greska prilikom dobavljanja krivicne prijave po id"))
+		return
+	}
+
+	sada := primitive.NewDateTimeFromTime(time.Now())
+	predmet := data.Predmet{
+		ID:              primitive.NewObjectID(),
+		Naziv:           izmena.Naziv,
+		Opis:            izmena.Opis,
+		Datum:           sada,
+		Status:          data.PREDMET_OTVOREN,
+		IdTuzioca:       logovaniKorisnikId,
+		IdIstrazitelja:  izmena.IdIstrazitelja,
+		KrivicnaPrijava: *prijava,
+		Rokovi:          izmena.Rokovi,
+	}
+	// Prijava za koju je vec podnet zahtev za sudski postupak se otvara kao predmet kod suda
+	if zahtev, _ := h.tuzilastvoRepo.DobaviZahtevZaSudskiPostupakPoPrijavi(ctx, prijavaId); zahtev != nil {
+		predmet.Status = data.PREDMET_KOD_SUDA
+	}
+	predmet.IstorijaStatusa = []data.PromenaStatusaPredmeta{{Status: predmet.Status, Datum: sada}}
+
+	err = h.tuzilastvoRepo.DodajPredmet(ctx, &predmet)
+	if mongo.IsDuplicateKeyError(err) {
+		span.SetStatus(codes.Error, "Predmet za prosledjenu prijavu vec postoji")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Predmet za prosledjenu prijavu vec postoji"))
+		return
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom kreiranja predmeta")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom kreiranja predmeta"))
+		return
+	}
+
+	if err := h.tuzilastvoRepo.PoveziZahteveSaPredmetom(ctx, prijavaId, predmet.ID); err != nil {
+		log.Println("Greska prilikom povezivanja zahteva sa predmetom:", err)
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusCreated)
+	predmet.ToJSON(writer)
+}
+
+// proveriIstrazitelje proverava u auth servisu da su svi navedeni korisnici istrazitelji. Vraca status i poruku
+// greske, ili 0 ako su svi korisnici istrazitelji.
+func (h *TuzilastvoHandler) proveriIstrazitelje(ctx context.Context, idIstrazitelja []primitive.ObjectID) (int, string) {
+	if len(idIstrazitelja) == 0 {
+		return 0, ""
+	}
+	istrazitelji, err := h.authClient.DobaviKorisnikePoRoli(ctx, data.RolaIstrazitelj)
+	if err != nil {
+		h.logger.Println("Greska prilikom dobavljanja istrazitelja:", err)
+		return http.StatusServiceUnavailable, "Provera istrazitelja trenutno nije moguca"
+	}
+
+	postoji := make(map[primitive.ObjectID]bool, len(istrazitelji))
+	for _, istrazitelj := range istrazitelji {
+		postoji[istrazitelj.ID] = true
+	}
+	for _, id := range idIstrazitelja {
+		if !postoji[id] {
+			return http.StatusBadRequest, fmt.Sprintf("Korisnik %s nije istrazitelj", id.Hex())
+		}
+	}
+	return 0, ""
+}
+
+// predmetKorisnika dobavlja predmet iz putanje i proverava da li prijavljeni korisnik radi na njemu
+func (h *TuzilastvoHandler) predmetKorisnika(ctx context.Context, req *http.Request) (*data.Predmet, primitive.ObjectID, int, string) {
+	predmetId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, primitive.NilObjectID, http.StatusBadRequest, "Id predmeta nije procitan"
+	}
+
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(req)["id"])
+	if err != nil {
+		return nil, primitive.NilObjectID, http.StatusBadRequest, "Id korisnika nije procitan"
+	}
+
+	predmet, err := h.tuzilastvoRepo.DobaviPredmet(ctx, predmetId)
+	if err != nil {
+		return nil, primitive.NilObjectID, http.StatusNotFound, "Predmet sa prosledjenim id ne postoji"
+	}
+	if !predmet.ImaPristup(logovaniKorisnikId) {
+		return nil, primitive.NilObjectID, http.StatusForbidden, "Korisnik nije dodeljen predmetu"
+	}
+
+	return predmet, logovaniKorisnikId, http.StatusOK, ""
+}
+
+// DobaviPredmet vraca predmet zajedno sa povezanim zahtevima i kanalima za poruke
+func (h *TuzilastvoHandler) DobaviPredmet(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviPredmet")
+	defer span.End()
+
+	predmet, _, status, poruka := h.predmetKorisnika(ctx, r)
+	if predmet == nil {
+		span.SetStatus(codes.Error, poruka)
+		rw.WriteHeader(status)
+		rw.Write([]byte(poruka))
+		return
+	}
+
+	var err error
+	predmet.ZahteviZaSudskiPostupak, err = h.tuzilastvoRepo.DobaviZahteveZaSudskiPostupakPoPredmetu(ctx, predmet.ID)
+	if err == nil {
+		predmet.ZahteviZaSklapanjeSporazuma, err = h.tuzilastvoRepo.DobaviZahteveZaSklapanjeSporazumaPoPredmetu(ctx, predmet.ID)
+	}
+	if err == nil {
+		predmet.Kanali, err = h.tuzilastvoRepo.DobaviKanalePoPredmetu(ctx, predmet.ID)
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja povezanih podataka predmeta")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja povezanih podataka predmeta"))
+		return
+	}
+
+	err = predmet.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+// AzurirajPredmet menja naziv, opis, istrazitelje i rokove predmeta, sto moze samo tuzilac predmeta
+func (h *TuzilastvoHandler) AzurirajPredmet(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.AzurirajPredmet")
+	defer span.End()
+
+	predmet, logovaniKorisnikId, status, poruka := h.predmetKorisnika(ctx, req)
+	if predmet == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
+		return
+	}
+	if predmet.IdTuzioca != logovaniKorisnikId {
+		span.SetStatus(codes.Error, "Predmet moze da menja samo tuzilac predmeta")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Predmet moze da menja samo tuzilac predmeta"))
+		return
+	}
+	if predmet.Status == data.PREDMET_ZATVOREN {
+		span.SetStatus(codes.Error, "Zatvoren predmet nije moguce menjati")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Zatvoren predmet nije moguce menjati"))
+		return
+	}
+
+	izmena := data.IzmenaPredmeta{
+		Naziv:          predmet.Naziv,
+		Opis:           predmet.Opis,
+		IdIstrazitelja: predmet.IdIstrazitelja,
+		Rokovi:         predmet.Rokovi,
+	}
+	if err := json.NewDecoder(req.Body).Decode(&izmena); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	// proveravaju se samo novododati istrazitelji, vec dodeljeni ostaju na predmetu dok ih tuzilac ne ukloni
+	vecDodeljen := make(map[primitive.ObjectID]bool, len(predmet.IdIstrazitelja))
+	for _, id := range predmet.IdIstrazitelja {
+		vecDodeljen[id] = true
+	}
+	novi := []primitive.ObjectID{}
+	for _, id := range izmena.IdIstrazitelja {
+		if !vecDodeljen[id] {
+			novi = append(novi, id)
+		}
+	}
+	if status, poruka := h.proveriIstrazitelje(ctx, novi); status != 0 {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
+		return
+	}
+
+	azuriran, err := h.tuzilastvoRepo.AzurirajPredmet(ctx, predmet.ID, izmena)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom izmene predmeta")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom izmene predmeta"))
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	azuriran.ToJSON(writer)
+}
+
+// DodajBeleskuPredmetu dodaje belesku istrage, beleske mogu da dodaju tuzilac i istrazitelji predmeta
+func (h *TuzilastvoHandler) DodajBeleskuPredmetu(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.DodajBeleskuPredmetu")
+	defer span.End()
+
+	predmet, logovaniKorisnikId, status, poruka := h.predmetKorisnika(ctx, req)
+	if predmet == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
+		return
+	}
+
+	var beleska data.Beleska
+	if err := json.NewDecoder(req.Body).Decode(&beleska); err != nil || beleska.Sadrzaj == "" {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+
+	rola, _ := helper.ExtractUserType(req)
+	beleska.IdAutora = logovaniKorisnikId
+	beleska.Rola = rola
+	beleska.Datum = primitive.NewDateTimeFromTime(time.Now())
+
+	err := h.tuzilastvoRepo.DodajBeleskuPredmetu(ctx, predmet.ID, beleska)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dodavanja beleske")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom dodavanja beleske"))
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusCreated)
+	json.NewEncoder(writer).Encode(beleska)
+}
+
+// PromeniStatusPredmeta menja status predmeta, zatvaranje predmeta zahteva konacnu odluku
+func (h *TuzilastvoHandler) PromeniStatusPredmeta(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.PromeniStatusPredmeta")
+	defer span.End()
+
+	predmet, logovaniKorisnikId, status, poruka := h.predmetKorisnika(ctx, req)
+	if predmet == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
+		return
+	}
+	if predmet.IdTuzioca != logovaniKorisnikId {
+		span.SetStatus(codes.Error, "Status predmeta moze da menja samo tuzilac predmeta")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Status predmeta moze da menja samo tuzilac predmeta"))
+		return
+	}
+
+	var promena data.PromenaStatusaPredmeta
+	if err := json.NewDecoder(req.Body).Decode(&promena); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if !data.DozvoljenaPromenaStatusaPredmeta(predmet.Status, promena.Status) {
+		span.SetStatus(codes.Error, "Nedozvoljena promena statusa predmeta")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte(fmt.Sprintf("Nedozvoljena promena statusa predmeta iz %s u %s", predmet.Status, promena.Status)))
+		return
+	}
+	if promena.Status == data.PREDMET_ZATVOREN && promena.Odluka == "" {
+		span.SetStatus(codes.Error, "Za zatvaranje predmeta je potrebna odluka")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Za zatvaranje predmeta je potrebna odluka"))
+		return
+	}
+	promena.Datum = primitive.NewDateTimeFromTime(time.Now())
+
+	err := h.tuzilastvoRepo.PromeniStatusPredmeta(ctx, predmet.ID, predmet.Status, promena)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom promene statusa predmeta")
+		writer.WriteHeader(http.StatusConflict)
+		writer.Write([]byte("Greska prilikom promene statusa predmeta"))
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// DobaviPregledPredmeta vraca kontrolnu tablu predmeta prijavljenog tuzioca ili istrazitelja
+func (h *TuzilastvoHandler) DobaviPregledPredmeta(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviPregledPredmeta")
+	defer span.End()
+
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(r)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	predmeti, err := h.tuzilastvoRepo.DobaviPredmetePoKorisniku(ctx, logovaniKorisnikId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja predmeta")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja predmeta"))
+		return
+	}
+
+	pregled := data.NapraviPregledPredmeta(predmeti, time.Now())
+	err = json.NewEncoder(rw).Encode(pregled)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+// predmetPrijave vraca predmet otvoren za prijavu ili nil ako predmet ne postoji
+// predmetPrijave vraca nil bez greske ako za prijavu jos nije otvoren predmet
+func (h *TuzilastvoHandler) predmetPrijave(ctx context.Context, prijavaId primitive.ObjectID) (*data.Predmet, error) {
+	predmet, err := h.tuzilastvoRepo.DobaviPredmetPoPrijavi(ctx, prijavaId)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return predmet, err
+}
+
+// dokazKorisnika dobavlja dokaz iz putanje i proverava da li mu prijavljeni korisnik ima pristup. Pristup imaju
+// trenutni cuvar dokaza i korisnici dodeljeni predmetu, a dokazu prijave za koju jos nema predmeta samo
+// trenutni cuvar i korisnik koji je dokaz evidentirao.
+func (h *TuzilastvoHandler) dokazKorisnika(ctx context.Context, req *http.Request) (*data.Dokaz, *data.Korisnik, *data.Predmet, int, string) {
+	dokazId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		return nil, nil, nil, http.StatusBadRequest, "Id dokaza nije procitan"
+	}
+
+	korisnik, err := korisnikIzTokena(req)
+	if err != nil {
+		return nil, nil, nil, http.StatusBadRequest, "Id korisnika nije procitan"
+	}
+
+	dokaz, err := h.tuzilastvoRepo.DobaviDokaz(ctx, dokazId)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil, nil, http.StatusNotFound, "Dokaz sa prosledjenim id ne postoji"
+	}
+	if err != nil {
+		return nil, nil, nil, http.StatusInternalServerError, "Greska prilikom dobavljanja dokaza"
+	}
+
+	var predmet *data.Predmet
+	if !dokaz.IdPredmeta.IsZero() {
+		predmet, err = h.tuzilastvoRepo.DobaviPredmet(ctx, dokaz.IdPredmeta)
+		if err == mongo.ErrNoDocuments {
+			predmet, err = nil, nil
+		}
+	} else if !dokaz.IdPrijave.IsZero() {
+		predmet, err = h.predmetPrijave(ctx, dokaz.IdPrijave)
+	}
+	if err != nil {
+		return nil, nil, nil, http.StatusInternalServerError, "Greska prilikom dobavljanja predmeta dokaza"
+	}
+
+	if dokaz.Cuvar.IdKorisnika == korisnik.ID {
+		return dokaz, korisnik, predmet, http.StatusOK, ""
+	}
+	if predmet != nil {
+		if !predmet.ImaPristup(korisnik.ID) {
+			return nil, nil, nil, http.StatusForbidden, "Korisnik nije dodeljen predmetu dokaza"
+		}
+		return dokaz, korisnik, predmet, http.StatusOK, ""
+	}
+
+	prviUnos, err := h.tuzilastvoRepo.DobaviPrviUnosLanca(ctx, dokaz.ID)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, nil, nil, http.StatusInternalServerError, "Greska prilikom dobavljanja lanca cuvanja"
+	}
+	if prviUnos == nil || prviUnos.IdZapisnicara != korisnik.ID {
+		return nil, nil, nil, http.StatusForbidden, "Dokaz bez predmeta je dostupan samo cuvaru i korisniku koji ga je evidentirao"
+	}
+
+	return dokaz, korisnik, predmet, http.StatusOK, ""
+}
+
+// EvidentirajDokaz evidentira dokaz za krivicnu prijavu ili predmet i otvara njegov lanac cuvanja,
+// korisnik koji evidentira dokaz postaje njegov prvi cuvar
+func (h *TuzilastvoHandler) EvidentirajDokaz(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.EvidentirajDokaz")
+	defer span.End()
+
+	korisnik, err := korisnikIzTokena(req)
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	var novi data.NoviDokaz
+	if err := json.NewDecoder(req.Body).Decode(&novi); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if novi.Naziv == "" || novi.Lokacija == "" {
+		span.SetStatus(codes.Error, "Naziv i lokacija dokaza su obavezni")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Naziv i lokacija dokaza su obavezni"))
+		return
+	}
+	if novi.IdPrijave.IsZero() && novi.IdPredmeta.IsZero() {
+		span.SetStatus(codes.Error, "Dokaz mora biti vezan za krivicnu prijavu ili predmet")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Dokaz mora biti vezan za krivicnu prijavu ili predmet"))
+		return
+	}
+
+	var predmet *data.Predmet
+	if !novi.IdPredmeta.IsZero() {
+		predmet, err = h.tuzilastvoRepo.DobaviPredmet(ctx, novi.IdPredmeta)
+		if err != nil {
+			span.SetStatus(codes.Error, "Predmet sa prosledjenim id ne postoji")
+			writer.WriteHeader(http.StatusNotFound)
+			writer.Write([]byte("Predmet sa prosledjenim id ne postoji"))
+			return
+		}
+		if !novi.IdPrijave.IsZero() && novi.IdPrijave != predmet.KrivicnaPrijava.ID {
+			span.SetStatus(codes.Error, "Krivicna prijava ne pripada predmetu")
+			writer.WriteHeader(http.StatusBadRequest)
+			writer.Write([]byte("Krivicna prijava ne pripada predmetu"))
+			return
+		}
+		novi.IdPrijave = predmet.KrivicnaPrijava.ID
+	} else {
+		_, err := h.DobaviKrivicnuPrijavuByID(ctx, novi.IdPrijave.Hex(), req.Header.Get("Authorization"))
+		if err != nil {
+			span.SetStatus(codes.Error, "Greska prilikom dobavljanja krivicne prijave po id")
+			writer.WriteHeader(statusGreskePrijave(err))
+			writer.Write([]byte("Greska prilikom dobavljanja krivicne prijave po id"))
+			return
+		}
+		predmet, err = h.predmetPrijave(ctx, novi.IdPrijave)
+		if err != nil {
+			span.SetStatus(codes.Error, "Greska prilikom dobavljanja predmeta prijave")
+			writer.WriteHeader(http.StatusInternalServerError)
+			writer.Write([]byte("Greska prilikom dobavljanja predmeta prijave"))
+			return
+		}
+		if predmet != nil {
+			novi.IdPredmeta = predmet.ID
+		}
+	}
+	if predmet != nil && !predmet.ImaPristup(korisnik.ID) {
+		span.SetStatus(codes.Error, "Korisnik nije dodeljen predmetu")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Korisnik nije dodeljen predmetu"))
+		return
+	}
+
+	cuvar := data.CuvarDokaza{IdKorisnika: korisnik.ID, Ime: korisnik.ImeKorisnika()}
+	dokaz := data.Dokaz{
+		ID:          primitive.NewObjectID(),
+		Naziv:       novi.Naziv,
+		Opis:        novi.Opis,
+		IdPrijave:   novi.IdPrijave,
+		IdPredmeta:  novi.IdPredmeta,
+		Evidentiran: data.VremeUnosaDokaza(),
+		Cuvar:       cuvar,
+		Lokacija:    novi.Lokacija,
+	}
+	prviUnos := dokaz.PrviUnos(cuvar)
+	dokaz.BrojUnosa = 1
+	dokaz.PoslednjiHes = prviUnos.Hes
+
+	err = h.tuzilastvoRepo.EvidentirajDokaz(ctx, &dokaz, prviUnos)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom evidentiranja dokaza")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom evidentiranja dokaza"))
+		return
+	}
+	dokaz.Lanac = []*data.UnosLancaDokaza{prviUnos}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusCreated)
+	dokaz.ToJSON(writer)
+}
+
+// DobaviDokaz vraca dokaz sa celim lancem cuvanja
+func (h *TuzilastvoHandler) DobaviDokaz(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviDokaz")
+	defer span.End()
+
+	dokaz, _, _, status, poruka := h.dokazKorisnika(ctx, r)
+	if dokaz == nil {
+		span.SetStatus(codes.Error, poruka)
+		rw.WriteHeader(status)
+		rw.Write([]byte(poruka))
+		return
+	}
+
+	lanac, err := h.tuzilastvoRepo.DobaviLanacDokaza(ctx, dokaz.ID)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja lanca cuvanja")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja lanca cuvanja"))
+		return
+	}
+	dokaz.Lanac = lanac
+
+	err = dokaz.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+// DobaviDokazePredmeta vraca dokaze predmeta i njegove krivicne prijave, bez lanaca cuvanja
+func (h *TuzilastvoHandler) DobaviDokazePredmeta(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviDokazePredmeta")
+	defer span.End()
+
+	predmet, _, status, poruka := h.predmetKorisnika(ctx, r)
+	if predmet == nil {
+		span.SetStatus(codes.Error, poruka)
+		rw.WriteHeader(status)
+		rw.Write([]byte(poruka))
+		return
+	}
+
+	dokazi, err := h.tuzilastvoRepo.DobaviDokazePredmeta(ctx, predmet)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja dokaza")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja dokaza"))
+		return
+	}
+
+	err = dokazi.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+// PredajDokaz belezi predaju dokaza novom cuvaru ili premestanje na drugu lokaciju. Predaju moze da zabelezi
+// trenutni cuvar ili tuzilac predmeta, a novi unos se nadovezuje na poslednji unos lanca.
+func (h *TuzilastvoHandler) PredajDokaz(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.PredajDokaz")
+	defer span.End()
+
+	dokaz, korisnik, predmet, status, poruka := h.dokazKorisnika(ctx, req)
+	if dokaz == nil {
+		span.SetStatus(codes.Error, poruka)
+		writer.WriteHeader(status)
+		writer.Write([]byte(poruka))
+		return
+	}
+
+	var predaja data.PredajaDokaza
+	if err := json.NewDecoder(req.Body).Decode(&predaja); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if predaja.IdPrimaoca.IsZero() || predaja.Lokacija == "" {
+		span.SetStatus(codes.Error, "Primalac i lokacija dokaza su obavezni")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Primalac i lokacija dokaza su obavezni"))
+		return
+	}
+
+	poslednji, err := h.tuzilastvoRepo.DobaviPoslednjiUnosLanca(ctx, dokaz.ID)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja lanca cuvanja")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom dobavljanja lanca cuvanja"))
+		return
+	}
+
+	tuzilac := predmet != nil && predmet.IdTuzioca == korisnik.ID
+	if poslednji.Primio.IdKorisnika != korisnik.ID && !tuzilac {
+		span.SetStatus(codes.Error, "Predaju dokaza moze da zabelezi samo njegov cuvar ili tuzilac predmeta")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Predaju dokaza moze da zabelezi samo njegov cuvar ili tuzilac predmeta"))
+		return
+	}
+	if predaja.IdPrimaoca == poslednji.Primio.IdKorisnika && predaja.Lokacija == poslednji.Lokacija {
+		span.SetStatus(codes.Error, "Predaja ne menja cuvara ni lokaciju dokaza")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Predaja ne menja cuvara ni lokaciju dokaza"))
+		return
+	}
+
+	primalac := data.CuvarDokaza{IdKorisnika: predaja.IdPrimaoca}
+	switch predaja.IdPrimaoca {
+	case poslednji.Primio.IdKorisnika:
+		primalac = poslednji.Primio
+	case korisnik.ID:
+		primalac.Ime = korisnik.ImeKorisnika()
+	default:
+		pronadjen, err := h.authClient.DobaviKorisnika(ctx, predaja.IdPrimaoca.Hex(), req.Header.Get("Authorization"))
+		if err != nil {
+			if client.JeNijePronadjen(err) {
+				span.SetStatus(codes.Error, "Primalac ne postoji")
+				writer.WriteHeader(http.StatusNotFound)
+				writer.Write([]byte("Primalac ne postoji"))
+				return
+			}
+			span.SetStatus(codes.Error, "Greska prilikom dobavljanja primaoca")
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			writer.Write([]byte("Greska prilikom dobavljanja primaoca"))
+			return
+		}
+		if !data.MozeDaCuvaDokaz(pronadjen.Rola) {
+			span.SetStatus(codes.Error, "Korisnik sa ovom rolom ne moze biti cuvar dokaza")
+			writer.WriteHeader(http.StatusBadRequest)
+			writer.Write([]byte("Korisnik sa ovom rolom ne moze biti cuvar dokaza"))
+			return
+		}
+		primalac.Ime = pronadjen.ImeKorisnika()
+	}
+
+	predao := poslednji.Primio
+	unos := data.UnosLancaDokaza{
+		ID:             primitive.NewObjectID(),
+		IdDokaza:       dokaz.ID,
+		Tip:            data.DOKAZ_PREDAT,
+		Datum:          data.VremeUnosaDokaza(),
+		IdZapisnicara:  korisnik.ID,
+		ImeZapisnicara: korisnik.ImeKorisnika(),
+		Predao:         &predao,
+		Primio:         primalac,
+		Lokacija:       predaja.Lokacija,
+		Napomena:       predaja.Napomena,
+	}
+	// satovi replika se mogu malo razlikovati, a unos ne sme biti stariji od prethodnog
+	if unos.Datum.Before(poslednji.Datum) {
+		unos.Datum = poslednji.Datum
+	}
+	unos.NastaviLanac(poslednji)
+
+	err = h.tuzilastvoRepo.DodajUnosLanca(ctx, &unos)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			span.SetStatus(codes.Error, "Dokaz je u medjuvremenu predat, proverite lanac i pokusajte ponovo")
+			writer.WriteHeader(http.StatusConflict)
+			writer.Write([]byte("Dokaz je u medjuvremenu predat, proverite lanac i pokusajte ponovo"))
+			return
+		}
+		span.SetStatus(codes.Error, "Greska prilikom belezenja predaje dokaza")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom belezenja predaje dokaza"))
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(&unos)
+}
+
+// ProveriLanacDokaza ponovo racuna hes svakog unosa lanca cuvanja i vraca sve pronadjene nepravilnosti
+func (h *TuzilastvoHandler) ProveriLanacDokaza(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.ProveriLanacDokaza")
+	defer span.End()
+
+	dokaz, _, _, status, poruka := h.dokazKorisnika(ctx, r)
+	if dokaz == nil {
+		span.SetStatus(codes.Error, poruka)
+		rw.WriteHeader(status)
+		rw.Write([]byte(poruka))
+		return
+	}
+
+	lanac, err := h.tuzilastvoRepo.DobaviLanacDokaza(ctx, dokaz.ID)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja lanca cuvanja")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja lanca cuvanja"))
+		return
+	}
+
+	provera := data.ProveriLanacDokaza(dokaz, lanac)
+	if !provera.Ispravan {
+		h.logger.Printf("Lanac cuvanja dokaza %s ima %d nepravilnosti", dokaz.ID.Hex(), len(provera.Nepravilnosti))
+	}
+
+	err = json.NewEncoder(rw).Encode(provera)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+// PokreniPracenjeRokova periodicno racuna rokove aktivnih predmeta i salje obavestenja o rokovima koji isticu
+// ili su istekli. Obavestenje za isti rok i nivo se salje samo jednom, pa provera moze da radi na vise instanci.
+func (h *TuzilastvoHandler) PokreniPracenjeRokova(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		h.obradiRokove(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *TuzilastvoHandler) obradiRokove(ctx context.Context) {
+	pravila, err := h.tuzilastvoRepo.DobaviPravilaRokova(ctx)
+	if err != nil {
+		h.logger.Println("Greska prilikom dobavljanja pravila rokova:", err)
+		return
+	}
+	predmeti, err := h.tuzilastvoRepo.DobaviAktivnePredmete(ctx)
+	if err != nil {
+		h.logger.Println("Greska prilikom dobavljanja aktivnih predmeta:", err)
+		return
+	}
+
+	// hitni i istekli rokovi se eskaliraju glavnim tuziocima, a ako auth servis nije dostupan
+	// obavestenje dobija samo tuzilac predmeta, dok glavni tuzioci obavestenje dobijaju u sledecoj proveri
+	var glavniTuzioci []primitive.ObjectID
+	korisnici, err := h.authClient.DobaviKorisnikePoRoli(ctx, data.RolaGlavniTuzilac)
+	if err != nil {
+		h.logger.Println("Greska prilikom dobavljanja glavnih tuzilaca:", err)
+	}
+	for _, korisnik := range korisnici {
+		glavniTuzioci = append(glavniTuzioci, korisnik.ID)
+	}
+
+	sada := time.Now()
+	poslato := 0
+	for _, predmet := range predmeti {
+		for _, rok := range data.IzracunajRokovePredmeta(predmet, pravila, sada) {
+			if rok.Nivo == "" {
+				continue
+			}
+			for _, idKorisnika := range rokovi.PrimaociObavestenja([]primitive.ObjectID{predmet.IdTuzioca}, glavniTuzioci, rok.Nivo) {
+				obavestenje := data.Obavestenje{
+					IdKorisnika: idKorisnika,
+					KljucRoka:   rok.Kljuc,
+					Nivo:        rok.Nivo,
+					PredmetId:   predmet.ID,
+					Tekst:       rok.TekstObavestenja(),
+					Istice:      rok.Istice,
+					Kreirano:    sada,
+				}
+				novo, err := h.tuzilastvoRepo.DodajObavestenje(ctx, &obavestenje)
+				if err != nil {
+					h.logger.Println("Greska prilikom slanja obavestenja o roku", rok.Kljuc, err)
+					continue
+				}
+				if novo {
+					poslato++
+				}
+			}
+		}
+	}
+	if poslato > 0 {
+		h.logger.Printf("Poslato %d obavestenja o rokovima", poslato)
+	}
+}
+
+// PredstojeciRokovi vraca rokove predmeta prijavljenog korisnika koji su istekli ili isticu u narednih
+// dana dana, podrazumevano data.PeriodPredstojecihRokovaDana
+func (h *TuzilastvoHandler) PredstojeciRokovi(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.PredstojeciRokovi")
+	defer span.End()
+
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(r)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	dana := data.PeriodPredstojecihRokovaDana
+	if parametar := r.URL.Query().Get("dana"); parametar != "" {
+		dana, err = strconv.Atoi(parametar)
+		if err != nil || dana < 0 {
+			span.SetStatus(codes.Error, "Broj dana mora biti pozitivan ceo broj")
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte("Broj dana mora biti pozitivan ceo broj"))
+			return
+		}
+	}
+
+	predmeti, err := h.tuzilastvoRepo.DobaviPredmetePoKorisniku(ctx, logovaniKorisnikId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja predmeta")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja predmeta"))
+		return
+	}
+	pravila, err := h.tuzilastvoRepo.DobaviPravilaRokova(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja pravila rokova")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja pravila rokova"))
+		return
+	}
+
+	rokovi := data.PredstojeciRokovi(predmeti, pravila, dana, time.Now())
+	err = json.NewEncoder(rw).Encode(rokovi)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+func (h *TuzilastvoHandler) DobaviPravilaRokova(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviPravilaRokova")
+	defer span.End()
+
+	pravila, err := h.tuzilastvoRepo.DobaviPravilaRokova(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja pravila rokova")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja pravila rokova"))
+		return
+	}
+
+	err = pravila.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+// SacuvajPraviloRoka dodaje pravilo roka ili menja postojece, pravilo bez zakona vazi za sva krivicna dela
+func (h *TuzilastvoHandler) SacuvajPraviloRoka(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.SacuvajPraviloRoka")
+	defer span.End()
+
+	var pravilo data.PraviloRoka
+	if err := json.NewDecoder(req.Body).Decode(&pravilo); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if greska := rokovi.ProveriPravilo(&pravilo, data.PoznatKorakPostupka); greska != "" {
+		span.SetStatus(codes.Error, greska)
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte(greska))
+		return
+	}
+
+	err := h.tuzilastvoRepo.SacuvajPraviloRoka(ctx, &pravilo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			span.SetStatus(codes.Error, "Pravilo sa prosledjenim id ne postoji")
+			writer.WriteHeader(http.StatusNotFound)
+			writer.Write([]byte("Pravilo sa prosledjenim id ne postoji"))
+			return
+		}
+		if err == rokovi.ErrPraviloPostoji {
+			span.SetStatus(codes.Error, "Pravilo za isti korak i krivicno delo vec postoji")
+			writer.WriteHeader(http.StatusConflict)
+			writer.Write([]byte("Pravilo za isti korak i krivicno delo vec postoji"))
+			return
+		}
+		span.SetStatus(codes.Error, "Greska prilikom cuvanja pravila roka")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom cuvanja pravila roka"))
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(&pravilo)
+}
+
+// DobaviObavestenja vraca obavestenja prijavljenog korisnika, uz neprocitana=true samo neprocitana
+func (h *TuzilastvoHandler) DobaviObavestenja(rw http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TuzilastvoHandler.DobaviObavestenja")
+	defer span.End()
+
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(r)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	obavestenja, err := h.tuzilastvoRepo.DobaviObavestenja(ctx, logovaniKorisnikId, r.URL.Query().Get("neprocitana") == "true")
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom dobavljanja obavestenja")
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom dobavljanja obavestenja"))
+		return
+	}
+
+	err = obavestenja.ToJSON(rw)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte("Greska prilikom konvertovanja u JSON"))
+		span.SetStatus(codes.Error, "Greska prilikom konvertovanja u JSON")
+	}
+}
+
+// PrimiObavestenjeSuda upisuje obavestenje suda tuziocu. Kljuc dobija prefiks sud:, kako se ne bi poklopio
+// sa kljucem roka tuzilastva, a ponovljeno obavestenje sa istim kljucem nije greska.
+func (h *TuzilastvoHandler) PrimiObavestenjeSuda(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.PrimiObavestenjeSuda")
+	defer span.End()
+
+	if !helper.PozivServisa(req, "sud") {
+		span.SetStatus(codes.Error, "Obavestenja suda dostavlja samo sud")
+		writer.WriteHeader(http.StatusForbidden)
+		writer.Write([]byte("Obavestenja suda dostavlja samo sud"))
+		return
+	}
+
+	var obavestenjeSuda data.ObavestenjeSuda
+	if err := json.NewDecoder(req.Body).Decode(&obavestenjeSuda); err != nil {
+		span.SetStatus(codes.Error, "Pogresan format zahteva")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Pogresan format zahteva"))
+		return
+	}
+	if obavestenjeSuda.Kljuc == "" || obavestenjeSuda.IdTuzioca.IsZero() || obavestenjeSuda.Tekst == "" {
+		span.SetStatus(codes.Error, "Kljuc, tuzilac i tekst obavestenja su obavezni")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Kljuc, tuzilac i tekst obavestenja su obavezni"))
+		return
+	}
+
+	obavestenje := data.Obavestenje{
+		IdKorisnika: obavestenjeSuda.IdTuzioca,
+		KljucRoka:   "sud:" + obavestenjeSuda.Kljuc,
+		Nivo:        data.OBAVESTENJE_INFORMACIJA,
+		PredmetId:   obavestenjeSuda.PredmetId,
+		Tekst:       obavestenjeSuda.Tekst,
+		Istice:      obavestenjeSuda.Istice,
+		Kreirano:    time.Now(),
+	}
+	_, err := h.tuzilastvoRepo.DodajObavestenje(ctx, &obavestenje)
+	if err != nil {
+		span.SetStatus(codes.Error, "Greska prilikom upisa obavestenja")
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte("Greska prilikom upisa obavestenja"))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+}
+
+func (h *TuzilastvoHandler) ProcitajObavestenje(writer http.ResponseWriter, req *http.Request) {
+	ctx, span := h.tracer.Start(req.Context(), "TuzilastvoHandler.ProcitajObavestenje")
+	defer span.End()
+
+	obavestenjeId, err := primitive.ObjectIDFromHex(mux.Vars(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id obavestenja nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id obavestenja nije procitan"))
+		return
+	}
+	logovaniKorisnikId, err := primitive.ObjectIDFromHex(helper.ExtractClaims(req)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, "Id korisnika nije procitan")
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte("Id korisnika nije procitan"))
+		return
+	}
+
+	err = h.tuzilastvoRepo.OznaciObavestenjeProcitanim(ctx, obavestenjeId, logovaniKorisnikId)
+	if err != nil {
+		span.SetStatus(codes.Error, "Obavestenje ne postoji")
+		writer.WriteHeader(http.StatusNotFound)
+		writer.Write([]byte("Obavestenje ne postoji"))
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
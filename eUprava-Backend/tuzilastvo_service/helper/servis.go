@@ -0,0 +1,40 @@
+package helper
+
+import (
+	"github.com/cristalhq/jwt/v4"
+	"net/http"
+	"time"
+)
+
+const (
+	// RolaServis je rola kojom servis poziva drugi servis u svoje ime, kada ne postoji prijavljeni korisnik
+	RolaServis              = "Servis"
+	NazivServisa            = "tuzilastvo"
+	trajanjeServisnogTokena = 5 * time.Minute
+)
+
+// ServisniToken potpisuje kratkotrajan token kojim se servis predstavlja drugim servisima,
+// vraca vrednost za zaglavlje Authorization
+func ServisniToken() (string, error) {
+	signer, err := jwt.NewSignerHS(jwt.HS256, jwtKey)
+	if err != nil {
+		return "", err
+	}
+	claims := map[string]string{
+		"rola":          RolaServis,
+		"servis":        NazivServisa,
+		"korisnickoIme": NazivServisa + "_service",
+		"expires_at":    time.Now().Add(trajanjeServisnogTokena).Format(time.RFC3339),
+	}
+	token, err := jwt.NewBuilder(signer).Build(claims)
+	if err != nil {
+		return "", err
+	}
+	return "Bearer " + token.String(), nil
+}
+
+// PozivServisa proverava da zahtev salje servis sa prosledjenim nazivom, a ne prijavljeni korisnik
+func PozivServisa(r *http.Request, naziv string) bool {
+	claims := ExtractClaims(r)
+	return claims["rola"] == RolaServis && claims["servis"] == naziv
+}
@@ -33,6 +33,12 @@ func ExtractUserType(r *http.Request) (string, error) {
 
 func ExtractClaims(r *http.Request) map[string]string {
 	bearer := r.Header.Get("Authorization")
+	if bearer == "" && strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		// EventSource u browseru ne moze da postavi zaglavlje, pa se token za tok dogadjaja salje kao parametar
+		if token := r.URL.Query().Get("token"); token != "" {
+			bearer = "Bearer " + token
+		}
+	}
 	if bearer == "" {
 		return nil
 	}
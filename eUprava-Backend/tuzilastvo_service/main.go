@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"github.com/gorilla/mux"
+	"github.com/sony/gobreaker"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/jaeger"
 	"go.opentelemetry.io/otel/propagation"
@@ -14,7 +16,9 @@ import (
 	"os"
 	"os/signal"
 	"time"
+	"tuzilastvo_service/client"
 	"tuzilastvo_service/data"
+	"tuzilastvo_service/domain"
 	"tuzilastvo_service/handlers"
 	"tuzilastvo_service/middlewares"
 )
@@ -54,8 +58,63 @@ func main() {
 	}
 	defer store.DisconnectMongo(timeoutContext)
 	store.Ping()
+	if err := store.MigrirajKanale(timeoutContext); err != nil {
+		logger.Println("Greska prilikom migracije kanala:", err)
+	}
+	if err := store.KreirajIndeksePoruka(timeoutContext); err != nil {
+		logger.Println("Greska prilikom kreiranja indeksa poruka:", err)
+	}
+	if err := store.KreirajIndekseDokaza(timeoutContext); err != nil {
+		logger.Println("Greska prilikom kreiranja indeksa dokaza:", err)
+	}
+	if !data.KljucLancaPostavljen() {
+		logger.Fatal("KLJUC_LANCA_DOKAZA nije postavljen")
+	}
+	if err := store.PotpisiLanceDokaza(timeoutContext); err != nil {
+		logger.Println("Greska prilikom potpisivanja lanaca dokaza:", err)
+	}
+	if err := store.KreirajIndeksePredmeta(timeoutContext); err != nil {
+		logger.Fatal("Greska prilikom kreiranja jedinstvenog indeksa predmeta po krivicnoj prijavi: ", err)
+	}
+	if err := store.KreirajIndeksePromenaStatusa(timeoutContext); err != nil {
+		logger.Println("Greska prilikom kreiranja indeksa promena statusa prijava:", err)
+	}
+	if err := store.KreirajIndekseRokova(timeoutContext); err != nil {
+		logger.Println("Greska prilikom kreiranja indeksa obavestenja:", err)
+	}
+	if err := store.UpisiPodrazumevanaPravilaRokova(timeoutContext); err != nil {
+		logger.Println("Greska prilikom upisa pravila rokova:", err)
+	}
+
+	servisClient := &http.Client{
+		Transport: &http.Transport{
+			MaxIdleConns:        10,
+			MaxIdleConnsPerHost: 10,
+			MaxConnsPerHost:     10,
+		},
+	}
+
+	granicnaPolicijaUri := fmt.Sprintf("http://%s:%s", os.Getenv("GRANICNA_POLICIJA_SERVICE_HOST"), os.Getenv("GRANICNA_POLICIJA_SERVICE_PORT"))
+	granicnaPolicija := client.NewGranicnaPolicijaClient(servisClient, granicnaPolicijaUri, newCircuitBreaker("granicna_policija", logger))
+
+	mupUri := fmt.Sprintf("http://%s:%s", os.Getenv("MUP_SERVICE_HOST"), os.Getenv("MUP_SERVICE_PORT"))
+	mup := client.NewMupClient(servisClient, mupUri, newCircuitBreaker("mup", logger))
 
-	tuzilastvoHandler := handlers.NewTuzilastvoHandler(logger, store, tracer)
+	sudUri := fmt.Sprintf("http://%s:%s", os.Getenv("SUD_SERVICE_HOST"), os.Getenv("SUD_SERVICE_PORT"))
+	sud := client.NewSudClient(servisClient, sudUri, newCircuitBreaker("sud", logger))
+
+	authUri := fmt.Sprintf("http://%s:%s", os.Getenv("AUTH_SERVICE_HOST"), os.Getenv("AUTH_SERVICE_PORT"))
+	auth := client.NewAuthClient(servisClient, authUri, newCircuitBreaker("auth", logger))
+
+	razglasPoruka := data.NewRazglasPoruka()
+	tuzilastvoHandler := handlers.NewTuzilastvoHandler(logger, store, tracer, granicnaPolicija, mup, sud, auth, razglasPoruka)
+
+	pozadinskeObradeCtx, zaustaviPozadinskeObrade := context.WithCancel(context.Background())
+	defer zaustaviPozadinskeObrade()
+	go tuzilastvoHandler.PokreniPotvrduSporazuma(pozadinskeObradeCtx, time.Minute)
+	go data.PokreniPrenosPoruka(pozadinskeObradeCtx, store, razglasPoruka, logger)
+	go tuzilastvoHandler.PokreniPracenjeRokova(pozadinskeObradeCtx, time.Hour)
+	go tuzilastvoHandler.PokreniSlanjePromenaStatusa(pozadinskeObradeCtx, time.Minute)
 
 	//Initialize the router and add a middleware for all the requests
 	router := mux.NewRouter()
@@ -74,6 +133,9 @@ func main() {
 	dobaviZahteveZaSudskiPostupak := router.Methods(http.MethodGet).Subrouter()
 	dobaviZahteveZaSudskiPostupak.HandleFunc("/dobaviZahteveZaSudskiPostupak", tuzilastvoHandler.DobaviZahteveZaSudskiPostupak)
 
+	prijemZahtevaZaSudskiPostupak := router.Methods(http.MethodPut).Subrouter()
+	prijemZahtevaZaSudskiPostupak.HandleFunc("/prijemZahtevaZaSudskiPostupak/{id}", tuzilastvoHandler.PrijemZahtevaZaSudskiPostupak)
+
 	kreirajZahtevZaSklapanjeSporazuma := router.Methods(http.MethodPut).Subrouter()
 	kreirajZahtevZaSklapanjeSporazuma.HandleFunc("/kreirajZahtevZaSklapanjeSporazuma/{id}", tuzilastvoHandler.KreirajZahtevZaSklapanjeSporazuma)
 
@@ -84,7 +146,10 @@ func main() {
 	dobaviSporazume.HandleFunc("/dobaviSporazume", tuzilastvoHandler.DobaviSporazume)
 
 	dobaviKrivicnePrijave := router.Methods(http.MethodGet).Subrouter()
-	dobaviKrivicnePrijave.HandleFunc("/krivicnePrijave", tuzilastvoHandler.DobaviKrivicnePrijaveOdGranicnePolicjie)
+	dobaviKrivicnePrijave.HandleFunc("/krivicnePrijave", tuzilastvoHandler.DobaviSveKrivicnePrijave)
+
+	pretraziKrivicnePrijave := router.Methods(http.MethodGet).Subrouter()
+	pretraziKrivicnePrijave.HandleFunc("/pretraziKrivicnePrijave", tuzilastvoHandler.PretraziKrivicnePrijave)
 
 	dobaviZahteveZaSklapanjeSporazumaPoGradjaninu := router.Methods(http.MethodGet).Subrouter()
 	dobaviZahteveZaSklapanjeSporazumaPoGradjaninu.HandleFunc("/dobaviZahteveZaSklapanjeSporazumaPoGradjaninu/{id}", tuzilastvoHandler.DobaviZahteveZaSklapanjeSporazumaByGradjanin)
@@ -95,6 +160,12 @@ func main() {
 	odbijZahtevZaSklapanjeSporazuma := router.Methods(http.MethodPut).Subrouter()
 	odbijZahtevZaSklapanjeSporazuma.HandleFunc("/odbijZahtevZaSklapanjeSporazuma/{id}", tuzilastvoHandler.OdbijZahtevZaSklapanjeSporazuma)
 
+	kontraPonudaZahtevaZaSklapanjeSporazuma := router.Methods(http.MethodPut).Subrouter()
+	kontraPonudaZahtevaZaSklapanjeSporazuma.HandleFunc("/kontraPonudaZahtevaZaSklapanjeSporazuma/{id}", tuzilastvoHandler.KontraPonudaZahtevaZaSklapanjeSporazuma)
+
+	dobaviZahtevZaSklapanjeSporazuma := router.Methods(http.MethodGet).Subrouter()
+	dobaviZahtevZaSklapanjeSporazuma.HandleFunc("/dobaviZahtevZaSklapanjeSporazuma/{id}", tuzilastvoHandler.DobaviZahtevZaSklapanjeSporazuma)
+
 	kreirajKanal := router.Methods(http.MethodPost).Subrouter()
 	kreirajKanal.HandleFunc("/kreirajKanal", tuzilastvoHandler.KreirajKanal)
 
@@ -107,6 +178,87 @@ func main() {
 	dobaviPorukePoKanalu := router.Methods(http.MethodGet).Subrouter()
 	dobaviPorukePoKanalu.HandleFunc("/dobaviPorukePoKanalu/{id}", tuzilastvoHandler.DobaviPorukePoKanalu)
 
+	pozoviUKanal := router.Methods(http.MethodPut).Subrouter()
+	pozoviUKanal.HandleFunc("/pozoviUKanal/{id}", tuzilastvoHandler.PozoviUKanal)
+
+	ukloniIzKanala := router.Methods(http.MethodPut).Subrouter()
+	ukloniIzKanala.HandleFunc("/ukloniIzKanala/{id}", tuzilastvoHandler.UkloniIzKanala)
+
+	zatvoriKanal := router.Methods(http.MethodPut).Subrouter()
+	zatvoriKanal.HandleFunc("/zatvoriKanal/{id}", tuzilastvoHandler.ZatvoriKanal)
+
+	pratiPorukeKanala := router.Methods(http.MethodGet).Subrouter()
+	pratiPorukeKanala.HandleFunc("/pratiPorukeKanala/{id}", tuzilastvoHandler.PratiPorukeKanala)
+
+	posaljiPrilog := router.Methods(http.MethodPost).Subrouter()
+	posaljiPrilog.HandleFunc("/posaljiPrilog/{id}", tuzilastvoHandler.PosaljiPrilog)
+
+	preuzmiPrilog := router.Methods(http.MethodGet).Subrouter()
+	preuzmiPrilog.HandleFunc("/preuzmiPrilog/{id}/{prilogId}", tuzilastvoHandler.PreuzmiPrilog)
+
+	izmeniPoruku := router.Methods(http.MethodPut).Subrouter()
+	izmeniPoruku.HandleFunc("/izmeniPoruku/{id}", tuzilastvoHandler.IzmeniPoruku)
+
+	obrisiPoruku := router.Methods(http.MethodPut).Subrouter()
+	obrisiPoruku.HandleFunc("/obrisiPoruku/{id}", tuzilastvoHandler.ObrisiPoruku)
+
+	pretraziPoruke := router.Methods(http.MethodGet).Subrouter()
+	pretraziPoruke.HandleFunc("/pretraziPoruke", tuzilastvoHandler.PretraziPoruke)
+
+	evidentirajDokaz := router.Methods(http.MethodPost).Subrouter()
+	evidentirajDokaz.HandleFunc("/evidentirajDokaz", tuzilastvoHandler.EvidentirajDokaz)
+
+	dobaviDokaz := router.Methods(http.MethodGet).Subrouter()
+	dobaviDokaz.HandleFunc("/dobaviDokaz/{id}", tuzilastvoHandler.DobaviDokaz)
+
+	dobaviDokazePredmeta := router.Methods(http.MethodGet).Subrouter()
+	dobaviDokazePredmeta.HandleFunc("/dobaviDokazePredmeta/{id}", tuzilastvoHandler.DobaviDokazePredmeta)
+
+	predajDokaz := router.Methods(http.MethodPut).Subrouter()
+	predajDokaz.HandleFunc("/predajDokaz/{id}", tuzilastvoHandler.PredajDokaz)
+
+	proveriLanacDokaza := router.Methods(http.MethodGet).Subrouter()
+	proveriLanacDokaza.HandleFunc("/proveriLanacDokaza/{id}", tuzilastvoHandler.ProveriLanacDokaza)
+
+	predstojeciRokovi := router.Methods(http.MethodGet).Subrouter()
+	predstojeciRokovi.HandleFunc("/predstojeciRokovi", tuzilastvoHandler.PredstojeciRokovi)
+
+	pravilaRokova := router.Methods(http.MethodGet).Subrouter()
+	pravilaRokova.HandleFunc("/pravilaRokova", tuzilastvoHandler.DobaviPravilaRokova)
+
+	sacuvajPraviloRoka := router.Methods(http.MethodPut).Subrouter()
+	sacuvajPraviloRoka.HandleFunc("/sacuvajPraviloRoka", tuzilastvoHandler.SacuvajPraviloRoka)
+
+	obavestenja := router.Methods(http.MethodGet).Subrouter()
+	obavestenja.HandleFunc("/obavestenja", tuzilastvoHandler.DobaviObavestenja)
+
+	procitajObavestenje := router.Methods(http.MethodPut).Subrouter()
+	procitajObavestenje.HandleFunc("/procitajObavestenje/{id}", tuzilastvoHandler.ProcitajObavestenje)
+
+	obavestenjeSuda := router.Methods(http.MethodPost).Subrouter()
+	obavestenjeSuda.HandleFunc("/obavestenjeSuda", tuzilastvoHandler.PrimiObavestenjeSuda)
+
+	kreirajPredmet := router.Methods(http.MethodPut).Subrouter()
+	kreirajPredmet.HandleFunc("/kreirajPredmet/{id}", tuzilastvoHandler.KreirajPredmet)
+
+	dobaviPredmet := router.Methods(http.MethodGet).Subrouter()
+	dobaviPredmet.HandleFunc("/dobaviPredmet/{id}", tuzilastvoHandler.DobaviPredmet)
+
+	azurirajPredmet := router.Methods(http.MethodPut).Subrouter()
+	azurirajPredmet.HandleFunc("/azurirajPredmet/{id}", tuzilastvoHandler.AzurirajPredmet)
+
+	dodajBeleskuPredmetu := router.Methods(http.MethodPut).Subrouter()
+	dodajBeleskuPredmetu.HandleFunc("/dodajBeleskuPredmetu/{id}", tuzilastvoHandler.DodajBeleskuPredmetu)
+
+	promeniStatusPredmeta := router.Methods(http.MethodPut).Subrouter()
+	promeniStatusPredmeta.HandleFunc("/promeniStatusPredmeta/{id}", tuzilastvoHandler.PromeniStatusPredmeta)
+
+	pregledPredmeta := router.Methods(http.MethodGet).Subrouter()
+	pregledPredmeta.HandleFunc("/pregledPredmeta", tuzilastvoHandler.DobaviPregledPredmeta)
+
+	odlukaSudaOSporazumu := router.Methods(http.MethodPut).Subrouter()
+	odlukaSudaOSporazumu.HandleFunc("/odlukaSudaOSporazumu/{id}", tuzilastvoHandler.OdlukaSudaOSporazumu)
+
 	//Initialize the server
 	server := http.Server{
 		Addr:         ":" + port,
@@ -139,6 +291,30 @@ func main() {
 
 }
 
+func newCircuitBreaker(name string, logger *log.Logger) *gobreaker.CircuitBreaker {
+	return gobreaker.NewCircuitBreaker(
+		gobreaker.Settings{
+			Name:        name,
+			MaxRequests: 1,
+			Timeout:     10 * time.Second,
+			Interval:    0,
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return counts.ConsecutiveFailures > 2
+			},
+			OnStateChange: func(name string, from, to gobreaker.State) {
+				logger.Printf("CB '%s' changed from '%s' to '%s'\n", name, from, to)
+			},
+			IsSuccessful: func(err error) bool {
+				if err == nil {
+					return true
+				}
+				errResp, ok := err.(domain.ErrResp)
+				return ok && errResp.StatusCode >= 400 && errResp.StatusCode < 500
+			},
+		},
+	)
+}
+
 func newTraceProvider(exp sdktrace.SpanExporter) *sdktrace.TracerProvider {
 	// Ensure default SDK resources and the required service name are set.
 	r, err := resource.Merge(
@@ -1,6 +1,7 @@
 import { Component, OnInit } from '@angular/core';
 import { FormBuilder, FormGroup, Validators } from '@angular/forms';
 import { Prelaz } from 'src/app/models/prelaz';
+import { GranicniPrelaz } from 'src/app/models/granicniPrelaz';
 import { GranicnaPolicijaService } from 'src/app/services/granicna-policija.service';
 import { Router } from '@angular/router';
 
@@ -12,6 +13,7 @@ import { Router } from '@angular/router';
 export class KreirajPrelazComponent implements OnInit {
 
   prelazForm!: FormGroup;
+  granicniPrelazi: GranicniPrelaz[] = [];
 
   constructor(
     private fb: FormBuilder,
@@ -30,8 +32,18 @@ export class KreirajPrelazComponent implements OnInit {
       markaVozila: [''],
       modelVozila: [''],
       svrhaPutovanja: [''],
-      odobren: [false]  // Default value for checkbox
+      smer: ['', Validators.required],
+      granicniPrelazId: ['', Validators.required]
     });
+
+    this.granicnaPolicijaService.getGranicniPrelazi().subscribe(
+      (granicniPrelazi) => {
+        this.granicniPrelazi = granicniPrelazi ?? [];
+      },
+      (error) => {
+        console.error('Došlo je do greške prilikom učitavanja graničnih prelaza:', error);
+      }
+    );
   }
 
   onSubmit(): void {
@@ -47,7 +59,8 @@ export class KreirajPrelazComponent implements OnInit {
         markaVozila: formValues.markaVozila,
         modelVozila: formValues.modelVozila,
         svrhaPutovanja: formValues.svrhaPutovanja,
-        odobren: formValues.odobren
+        smer: formValues.smer,
+        granicniPrelazId: formValues.granicniPrelazId
       };
 
       // Call service method to create Prelaz
@@ -58,14 +71,14 @@ export class KreirajPrelazComponent implements OnInit {
         },
         (error) => {
           console.error('Došlo je do greške prilikom kreiranja prelaza:', error);
-          alert('Došlo je do greške prilikom kreiranja prelaza.');
+          alert(typeof error.error === 'string' && error.error ? error.error : 'Došlo je do greške prilikom kreiranja prelaza.');
         }
       );
     }
   }
 
   resetForm(): void {
-    this.prelazForm.reset();
+    this.prelazForm.reset({ smer: '', granicniPrelazId: '' });
   }
 
   cancel(): void {
@@ -1,6 +1,6 @@
 import { Component, OnInit } from '@angular/core';
 import { Presuda } from 'src/app/models/presuda';
-import { TerminSudjenja } from 'src/app/models/terminSudjenja';
+import { IzmenaTermina, TerminSudjenja } from 'src/app/models/terminSudjenja';
 import { SudService } from 'src/app/services/sud.service';
 
 @Component({
@@ -13,6 +13,10 @@ export class TerminiComponent implements OnInit {
   termini: TerminSudjenja[] = [];
   novaPresuda: Presuda = {};
   selectedId: string = "";
+  izmena: IzmenaTermina = {};
+  izmenaId: string = "";
+  tipIzmene: string = "";
+  greskaIzmene: string = "";
 
   constructor(private sudService: SudService) { }
 
@@ -36,6 +40,50 @@ export class TerminiComponent implements OnInit {
   }
 
 
+  oznaciOdrzanim(terminId: string): void {
+    this.sudService.oznaciTerminOdrzanim(terminId).subscribe(() => {
+      this.fetchTermini();
+    });
+  }
+
+  selectIzmena(id: string, tip: string): void {
+    if(this.izmenaId == id && this.tipIzmene == tip) {
+      this.izmenaId = "";
+      this.tipIzmene = "";
+    }
+    else {
+      this.izmenaId = id;
+      this.tipIzmene = tip;
+    }
+    this.izmena = {};
+    this.greskaIzmene = "";
+  }
+
+  odloziTermin(terminId: string): void {
+    const izmena: IzmenaTermina = { ...this.izmena };
+    if (izmena.datum) {
+      izmena.datum = new Date(izmena.datum).toISOString();
+    }
+    this.sudService.odloziTermin(terminId, izmena).subscribe({
+      next: () => this.zavrsiIzmenu(),
+      error: (err) => this.greskaIzmene = typeof err.error === 'string' ? err.error : "Termin nije odlozen"
+    });
+  }
+
+  otkaziTermin(terminId: string): void {
+    this.sudService.otkaziTermin(terminId, { razlog: this.izmena.razlog }).subscribe({
+      next: () => this.zavrsiIzmenu(),
+      error: (err) => this.greskaIzmene = typeof err.error === 'string' ? err.error : "Termin nije otkazan"
+    });
+  }
+
+  zavrsiIzmenu(): void {
+    this.izmena = {};
+    this.izmenaId = "";
+    this.tipIzmene = "";
+    this.greskaIzmene = "";
+    this.fetchTermini();
+  }
 
   kreirajPresudu(terminId: string): void {
     this.sudService.createPresuda(this.novaPresuda, terminId).subscribe(() => {
@@ -17,6 +17,7 @@ export class KrivicnePrijaveComponent implements OnInit {
   constructor(private authService: AuthService,private tuzilastvoService:TuzilastvoService,public dialog: MatDialog) { }
 
   krivicnePrijave: KrivicnaPrijava[] = [];
+  nedostupniIzvori: string[] = [];
   rolaLogovanogKorisnika: string | null = ""
 
   ngOnInit(): void {
@@ -26,8 +27,10 @@ export class KrivicnePrijaveComponent implements OnInit {
 
   getKrivicnePrijave(): void {
     this.tuzilastvoService.getKrivicnePrijave().subscribe(
-      (data) => {
-        this.krivicnePrijave = data;
+      (response) => {
+        this.krivicnePrijave = response.body ?? [];
+        const izvori = response.headers.get('Nedostupni-Izvori');
+        this.nedostupniIzvori = izvori ? izvori.split(',') : [];
       },
       (error) => {
         console.error(error);
@@ -0,0 +1,6 @@
+export interface GranicniPrelaz {
+    id: string;
+    sifra?: string;
+    naziv?: string;
+    susednaDrzava?: string;
+  }
@@ -11,4 +11,6 @@ export interface Prelaz {
     modelVozila?: string;
     svrhaPutovanja?: string;
     odobren?: boolean;
+    smer?: 'ULAZ' | 'IZLAZ';
+    granicniPrelazId?: string;
   }
\ No newline at end of file
@@ -1,7 +1,22 @@
-import { Prelaz } from "./prelaz"
+export interface Incident {
+    tip: string
+    datum: string
+    opis?: string
+    prelazId?: string
+    idSluzbenika?: string
+}
 
 export interface SumnjivoLice {
     id: string
-	Prelaz?:Prelaz            
-	opis?: string          
-}
\ No newline at end of file
+    jmbg?: string
+    brojeviPasosa?: string[]
+    ime?: string
+    prezime?: string
+    drzavljanstvo?: string
+    varijanteImena?: string[]
+    opis?: string
+    oznaceno: boolean
+    rizik: number
+    incidenti?: Incident[]
+    prelaziIds?: string[]
+}
@@ -6,4 +6,12 @@ export interface TerminSudjenja{
     datum?: string;
     prostorija?: string;
     predmet?: Predmet;
-}
\ No newline at end of file
+    status?: string;
+}
+
+export interface IzmenaTermina{
+    datum?: string;
+    adresa?: string;
+    prostorija?: string;
+    razlog?: string;
+}
@@ -5,6 +5,7 @@ import { environment } from 'src/environments/environment';
 import { Prelaz } from '../models/prelaz';
 import { SumnjivoLice } from '../models/sumnjivoLice';
 import { KrivicnaPrijava } from '../models/krivicnaPrijava';
+import { GranicniPrelaz } from '../models/granicniPrelaz';
 
 @Injectable({
   providedIn: 'root'
@@ -41,6 +42,11 @@ export class GranicnaPolicijaService {
     return this.http.get<Prelaz[]>(`${environment.baseApiUrl}/${this.url}/prelaz/all`);
   }
 
+  // Get Granicni Prelazi
+  public getGranicniPrelazi(): Observable<GranicniPrelaz[]> {
+    return this.http.get<GranicniPrelaz[]>(`${environment.baseApiUrl}/${this.url}/granicni-prelaz/all`);
+  }
+
   // Get Krivicne Prijave
   public getKrivicnePrijave(): Observable<KrivicnaPrijava[]> {
     return this.http.get<KrivicnaPrijava[]>(`${environment.baseApiUrl}/${this.url}/krivicna-prijava/all`);
@@ -3,7 +3,7 @@ import { Injectable } from '@angular/core';
 import { Observable } from 'rxjs';
 import { environment } from 'src/environments/environment';
 import { Predmet } from '../models/predmet';
-import { TerminSudjenja } from '../models/terminSudjenja';
+import { IzmenaTermina, TerminSudjenja } from '../models/terminSudjenja';
 import { Presuda } from '../models/presuda';
 
 @Injectable({
@@ -42,6 +42,18 @@ export class SudService {
     return this.http.post(`${environment.baseApiUrl}/${this.url}/termini/${predmetId}`, termin);
   }
 
+  oznaciTerminOdrzanim(terminId: String) {
+    return this.http.put(`${environment.baseApiUrl}/${this.url}/termini/${terminId}/odrzan`, null);
+  }
+
+  odloziTermin(terminId: String, izmena: IzmenaTermina) {
+    return this.http.put(`${environment.baseApiUrl}/${this.url}/termini/${terminId}/odlozi`, izmena);
+  }
+
+  otkaziTermin(terminId: String, izmena: IzmenaTermina) {
+    return this.http.put(`${environment.baseApiUrl}/${this.url}/termini/${terminId}/otkazi`, izmena);
+  }
+
   public getPresude(): Observable<Presuda[]> {
     return this.http.get<Presuda[]>(`${environment.baseApiUrl}/${this.url}/presude`);
   }
@@ -1,4 +1,4 @@
-import { HttpClient } from '@angular/common/http';
+import { HttpClient, HttpResponse } from '@angular/common/http';
 import { Injectable } from '@angular/core';
 import { KrivicnaPrijava } from '../models/krivicnaPrijava';
 import { Observable } from 'rxjs';
@@ -17,8 +17,9 @@ export class TuzilastvoService {
   private url = "tuzilastvo";
   constructor(private http: HttpClient) { }
 
-  public getKrivicnePrijave(): Observable<KrivicnaPrijava[]> {
-    return this.http.get<KrivicnaPrijava[]>(`${environment.baseApiUrl}/${this.url}/krivicnePrijave`);
+  // Zaglavlje Nedostupni-Izvori odgovora navodi izvore cije prijave nedostaju u spisku
+  public getKrivicnePrijave(): Observable<HttpResponse<KrivicnaPrijava[]>> {
+    return this.http.get<KrivicnaPrijava[]>(`${environment.baseApiUrl}/${this.url}/krivicnePrijave`, { observe: 'response' });
   }
   public getZahteviZaSudskiPostupak(): Observable<ZahtevZaSudskiPostupak[]> {
     return this.http.get<ZahtevZaSudskiPostupak[]>(`${environment.baseApiUrl}/${this.url}/dobaviZahteveZaSudskiPostupak`);